@@ -0,0 +1,149 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package accessibility
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// The accessibility event stream re-renders service notifications as
+// semantic, plain-text events for assistive technologies. Screen readers
+// and alternative frontends read it from the local HTTP server instead of
+// scraping the standard notification channels, which are shaped for the
+// GUI and full of structured payloads a reader can't speak.
+
+// EventType classifies an accessibility event.
+type EventType string
+
+const (
+	// EventChatMessage is an incoming chat message with a spoken-friendly
+	// rendering.
+	EventChatMessage EventType = "chat-message"
+	// EventFileOp is a completed KBFS file operation.
+	EventFileOp EventType = "file-op"
+	// EventFollow is a change in who the user follows.
+	EventFollow EventType = "follow"
+)
+
+// Event is one semantic event. Text is a complete plain-text sentence
+// suitable for speech output; Detail carries structured extras frontends
+// may use for navigation.
+type Event struct {
+	Seq    uint64            `json:"seq"`
+	Type   EventType         `json:"type"`
+	Time   time.Time         `json:"time"`
+	Text   string            `json:"text"`
+	Detail map[string]string `json:"detail,omitempty"`
+}
+
+// streamBufferSize bounds how much history a late-polling frontend can
+// catch up on.
+const streamBufferSize = 256
+
+// Stream is an in-memory ring of recent events.
+type Stream struct {
+	libkb.Contextified
+	sync.Mutex
+	events  []Event
+	nextSeq uint64
+}
+
+func NewStream(g *libkb.GlobalContext) *Stream {
+	return &Stream{
+		Contextified: libkb.NewContextified(g),
+		nextSeq:      1,
+	}
+}
+
+// Publish appends an event to the stream.
+func (s *Stream) Publish(typ EventType, text string, detail map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, Event{
+		Seq:    s.nextSeq,
+		Type:   typ,
+		Time:   time.Now(),
+		Text:   text,
+		Detail: detail,
+	})
+	s.nextSeq++
+	if len(s.events) > streamBufferSize {
+		s.events = s.events[len(s.events)-streamBufferSize:]
+	}
+}
+
+// Since returns events with sequence numbers greater than seq, plus the
+// cursor to poll with next time.
+func (s *Stream) Since(seq uint64) (events []Event, next uint64) {
+	s.Lock()
+	defer s.Unlock()
+	for _, ev := range s.events {
+		if ev.Seq > seq {
+			events = append(events, ev)
+		}
+	}
+	return events, s.nextSeq - 1
+}
+
+// listener translates router notifications into accessibility events.
+type listener struct {
+	libkb.NoopNotifyListener
+	stream *Stream
+}
+
+var _ libkb.NotifyListener = (*listener)(nil)
+
+func (l *listener) NewChatActivity(uid keybase1.UID, activity chat1.ChatActivity,
+	source chat1.ChatActivitySource) {
+	typ, err := activity.ActivityType()
+	if err != nil || typ != chat1.ChatActivityType_INCOMING_MESSAGE {
+		return
+	}
+	msg := activity.IncomingMessage().Message
+	state, err := msg.State()
+	if err != nil || state != chat1.MessageUnboxedState_VALID {
+		return
+	}
+	valid := msg.Valid()
+	text := valid.BodySummary
+	if text == "" {
+		return
+	}
+	l.stream.Publish(EventChatMessage,
+		valid.SenderUsername+" says: "+text,
+		map[string]string{
+			"convID": activity.IncomingMessage().ConvID.String(),
+			"sender": valid.SenderUsername,
+		})
+}
+
+func (l *listener) FSActivity(activity keybase1.FSNotification) {
+	var verb string
+	switch activity.NotificationType {
+	case keybase1.FSNotificationType_FILE_CREATED:
+		verb = "created"
+	case keybase1.FSNotificationType_FILE_MODIFIED:
+		verb = "modified"
+	case keybase1.FSNotificationType_FILE_DELETED:
+		verb = "deleted"
+	case keybase1.FSNotificationType_FILE_RENAMED:
+		verb = "renamed"
+	default:
+		return
+	}
+	l.stream.Publish(EventFileOp,
+		"File "+verb+": "+activity.Filename,
+		map[string]string{"filename": activity.Filename})
+}
+
+func (l *listener) TrackingChanged(uid keybase1.UID, username libkb.NormalizedUsername) {
+	l.stream.Publish(EventFollow,
+		"Follow status changed for "+username.String(),
+		map[string]string{"username": username.String()})
+}
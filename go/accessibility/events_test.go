@@ -0,0 +1,51 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package accessibility
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamSince(t *testing.T) {
+	tc := libkb.SetupTest(t, "accessibility", 1)
+	defer tc.Cleanup()
+
+	s := NewStream(tc.G)
+	events, next := s.Since(0)
+	require.Len(t, events, 0)
+	require.EqualValues(t, 0, next)
+
+	s.Publish(EventChatMessage, "alice says: hi", nil)
+	s.Publish(EventFileOp, "File created: notes.txt", nil)
+
+	events, next = s.Since(0)
+	require.Len(t, events, 2)
+	require.EqualValues(t, 2, next)
+	require.Equal(t, EventChatMessage, events[0].Type)
+
+	// Polling from the returned cursor only yields new events.
+	events, _ = s.Since(next)
+	require.Len(t, events, 0)
+	s.Publish(EventFollow, "Follow status changed for t_bob", nil)
+	events, next = s.Since(next)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 3, next)
+}
+
+func TestStreamRingBuffer(t *testing.T) {
+	tc := libkb.SetupTest(t, "accessibility", 1)
+	defer tc.Cleanup()
+
+	s := NewStream(tc.G)
+	for i := 0; i < streamBufferSize+10; i++ {
+		s.Publish(EventFileOp, "File modified: big.log", nil)
+	}
+	events, _ := s.Since(0)
+	require.Len(t, events, streamBufferSize)
+	// The oldest events fell off the ring.
+	require.EqualValues(t, 11, events[0].Seq)
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package accessibility
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/keybase/client/go/kbhttp/manager"
+	"github.com/keybase/client/go/libkb"
+)
+
+// Srv serves the accessibility event stream over the local HTTP server.
+// Frontends poll `GET /a11y?since=N` and speak whatever comes back.
+type Srv struct {
+	libkb.Contextified
+	httpSrv *manager.Srv
+	stream  *Stream
+}
+
+func NewSrv(g *libkb.GlobalContext, httpSrv *manager.Srv, stream *Stream) *Srv {
+	s := &Srv{
+		Contextified: libkb.NewContextified(g),
+		httpSrv:      httpSrv,
+		stream:       stream,
+	}
+	s.httpSrv.HandleFunc("a11y", manager.SrvTokenModeDefault, s.serve)
+	return s
+}
+
+func (s *Srv) serve(w http.ResponseWriter, req *http.Request) {
+	var since uint64
+	if raw := req.URL.Query().Get("since"); len(raw) > 0 {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			s.G().GetLog().Debug("Accessibility.Srv: bad since: %s", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+	events, next := s.stream.Since(since)
+	res := struct {
+		Events []Event `json:"events"`
+		Next   uint64  `json:"next"`
+	}{Events: events, Next: next}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.G().GetLog().Debug("Accessibility.Srv: encode: %s", err)
+	}
+}
+
+// ServiceInit hooks the stream into the notify router and exposes it over
+// the local HTTP server.
+func ServiceInit(g *libkb.GlobalContext, httpSrv *manager.Srv) *Stream {
+	stream := NewStream(g)
+	NewSrv(g, httpSrv, stream)
+	if g.NotifyRouter != nil {
+		g.NotifyRouter.AddListener(&listener{stream: stream})
+	}
+	return stream
+}
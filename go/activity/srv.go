@@ -0,0 +1,108 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/kbhttp/manager"
+	"github.com/keybase/client/go/libkb"
+)
+
+// Srv serves the merged timeline over the local HTTP server, so the GUI
+// can page through it with a single authenticated endpoint.
+type Srv struct {
+	libkb.Contextified
+	httpSrv  *manager.Srv
+	timeline *Timeline
+}
+
+func NewSrv(g *libkb.GlobalContext, httpSrv *manager.Srv, timeline *Timeline) *Srv {
+	s := &Srv{
+		Contextified: libkb.NewContextified(g),
+		httpSrv:      httpSrv,
+		timeline:     timeline,
+	}
+	s.httpSrv.HandleFunc("activity", manager.SrvTokenModeDefault, s.serve)
+	return s
+}
+
+func (s *Srv) makeError(w http.ResponseWriter, code int, msg string,
+	args ...interface{}) {
+	s.G().GetLog().Debug("Activity.Srv: error code: %d msg %s", code,
+		fmt.Sprintf(msg, args...))
+	w.WriteHeader(code)
+}
+
+func (s *Srv) serve(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if req.URL.Query().Get("action") == "markread" {
+		at := time.Now()
+		if raw := req.URL.Query().Get("at"); len(raw) > 0 {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				s.makeError(w, http.StatusBadRequest, "bad at: %s", err)
+				return
+			}
+			at = parsed
+		}
+		if err := s.timeline.MarkRead(ctx, at); err != nil {
+			s.makeError(w, http.StatusInternalServerError, "markread: %s", err)
+		}
+		return
+	}
+	var arg ListArg
+	if raw := req.URL.Query().Get("before"); len(raw) > 0 {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.makeError(w, http.StatusBadRequest, "bad before: %s", err)
+			return
+		}
+		arg.Before = parsed
+	}
+	if raw := req.URL.Query().Get("limit"); len(raw) > 0 {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			s.makeError(w, http.StatusBadRequest, "bad limit: %s", err)
+			return
+		}
+		arg.Limit = limit
+	}
+	if raw := req.URL.Query().Get("types"); len(raw) > 0 {
+		for _, typ := range strings.Split(raw, ",") {
+			arg.Types = append(arg.Types, ItemType(typ))
+		}
+	}
+	page, err := s.timeline.List(ctx, arg)
+	if err != nil {
+		s.makeError(w, http.StatusInternalServerError, "list: %s", err)
+		return
+	}
+	unread, err := s.timeline.UnreadCount(ctx)
+	if err != nil {
+		s.makeError(w, http.StatusInternalServerError, "unread: %s", err)
+		return
+	}
+	res := struct {
+		Page
+		Unread int `json:"unread"`
+	}{Page: page, Unread: unread}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.G().GetLog().Debug("Activity.Srv: encode: %s", err)
+	}
+}
+
+// ServiceInit creates the timeline and its HTTP endpoint. The returned
+// Timeline is what subsystems register their sources on.
+func ServiceInit(g *libkb.GlobalContext, httpSrv *manager.Srv) *Timeline {
+	timeline := NewTimeline(g)
+	NewSrv(g, httpSrv, timeline)
+	return timeline
+}
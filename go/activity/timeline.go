@@ -0,0 +1,200 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package activity aggregates recent events from the different subsystems
+// (chat mentions, KBFS edits, git pushes, follows, wallet payments) into a
+// single timeline the GUI home screen can page through, instead of
+// assembling it from several RPCs. Subsystems register a Source at service
+// startup; events from subsystems that haven't registered simply don't
+// appear.
+package activity
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+// ItemType tags which subsystem produced a timeline item.
+type ItemType string
+
+const (
+	ItemTypeChatMention   ItemType = "chat-mention"
+	ItemTypeKBFSEdit      ItemType = "kbfs-edit"
+	ItemTypeGitPush       ItemType = "git-push"
+	ItemTypeFollow        ItemType = "follow"
+	ItemTypeWalletPayment ItemType = "wallet-payment"
+)
+
+// Item is one event on the timeline.
+type Item struct {
+	ID    string    `json:"id"`
+	Type  ItemType  `json:"type"`
+	Time  time.Time `json:"time"`
+	Actor string    `json:"actor"`
+	Title string    `json:"title"`
+	Body  string    `json:"body,omitempty"`
+}
+
+// Source supplies recent items for one subsystem. Fetch returns up to limit
+// items no newer than before, newest first.
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context, before time.Time, limit int) ([]Item, error)
+}
+
+// ListArg selects a page of the timeline. A zero Before means "now". An
+// empty Types list means all types.
+type ListArg struct {
+	Before time.Time
+	Limit  int
+	Types  []ItemType
+}
+
+// Page is one page of timeline results. Next is the cursor for the
+// following page; it is zero when the timeline is exhausted.
+type Page struct {
+	Items []Item    `json:"items"`
+	Next  time.Time `json:"next,omitempty"`
+}
+
+type readState struct {
+	LastRead time.Time `json:"lastRead"`
+}
+
+const defaultPageSize = 20
+const maxPageSize = 100
+
+// Timeline merges items from registered sources and tracks how far the
+// user has read. Read state persists in the local DB, so it survives
+// restarts but is per-device.
+type Timeline struct {
+	libkb.Contextified
+	sync.Mutex
+	sources []Source
+}
+
+func NewTimeline(g *libkb.GlobalContext) *Timeline {
+	return &Timeline{Contextified: libkb.NewContextified(g)}
+}
+
+// RegisterSource adds a subsystem's source. Sources registered later
+// override earlier ones with the same name.
+func (t *Timeline) RegisterSource(source Source) {
+	t.Lock()
+	defer t.Unlock()
+	for index, existing := range t.sources {
+		if existing.Name() == source.Name() {
+			t.sources[index] = source
+			return
+		}
+	}
+	t.sources = append(t.sources, source)
+}
+
+func (t *Timeline) getSources() []Source {
+	t.Lock()
+	defer t.Unlock()
+	return append([]Source{}, t.sources...)
+}
+
+func typeAllowed(typ ItemType, types []ItemType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, allowed := range types {
+		if typ == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns one page of the merged timeline, newest first. A source
+// failing only drops its items from the page; the others still show.
+func (t *Timeline) List(ctx context.Context, arg ListArg) (res Page, err error) {
+	before := arg.Before
+	if before.IsZero() {
+		before = time.Now()
+	}
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	var items []Item
+	for _, source := range t.getSources() {
+		sourceItems, err := source.Fetch(ctx, before, limit)
+		if err != nil {
+			t.G().Log.CDebugf(ctx, "Timeline: source %s failed: %s", source.Name(), err)
+			continue
+		}
+		for _, item := range sourceItems {
+			if !item.Time.Before(before) {
+				continue
+			}
+			if typeAllowed(item.Type, arg.Types) {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Time.Equal(items[j].Time) {
+			return items[i].Time.After(items[j].Time)
+		}
+		return items[i].ID < items[j].ID
+	})
+	if len(items) > limit {
+		items = items[:limit]
+		res.Next = items[len(items)-1].Time
+	}
+	res.Items = items
+	return res, nil
+}
+
+func (t *Timeline) dbKey() libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBActivityTimeline,
+		Key: t.G().Env.GetUID().String(),
+	}
+}
+
+// MarkRead records that the user has seen everything up to at.
+func (t *Timeline) MarkRead(ctx context.Context, at time.Time) error {
+	return t.G().GetKVStore().PutObj(t.dbKey(), nil, readState{LastRead: at})
+}
+
+// LastRead returns the read watermark, zero if the user has never marked.
+func (t *Timeline) LastRead(ctx context.Context) (res time.Time, err error) {
+	var state readState
+	found, err := t.G().GetKVStore().GetInto(&state, t.dbKey())
+	if err != nil || !found {
+		return res, err
+	}
+	return state.LastRead, nil
+}
+
+// UnreadCount counts items newer than the read watermark, capped at
+// maxPageSize since the GUI only shows "99+" anyway.
+func (t *Timeline) UnreadCount(ctx context.Context) (int, error) {
+	lastRead, err := t.LastRead(ctx)
+	if err != nil {
+		return 0, err
+	}
+	page, err := t.List(ctx, ListArg{Limit: maxPageSize})
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for _, item := range page.Items {
+		if item.Time.After(lastRead) {
+			count++
+		}
+	}
+	return count, nil
+}
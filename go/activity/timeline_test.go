@@ -0,0 +1,107 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type fakeSource struct {
+	name  string
+	items []Item
+	err   error
+}
+
+func (s fakeSource) Name() string { return s.name }
+
+func (s fakeSource) Fetch(ctx context.Context, before time.Time, limit int) ([]Item, error) {
+	return s.items, s.err
+}
+
+func fakeItem(typ ItemType, id string, at time.Time) Item {
+	return Item{ID: id, Type: typ, Time: at, Title: id}
+}
+
+func TestTimelineMerge(t *testing.T) {
+	tc := libkb.SetupTest(t, "activity", 1)
+	defer tc.Cleanup()
+
+	now := time.Now()
+	timeline := NewTimeline(tc.G)
+	timeline.RegisterSource(fakeSource{name: "chat", items: []Item{
+		fakeItem(ItemTypeChatMention, "m1", now.Add(-1*time.Minute)),
+		fakeItem(ItemTypeChatMention, "m2", now.Add(-3*time.Minute)),
+	}})
+	timeline.RegisterSource(fakeSource{name: "git", items: []Item{
+		fakeItem(ItemTypeGitPush, "p1", now.Add(-2*time.Minute)),
+	}})
+	timeline.RegisterSource(fakeSource{name: "broken", err: errors.New("nope")})
+
+	page, err := timeline.List(context.TODO(), ListArg{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 3)
+	require.Equal(t, "m1", page.Items[0].ID)
+	require.Equal(t, "p1", page.Items[1].ID)
+	require.Equal(t, "m2", page.Items[2].ID)
+	require.True(t, page.Next.IsZero())
+
+	// type filter
+	page, err = timeline.List(context.TODO(), ListArg{Types: []ItemType{ItemTypeGitPush}})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "p1", page.Items[0].ID)
+}
+
+func TestTimelinePagination(t *testing.T) {
+	tc := libkb.SetupTest(t, "activity", 1)
+	defer tc.Cleanup()
+
+	now := time.Now()
+	var items []Item
+	for i := 0; i < 5; i++ {
+		items = append(items, fakeItem(ItemTypeFollow, fmt.Sprintf("f%d", i),
+			now.Add(-time.Duration(i)*time.Minute)))
+	}
+	timeline := NewTimeline(tc.G)
+	timeline.RegisterSource(fakeSource{name: "follows", items: items})
+
+	page, err := timeline.List(context.TODO(), ListArg{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "f0", page.Items[0].ID)
+	require.False(t, page.Next.IsZero())
+
+	page, err = timeline.List(context.TODO(), ListArg{Limit: 2, Before: page.Next})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "f2", page.Items[0].ID)
+}
+
+func TestTimelineReadState(t *testing.T) {
+	tc := libkb.SetupTest(t, "activity", 1)
+	defer tc.Cleanup()
+
+	now := time.Now()
+	timeline := NewTimeline(tc.G)
+	timeline.RegisterSource(fakeSource{name: "wallet", items: []Item{
+		fakeItem(ItemTypeWalletPayment, "w1", now.Add(-1*time.Minute)),
+		fakeItem(ItemTypeWalletPayment, "w2", now.Add(-5*time.Minute)),
+	}})
+
+	unread, err := timeline.UnreadCount(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, unread)
+
+	require.NoError(t, timeline.MarkRead(context.TODO(), now.Add(-2*time.Minute)))
+	unread, err = timeline.UnreadCount(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 1, unread)
+}
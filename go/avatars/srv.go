@@ -82,7 +82,7 @@ func (s *Srv) loadFromURL(raw string) (io.ReadCloser, error) {
 	}
 	switch parsed.Scheme {
 	case "http", "https":
-		avatarTransport.Proxy = libkb.MakeProxy(s.G().GetEnv())
+		avatarTransport.Proxy = libkb.MakeProxyForCircuit(s.G().GetEnv(), libkb.TorCircuitAvatars)
 		xprt := libkb.NewInstrumentedRoundTripper(s.G(), func(*http.Request) string { return "AvatarSrv" },
 			libkb.NewClosingRoundTripper(avatarTransport))
 		cli := &http.Client{
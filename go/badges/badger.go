@@ -4,6 +4,10 @@
 package badges
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"golang.org/x/net/context"
 
 	"github.com/keybase/client/go/gregor"
@@ -37,6 +41,9 @@ type Badger struct {
 	iboxVersSource InboxVersionSource
 	notifyCh       chan keybase1.BadgeState
 	shutdownCh     chan struct{}
+
+	snoozeMu sync.Mutex
+	snoozes  map[BadgeCategory]time.Time
 }
 
 func NewBadger(g *libkb.GlobalContext) *Badger {
@@ -46,6 +53,7 @@ func NewBadger(g *libkb.GlobalContext) *Badger {
 		iboxVersSource: nullInboxVersionSource{},
 		notifyCh:       make(chan keybase1.BadgeState, 1000),
 		shutdownCh:     make(chan struct{}),
+		snoozes:        make(map[BadgeCategory]time.Time),
 	}
 	go b.notifyLoop()
 	g.PushShutdownHook(func(mctx libkb.MetaContext) error {
@@ -123,12 +131,114 @@ func (b *Badger) Clear(ctx context.Context) {
 	}
 }
 
+// BadgeCategory names a slice of the badge state that can be snoozed.
+type BadgeCategory string
+
+const (
+	BadgeCategoryChat    BadgeCategory = "chat"
+	BadgeCategoryFollows BadgeCategory = "follows"
+	BadgeCategoryTeams   BadgeCategory = "teams"
+	BadgeCategoryKBFS    BadgeCategory = "kbfs"
+	BadgeCategoryDevices BadgeCategory = "devices"
+	BadgeCategoryWallet  BadgeCategory = "wallet"
+)
+
+var badgeCategories = []BadgeCategory{
+	BadgeCategoryChat,
+	BadgeCategoryFollows,
+	BadgeCategoryTeams,
+	BadgeCategoryKBFS,
+	BadgeCategoryDevices,
+	BadgeCategoryWallet,
+}
+
+func validBadgeCategory(category BadgeCategory) bool {
+	for _, c := range badgeCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// SnoozeCategory suppresses a category of badges until the given time
+// and pushes an updated state, so frontends drop the badge right away.
+// An expired snooze stops filtering on the next state push; nothing
+// wakes the frontends up at exactly the expiry time.
+func (b *Badger) SnoozeCategory(ctx context.Context, category BadgeCategory, until time.Time) error {
+	if !validBadgeCategory(category) {
+		return fmt.Errorf("unknown badge category %q (have: %v)", category, badgeCategories)
+	}
+	b.snoozeMu.Lock()
+	b.snoozes[category] = until
+	b.snoozeMu.Unlock()
+	return b.Send(ctx)
+}
+
+// UnsnoozeCategory clears a snooze and pushes an updated state.
+func (b *Badger) UnsnoozeCategory(ctx context.Context, category BadgeCategory) error {
+	if !validBadgeCategory(category) {
+		return fmt.Errorf("unknown badge category %q (have: %v)", category, badgeCategories)
+	}
+	b.snoozeMu.Lock()
+	delete(b.snoozes, category)
+	b.snoozeMu.Unlock()
+	return b.Send(ctx)
+}
+
+// Snoozes returns the currently active snoozes, pruning expired ones.
+func (b *Badger) Snoozes() map[BadgeCategory]time.Time {
+	now := b.G().Clock().Now()
+	b.snoozeMu.Lock()
+	defer b.snoozeMu.Unlock()
+	ret := make(map[BadgeCategory]time.Time)
+	for category, until := range b.snoozes {
+		if until.Before(now) {
+			delete(b.snoozes, category)
+			continue
+		}
+		ret[category] = until
+	}
+	return ret
+}
+
+// applySnoozes is the policy step between the raw badge state and what
+// frontends see: snoozed categories are zeroed out of the export.
+func (b *Badger) applySnoozes(state keybase1.BadgeState) keybase1.BadgeState {
+	for category := range b.Snoozes() {
+		switch category {
+		case BadgeCategoryChat:
+			state.Conversations = nil
+			state.SmallTeamBadgeCount = 0
+			state.BigTeamBadgeCount = 0
+		case BadgeCategoryFollows:
+			state.NewFollowers = 0
+		case BadgeCategoryTeams:
+			state.NewTeamAccessRequestCount = 0
+			state.NewTeams = nil
+			state.DeletedTeams = nil
+			state.TeamsWithResetUsers = nil
+		case BadgeCategoryKBFS:
+			state.NewTlfs = 0
+			state.RekeysNeeded = 0
+			state.NewGitRepoGlobalUniqueIDs = nil
+		case BadgeCategoryDevices:
+			state.NewDevices = nil
+			state.RevokedDevices = nil
+		case BadgeCategoryWallet:
+			state.UnreadWalletAccounts = nil
+		}
+	}
+	return state
+}
+
 // Send the badgestate to electron
 func (b *Badger) Send(ctx context.Context) error {
 	state, err := b.badgeState.Export(ctx)
 	if err != nil {
 		return err
 	}
+	state = b.applySnoozes(state)
 	b.log(ctx, state)
 	b.notifyCh <- state
 	return nil
@@ -0,0 +1,250 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package calendar implements a lightweight shared calendar for teams. The
+// event list is a single JSON file in the team's KBFS folder, so it is
+// team-keyed and end-to-end encrypted with no extra server support. All
+// access goes through SimpleFS.
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// calendarDir is the directory inside the team folder that holds calendar
+// data.
+const calendarDir = ".kbcalendar"
+const eventsFile = "events.json"
+
+const readBufSize = 1024 * 1024
+
+// Event is a single calendar event.
+type Event struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Creator     string    `json:"creator,omitempty"`
+}
+
+type eventsRecord struct {
+	Events []Event `json:"events"`
+}
+
+// Store reads and writes a team's calendar. There is no service-side
+// reminder loop yet; callers poll Upcoming for events due soon.
+type Store struct {
+	libkb.Contextified
+	cli  keybase1.SimpleFSClient
+	team string
+}
+
+func NewStore(g *libkb.GlobalContext, cli keybase1.SimpleFSClient, team string) *Store {
+	return &Store{
+		Contextified: libkb.NewContextified(g),
+		cli:          cli,
+		team:         team,
+	}
+}
+
+func (s *Store) eventsPath() keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s/%s", s.team, calendarDir, eventsFile))
+}
+
+func (s *Store) dirPath() keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s", s.team, calendarDir))
+}
+
+func (s *Store) readEvents(ctx context.Context) (res []Event, err error) {
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  s.eventsPath(),
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	}); err != nil {
+		// no calendar yet
+		return nil, nil
+	}
+	var dat []byte
+	var offset int64
+	for {
+		content, err := s.cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   readBufSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(content.Data) == 0 {
+			break
+		}
+		dat = append(dat, content.Data...)
+		offset += int64(len(content.Data))
+	}
+	var rec eventsRecord
+	if err := json.Unmarshal(dat, &rec); err != nil {
+		return nil, err
+	}
+	return rec.Events, nil
+}
+
+func (s *Store) writeEvents(ctx context.Context, events []Event) error {
+	sortEvents(events)
+	dat, err := json.MarshalIndent(eventsRecord{Events: events}, "", "  ")
+	if err != nil {
+		return err
+	}
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  s.dirPath(),
+		Flags: keybase1.OpenFlags_DIRECTORY,
+	}); err != nil {
+		return err
+	}
+	s.cli.SimpleFSClose(ctx, opid)
+	if opid, err = s.cli.SimpleFSMakeOpid(ctx); err != nil {
+		return err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  s.eventsPath(),
+		Flags: keybase1.OpenFlags_WRITE | keybase1.OpenFlags_REPLACE,
+	}); err != nil {
+		return err
+	}
+	return s.cli.SimpleFSWrite(ctx, keybase1.SimpleFSWriteArg{
+		OpID:    opid,
+		Offset:  0,
+		Content: dat,
+	})
+}
+
+func sortEvents(events []Event) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartTime.Equal(events[j].StartTime) {
+			return events[i].StartTime.Before(events[j].StartTime)
+		}
+		return events[i].ID < events[j].ID
+	})
+}
+
+// List returns the team's events sorted by start time.
+func (s *Store) List(ctx context.Context) ([]Event, error) {
+	events, err := s.readEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+// Add stores a new event. An empty ID is filled with a random one.
+func (s *Store) Add(ctx context.Context, event Event) (res Event, err error) {
+	if len(event.Title) == 0 {
+		return res, fmt.Errorf("event title required")
+	}
+	if event.StartTime.IsZero() {
+		return res, fmt.Errorf("event start time required")
+	}
+	if len(event.ID) == 0 {
+		id, err := libkb.RandHexString("evt", 8)
+		if err != nil {
+			return res, err
+		}
+		event.ID = id
+	}
+	events, err := s.readEvents(ctx)
+	if err != nil {
+		return res, err
+	}
+	for _, existing := range events {
+		if existing.ID == event.ID {
+			return res, fmt.Errorf("event already exists: %s", event.ID)
+		}
+	}
+	events = append(events, event)
+	if err := s.writeEvents(ctx, events); err != nil {
+		return res, err
+	}
+	return event, nil
+}
+
+// Remove deletes the event with the given ID.
+func (s *Store) Remove(ctx context.Context, id string) error {
+	events, err := s.readEvents(ctx)
+	if err != nil {
+		return err
+	}
+	var kept []Event
+	for _, event := range events {
+		if event.ID == id {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if len(kept) == len(events) {
+		return fmt.Errorf("no such event: %s", id)
+	}
+	return s.writeEvents(ctx, kept)
+}
+
+// Import merges the given events into the calendar, skipping IDs that are
+// already present, and returns the number added.
+func (s *Store) Import(ctx context.Context, imported []Event) (added int, err error) {
+	events, err := s.readEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+	existing := make(map[string]bool, len(events))
+	for _, event := range events {
+		existing[event.ID] = true
+	}
+	for _, event := range imported {
+		if len(event.ID) == 0 || existing[event.ID] {
+			continue
+		}
+		existing[event.ID] = true
+		events = append(events, event)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.writeEvents(ctx, events)
+}
+
+// Upcoming returns events starting within the given window of now, soonest
+// first. This is the hook for reminders.
+func Upcoming(events []Event, now time.Time, window time.Duration) (res []Event) {
+	for _, event := range events {
+		if event.StartTime.Before(now) {
+			continue
+		}
+		if event.StartTime.After(now.Add(window)) {
+			continue
+		}
+		res = append(res, event)
+	}
+	sortEvents(res)
+	return res
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestICalRoundTrip(t *testing.T) {
+	start := time.Date(2026, 9, 1, 15, 0, 0, 0, time.UTC)
+	events := []Event{
+		{
+			ID:        "evt1",
+			Title:     "Standup; daily",
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+			Location:  "big room, HQ",
+		},
+		{
+			ID:          "evt2",
+			Title:       "Launch",
+			StartTime:   start.Add(24 * time.Hour),
+			Description: "line one\nline two",
+		},
+	}
+	parsed, err := ImportICal(ExportICal(events))
+	require.NoError(t, err)
+	require.Equal(t, events, parsed)
+}
+
+func TestICalImportErrors(t *testing.T) {
+	_, err := ImportICal("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VCALENDAR\r\n")
+	require.Error(t, err)
+	_, err = ImportICal("BEGIN:VEVENT\r\nSUMMARY:no start\r\nEND:VEVENT\r\n")
+	require.Error(t, err)
+	// unfolded continuation lines and property params are handled
+	parsed, err := ImportICal("BEGIN:VEVENT\r\nSUMMARY:long\r\n  title\r\nDTSTART;TZID=UTC:20260901T150000Z\r\nEND:VEVENT\r\n")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(parsed))
+	require.Equal(t, "long title", parsed[0].Title)
+}
+
+func TestUpcoming(t *testing.T) {
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{ID: "past", Title: "past", StartTime: now.Add(-time.Hour)},
+		{ID: "soon", Title: "soon", StartTime: now.Add(30 * time.Minute)},
+		{ID: "sooner", Title: "sooner", StartTime: now.Add(10 * time.Minute)},
+		{ID: "later", Title: "later", StartTime: now.Add(3 * time.Hour)},
+	}
+	res := Upcoming(events, now, time.Hour)
+	require.Equal(t, 2, len(res))
+	require.Equal(t, "sooner", res[0].ID)
+	require.Equal(t, "soon", res[1].ID)
+}
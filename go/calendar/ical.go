@@ -0,0 +1,129 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const icalTimeFormat = "20060102T150405Z"
+
+func icalEscape(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\n", "\\n")
+	return r.Replace(s)
+}
+
+func icalUnescape(s string) string {
+	r := strings.NewReplacer("\\\\", "\\", "\\;", ";", "\\,", ",", "\\n", "\n", "\\N", "\n")
+	return r.Replace(s)
+}
+
+// ExportICal renders the given events as an iCalendar (RFC 5545) document,
+// covering the small subset of the format the calendar uses.
+func ExportICal(events []Event) string {
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VCALENDAR\r\n")
+	sb.WriteString("VERSION:2.0\r\n")
+	sb.WriteString("PRODID:-//Keybase//Team Calendar//EN\r\n")
+	for _, event := range events {
+		sb.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&sb, "UID:%s\r\n", icalEscape(event.ID))
+		fmt.Fprintf(&sb, "SUMMARY:%s\r\n", icalEscape(event.Title))
+		fmt.Fprintf(&sb, "DTSTART:%s\r\n", event.StartTime.UTC().Format(icalTimeFormat))
+		if !event.EndTime.IsZero() {
+			fmt.Fprintf(&sb, "DTEND:%s\r\n", event.EndTime.UTC().Format(icalTimeFormat))
+		}
+		if len(event.Location) > 0 {
+			fmt.Fprintf(&sb, "LOCATION:%s\r\n", icalEscape(event.Location))
+		}
+		if len(event.Description) > 0 {
+			fmt.Fprintf(&sb, "DESCRIPTION:%s\r\n", icalEscape(event.Description))
+		}
+		sb.WriteString("END:VEVENT\r\n")
+	}
+	sb.WriteString("END:VCALENDAR\r\n")
+	return sb.String()
+}
+
+// ImportICal parses an iCalendar document into events. Properties the
+// calendar doesn't track are ignored; VEVENT blocks without a summary or
+// start time are rejected.
+func ImportICal(dat string) (res []Event, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(dat))
+	var cur *Event
+	var lines []string
+	// unfold continuation lines first
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			if cur != nil {
+				return nil, fmt.Errorf("nested VEVENT")
+			}
+			cur = new(Event)
+			continue
+		case line == "END:VEVENT":
+			if cur == nil {
+				return nil, fmt.Errorf("END:VEVENT without BEGIN")
+			}
+			if len(cur.Title) == 0 || cur.StartTime.IsZero() {
+				return nil, fmt.Errorf("VEVENT missing SUMMARY or DTSTART")
+			}
+			res = append(res, *cur)
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		toks := strings.SplitN(line, ":", 2)
+		if len(toks) != 2 {
+			continue
+		}
+		// drop property parameters, e.g. DTSTART;TZID=...
+		name := strings.SplitN(toks[0], ";", 2)[0]
+		value := toks[1]
+		switch strings.ToUpper(name) {
+		case "UID":
+			cur.ID = icalUnescape(value)
+		case "SUMMARY":
+			cur.Title = icalUnescape(value)
+		case "LOCATION":
+			cur.Location = icalUnescape(value)
+		case "DESCRIPTION":
+			cur.Description = icalUnescape(value)
+		case "DTSTART":
+			if cur.StartTime, err = parseICalTime(value); err != nil {
+				return nil, err
+			}
+		case "DTEND":
+			if cur.EndTime, err = parseICalTime(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+	return res, nil
+}
+
+func parseICalTime(value string) (time.Time, error) {
+	for _, format := range []string{icalTimeFormat, "20060102T150405", "20060102"} {
+		if t, err := time.Parse(format, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unparseable iCal time: %s", value)
+}
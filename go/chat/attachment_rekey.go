@@ -0,0 +1,295 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/chat/attachments"
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// AttachmentRekeyStatus is a snapshot of a running or finished
+// attachment rekey job, for the attachment-rekey-status debug script.
+type AttachmentRekeyStatus struct {
+	Running         bool      `json:"running"`
+	StartedAt       time.Time `json:"startedAt,omitempty"`
+	ConvsScanned    int       `json:"convsScanned"`
+	MessagesScanned int       `json:"messagesScanned"`
+	Reuploaded      int       `json:"reuploaded"`
+	OldDeleted      int       `json:"oldDeleted"`
+	BytesDownloaded int64     `json:"bytesDownloaded"`
+	LastError       string    `json:"lastError,omitempty"`
+}
+
+// bwThrottle caps download bandwidth by sleeping in the progress
+// callback until the cumulative byte count falls back under the limit.
+type bwThrottle struct {
+	start          time.Time
+	bytesPerSecond int64
+}
+
+func (b *bwThrottle) wait(bytesComplete int64) {
+	if b.bytesPerSecond <= 0 {
+		return
+	}
+	expected := time.Duration(
+		float64(bytesComplete) / float64(b.bytesPerSecond) * float64(time.Second))
+	if elapsed := time.Since(b.start); expected > elapsed {
+		time.Sleep(expected - elapsed)
+	}
+}
+
+// AttachmentRekeyer is an optional background job for team owners that
+// deals with attachments posted before a team key rotation. Chat
+// attachment assets are encrypted under per-asset keys carried in the
+// message body, so after a rotation old attachments stay readable with
+// old message keys forever. The job walks recent attachment messages in
+// teams the user owns, downloads each asset, re-posts it through the
+// regular attachment pipeline (encrypting everything under the current
+// key), and deletes the old message, which marks its assets for
+// deletion on the server. Only one job runs at a time, and downloads
+// can be bandwidth limited.
+type AttachmentRekeyer struct {
+	globals.Contextified
+	utils.DebugLabeler
+
+	ri func() chat1.RemoteInterface
+
+	sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	status  AttachmentRekeyStatus
+}
+
+func NewAttachmentRekeyer(g *globals.Context, ri func() chat1.RemoteInterface) *AttachmentRekeyer {
+	return &AttachmentRekeyer{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "AttachmentRekeyer", false),
+		ri:           ri,
+	}
+}
+
+// Start kicks off a background job covering attachment messages younger
+// than age. bwKBps, if positive, caps download bandwidth in KB/s. It
+// errors if a job is already running.
+func (r *AttachmentRekeyer) Start(ctx context.Context, uid gregor1.UID,
+	age time.Duration, bwKBps int) (err error) {
+	defer r.Trace(ctx, &err, "Start")()
+	r.Lock()
+	defer r.Unlock()
+	if r.running {
+		return errors.New("attachment rekey job already running")
+	}
+	bgctx := globals.ChatCtx(context.Background(), r.G(),
+		keybase1.TLFIdentifyBehavior_CHAT_CLI, nil, nil)
+	bgctx, r.cancel = context.WithCancel(bgctx)
+	r.running = true
+	r.status = AttachmentRekeyStatus{
+		Running:   true,
+		StartedAt: time.Now(),
+	}
+	go r.run(bgctx, uid, age, bwKBps)
+	return nil
+}
+
+// Stop cancels a running job, if any.
+func (r *AttachmentRekeyer) Stop(ctx context.Context) {
+	defer r.Trace(ctx, nil, "Stop")()
+	r.Lock()
+	defer r.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// Status returns a snapshot of the current (or most recent) job.
+func (r *AttachmentRekeyer) Status() AttachmentRekeyStatus {
+	r.Lock()
+	defer r.Unlock()
+	status := r.status
+	status.Running = r.running
+	return status
+}
+
+func (r *AttachmentRekeyer) update(f func(status *AttachmentRekeyStatus)) {
+	r.Lock()
+	defer r.Unlock()
+	f(&r.status)
+}
+
+func (r *AttachmentRekeyer) setErr(err error) {
+	r.update(func(status *AttachmentRekeyStatus) {
+		status.LastError = err.Error()
+	})
+}
+
+func (r *AttachmentRekeyer) run(ctx context.Context, uid gregor1.UID,
+	age time.Duration, bwKBps int) {
+	defer func() {
+		r.Lock()
+		r.running = false
+		if r.cancel != nil {
+			r.cancel()
+			r.cancel = nil
+		}
+		r.Unlock()
+	}()
+
+	topicType := chat1.TopicType_CHAT
+	lbox, _, err := r.G().InboxSource.Read(ctx, uid, types.ConversationLocalizerBlocking,
+		types.InboxSourceDataSourceAll, nil, &chat1.GetInboxLocalQuery{
+			TopicType: &topicType,
+			MemberStatus: []chat1.ConversationMemberStatus{
+				chat1.ConversationMemberStatus_ACTIVE,
+			},
+		})
+	if err != nil {
+		r.Debug(ctx, "run: failed to read inbox: %s", err)
+		r.setErr(err)
+		return
+	}
+	roleMap, err := r.G().ExternalG().GetTeamRoleMapManager().Get(
+		libkb.NewMetaContext(ctx, r.G().ExternalG()), false)
+	if err != nil {
+		r.Debug(ctx, "run: failed to get team role map: %s", err)
+		r.setErr(err)
+		return
+	}
+
+	after := gregor1.ToTime(time.Now().Add(-age))
+	throttle := &bwThrottle{
+		start:          time.Now(),
+		bytesPerSecond: int64(bwKBps) * 1024,
+	}
+	for _, conv := range lbox.Convs {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if conv.GetMembersType() != chat1.ConversationMembersType_TEAM {
+			continue
+		}
+		teamID, err := keybase1.TeamIDFromString(conv.Info.Triple.Tlfid.String())
+		if err != nil {
+			r.Debug(ctx, "run: skipping conv %s: %s", conv.GetConvID(), err)
+			continue
+		}
+		// This is a job for team owners only; admins and below don't
+		// get to rewrite other people's attachment messages.
+		if pair, ok := roleMap.Teams[teamID]; !ok ||
+			pair.Role != keybase1.TeamRole_OWNER {
+			continue
+		}
+		if err := r.rekeyConv(ctx, uid, conv, after, throttle); err != nil {
+			r.Debug(ctx, "run: conv %s: %s", conv.GetConvID(), err)
+			r.setErr(err)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		r.update(func(status *AttachmentRekeyStatus) {
+			status.ConvsScanned++
+		})
+	}
+}
+
+func (r *AttachmentRekeyer) rekeyConv(ctx context.Context, uid gregor1.UID,
+	conv chat1.ConversationLocal, after gregor1.Time, throttle *bwThrottle) error {
+	convID := conv.GetConvID()
+	thread, err := r.G().ConvSource.Pull(ctx, convID, uid,
+		chat1.GetThreadReason_GENERAL, nil, &chat1.GetThreadQuery{
+			MessageTypes: []chat1.MessageType{chat1.MessageType_ATTACHMENT},
+			After:        &after,
+		}, nil)
+	if err != nil {
+		return err
+	}
+	for _, msg := range thread.Messages {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if !msg.IsValid() {
+			continue
+		}
+		r.update(func(status *AttachmentRekeyStatus) {
+			status.MessagesScanned++
+		})
+		body := msg.Valid().MessageBody
+		if typ, err := body.MessageType(); err != nil ||
+			typ != chat1.MessageType_ATTACHMENT || !body.Attachment().Uploaded {
+			continue
+		}
+		if err := r.rekeyMessage(ctx, uid, conv, msg.GetMessageID(),
+			body.Attachment(), throttle); err != nil {
+			r.Debug(ctx, "rekeyConv: msg %d: %s", msg.GetMessageID(), err)
+			r.setErr(err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+func (r *AttachmentRekeyer) rekeyMessage(ctx context.Context, uid gregor1.UID,
+	conv chat1.ConversationLocal, msgID chat1.MessageID,
+	attachment chat1.MessageAttachment, throttle *bwThrottle) error {
+	convID := conv.GetConvID()
+	parentDir := filepath.Join(
+		r.G().ExternalG().Env.GetCacheDir(), "attachmentrekey")
+	filePath, sink, err := attachments.SinkFromFilename(
+		ctx, r.G(), uid, convID, msgID, parentDir, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(filePath) }()
+	progress := func(bytesComplete, bytesTotal int64) {
+		throttle.wait(bytesComplete)
+	}
+	if err := attachments.Download(
+		ctx, r.G(), uid, convID, msgID, sink, false, progress, r.ri); err != nil {
+		return fmt.Errorf("downloading: %s", err)
+	}
+	r.update(func(status *AttachmentRekeyStatus) {
+		status.BytesDownloaded += attachment.Object.Size
+	})
+
+	// Re-post through the full attachment pipeline, so the new assets
+	// get fresh keys under the team's current key generation.
+	sender := NewBlockingSender(r.G(), NewBoxer(r.G()), r.ri)
+	if _, _, err := attachments.NewSender(r.G()).PostFileAttachment(
+		ctx, sender, uid, convID, conv.Info.TlfName, conv.Info.Visibility, nil,
+		filePath, attachment.Object.Title, attachment.Metadata, 0,
+		nil, nil); err != nil {
+		return fmt.Errorf("re-posting: %s", err)
+	}
+	r.update(func(status *AttachmentRekeyStatus) {
+		status.Reuploaded++
+	})
+
+	// Deleting the old message marks its assets for deletion on the
+	// server through the regular deletion path.
+	if err := r.G().ChatHelper.DeleteMsg(
+		ctx, convID, conv.Info.TlfName, msgID); err != nil {
+		return fmt.Errorf("deleting old message: %s", err)
+	}
+	r.update(func(status *AttachmentRekeyStatus) {
+		status.OldDeleted++
+	})
+	return nil
+}
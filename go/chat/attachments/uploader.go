@@ -310,6 +310,16 @@ func (u *Uploader) saveTask(ctx context.Context, uid gregor1.UID, convID chat1.C
 func (u *Uploader) Register(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
 	outboxID chat1.OutboxID, title, filename string, metadata []byte, callerPreview *chat1.MakePreviewRes) (res types.AttachmentUploaderResultCb, err error) {
 	defer u.Trace(ctx, &err, "Register(%s)", outboxID)()
+	// Run any locally configured content scanner before we commit to the
+	// upload.
+	bypassed, err := libkb.ScanOutgoingContent(u.G().Env, filename)
+	if err != nil {
+		u.Debug(ctx, "Register: content scan blocked %s: %s", filename, err)
+		return nil, err
+	}
+	if bypassed {
+		u.G().Log.CWarningf(ctx, "content scan bypassed for attachment %s", filename)
+	}
 	// Write down the task information
 	if err := u.saveTask(ctx, uid, convID, outboxID, title, filename, metadata, callerPreview); err != nil {
 		return nil, err
@@ -0,0 +1,174 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/clockwork"
+)
+
+const savedMessagesName = "__saved_messages"
+
+// SavedMessage identifies a single bookmarked message.
+type SavedMessage struct {
+	ConvID  chat1.ConvIDStr
+	MsgID   chat1.MessageID
+	SavedAt gregor1.Time
+}
+
+type savedMessagesRecord struct {
+	Saved []SavedMessage
+}
+
+// SavedMessageView is a bookmark decorated for display: the conversation
+// name and a snippet of the saved message's body.
+type SavedMessageView struct {
+	SavedMessage
+	ConvName string
+	Snippet  string
+}
+
+// Bookmarks implements a per-user saved messages store. Bookmarks live in a
+// conversation backed store so they are end-to-end encrypted and sync across
+// the user's devices. There is no RPC surface for this yet; in-process
+// callers use it directly.
+type Bookmarks struct {
+	globals.Contextified
+	utils.DebugLabeler
+
+	storage types.UserConversationBackedStorage
+	clock   clockwork.Clock
+}
+
+func NewBookmarks(g *globals.Context, storage types.UserConversationBackedStorage) *Bookmarks {
+	return &Bookmarks{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "Bookmarks", false),
+		storage:      storage,
+		clock:        clockwork.NewRealClock(),
+	}
+}
+
+func (b *Bookmarks) SetClock(clock clockwork.Clock) {
+	b.clock = clock
+}
+
+func (b *Bookmarks) getRecord(ctx context.Context, uid gregor1.UID) (res savedMessagesRecord, err error) {
+	if _, err := b.storage.Get(ctx, uid, savedMessagesName, &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// Save bookmarks the given message. Saving an already bookmarked message is
+// a no-op.
+func (b *Bookmarks) Save(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	msgID chat1.MessageID) (err error) {
+	defer b.Trace(ctx, &err, "Save(%s,%d)", convID, msgID)()
+	rec, err := b.getRecord(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, sm := range rec.Saved {
+		if sm.ConvID == convID.ConvIDStr() && sm.MsgID == msgID {
+			return nil
+		}
+	}
+	// newest first
+	rec.Saved = append([]SavedMessage{{
+		ConvID:  convID.ConvIDStr(),
+		MsgID:   msgID,
+		SavedAt: gregor1.ToTime(b.clock.Now()),
+	}}, rec.Saved...)
+	return b.storage.Put(ctx, uid, savedMessagesName, rec)
+}
+
+// Unsave removes the bookmark on the given message, if any.
+func (b *Bookmarks) Unsave(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	msgID chat1.MessageID) (err error) {
+	defer b.Trace(ctx, &err, "Unsave(%s,%d)", convID, msgID)()
+	rec, err := b.getRecord(ctx, uid)
+	if err != nil {
+		return err
+	}
+	var saved []SavedMessage
+	for _, sm := range rec.Saved {
+		if sm.ConvID == convID.ConvIDStr() && sm.MsgID == msgID {
+			continue
+		}
+		saved = append(saved, sm)
+	}
+	if len(saved) == len(rec.Saved) {
+		b.Debug(ctx, "Unsave: not found, doing nothing")
+		return nil
+	}
+	rec.Saved = saved
+	return b.storage.Put(ctx, uid, savedMessagesName, rec)
+}
+
+func (b *Bookmarks) IsSaved(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	msgID chat1.MessageID) (res bool, err error) {
+	defer b.Trace(ctx, &err, "IsSaved(%s,%d)", convID, msgID)()
+	rec, err := b.getRecord(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	for _, sm := range rec.Saved {
+		if sm.ConvID == convID.ConvIDStr() && sm.MsgID == msgID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// List returns the user's bookmarks, newest first.
+func (b *Bookmarks) List(ctx context.Context, uid gregor1.UID) (res []SavedMessage, err error) {
+	defer b.Trace(ctx, &err, "List")()
+	rec, err := b.getRecord(ctx, uid)
+	if err != nil {
+		return res, err
+	}
+	return rec.Saved, nil
+}
+
+// ListWithSnippets returns the user's bookmarks decorated with the
+// conversation name and a snippet of the message body. Bookmarks whose
+// conversation or message can no longer be loaded are returned undecorated
+// rather than dropped, so the caller can still offer to remove them.
+func (b *Bookmarks) ListWithSnippets(ctx context.Context, uid gregor1.UID,
+	ri func() chat1.RemoteInterface) (res []SavedMessageView, err error) {
+	defer b.Trace(ctx, &err, "ListWithSnippets")()
+	saved, err := b.List(ctx, uid)
+	if err != nil {
+		return res, err
+	}
+	for _, sm := range saved {
+		view := SavedMessageView{SavedMessage: sm}
+		convID, err := chat1.MakeConvID(string(sm.ConvID))
+		if err != nil {
+			b.Debug(ctx, "ListWithSnippets: invalid conv ID %s: %s", sm.ConvID, err)
+			res = append(res, view)
+			continue
+		}
+		conv, err := utils.GetVerifiedConv(ctx, b.G(), uid, convID, types.InboxSourceDataSourceAll)
+		if err != nil {
+			b.Debug(ctx, "ListWithSnippets: failed to load conv %s: %s", sm.ConvID, err)
+			res = append(res, view)
+			continue
+		}
+		view.ConvName = conv.Info.TlfName
+		msg, err := b.G().ConvSource.GetMessage(ctx, convID, uid, sm.MsgID, nil, ri, true)
+		if err != nil {
+			b.Debug(ctx, "ListWithSnippets: failed to load msg %d in %s: %s", sm.MsgID, sm.ConvID, err)
+			res = append(res, view)
+			continue
+		}
+		view.Snippet, _ = utils.GetMsgSnippetBody(ctx, b.G(), uid, convID, msg)
+		res = append(res, view)
+	}
+	return res, nil
+}
@@ -0,0 +1,54 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	tc := externalstest.SetupTest(t, "bookmarks", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	convID1 := chat1.ConversationID([]byte{0, 1})
+	convID2 := chat1.ConversationID([]byte{0, 2})
+	bookmarks := NewBookmarks(g, newMemConvFolderStorage())
+	clock := clockwork.NewFakeClock()
+	bookmarks.SetClock(clock)
+	ctx := context.TODO()
+
+	saved, err := bookmarks.IsSaved(ctx, uid, convID1, 3)
+	require.NoError(t, err)
+	require.False(t, saved)
+	require.NoError(t, bookmarks.Save(ctx, uid, convID1, 3))
+	clock.Advance(time.Minute)
+	require.NoError(t, bookmarks.Save(ctx, uid, convID2, 5))
+	saved, err = bookmarks.IsSaved(ctx, uid, convID1, 3)
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	// Re-saving doesn't duplicate, and listing is newest first.
+	require.NoError(t, bookmarks.Save(ctx, uid, convID1, 3))
+	res, err := bookmarks.List(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+	require.Equal(t, convID2.ConvIDStr(), res[0].ConvID)
+	require.Equal(t, chat1.MessageID(5), res[0].MsgID)
+	require.Equal(t, convID1.ConvIDStr(), res[1].ConvID)
+
+	require.NoError(t, bookmarks.Unsave(ctx, uid, convID1, 3))
+	require.NoError(t, bookmarks.Unsave(ctx, uid, convID1, 3))
+	res, err = bookmarks.List(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, convID2.ConvIDStr(), res[0].ConvID)
+}
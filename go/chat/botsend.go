@@ -0,0 +1,132 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/storage"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+// BotSendTimeoutError is returned by BotSender.SendWithAck when the server
+// ack does not arrive in time. The send may still land; retrying with the
+// same idempotency key is safe and will not duplicate the message.
+type BotSendTimeoutError struct {
+	IdempotencyKey string
+	Timeout        time.Duration
+}
+
+func (e BotSendTimeoutError) Error() string {
+	return fmt.Sprintf("bot send timed out after %v waiting for ack (key: %s)", e.Timeout,
+		e.IdempotencyKey)
+}
+
+type botSendIntent struct {
+	ConvID   chat1.ConvIDStr
+	OutboxID chat1.OutboxID
+	MsgID    chat1.MessageID
+	Acked    bool
+	Ctime    gregor1.Time
+}
+
+// BotSender wraps a blocking sender with the delivery guarantees bot
+// frameworks need: each send carries a caller-supplied idempotency key, the
+// intent is persisted locally before anything hits the network, and the call
+// returns only once the server has acked the message (or with a typed
+// timeout). Because the outbox ID is derived from the idempotency key,
+// retries after a crash or timeout resend the same message identity instead
+// of producing a duplicate.
+type BotSender struct {
+	globals.Contextified
+	utils.DebugLabeler
+
+	sender types.Sender
+}
+
+func NewBotSender(g *globals.Context, sender types.Sender) *BotSender {
+	return &BotSender{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "BotSender", false),
+		sender:       sender,
+	}
+}
+
+func (s *BotSender) dbKey(uid gregor1.UID, idempotencyKey string) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatBotSendIntents,
+		Key: fmt.Sprintf("%s:%s", uid, idempotencyKey),
+	}
+}
+
+func (s *BotSender) outboxID(uid gregor1.UID, convID chat1.ConversationID,
+	idempotencyKey string) chat1.OutboxID {
+	seed := fmt.Sprintf("botsend:%s:%s:%s", uid, convID, idempotencyKey)
+	return storage.DeriveOutboxID([]byte(seed))
+}
+
+// SendWithAck posts msg to convID and blocks until the server acks it,
+// returning the acked message ID. If a previous call with the same
+// idempotency key already got an ack, the recorded message ID is returned
+// without sending anything.
+func (s *BotSender) SendWithAck(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	msg chat1.MessagePlaintext, idempotencyKey string, timeout time.Duration) (msgID chat1.MessageID, err error) {
+	defer s.Trace(ctx, &err, "SendWithAck(%s,%s)", convID, idempotencyKey)()
+	if len(idempotencyKey) == 0 {
+		return 0, fmt.Errorf("empty idempotency key")
+	}
+	key := s.dbKey(uid, idempotencyKey)
+	var intent botSendIntent
+	found, ferr := s.G().GetKVStore().GetInto(&intent, key)
+	if ferr != nil {
+		return 0, ferr
+	}
+	if found {
+		if intent.ConvID != convID.ConvIDStr() {
+			return 0, fmt.Errorf("idempotency key already used in conv %s", intent.ConvID)
+		}
+		if intent.Acked {
+			s.Debug(ctx, "SendWithAck: already acked as msgID: %d, skipping send", intent.MsgID)
+			return intent.MsgID, nil
+		}
+		s.Debug(ctx, "SendWithAck: retrying unacked intent with outbox ID: %s", intent.OutboxID)
+	} else {
+		intent = botSendIntent{
+			ConvID:   convID.ConvIDStr(),
+			OutboxID: s.outboxID(uid, convID, idempotencyKey),
+			Ctime:    gregor1.ToTime(time.Now()),
+		}
+		// persist the intent before we touch the network so a crash mid-send
+		// is retryable under the same message identity
+		if err := s.G().GetKVStore().PutObj(key, nil, intent); err != nil {
+			return 0, err
+		}
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	obid := intent.OutboxID
+	_, boxed, err := s.sender.Send(ctx, convID, msg, 0, &obid, nil, nil)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, BotSendTimeoutError{
+				IdempotencyKey: idempotencyKey,
+				Timeout:        timeout,
+			}
+		}
+		return 0, err
+	}
+	intent.Acked = true
+	intent.MsgID = boxed.GetMessageID()
+	if err := s.G().GetKVStore().PutObj(key, nil, intent); err != nil {
+		s.Debug(ctx, "SendWithAck: failed to record ack: %s", err)
+	}
+	return intent.MsgID, nil
+}
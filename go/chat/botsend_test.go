@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/stretchr/testify/require"
+)
+
+type botSendTestSender struct {
+	sends     int
+	outboxIDs []chat1.OutboxID
+	nextMsgID chat1.MessageID
+	err       error
+}
+
+func (s *botSendTestSender) Send(ctx context.Context, convID chat1.ConversationID,
+	msg chat1.MessagePlaintext, clientPrev chat1.MessageID, outboxID *chat1.OutboxID,
+	sendOpts *chat1.SenderSendOptions, prepareOpts *chat1.SenderPrepareOptions) (chat1.OutboxID, *chat1.MessageBoxed, error) {
+	s.sends++
+	s.outboxIDs = append(s.outboxIDs, *outboxID)
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return *outboxID, &chat1.MessageBoxed{
+		ServerHeader: &chat1.MessageServerHeader{
+			MessageID: s.nextMsgID,
+		},
+	}, nil
+}
+
+func (s *botSendTestSender) Prepare(ctx context.Context, msg chat1.MessagePlaintext,
+	membersType chat1.ConversationMembersType, conv *chat1.ConversationLocal,
+	opts *chat1.SenderPrepareOptions) (types.SenderPrepareResult, error) {
+	return types.SenderPrepareResult{}, nil
+}
+
+func TestBotSenderIdempotency(t *testing.T) {
+	tc := externalstest.SetupTest(t, "botsend", 0)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	convID := chat1.ConversationID([]byte{0, 1})
+	sender := &botSendTestSender{nextMsgID: 5}
+	botSender := NewBotSender(g, sender)
+	ctx := context.TODO()
+	msg := chat1.MessagePlaintext{
+		MessageBody: chat1.NewMessageBodyWithText(chat1.MessageText{Body: "hi"}),
+	}
+
+	_, err := botSender.SendWithAck(ctx, uid, convID, msg, "", time.Minute)
+	require.Error(t, err)
+
+	msgID, err := botSender.SendWithAck(ctx, uid, convID, msg, "key1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, chat1.MessageID(5), msgID)
+	require.Equal(t, 1, sender.sends)
+
+	// Acked key short circuits without another send.
+	sender.nextMsgID = 6
+	msgID, err = botSender.SendWithAck(ctx, uid, convID, msg, "key1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, chat1.MessageID(5), msgID)
+	require.Equal(t, 1, sender.sends)
+
+	// A failed send leaves the intent retryable under the same outbox ID.
+	sender.err = context.DeadlineExceeded
+	_, err = botSender.SendWithAck(ctx, uid, convID, msg, "key2", time.Minute)
+	require.Error(t, err)
+	sender.err = nil
+	msgID, err = botSender.SendWithAck(ctx, uid, convID, msg, "key2", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, chat1.MessageID(6), msgID)
+	require.Equal(t, 3, sender.sends)
+	require.Equal(t, sender.outboxIDs[1], sender.outboxIDs[2])
+	require.NotEqual(t, sender.outboxIDs[0], sender.outboxIDs[1])
+
+	// Reusing a key in a different conversation is rejected.
+	otherConv := chat1.ConversationID([]byte{0, 2})
+	_, err = botSender.SendWithAck(ctx, uid, otherConv, msg, "key1", time.Minute)
+	require.Error(t, err)
+}
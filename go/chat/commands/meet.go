@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/teams"
+)
+
+// meetDefaultBaseURL is where rooms are hosted when the caller
+// doesn't paste a URL of their own.
+const meetDefaultBaseURL = "https://meet.jit.si"
+
+// meetTokenLifetime is how long a minted join token stays valid.
+const meetTokenLifetime = 15 * time.Minute
+
+// MeetingInvite is the provider-agnostic payload a /meet command
+// posts into the conversation. It rides inside an ordinary text
+// message, so any client can at least render the join URL; clients
+// that understand invites can show a richer join button.
+type MeetingInvite struct {
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	Room     string `json:"room,omitempty"`
+	// Token is a short-lived join token signed with the hosting
+	// team's chat key; empty for conversations not backed by a team
+	// or for externally hosted meetings.
+	Token string `json:"token,omitempty"`
+}
+
+type Meet struct {
+	*baseCommand
+}
+
+func NewMeet(g *globals.Context) *Meet {
+	return &Meet{
+		baseCommand: newBaseCommand(g, "meet", "[url]",
+			"Start a meeting and post an invite", true),
+	}
+}
+
+func (h *Meet) Execute(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	tlfName, text string, replyTo *chat1.MessageID) (err error) {
+	defer h.Trace(ctx, &err, "Meet")()
+	if !h.Match(ctx, text) {
+		return ErrInvalidCommand
+	}
+	_, url, err := h.commandAndMessage(text)
+	if err != nil {
+		return err
+	}
+
+	invite := MeetingInvite{
+		Provider: "custom",
+		URL:      url,
+	}
+	if invite.URL == "" {
+		// No URL pasted; host a fresh room on the default bridge,
+		// and mint a join token if a team is backing this
+		// conversation.
+		room, err := libkb.RandHexString("kb", 8)
+		if err != nil {
+			return err
+		}
+		invite.Provider = "jitsi"
+		invite.Room = room
+		invite.URL = fmt.Sprintf("%s/%s", meetDefaultBaseURL, room)
+		username := h.G().ExternalG().Env.GetUsername().String()
+		token, err := mintMeetingToken(ctx, h.G(), tlfName, room, username)
+		if err != nil {
+			h.Debug(ctx, "Execute: not attaching join token: %s", err)
+		} else {
+			invite.Token = token
+			invite.URL += "#kbtoken=" + token
+		}
+	}
+
+	body := fmt.Sprintf("📞 Meeting started: %s", invite.URL)
+	if invite.Token != "" {
+		body += fmt.Sprintf(" (join token expires in %s)", meetTokenLifetime)
+	}
+	_, err = h.G().ChatHelper.SendTextByIDNonblock(ctx, convID, tlfName, body, nil, replyTo)
+	return err
+}
+
+// Export is a convenience for clients that want the structured
+// payload rather than the rendered text.
+func (m MeetingInvite) Export() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// mintMeetingToken signs (room, username, expiry) with the chat
+// application key of the team named tlfName. It fails for
+// conversations that aren't backed by a named team, in which case
+// the invite simply goes out without a token.
+func mintMeetingToken(ctx context.Context, g *globals.Context, tlfName, room,
+	username string) (string, error) {
+	team, err := teams.Load(ctx, g.ExternalG(), keybase1.LoadTeamArg{
+		Name: tlfName,
+	})
+	if err != nil {
+		return "", err
+	}
+	key, err := team.ChatKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	expiry := g.ExternalG().Clock().Now().Add(meetTokenLifetime).Unix()
+	payload := meetingTokenPayload(room, username, expiry)
+	mac := hmac.New(sha256.New, key.Key[:])
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16]), nil
+}
+
+func meetingTokenPayload(room, username string, expiry int64) string {
+	return fmt.Sprintf("%s|%s|%d", room, username, expiry)
+}
+
+// VerifyMeetingToken checks a join token against the named team's
+// chat key, returning the room and username it was minted for.
+func VerifyMeetingToken(ctx context.Context, g *globals.Context, tlfName,
+	token string) (room, username string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("malformed meeting token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	givenMAC, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", err
+	}
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 3 {
+		return "", "", fmt.Errorf("malformed meeting token payload")
+	}
+	room, username = fields[0], fields[1]
+	expiry, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return "", "", err
+	}
+	if g.ExternalG().Clock().Now().Unix() > expiry {
+		return "", "", fmt.Errorf("meeting token expired")
+	}
+
+	team, err := teams.Load(ctx, g.ExternalG(), keybase1.LoadTeamArg{
+		Name: tlfName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	key, err := team.ChatKey(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	mac := hmac.New(sha256.New, key.Key[:])
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(givenMAC, mac.Sum(nil)[:16]) {
+		return "", "", fmt.Errorf("invalid meeting token signature")
+	}
+	return room, username, nil
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+type MentionGroup struct {
+	*baseCommand
+}
+
+func NewMentionGroup(g *globals.Context) *MentionGroup {
+	return &MentionGroup{
+		baseCommand: newBaseCommand(g, "mentiongroup", "add|remove|list [handle] [user1,user2]",
+			"Manage team mention groups like @oncall (admins only)", false),
+	}
+}
+
+func (h *MentionGroup) Execute(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	tlfName, text string, replyTo *chat1.MessageID) (err error) {
+	defer h.Trace(ctx, &err, "MentionGroup")()
+	if !h.Match(ctx, text) {
+		return ErrInvalidCommand
+	}
+	defer func() {
+		if err != nil {
+			_ = h.getChatUI().ChatCommandStatus(ctx, convID,
+				fmt.Sprintf("Failed to update mention groups: %v", err),
+				chat1.UICommandStatusDisplayTyp_ERROR, nil)
+		}
+	}()
+	toks, err := h.tokenize(text, 2)
+	if err != nil {
+		return err
+	}
+	switch toks[1] {
+	case "add":
+		if len(toks) != 4 {
+			return ErrInvalidArguments
+		}
+		handle := strings.TrimPrefix(toks[2], "@")
+		members := strings.Split(toks[3], ",")
+		for i, member := range members {
+			members[i] = strings.TrimPrefix(strings.TrimSpace(member), "@")
+		}
+		if err = h.G().MentionGroupSource.Set(ctx, uid, convID, handle, members); err != nil {
+			return err
+		}
+		return h.getChatUI().ChatCommandStatus(ctx, convID,
+			fmt.Sprintf("Mention group @%s set with %d member(s)", handle, len(members)),
+			chat1.UICommandStatusDisplayTyp_STATUS, nil)
+	case "remove":
+		if len(toks) != 3 {
+			return ErrInvalidArguments
+		}
+		handle := strings.TrimPrefix(toks[2], "@")
+		if err = h.G().MentionGroupSource.Remove(ctx, uid, convID, handle); err != nil {
+			return err
+		}
+		return h.getChatUI().ChatCommandStatus(ctx, convID,
+			fmt.Sprintf("Mention group @%s removed", handle),
+			chat1.UICommandStatusDisplayTyp_STATUS, nil)
+	case "list":
+		groups, err := h.G().MentionGroupSource.Get(ctx, uid, convID)
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return h.getChatUI().ChatCommandStatus(ctx, convID, "No mention groups defined",
+				chat1.UICommandStatusDisplayTyp_STATUS, nil)
+		}
+		handles := make([]string, 0, len(groups))
+		for handle := range groups {
+			handles = append(handles, handle)
+		}
+		sort.Strings(handles)
+		parts := make([]string, 0, len(handles))
+		for _, handle := range handles {
+			parts = append(parts, fmt.Sprintf("@%s: %s", handle, strings.Join(groups[handle], ", ")))
+		}
+		return h.getChatUI().ChatCommandStatus(ctx, convID, strings.Join(parts, " · "),
+			chat1.UICommandStatusDisplayTyp_STATUS, nil)
+	default:
+		return ErrInvalidArguments
+	}
+}
@@ -51,6 +51,8 @@ const (
 	cmdLeave
 	cmdLocation
 	cmdMe
+	cmdMeet
+	cmdMentionGroup
 	cmdMsg
 	cmdMute
 	cmdShrug
@@ -70,6 +72,8 @@ func (s *Source) allCommands() (res map[int]types.ConversationCommand) {
 	res[cmdLeave] = NewLeave(s.G())
 	res[cmdLocation] = NewLocation(s.G())
 	res[cmdMe] = NewMe(s.G())
+	res[cmdMeet] = NewMeet(s.G())
+	res[cmdMentionGroup] = NewMentionGroup(s.G())
 	res[cmdMsg] = NewMsg(s.G())
 	res[cmdMute] = NewMute(s.G())
 	res[cmdShrug] = NewShrug(s.G())
@@ -88,6 +92,7 @@ func (s *Source) makeBuiltins() {
 		cmds[cmdHeadline],
 		cmds[cmdHide],
 		cmds[cmdMe],
+		cmds[cmdMeet],
 		cmds[cmdMsg],
 		cmds[cmdMute],
 		cmds[cmdShrug],
@@ -102,12 +107,15 @@ func (s *Source) makeBuiltins() {
 	s.builtins[chat1.ConversationBuiltinCommandTyp_BIGTEAM] = append([]types.ConversationCommand{
 		cmds[cmdJoin],
 		cmds[cmdLeave],
+		cmds[cmdMentionGroup],
 	}, common...)
 	s.builtins[chat1.ConversationBuiltinCommandTyp_BIGTEAMGENERAL] = append([]types.ConversationCommand{
 		cmds[cmdJoin],
+		cmds[cmdMentionGroup],
 	}, common...)
 	s.builtins[chat1.ConversationBuiltinCommandTyp_SMALLTEAM] = append([]types.ConversationCommand{
 		cmds[cmdJoin],
+		cmds[cmdMentionGroup],
 	}, common...)
 	for _, cmds := range s.builtins {
 		sort.Slice(cmds, func(i, j int) bool {
@@ -0,0 +1,167 @@
+package chat
+
+import (
+	"context"
+	"sort"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+const convFoldersName = "__conv_folders"
+
+type convFolderEntry struct {
+	Folder   string
+	Archived bool
+}
+
+type convFoldersRecord struct {
+	Entries map[chat1.ConvIDStr]convFolderEntry
+}
+
+// ConvFolders tracks per-conversation organization state: whether the user
+// has archived a conversation, and which user-defined folder (if any) it is
+// filed under. State lives in a conversation backed store so it syncs across
+// the user's devices. GetInboxQuery is wire-defined, so archive and folder
+// filtering is applied locally with FilterInbox after the inbox query runs;
+// archived conversations stay in local storage and remain searchable.
+type ConvFolders struct {
+	globals.Contextified
+	utils.DebugLabeler
+
+	storage types.UserConversationBackedStorage
+}
+
+func NewConvFolders(g *globals.Context, storage types.UserConversationBackedStorage) *ConvFolders {
+	return &ConvFolders{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "ConvFolders", false),
+		storage:      storage,
+	}
+}
+
+func (f *ConvFolders) getRecord(ctx context.Context, uid gregor1.UID) (res convFoldersRecord, err error) {
+	found, err := f.storage.Get(ctx, uid, convFoldersName, &res)
+	if err != nil {
+		return res, err
+	}
+	if !found || res.Entries == nil {
+		res.Entries = make(map[chat1.ConvIDStr]convFolderEntry)
+	}
+	return res, nil
+}
+
+func (f *ConvFolders) putEntry(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	mod func(entry convFolderEntry) convFolderEntry) error {
+	rec, err := f.getRecord(ctx, uid)
+	if err != nil {
+		return err
+	}
+	entry := mod(rec.Entries[convID.ConvIDStr()])
+	if entry == (convFolderEntry{}) {
+		delete(rec.Entries, convID.ConvIDStr())
+	} else {
+		rec.Entries[convID.ConvIDStr()] = entry
+	}
+	return f.storage.Put(ctx, uid, convFoldersName, rec)
+}
+
+// Archive hides the given conversation from the inbox without leaving it.
+func (f *ConvFolders) Archive(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (err error) {
+	defer f.Trace(ctx, &err, "Archive(%s)", convID)()
+	return f.putEntry(ctx, uid, convID, func(entry convFolderEntry) convFolderEntry {
+		entry.Archived = true
+		return entry
+	})
+}
+
+// Unarchive restores an archived conversation to the inbox.
+func (f *ConvFolders) Unarchive(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (err error) {
+	defer f.Trace(ctx, &err, "Unarchive(%s)", convID)()
+	return f.putEntry(ctx, uid, convID, func(entry convFolderEntry) convFolderEntry {
+		entry.Archived = false
+		return entry
+	})
+}
+
+func (f *ConvFolders) IsArchived(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (res bool, err error) {
+	defer f.Trace(ctx, &err, "IsArchived(%s)", convID)()
+	rec, err := f.getRecord(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return rec.Entries[convID.ConvIDStr()].Archived, nil
+}
+
+// SetFolder files the given conversation under the named folder. An empty
+// name removes the conversation from whatever folder it is in.
+func (f *ConvFolders) SetFolder(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
+	folder string) (err error) {
+	defer f.Trace(ctx, &err, "SetFolder(%s,%s)", convID, folder)()
+	return f.putEntry(ctx, uid, convID, func(entry convFolderEntry) convFolderEntry {
+		entry.Folder = folder
+		return entry
+	})
+}
+
+func (f *ConvFolders) Folder(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (res string, err error) {
+	defer f.Trace(ctx, &err, "Folder(%s)", convID)()
+	rec, err := f.getRecord(ctx, uid)
+	if err != nil {
+		return "", err
+	}
+	return rec.Entries[convID.ConvIDStr()].Folder, nil
+}
+
+// Folders returns the user's folder names in sorted order along with the
+// conversations filed under each.
+func (f *ConvFolders) Folders(ctx context.Context, uid gregor1.UID) (names []string,
+	convs map[string][]chat1.ConvIDStr, err error) {
+	defer f.Trace(ctx, &err, "Folders")()
+	rec, err := f.getRecord(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	convs = make(map[string][]chat1.ConvIDStr)
+	for convID, entry := range rec.Entries {
+		if len(entry.Folder) == 0 {
+			continue
+		}
+		if _, ok := convs[entry.Folder]; !ok {
+			names = append(names, entry.Folder)
+		}
+		convs[entry.Folder] = append(convs[entry.Folder], convID)
+	}
+	sort.Strings(names)
+	for _, ids := range convs {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	return names, convs, nil
+}
+
+// FilterInbox applies the user's archive and folder state to an inbox query
+// result. Archived conversations are dropped unless includeArchived is set,
+// and a non-empty folder restricts the result to conversations filed under
+// that folder.
+func (f *ConvFolders) FilterInbox(ctx context.Context, uid gregor1.UID,
+	rcs []types.RemoteConversation, folder string, includeArchived bool) (res []types.RemoteConversation, err error) {
+	defer f.Trace(ctx, &err, "FilterInbox")()
+	rec, err := f.getRecord(ctx, uid)
+	if err != nil {
+		return res, err
+	}
+	for _, rc := range rcs {
+		entry := rec.Entries[rc.ConvIDStr]
+		if entry.Archived && !includeArchived {
+			continue
+		}
+		if len(folder) > 0 && entry.Folder != folder {
+			continue
+		}
+		res = append(res, rc)
+	}
+	return res, nil
+}
@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/stretchr/testify/require"
+)
+
+type memConvFolderStorage struct {
+	sync.Mutex
+	storage map[string]string
+}
+
+func newMemConvFolderStorage() *memConvFolderStorage {
+	return &memConvFolderStorage{
+		storage: make(map[string]string),
+	}
+}
+
+func (s *memConvFolderStorage) Get(ctx context.Context, uid gregor1.UID, name string, res interface{}) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	dat, ok := s.storage[name]
+	if !ok {
+		return false, nil
+	}
+	err := json.Unmarshal([]byte(dat), res)
+	return true, err
+}
+
+func (s *memConvFolderStorage) Put(ctx context.Context, uid gregor1.UID, name string, src interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+	dat, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	s.storage[name] = string(dat)
+	return nil
+}
+
+func TestConvFolders(t *testing.T) {
+	tc := externalstest.SetupTest(t, "conv_folders", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	convID1 := chat1.ConversationID([]byte{0, 1})
+	convID2 := chat1.ConversationID([]byte{0, 2})
+	folders := NewConvFolders(g, newMemConvFolderStorage())
+	ctx := context.TODO()
+
+	archived, err := folders.IsArchived(ctx, uid, convID1)
+	require.NoError(t, err)
+	require.False(t, archived)
+	require.NoError(t, folders.Archive(ctx, uid, convID1))
+	archived, err = folders.IsArchived(ctx, uid, convID1)
+	require.NoError(t, err)
+	require.True(t, archived)
+
+	require.NoError(t, folders.SetFolder(ctx, uid, convID2, "work"))
+	folder, err := folders.Folder(ctx, uid, convID2)
+	require.NoError(t, err)
+	require.Equal(t, "work", folder)
+	names, convs, err := folders.Folders(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, []string{"work"}, names)
+	require.Equal(t, []chat1.ConvIDStr{convID2.ConvIDStr()}, convs["work"])
+
+	rcs := []types.RemoteConversation{
+		{ConvIDStr: convID1.ConvIDStr()},
+		{ConvIDStr: convID2.ConvIDStr()},
+	}
+	res, err := folders.FilterInbox(ctx, uid, rcs, "", false)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, convID2.ConvIDStr(), res[0].ConvIDStr)
+	res, err = folders.FilterInbox(ctx, uid, rcs, "", true)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+	res, err = folders.FilterInbox(ctx, uid, rcs, "work", true)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, convID2.ConvIDStr(), res[0].ConvIDStr)
+
+	// Clearing all state on a conversation drops its entry entirely.
+	require.NoError(t, folders.Unarchive(ctx, uid, convID1))
+	require.NoError(t, folders.SetFolder(ctx, uid, convID2, ""))
+	names, _, err = folders.Folders(ctx, uid)
+	require.NoError(t, err)
+	require.Zero(t, len(names))
+	res, err = folders.FilterInbox(ctx, uid, rcs, "", false)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+}
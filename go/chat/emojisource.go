@@ -88,6 +88,7 @@ type DevConvEmojiSource struct {
 
 	aliasLookupLock sync.Mutex
 	aliasLookup     map[string]chat1.Emoji
+	usageLock       sync.Mutex
 	ri              func() chat1.RemoteInterface
 	encryptedDB     *encrypteddb.EncryptedDB
 
@@ -133,6 +134,47 @@ func (s *DevConvEmojiSource) dbKey(uid gregor1.UID) libkb.DbKey {
 	}
 }
 
+func (s *DevConvEmojiSource) usageDbKey(uid gregor1.UID) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatEmojiUsage,
+		Key: uid.String(),
+	}
+}
+
+// recordUsage bumps the use count for each custom emoji alias harvested
+// from a sent message; the totals back the Usage statistics.
+func (s *DevConvEmojiSource) recordUsage(ctx context.Context, uid gregor1.UID, aliases []string) {
+	if len(aliases) == 0 {
+		return
+	}
+	s.usageLock.Lock()
+	defer s.usageLock.Unlock()
+	usage := make(map[string]int)
+	if _, err := s.encryptedDB.Get(ctx, s.usageDbKey(uid), &usage); err != nil {
+		s.Debug(ctx, "recordUsage: failed to read usage: %s", err)
+		return
+	}
+	for _, alias := range aliases {
+		usage[s.stripAlias(alias)]++
+	}
+	if err := s.encryptedDB.Put(ctx, s.usageDbKey(uid), usage); err != nil {
+		s.Debug(ctx, "recordUsage: failed to write usage: %s", err)
+	}
+}
+
+// Usage returns how many times each custom emoji has appeared in messages
+// sent by this user.
+func (s *DevConvEmojiSource) Usage(ctx context.Context, uid gregor1.UID) (res map[string]int, err error) {
+	defer s.Trace(ctx, &err, "Usage")()
+	s.usageLock.Lock()
+	defer s.usageLock.Unlock()
+	res = make(map[string]int)
+	if _, err = s.encryptedDB.Get(ctx, s.usageDbKey(uid), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *DevConvEmojiSource) getAliasLookup(ctx context.Context, uid gregor1.UID) (res map[string]chat1.Emoji, err error) {
 	s.aliasLookupLock.Lock()
 	defer s.aliasLookupLock.Unlock()
@@ -902,6 +944,13 @@ func (s *DevConvEmojiSource) Harvest(ctx context.Context, body string, uid grego
 			res = append(res, resEmoji)
 		}
 	}
+	if mode == types.EmojiHarvestModeNormal && len(res) > 0 {
+		aliases := make([]string, 0, len(res))
+		for _, emoji := range res {
+			aliases = append(aliases, emoji.Alias)
+		}
+		s.recordUsage(ctx, uid, aliases)
+	}
 	return res, nil
 }
 
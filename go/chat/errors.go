@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/keybase/client/go/chat/types"
@@ -313,6 +314,30 @@ func (e RestrictedBotChannelError) IsImmediateFail() (chat1.OutboxErrorType, boo
 
 //=============================================================================
 
+// UnknownMentionError is returned from the send path when an @mention
+// doesn't resolve to a user or team but is within typo distance of a
+// conversation member, so frontends can offer "did you mean" corrections
+// instead of silently sending a broken mention.
+type UnknownMentionError struct {
+	Name       string
+	Candidates []string
+}
+
+func NewUnknownMentionError(name string, candidates []string) UnknownMentionError {
+	return UnknownMentionError{Name: name, Candidates: candidates}
+}
+
+func (e UnknownMentionError) Error() string {
+	return fmt.Sprintf("unknown mention @%s; did you mean @%s?", e.Name,
+		strings.Join(e.Candidates, ", @"))
+}
+
+func (e UnknownMentionError) IsImmediateFail() (chat1.OutboxErrorType, bool) {
+	return chat1.OutboxErrorType_MISC, true
+}
+
+//=============================================================================
+
 type BoxingCryptKeysError struct {
 	Err error
 }
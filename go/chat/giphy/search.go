@@ -111,7 +111,7 @@ func httpClient(mctx libkb.MetaContext, host string) *http.Client {
 	xprt.TLSClientConfig = tlsConfig
 
 	env := mctx.G().Env
-	xprt.Proxy = libkb.MakeProxy(env)
+	xprt.Proxy = libkb.MakeProxyForCircuit(env, libkb.TorCircuitChatAttachments)
 
 	return &http.Client{
 		Transport: libkb.NewInstrumentedRoundTripper(mctx.G(),
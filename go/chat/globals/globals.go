@@ -47,7 +47,9 @@ type ChatContext struct {
 	Badger               *badges.Badger                   // app badging
 	ParticipantsSource   types.ParticipantSource          // get team participants
 	EmojiSource          types.EmojiSource                // emoji support
+	MentionGroupSource   types.MentionGroupSource         // team-defined @mention groups
 	EphemeralTracker     types.EphemeralTracker           // tracking of ephemeral msg caches
+	MessageRequests      types.MessageRequestManager      // quarantine first messages from strangers
 }
 
 func (c *ChatContext) Describe() string {
@@ -85,7 +87,9 @@ func (c *ChatContext) Describe() string {
   Badger: %v,
   ParticipantSource %v,
   EmojiSource: %v
+  MentionGroupSource: %v
   EphemeralTracker: %v
+  MessageRequests: %v
 }`,
 		c.CtxFactory != nil,
 		c.InboxSource != nil,
@@ -120,7 +124,9 @@ func (c *ChatContext) Describe() string {
 		c.Badger != nil,
 		c.ParticipantsSource != nil,
 		c.EmojiSource != nil,
+		c.MentionGroupSource != nil,
 		c.EphemeralTracker != nil,
+		c.MessageRequests != nil,
 	)
 }
 
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -25,16 +26,18 @@ type Helper struct {
 	globals.Contextified
 	utils.DebugLabeler
 
-	ri func() chat1.RemoteInterface
+	ri                func() chat1.RemoteInterface
+	attachmentRekeyer *AttachmentRekeyer
 }
 
 var _ (libkb.ChatHelper) = (*Helper)(nil)
 
 func NewHelper(g *globals.Context, ri func() chat1.RemoteInterface) *Helper {
 	return &Helper{
-		Contextified: globals.NewContextified(g),
-		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "Helper", false),
-		ri:           ri,
+		Contextified:      globals.NewContextified(g),
+		DebugLabeler:      utils.NewDebugLabeler(g.ExternalG(), "Helper", false),
+		ri:                ri,
+		attachmentRekeyer: NewAttachmentRekeyer(g, ri),
 	}
 }
 
@@ -318,6 +321,171 @@ func (h *Helper) JourneycardDebugState(ctx context.Context, uid gregor1.UID, tea
 	return j.DebugState(ctx, uid, teamID)
 }
 
+func (h *Helper) ListMessageRequests(ctx context.Context, uid gregor1.UID) (
+	[]chat1.ConversationLocal, error) {
+	if h.G().MessageRequests == nil {
+		return nil, fmt.Errorf("could not get MessageRequestManager")
+	}
+	return h.G().MessageRequests.ListPending(ctx, uid)
+}
+
+func (h *Helper) AcceptMessageRequest(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) error {
+	if h.G().MessageRequests == nil {
+		return fmt.Errorf("could not get MessageRequestManager")
+	}
+	return h.G().MessageRequests.Accept(ctx, uid, convID)
+}
+
+// MarkAllAsRead marks every conversation with unread messages as read,
+// pushing the read pointers to the server so every device converges on
+// the same read state. It returns the number of conversations marked.
+func (h *Helper) MarkAllAsRead(ctx context.Context, uid gregor1.UID) (marked int, err error) {
+	defer h.Trace(ctx, &err, "MarkAllAsRead")()
+	ibox, err := h.G().InboxSource.ReadUnverified(
+		ctx, uid, types.InboxSourceDataSourceAll, &chat1.GetInboxQuery{
+			MemberStatus: []chat1.ConversationMemberStatus{
+				chat1.ConversationMemberStatus_ACTIVE,
+				chat1.ConversationMemberStatus_PREVIEW,
+			},
+		})
+	if err != nil {
+		return 0, err
+	}
+	for _, conv := range ibox.ConvsUnverified {
+		ri := conv.Conv.ReaderInfo
+		if ri == nil || ri.ReadMsgid >= ri.MaxMsgid {
+			continue
+		}
+		if err := h.G().InboxSource.MarkAsRead(
+			ctx, conv.GetConvID(), uid, nil); err != nil {
+			return marked, err
+		}
+		marked++
+	}
+	return marked, nil
+}
+
+func (h *Helper) PutChatSavedSearch(ctx context.Context, name, query string,
+	unreadOnly, teamsOnly, hasAttachment bool) error {
+	return utils.NewSavedSearches(h.G()).Put(ctx, utils.SavedSearch{
+		Name:          name,
+		Query:         query,
+		UnreadOnly:    unreadOnly,
+		TeamsOnly:     teamsOnly,
+		HasAttachment: hasAttachment,
+	})
+}
+
+func (h *Helper) RemoveChatSavedSearch(ctx context.Context, name string) error {
+	return utils.NewSavedSearches(h.G()).Remove(ctx, name)
+}
+
+func (h *Helper) ListChatSavedSearches(ctx context.Context) (string, error) {
+	searches, err := utils.NewSavedSearches(h.G()).List(ctx)
+	if err != nil {
+		return "", err
+	}
+	out, err := json.MarshalIndent(searches, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// RunChatSavedSearch evaluates a saved search against the inbox: the
+// structured filters become an inbox query, the rest are applied to the
+// unverified conversations, and only the matches get localized.
+func (h *Helper) RunChatSavedSearch(ctx context.Context, uid gregor1.UID,
+	name string) (res []chat1.ConversationLocal, err error) {
+	defer h.Trace(ctx, &err, "RunChatSavedSearch")()
+	search, err := utils.NewSavedSearches(h.G()).Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	ibox, err := h.G().InboxSource.ReadUnverified(
+		ctx, uid, types.InboxSourceDataSourceAll, search.InboxQuery())
+	if err != nil {
+		return nil, err
+	}
+	var convIDs []chat1.ConversationID
+	for _, conv := range ibox.ConvsUnverified {
+		if search.MatchesConv(conv) {
+			convIDs = append(convIDs, conv.GetConvID())
+		}
+	}
+	if len(convIDs) == 0 {
+		return nil, nil
+	}
+	lbox, _, err := h.G().InboxSource.Read(ctx, uid,
+		types.ConversationLocalizerBlocking, types.InboxSourceDataSourceAll, nil,
+		&chat1.GetInboxLocalQuery{ConvIDs: convIDs})
+	if err != nil {
+		return nil, err
+	}
+	return lbox.Convs, nil
+}
+
+func (h *Helper) StartAttachmentRekey(ctx context.Context, uid gregor1.UID,
+	age time.Duration, bwKBps int) error {
+	return h.attachmentRekeyer.Start(ctx, uid, age, bwKBps)
+}
+
+func (h *Helper) StopAttachmentRekey(ctx context.Context) {
+	h.attachmentRekeyer.Stop(ctx)
+}
+
+func (h *Helper) AttachmentRekeyStatus(ctx context.Context) (string, error) {
+	out, err := json.MarshalIndent(h.attachmentRekeyer.Status(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// RecentChatPartners returns usernames drawn from the user's most
+// recent conversations: partners from non-team chats, and teammates
+// seen in team channels. Both lists are ordered by conversation
+// recency and deduplicated, and exclude the user themselves.
+func (h *Helper) RecentChatPartners(ctx context.Context, uid gregor1.UID) (
+	direct, teammates []string, err error) {
+	defer h.Trace(ctx, &err, "RecentChatPartners")()
+	topicType := chat1.TopicType_CHAT
+	ibox, _, err := h.G().InboxSource.Read(ctx, uid,
+		types.ConversationLocalizerBlocking, types.InboxSourceDataSourceAll, nil,
+		&chat1.GetInboxLocalQuery{
+			TopicType: &topicType,
+			MemberStatus: []chat1.ConversationMemberStatus{
+				chat1.ConversationMemberStatus_ACTIVE,
+			},
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	convs := ibox.Convs
+	sort.Slice(convs, func(i, j int) bool {
+		return convs[i].GetMtime() > convs[j].GetMtime()
+	})
+	self := h.G().GetEnv().GetUsername().String()
+	seenDirect := make(map[string]bool)
+	seenTeam := make(map[string]bool)
+	for _, conv := range convs {
+		isTeam := conv.GetMembersType() == chat1.ConversationMembersType_TEAM
+		for _, part := range conv.Info.Participants {
+			switch {
+			case part.Username == self:
+			case isTeam && !seenTeam[part.Username]:
+				seenTeam[part.Username] = true
+				teammates = append(teammates, part.Username)
+			case !isTeam && !seenDirect[part.Username]:
+				seenDirect[part.Username] = true
+				direct = append(direct, part.Username)
+			}
+		}
+	}
+	return direct, teammates, nil
+}
+
 // InTeam gives a best effort to answer team membership based on the current state of the inbox cache
 func (h *Helper) InTeam(ctx context.Context, uid gregor1.UID, teamID keybase1.TeamID) (bool, error) {
 	tlfID := chat1.TLFID(teamID.ToBytes())
@@ -190,6 +190,9 @@ func (l *LiveLocationTracker) updateMapUnfurl(ctx context.Context, t *locationTr
 		coords = trackerCoords
 	}
 	last := coords[len(coords)-1]
+	if t.coarse {
+		last = coarsenCoord(last)
+	}
 	conv, err := utils.GetVerifiedConv(ctx, l.G(), l.uid, t.convID, types.InboxSourceDataSourceAll)
 	if err != nil {
 		return err
@@ -262,6 +265,21 @@ func (l *LiveLocationTracker) tracker(t *locationTrack) error {
 		return errors.New("tracker from the past")
 	}
 
+	// wait out a scheduled sharing window before hitting the OS for
+	// position updates
+	if t.startTime.After(l.clock.Now()) {
+		l.Debug(ctx, "tracker: waiting for scheduled start time: %v", t.startTime)
+		select {
+		case <-l.clock.AfterTime(t.startTime):
+		case <-t.stopCh:
+			l.Lock()
+			defer l.Unlock()
+			delete(l.trackers, t.Key())
+			l.saveLocked(ctx)
+			return nil
+		}
+	}
+
 	// start up the OS watch routine
 	watchID, err := l.startWatch(ctx, t)
 	if err != nil {
@@ -292,6 +310,11 @@ func (l *LiveLocationTracker) tracker(t *locationTrack) error {
 		case coord := <-t.updateCh:
 			added := t.Drain(coord)
 			l.Debug(ctx, "tracker[%v]: got coords", watchID)
+			if t.outsideGeofence(coord) {
+				l.Debug(ctx, "tracker[%v]: left geofence, stopping share", watchID)
+				_ = l.updateMapUnfurl(ctx, t, true)
+				return nil
+			}
 			if firstUpdate {
 				l.Debug(ctx, "tracker[%v]: updating due to live location first update", watchID)
 				_ = l.updateMapUnfurl(ctx, t, false)
@@ -355,9 +378,24 @@ func (l *LiveLocationTracker) GetCurrentPosition(ctx context.Context, convID cha
 func (l *LiveLocationTracker) StartTracking(ctx context.Context, convID chat1.ConversationID,
 	msgID chat1.MessageID, endTime time.Time) {
 	defer l.Trace(ctx, nil, "StartTracking")()
+	l.startTracking(ctx, convID, msgID, endTime, types.LiveLocationTrackOpts{})
+}
+
+// StartTrackingWithOpts is StartTracking with the optional sharing behaviors
+// applied: coarse coordinates, a scheduled start time, and a geofence that
+// ends the share when the user leaves the region.
+func (l *LiveLocationTracker) StartTrackingWithOpts(ctx context.Context, convID chat1.ConversationID,
+	msgID chat1.MessageID, endTime time.Time, opts types.LiveLocationTrackOpts) {
+	defer l.Trace(ctx, nil, "StartTrackingWithOpts")()
+	l.startTracking(ctx, convID, msgID, endTime, opts)
+}
+
+func (l *LiveLocationTracker) startTracking(ctx context.Context, convID chat1.ConversationID,
+	msgID chat1.MessageID, endTime time.Time, opts types.LiveLocationTrackOpts) {
 	l.Lock()
 	defer l.Unlock()
 	t := newLocationTrack(convID, msgID, endTime, false, l.maxCoords, false)
+	t.setOpts(opts)
 	l.trackers[t.Key()] = t
 	l.saveLocked(ctx)
 	l.eg.Go(func() error { return l.tracker(t) })
@@ -394,12 +432,19 @@ func (l *LiveLocationTracker) GetCoordinates(ctx context.Context, key types.Live
 	defer l.Trace(ctx, nil, "GetCoordinates")()
 	l.Lock()
 	defer l.Unlock()
+	coarse := false
 	if t, ok := l.trackers[key]; ok {
 		res = t.GetCoords()
+		coarse = t.coarse
 	}
 	if len(res) == 0 {
 		res = append(res, l.lastCoord)
 	}
+	if coarse {
+		for index, coord := range res {
+			res[index] = coarsenCoord(coord)
+		}
+	}
 	return res
 }
 
@@ -3,6 +3,7 @@ package maps
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -24,6 +25,9 @@ type locationTrack struct {
 	maxCoords          int
 	stopped            bool
 	perm               chat1.UIWatchPositionPerm
+	coarse             bool
+	startTime          time.Time
+	geofence           *types.LiveLocationGeofence
 }
 
 func (t *locationTrack) GetCoords() (res []chat1.Coordinate) {
@@ -91,6 +95,24 @@ func (t *locationTrack) Key() types.LiveLocationKey {
 	return types.LiveLocationKey(key)
 }
 
+func (t *locationTrack) setOpts(opts types.LiveLocationTrackOpts) {
+	t.coarse = opts.Coarse
+	t.startTime = opts.StartTime
+	t.geofence = opts.Geofence
+}
+
+// outsideGeofence reports whether the given coordinate has left the track's
+// geofence, if one is set.
+func (t *locationTrack) outsideGeofence(coord chat1.Coordinate) bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.geofence == nil || coord.IsZero() {
+		return false
+	}
+	return coordDistanceMeters(coord.Lat, coord.Lon, t.geofence.Lat, t.geofence.Lon) >
+		t.geofence.RadiusMeters
+}
+
 func (t *locationTrack) ToDisk() diskLocationTrack {
 	return diskLocationTrack{
 		ConvID:             t.convID,
@@ -100,6 +122,9 @@ func (t *locationTrack) ToDisk() diskLocationTrack {
 		GetCurrentPosition: t.getCurrentPosition,
 		MaxCoords:          t.maxCoords,
 		Stopped:            t.stopped,
+		Coarse:             t.coarse,
+		StartTime:          gregor1.ToTime(t.startTime),
+		Geofence:           t.geofence,
 	}
 }
 
@@ -126,5 +151,36 @@ func newLocationTrackFromDisk(d diskLocationTrack) *locationTrack {
 	t := newLocationTrack(d.ConvID, d.MsgID, gregor1.FromTime(d.EndTime), d.GetCurrentPosition, d.MaxCoords,
 		d.Stopped)
 	t.allCoords = d.Coords
+	t.setOpts(types.LiveLocationTrackOpts{
+		Coarse:    d.Coarse,
+		StartTime: gregor1.FromTime(d.StartTime),
+		Geofence:  d.Geofence,
+	})
 	return t
 }
+
+// coarseCoordPrecision is roughly city-level: coordinates are snapped to a
+// 0.1 degree grid (~11km at the equator).
+const coarseCoordPrecision = 0.1
+const coarseCoordAccuracy = 10000
+
+// coarsenCoord snaps a coordinate to a coarse grid so only a city-level
+// position is revealed.
+func coarsenCoord(coord chat1.Coordinate) chat1.Coordinate {
+	return chat1.Coordinate{
+		Lat:      math.Round(coord.Lat/coarseCoordPrecision) * coarseCoordPrecision,
+		Lon:      math.Round(coord.Lon/coarseCoordPrecision) * coarseCoordPrecision,
+		Accuracy: coarseCoordAccuracy,
+	}
+}
+
+// coordDistanceMeters returns the haversine distance between two points.
+func coordDistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusMeters = 6371000
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
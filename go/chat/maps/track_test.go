@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/keybase/client/go/chat/types"
 	"github.com/keybase/client/go/protocol/chat1"
 	"github.com/stretchr/testify/require"
 )
@@ -24,3 +25,31 @@ func TestLocationTrackMaxCoords(t *testing.T) {
 	coords := tr.GetCoords()
 	require.Equal(t, []chat1.Coordinate{firstCoord, secondCoord, latestCoord}, coords)
 }
+
+func TestLocationTrackCoarse(t *testing.T) {
+	coord := chat1.Coordinate{Lat: 40.678, Lon: -73.944, Accuracy: 20}
+	coarse := coarsenCoord(coord)
+	require.Equal(t, 40.7, coarse.Lat)
+	require.Equal(t, -73.9, coarse.Lon)
+	require.Equal(t, float64(coarseCoordAccuracy), coarse.Accuracy)
+}
+
+func TestLocationTrackGeofence(t *testing.T) {
+	convID := chat1.ConversationID([]byte{0, 0, 1})
+	msgID := chat1.MessageID(5)
+	endTime := time.Now().Add(time.Hour)
+	tr := newLocationTrack(convID, msgID, endTime, false, 10, false)
+	require.False(t, tr.outsideGeofence(chat1.Coordinate{Lat: 40.678, Lon: -73.944}))
+	tr.setOpts(types.LiveLocationTrackOpts{
+		Geofence: &types.LiveLocationGeofence{
+			Lat:          40.678,
+			Lon:          -73.944,
+			RadiusMeters: 500,
+		},
+	})
+	// a block away is inside, downtown is not
+	require.False(t, tr.outsideGeofence(chat1.Coordinate{Lat: 40.680, Lon: -73.944}))
+	require.True(t, tr.outsideGeofence(chat1.Coordinate{Lat: 40.712, Lon: -74.006}))
+	// zero coordinates are ignored
+	require.False(t, tr.outsideGeofence(chat1.Coordinate{}))
+}
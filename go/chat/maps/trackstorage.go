@@ -5,6 +5,7 @@ import (
 
 	"github.com/keybase/client/go/chat/globals"
 	"github.com/keybase/client/go/chat/storage"
+	"github.com/keybase/client/go/chat/types"
 	"github.com/keybase/client/go/encrypteddb"
 	"github.com/keybase/client/go/libkb"
 	"github.com/keybase/client/go/protocol/chat1"
@@ -14,13 +15,16 @@ import (
 const diskTrackStorageVersion = 1
 
 type diskLocationTrack struct {
-	ConvID             chat1.ConversationID `codec:"C"`
-	MsgID              chat1.MessageID      `codec:"M"`
-	EndTime            gregor1.Time         `codec:"E"`
-	Coords             []chat1.Coordinate   `codec:"O"`
-	GetCurrentPosition bool                 `codec:"P"`
-	MaxCoords          int                  `codec:"MC"`
-	Stopped            bool                 `codec:"S"`
+	ConvID             chat1.ConversationID        `codec:"C"`
+	MsgID              chat1.MessageID             `codec:"M"`
+	EndTime            gregor1.Time                `codec:"E"`
+	Coords             []chat1.Coordinate          `codec:"O"`
+	GetCurrentPosition bool                        `codec:"P"`
+	MaxCoords          int                         `codec:"MC"`
+	Stopped            bool                        `codec:"S"`
+	Coarse             bool                        `codec:"CR"`
+	StartTime          gregor1.Time                `codec:"ST"`
+	Geofence           *types.LiveLocationGeofence `codec:"GF"`
 }
 
 type diskTrackStorage struct {
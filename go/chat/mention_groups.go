@@ -0,0 +1,114 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+const mentionGroupsStorageName = "mentionGroups"
+
+// mentionGroupStorage is the JSON payload kept in the backing dev
+// conversation: group handle (without the @) to member usernames.
+type mentionGroupStorage struct {
+	Groups map[string][]string `json:"groups"`
+}
+
+var mentionGroupHandleRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]{2,30}$`)
+
+// DevConvMentionGroupSource manages team-defined mention groups like
+// @oncall. Groups live in admin-only dev-conversation-backed storage, so
+// only admins can change them while every member can read them for
+// expansion at send time.
+type DevConvMentionGroupSource struct {
+	globals.Contextified
+	utils.DebugLabeler
+	ri func() chat1.RemoteInterface
+}
+
+var _ types.MentionGroupSource = (*DevConvMentionGroupSource)(nil)
+
+func NewDevConvMentionGroupSource(g *globals.Context, ri func() chat1.RemoteInterface) *DevConvMentionGroupSource {
+	return &DevConvMentionGroupSource{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "DevConvMentionGroupSource", false),
+		ri:           ri,
+	}
+}
+
+func (s *DevConvMentionGroupSource) storage() *ConvDevConversationBackedStorage {
+	return NewConvDevConversationBackedStorage(s.G(), chat1.TopicType_DEV, true /* adminOnly */, s.ri)
+}
+
+func (s *DevConvMentionGroupSource) load(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, createConvIfMissing bool) (res mentionGroupStorage, err error) {
+	res.Groups = make(map[string][]string)
+	var stored mentionGroupStorage
+	found, _, err := s.storage().Get(ctx, uid, convID, mentionGroupsStorageName, &stored,
+		createConvIfMissing)
+	switch err.(type) {
+	case nil:
+	case *DevStorageAdminOnlyError:
+		// Storage not locked down to admins: ignore it rather than honor
+		// groups anyone could have written.
+		return res, nil
+	default:
+		return res, err
+	}
+	if found && stored.Groups != nil {
+		for handle, members := range stored.Groups {
+			res.Groups[strings.ToLower(handle)] = members
+		}
+	}
+	return res, nil
+}
+
+func (s *DevConvMentionGroupSource) Get(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) (res map[string][]string, err error) {
+	defer s.Trace(ctx, &err, "Get")()
+	stored, err := s.load(ctx, uid, convID, false)
+	if err != nil {
+		return nil, err
+	}
+	return stored.Groups, nil
+}
+
+func (s *DevConvMentionGroupSource) Set(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, handle string, members []string) (err error) {
+	defer s.Trace(ctx, &err, "Set(%s)", handle)()
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+	if !mentionGroupHandleRegexp.MatchString(handle) {
+		return fmt.Errorf("invalid mention group handle %q", handle)
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("mention group needs at least one member")
+	}
+	stored, err := s.load(ctx, uid, convID, true)
+	if err != nil {
+		return err
+	}
+	stored.Groups[handle] = members
+	return s.storage().Put(ctx, uid, convID, mentionGroupsStorageName, stored)
+}
+
+func (s *DevConvMentionGroupSource) Remove(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, handle string) (err error) {
+	defer s.Trace(ctx, &err, "Remove(%s)", handle)()
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+	stored, err := s.load(ctx, uid, convID, false)
+	if err != nil {
+		return err
+	}
+	if _, ok := stored.Groups[handle]; !ok {
+		return fmt.Errorf("no mention group named @%s", handle)
+	}
+	delete(stored.Groups, handle)
+	return s.storage().Put(ctx, uid, convID, mentionGroupsStorageName, stored)
+}
@@ -0,0 +1,160 @@
+package chat
+
+import (
+	"fmt"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"golang.org/x/net/context"
+)
+
+// Message request modes, from Env.GetChatMessageRequestMode.
+const (
+	// MessageRequestModeOff disables the message-request layer.
+	MessageRequestModeOff = "off"
+	// MessageRequestModeStrangers quarantines DMs from senders the
+	// user doesn't follow.
+	MessageRequestModeStrangers = "strangers"
+	// MessageRequestModeAll quarantines every DM that hasn't been
+	// explicitly accepted.
+	MessageRequestModeAll = "all"
+)
+
+// MessageRequestManager implements the message-request layer: new
+// direct conversations from strangers are held in a requests queue and
+// don't make noise until the user accepts them. Team conversations are
+// exempt, since joining a team is already consent. Declining and
+// blocking reuse the existing conversation status machinery; this
+// manager only tracks acceptance and classifies conversations.
+type MessageRequestManager struct {
+	globals.Contextified
+	utils.DebugLabeler
+}
+
+var _ types.MessageRequestManager = (*MessageRequestManager)(nil)
+
+func NewMessageRequestManager(g *globals.Context) *MessageRequestManager {
+	return &MessageRequestManager{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "MessageRequestManager", false),
+	}
+}
+
+func (m *MessageRequestManager) dbKey(uid gregor1.UID, convID chat1.ConversationID) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatMessageRequests,
+		Key: fmt.Sprintf("%s:%s", uid, convID),
+	}
+}
+
+func (m *MessageRequestManager) mode() string {
+	return m.G().ExternalG().Env.GetChatMessageRequestMode()
+}
+
+func (m *MessageRequestManager) isAccepted(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) bool {
+	var accepted bool
+	found, err := m.G().GetKVStore().GetInto(&accepted, m.dbKey(uid, convID))
+	if err != nil {
+		m.Debug(ctx, "isAccepted: failed to read from storage: %s", err)
+		return false
+	}
+	return found && accepted
+}
+
+// Accept marks a conversation as accepted so it stops being treated as
+// a message request.
+func (m *MessageRequestManager) Accept(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) error {
+	return m.G().GetKVStore().PutObj(m.dbKey(uid, convID), nil, true)
+}
+
+// follows returns whether the logged-in user follows the given user,
+// from the cached following list; any failure counts as not following.
+func (m *MessageRequestManager) follows(ctx context.Context, target string) bool {
+	myUID := m.G().ExternalG().Env.GetUID()
+	if myUID.IsNil() || len(target) == 0 {
+		return false
+	}
+	ts := libkb.NewServertrustTrackerSyncer(m.G().ExternalG(), myUID,
+		libkb.FollowDirectionFollowing)
+	if err := libkb.RunSyncerCached(m.MetaContext(ctx), ts, myUID); err != nil {
+		m.Debug(ctx, "follows: failed to load following list: %s", err)
+		return false
+	}
+	targetNorm := libkb.NewNormalizedUsername(target)
+	for _, u := range ts.Result().Users {
+		if libkb.NewNormalizedUsername(u.Username).Eq(targetNorm) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPending reports whether the conversation should be held in the
+// requests queue for a message from senderUsername.
+func (m *MessageRequestManager) IsPending(ctx context.Context, uid gregor1.UID,
+	conv *chat1.ConversationLocal, senderUsername string) bool {
+	mode := m.mode()
+	if mode == MessageRequestModeOff || conv == nil {
+		return false
+	}
+	if conv.GetTopicType() != chat1.TopicType_CHAT {
+		return false
+	}
+	// Shared team membership is consent already.
+	if conv.Info.MembersType == chat1.ConversationMembersType_TEAM {
+		return false
+	}
+	// Anything the user has filed away (muted, favorited, etc.) has
+	// been seen and judged.
+	if conv.Info.Status != chat1.ConversationStatus_UNFILED {
+		return false
+	}
+	if m.isAccepted(ctx, uid, conv.GetConvID()) {
+		return false
+	}
+	if mode == MessageRequestModeStrangers && m.follows(ctx, senderUsername) {
+		return false
+	}
+	return true
+}
+
+// ListPending returns the conversations currently held in the requests
+// queue.
+func (m *MessageRequestManager) ListPending(ctx context.Context, uid gregor1.UID) (
+	res []chat1.ConversationLocal, err error) {
+	defer m.Trace(ctx, &err, "ListPending")()
+	topicType := chat1.TopicType_CHAT
+	ib, _, err := m.G().InboxSource.Read(ctx, uid, types.ConversationLocalizerBlocking,
+		types.InboxSourceDataSourceAll, nil, &chat1.GetInboxLocalQuery{
+			TopicType: &topicType,
+		})
+	if err != nil {
+		return nil, err
+	}
+	for i := range ib.Convs {
+		conv := &ib.Convs[i]
+		if m.IsPending(ctx, uid, conv, m.strangerIn(ctx, conv)) {
+			res = append(res, *conv)
+		}
+	}
+	return res, nil
+}
+
+// strangerIn picks a participant other than the user to run the
+// follow check against; in a DM that's the other side.
+func (m *MessageRequestManager) strangerIn(ctx context.Context,
+	conv *chat1.ConversationLocal) string {
+	me := m.G().ExternalG().Env.GetUsername()
+	for _, p := range conv.Info.Participants {
+		if !libkb.NewNormalizedUsername(p.Username).Eq(me) {
+			return p.Username
+		}
+	}
+	return ""
+}
@@ -402,6 +402,12 @@ func (g *PushHandler) shouldDisplayDesktopNotification(ctx context.Context,
 	if !utils.GetConversationStatusBehavior(conv.Info.Status).DesktopNotifications {
 		return false
 	}
+	// Pending message requests stay quiet until accepted.
+	if g.G().MessageRequests != nil && msg.IsValid() &&
+		g.G().MessageRequests.IsPending(ctx, uid, conv, msg.Valid().SenderUsername) {
+		g.Debug(ctx, "shouldDisplayDesktopNotification: squashing pending message request")
+		return false
+	}
 	if msg.IsValid() {
 		// No notifications for our own messages
 		if msg.Valid().ClientHeader.Sender.Eq(uid) {
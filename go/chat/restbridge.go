@@ -0,0 +1,200 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/kbhttp/manager"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+const restBridgeDefaultNum = 50
+const restBridgeMaxNum = 200
+
+type restBridgeMessage struct {
+	MsgID    chat1.MessageID `json:"msgID"`
+	Sender   string          `json:"sender"`
+	SentAt   gregor1.Time    `json:"sentAt"`
+	Type     string          `json:"type"`
+	Body     string          `json:"body,omitempty"`
+	Exploded bool            `json:"exploded,omitempty"`
+}
+
+type restBridgeMember struct {
+	Username string  `json:"username"`
+	Fullname *string `json:"fullname,omitempty"`
+}
+
+// RESTBridgeSrv exposes read-only conversation history and membership over
+// the local HTTP server, so tools that cannot speak msgpack RPC (internal
+// dashboards, scripts) can embed chat excerpts. Requests are authenticated
+// with the HTTP server's session token, and only conversations explicitly
+// allowed with AllowConversation are served; everything else is a 403.
+type RESTBridgeSrv struct {
+	sync.Mutex
+	globals.Contextified
+	utils.DebugLabeler
+
+	endpoint string
+	httpSrv  *manager.Srv
+	ri       func() chat1.RemoteInterface
+	allowed  map[chat1.ConvIDStr]bool
+}
+
+func NewRESTBridgeSrv(g *globals.Context, httpSrv *manager.Srv,
+	ri func() chat1.RemoteInterface) *RESTBridgeSrv {
+	r := &RESTBridgeSrv{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.ExternalG(), "RESTBridgeSrv", false),
+		endpoint:     "chatrest",
+		httpSrv:      httpSrv,
+		ri:           ri,
+		allowed:      make(map[chat1.ConvIDStr]bool),
+	}
+	r.httpSrv.HandleFunc(r.endpoint, manager.SrvTokenModeDefault, r.serve)
+	return r
+}
+
+// AllowConversation makes the given conversation readable through the
+// bridge.
+func (r *RESTBridgeSrv) AllowConversation(convID chat1.ConversationID) {
+	r.Lock()
+	defer r.Unlock()
+	r.allowed[convID.ConvIDStr()] = true
+}
+
+// DisallowConversation removes the given conversation from the bridge.
+func (r *RESTBridgeSrv) DisallowConversation(convID chat1.ConversationID) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.allowed, convID.ConvIDStr())
+}
+
+func (r *RESTBridgeSrv) isAllowed(convID chat1.ConversationID) bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.allowed[convID.ConvIDStr()]
+}
+
+func (r *RESTBridgeSrv) makeError(ctx context.Context, w http.ResponseWriter, code int, msg string,
+	args ...interface{}) {
+	r.Debug(ctx, "serve: error code: %d msg %s", code, fmt.Sprintf(msg, args...))
+	w.WriteHeader(code)
+}
+
+func (r *RESTBridgeSrv) writeJSON(ctx context.Context, w http.ResponseWriter, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		r.Debug(ctx, "writeJSON: failed to encode response: %s", err)
+	}
+}
+
+func (r *RESTBridgeSrv) serve(w http.ResponseWriter, req *http.Request) {
+	ctx := globals.ChatCtx(context.Background(), r.G(), keybase1.TLFIdentifyBehavior_CHAT_GUI, nil,
+		NewSimpleIdentifyNotifier(r.G()))
+	defer r.Trace(ctx, nil, "serve")()
+	addr, err := r.httpSrv.Addr()
+	if err != nil {
+		r.Debug(ctx, "serve: failed to get HTTP server address: %s", err)
+		r.makeError(ctx, w, http.StatusInternalServerError, "unable to determine addr")
+		return
+	}
+	if req.Host != addr {
+		r.Debug(ctx, "Host %s didn't match addr %s, failing request to protect against DNS rebinding", req.Host, addr)
+		r.makeError(ctx, w, http.StatusBadRequest, "invalid host")
+		return
+	}
+	if req.Method != http.MethodGet {
+		r.makeError(ctx, w, http.StatusMethodNotAllowed, "read-only bridge")
+		return
+	}
+	convID, err := chat1.MakeConvID(req.URL.Query().Get("conv_id"))
+	if err != nil {
+		r.makeError(ctx, w, http.StatusBadRequest, "invalid conv_id: %s", err)
+		return
+	}
+	if !r.isAllowed(convID) {
+		r.makeError(ctx, w, http.StatusForbidden, "conversation not allowed: %s", convID)
+		return
+	}
+	uid := gregor1.UID(r.G().Env.GetUID().ToBytes())
+	switch req.URL.Query().Get("view") {
+	case "", "messages":
+		r.serveMessages(ctx, w, req, uid, convID)
+	case "members":
+		r.serveMembers(ctx, w, uid, convID)
+	default:
+		r.makeError(ctx, w, http.StatusNotFound, "unknown view")
+	}
+}
+
+func (r *RESTBridgeSrv) serveMessages(ctx context.Context, w http.ResponseWriter, req *http.Request,
+	uid gregor1.UID, convID chat1.ConversationID) {
+	num := restBridgeDefaultNum
+	if strNum := req.URL.Query().Get("num"); len(strNum) > 0 {
+		parsed, err := strconv.Atoi(strNum)
+		if err != nil || parsed <= 0 {
+			r.makeError(ctx, w, http.StatusBadRequest, "invalid num")
+			return
+		}
+		num = parsed
+	}
+	if num > restBridgeMaxNum {
+		num = restBridgeMaxNum
+	}
+	tv, err := r.G().ConvSource.Pull(ctx, convID, uid, chat1.GetThreadReason_GENERAL, r.ri, nil,
+		&chat1.Pagination{Num: num})
+	if err != nil {
+		r.makeError(ctx, w, http.StatusInternalServerError, "failed to read thread: %s", err)
+		return
+	}
+	res := make([]restBridgeMessage, 0, len(tv.Messages))
+	for _, msg := range tv.Messages {
+		if !msg.IsValid() {
+			continue
+		}
+		valid := msg.Valid()
+		typ, err := valid.MessageBody.MessageType()
+		if err != nil {
+			continue
+		}
+		bmsg := restBridgeMessage{
+			MsgID:    msg.GetMessageID(),
+			Sender:   valid.SenderUsername,
+			SentAt:   valid.ServerHeader.Ctime,
+			Type:     typ.String(),
+			Exploded: valid.IsEphemeral(),
+		}
+		if typ == chat1.MessageType_TEXT && !valid.IsEphemeral() {
+			bmsg.Body = valid.MessageBody.Text().Body
+		}
+		res = append(res, bmsg)
+	}
+	r.writeJSON(ctx, w, res)
+}
+
+func (r *RESTBridgeSrv) serveMembers(ctx context.Context, w http.ResponseWriter, uid gregor1.UID,
+	convID chat1.ConversationID) {
+	conv, err := utils.GetVerifiedConv(ctx, r.G(), uid, convID, types.InboxSourceDataSourceAll)
+	if err != nil {
+		r.makeError(ctx, w, http.StatusInternalServerError, "failed to load conv: %s", err)
+		return
+	}
+	res := make([]restBridgeMember, 0, len(conv.Info.Participants))
+	for _, p := range conv.Info.Participants {
+		res = append(res, restBridgeMember{
+			Username: p.Username,
+			Fullname: p.Fullname,
+		})
+	}
+	r.writeJSON(ctx, w, res)
+}
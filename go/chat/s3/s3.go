@@ -976,7 +976,7 @@ func (s3 *S3) run(ctx context.Context, req *request, resp interface{}) (*http.Re
 					}
 					return
 				},
-				Proxy: libkb.MakeProxy(s3.G().Env),
+				Proxy: libkb.MakeProxyForCircuit(s3.G().Env, libkb.TorCircuitChatAttachments),
 			},
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/keybase/client/go/chat/attachments"
@@ -692,6 +693,90 @@ func (s *BlockingSender) getUsernamesForMentions(ctx context.Context, uid gregor
 		return res, nil
 	}
 }
+
+// expandMentionGroups adds the members of any team-defined mention group
+// (like @oncall) referenced in the message to the notification list. The
+// group handle stays in the message text; only routing changes. Returns
+// the handles that matched a group so the typo check can skip them.
+// Expansion is best effort: any failure leaves the mentions untouched.
+func (s *BlockingSender) expandMentionGroups(ctx context.Context, uid gregor1.UID,
+	conv *chat1.ConversationLocal, maybeMentions []chat1.MaybeMention,
+	atMentions []gregor1.UID) ([]gregor1.UID, map[string]bool) {
+	handled := make(map[string]bool)
+	if conv == nil || len(maybeMentions) == 0 || s.G().MentionGroupSource == nil ||
+		conv.GetMembersType() != chat1.ConversationMembersType_TEAM {
+		return atMentions, handled
+	}
+	groups, err := s.G().MentionGroupSource.Get(ctx, uid, conv.GetConvID())
+	if err != nil {
+		s.Debug(ctx, "expandMentionGroups: failed to load groups: %s", err)
+		return atMentions, handled
+	}
+	if len(groups) == 0 {
+		return atMentions, handled
+	}
+	seen := make(map[string]bool, len(atMentions))
+	for _, at := range atMentions {
+		seen[at.String()] = true
+	}
+	for _, maybe := range maybeMentions {
+		members, ok := groups[strings.ToLower(maybe.Name)]
+		if !ok {
+			continue
+		}
+		handled[strings.ToLower(maybe.Name)] = true
+		for _, member := range members {
+			kuid, err := s.G().GetUPAKLoader().LookupUID(ctx, libkb.NewNormalizedUsername(member))
+			if err != nil {
+				s.Debug(ctx, "expandMentionGroups: failed to resolve %s: %s", member, err)
+				continue
+			}
+			guid := gregor1.UID(kuid.ToBytes())
+			if !seen[guid.String()] {
+				seen[guid.String()] = true
+				atMentions = append(atMentions, guid)
+			}
+		}
+	}
+	return atMentions, handled
+}
+
+// checkMistypedMentions rejects sends whose unresolved @mentions look like
+// typos of conversation members, returning the candidates through
+// UnknownMentionError so frontends can offer corrections. Mentions the
+// frontend already confirmed as team mentions pass through, as do
+// team-defined mention groups and anything too far from every member's
+// name to plausibly be a typo.
+func (s *BlockingSender) checkMistypedMentions(ctx context.Context,
+	confirmedTeamMentions []chat1.KnownTeamMention, maybeMentions []chat1.MaybeMention,
+	getConvMembs func() ([]string, error), skip map[string]bool) error {
+	if len(maybeMentions) == 0 {
+		return nil
+	}
+	confirmed := make(map[string]bool)
+	for _, tm := range confirmedTeamMentions {
+		confirmed[strings.ToLower(tm.Name)] = true
+	}
+	var membs []string
+	for _, maybe := range maybeMentions {
+		if confirmed[strings.ToLower(maybe.Name)] || skip[strings.ToLower(maybe.Name)] {
+			continue
+		}
+		if membs == nil {
+			var err error
+			if membs, err = getConvMembs(); err != nil {
+				// Can't fetch members; don't block the send over it.
+				s.Debug(ctx, "checkMistypedMentions: failed to get members: %s", err)
+				return nil
+			}
+		}
+		if candidates := utils.SimilarMentionCandidates(maybe.Name, membs); len(candidates) > 0 {
+			return NewUnknownMentionError(maybe.Name, candidates)
+		}
+	}
+	return nil
+}
+
 func (s *BlockingSender) handleMentions(ctx context.Context, uid gregor1.UID, msg chat1.MessagePlaintext,
 	conv *chat1.ConversationLocal) (res chat1.MessagePlaintext, atMentions []gregor1.UID, chanMention chat1.ChannelMention, err error) {
 	if msg.ClientHeader.Conv.TopicType != chat1.TopicType_CHAT {
@@ -740,6 +825,12 @@ func (s *BlockingSender) handleMentions(ctx context.Context, uid gregor1.UID, ms
 		knownUserMentions, maybeMentions, chanMention = utils.GetTextAtMentionedItems(ctx, s.G(),
 			uid, conv.GetConvID(), msg.MessageBody.Text(), getConvUsernames, &s.DebugLabeler)
 		atMentions = atFromKnown(knownUserMentions)
+		var groupHandles map[string]bool
+		atMentions, groupHandles = s.expandMentionGroups(ctx, uid, conv, maybeMentions, atMentions)
+		if err = s.checkMistypedMentions(ctx, msg.MessageBody.Text().TeamMentions,
+			maybeMentions, getConvUsernames, groupHandles); err != nil {
+			return res, atMentions, chanMention, err
+		}
 		newBody := msg.MessageBody.Text().DeepCopy()
 		newBody.TeamMentions = maybeToTeam(maybeMentions)
 		newBody.UserMentions = knownUserMentions
@@ -800,7 +800,8 @@ func (h *Server) PostLocal(ctx context.Context, arg chat1.PostLocalArg) (res cha
 	_, msgBoxed, err := sender.Send(ctx, arg.ConversationID, arg.Msg, 0, nil, nil, &prepareOpts)
 	if err != nil {
 		h.Debug(ctx, "PostLocal: unable to send message: %s", err.Error())
-		return res, err
+		return res, libkb.NewStructuredError(libkb.ErrorSubsystemChat, libkb.ErrorIsRetryable(err),
+			"The message was not sent; it is safe to retry", err)
 	}
 
 	return chat1.PostLocalRes{
@@ -3900,7 +3901,7 @@ func (h *Server) ForwardMessage(ctx context.Context, arg chat1.ForwardMessageArg
 				TlfName:           dstConv.Info.TlfName,
 				Visibility:        dstConv.Info.Visibility,
 				Filename:          sink.Name(),
-				Title:             "",
+				Title:             forwardedAttachmentTitle(ctx, h.G(), arg.SrcConvID, mvalid),
 				Metadata:          mbod.Metadata,
 				IdentifyBehavior:  arg.IdentifyBehavior,
 				EphemeralLifetime: ephemeralLifetime,
@@ -3918,7 +3919,7 @@ func (h *Server) ForwardMessage(ctx context.Context, arg chat1.ForwardMessageArg
 					MessageType:       mvalid.ClientHeader.MessageType,
 					EphemeralMetadata: mvalid.EphemeralMetadata(),
 				},
-				MessageBody: mvalid.MessageBody.DeepCopy(),
+				MessageBody: forwardedMessageBody(ctx, h.G(), arg.SrcConvID, mvalid),
 			},
 			IdentifyBehavior:   arg.IdentifyBehavior,
 			SkipInChatPayments: true,
@@ -3976,7 +3977,7 @@ func (h *Server) ForwardMessageNonblock(ctx context.Context, arg chat1.ForwardMe
 				TlfName:           dstConv.Info.TlfName,
 				Visibility:        dstConv.Info.Visibility,
 				Filename:          sink.Name(),
-				Title:             "",
+				Title:             forwardedAttachmentTitle(ctx, h.G(), arg.SrcConvID, mvalid),
 				Metadata:          mbod.Metadata,
 				IdentifyBehavior:  arg.IdentifyBehavior,
 				EphemeralLifetime: ephemeralLifetime,
@@ -3994,7 +3995,7 @@ func (h *Server) ForwardMessageNonblock(ctx context.Context, arg chat1.ForwardMe
 					MessageType:       mvalid.ClientHeader.MessageType,
 					EphemeralMetadata: mvalid.EphemeralMetadata(),
 				},
-				MessageBody: mvalid.MessageBody.DeepCopy(),
+				MessageBody: forwardedMessageBody(ctx, h.G(), arg.SrcConvID, mvalid),
 			},
 			IdentifyBehavior:   arg.IdentifyBehavior,
 			SkipInChatPayments: true,
@@ -207,6 +207,10 @@ func (g *gregorTestConnection) LocalDismissItem(ctx context.Context, id gregor.M
 	return nil
 }
 
+func (g *gregorTestConnection) ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (int, error) {
+	return 0, nil
+}
+
 func (g *gregorTestConnection) OnConnectError(err error, reconnectThrottleDuration time.Duration) {
 }
 
@@ -472,6 +476,7 @@ func (c *chatTestContext) as(t *testing.T, user *kbtest.FakeUser) *chatTestUserC
 	g.UIThreadLoader = NewUIThreadLoader(g, func() chat1.RemoteInterface { return ri })
 	g.ParticipantsSource = NewCachingParticipantSource(g, func() chat1.RemoteInterface { return ri })
 	g.EmojiSource = NewDevConvEmojiSource(g, func() chat1.RemoteInterface { return ri })
+	g.MentionGroupSource = NewDevConvMentionGroupSource(g, func() chat1.RemoteInterface { return ri })
 	g.EphemeralTracker = NewEphemeralTracker(g)
 	g.EphemeralTracker.Start(context.TODO(), uid)
 
@@ -957,6 +957,10 @@ func (i *Inbox) MarkLocalRead(ctx context.Context, uid gregor1.UID, convID chat1
 		i.Debug(ctx, "MarkLocalRead: no conversation found: convID: %s", convID)
 		return nil
 	}
+	// Record the read under this device in the per-device read vector,
+	// so a stale server read pointer in a later sync can't revert it.
+	utils.NewReadVectors(i.G()).ObserveRead(
+		ctx, uid, convID, i.G().ActiveDevice.DeviceID().String(), msgID)
 	conv.LocalReadMsgID = msgID
 	return i.writeConv(ctx, uid, conv, false)
 }
@@ -1119,6 +1123,11 @@ func (i *Inbox) ReadMessage(ctx context.Context, uid gregor1.UID, vers chat1.Inb
 	if !found {
 		i.Debug(ctx, "ReadMessage: no conversation found: convID: %s", convID)
 	} else {
+		// Track the server's merged read pointer in the per-device read
+		// vector, so stale pointers in later syncs can be clamped
+		// against it.
+		utils.NewReadVectors(i.G()).ObserveRead(
+			ctx, uid, convID, utils.ReadVectorServerDevice, msgID)
 		// Update conv
 		if conv.Conv.ReaderInfo.ReadMsgid < msgID {
 			i.Debug(ctx, "ReadMessage: updating mtime: readMsgID: %d msgID: %d", conv.Conv.ReaderInfo.ReadMsgid,
@@ -1664,6 +1673,7 @@ func (i *Inbox) Sync(ctx context.Context, uid gregor1.UID, vers chat1.InboxVers,
 	// Sync inbox with new conversations
 	oldVers := iboxVers.InboxVersion
 	iboxVers.InboxVersion = vers
+	readVectors := utils.NewReadVectors(i.G())
 	convMap := make(map[chat1.ConvIDStr]chat1.Conversation)
 	for _, conv := range convs {
 		convMap[conv.GetConvID().ConvIDStr()] = conv
@@ -1699,6 +1709,18 @@ func (i *Inbox) Sync(ctx context.Context, uid gregor1.UID, vers chat1.InboxVers,
 				res.TopicNameChanged = append(res.TopicNameChanged, newConv.GetConvID())
 			}
 			delete(convMap, oldConv.ConvIDStr)
+			// Read pointers race between devices, and the incoming conv
+			// can carry a pointer older than a read we've already
+			// performed or observed. Merge with max semantics so the
+			// sync can't resurrect phantom unread state.
+			if newConv.ReaderInfo != nil {
+				newConv.ReaderInfo.ReadMsgid = readVectors.ClampReadMsgID(
+					ctx, uid, newConv.GetConvID(), newConv.ReaderInfo.ReadMsgid)
+				if oldConv.Conv.ReaderInfo != nil &&
+					oldConv.Conv.ReaderInfo.ReadMsgid > newConv.ReaderInfo.ReadMsgid {
+					newConv.ReaderInfo.ReadMsgid = oldConv.Conv.ReaderInfo.ReadMsgid
+				}
+			}
 			oldConv.Conv = newConv
 			if err := i.writeConv(ctx, uid, oldConv, false); err != nil {
 				return res, err
@@ -492,6 +492,10 @@ type Unfurler interface {
 	WhitelistAdd(ctx context.Context, uid gregor1.UID, domain string) error
 	WhitelistRemove(ctx context.Context, uid gregor1.UID, domain string) error
 	WhitelistAddExemption(ctx context.Context, uid gregor1.UID, exemption WhitelistExemption)
+	DenylistAdd(ctx context.Context, uid gregor1.UID, domain string) error
+	DenylistRemove(ctx context.Context, uid gregor1.UID, domain string) error
+	SetTeamDefaultMode(ctx context.Context, uid gregor1.UID, tlfID chat1.TLFID,
+		mode chat1.UnfurlMode) error
 	SetMode(ctx context.Context, uid gregor1.UID, mode chat1.UnfurlMode) error
 }
 
@@ -556,6 +560,8 @@ type LiveLocationTracker interface {
 	Resumable
 	GetCurrentPosition(ctx context.Context, convID chat1.ConversationID, msgID chat1.MessageID)
 	StartTracking(ctx context.Context, convID chat1.ConversationID, msgID chat1.MessageID, endTime time.Time)
+	StartTrackingWithOpts(ctx context.Context, convID chat1.ConversationID, msgID chat1.MessageID,
+		endTime time.Time, opts LiveLocationTrackOpts)
 	LocationUpdate(ctx context.Context, coord chat1.Coordinate)
 	GetCoordinates(ctx context.Context, key LiveLocationKey) []chat1.Coordinate
 	GetEndTime(ctx context.Context, key LiveLocationKey) *time.Time
@@ -635,6 +641,13 @@ type EmojiSource interface {
 	RemoteToLocalSource(ctx context.Context, uid gregor1.UID, remote chat1.EmojiRemoteSource) (chat1.EmojiLoadSource, chat1.EmojiLoadSource, error)
 	ToggleAnimations(ctx context.Context, uid gregor1.UID, enabled bool) error
 	IsValidSize(size int64) bool
+	Usage(ctx context.Context, uid gregor1.UID) (map[string]int, error)
+}
+
+type MentionGroupSource interface {
+	Get(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (map[string][]string, error)
+	Set(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID, handle string, members []string) error
+	Remove(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID, handle string) error
 }
 
 type EphemeralTracker interface {
@@ -649,6 +662,13 @@ type EphemeralTracker interface {
 	OnDbNuke(libkb.MetaContext) error
 }
 
+type MessageRequestManager interface {
+	IsPending(ctx context.Context, uid gregor1.UID, conv *chat1.ConversationLocal,
+		senderUsername string) bool
+	Accept(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) error
+	ListPending(ctx context.Context, uid gregor1.UID) ([]chat1.ConversationLocal, error)
+}
+
 type ServerConnection interface {
 	Reconnect(context.Context) (bool, error)
 	GetClient() chat1.RemoteInterface
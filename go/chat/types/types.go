@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/keybase/client/go/chat/s3"
 	"github.com/keybase/client/go/libkb"
@@ -583,6 +584,19 @@ func (d DummyUnfurler) WhitelistAddExemption(ctx context.Context, uid gregor1.UI
 	exemption WhitelistExemption) {
 }
 
+func (d DummyUnfurler) DenylistAdd(ctx context.Context, uid gregor1.UID, domain string) error {
+	return nil
+}
+
+func (d DummyUnfurler) DenylistRemove(ctx context.Context, uid gregor1.UID, domain string) error {
+	return nil
+}
+
+func (d DummyUnfurler) SetTeamDefaultMode(ctx context.Context, uid gregor1.UID, tlfID chat1.TLFID,
+	mode chat1.UnfurlMode) error {
+	return nil
+}
+
 func (d DummyUnfurler) SetMode(ctx context.Context, uid gregor1.UID, mode chat1.UnfurlMode) error {
 	return nil
 }
@@ -861,6 +875,9 @@ func (DummyEmojiSource) ToggleAnimations(ctx context.Context, uid gregor1.UID, e
 func (DummyEmojiSource) IsValidSize(size int64) bool {
 	return false
 }
+func (DummyEmojiSource) Usage(ctx context.Context, uid gregor1.UID) (map[string]int, error) {
+	return nil, nil
+}
 
 type ClearOpts struct {
 	SendLocalAdminNotification bool
@@ -904,3 +921,21 @@ var _ ContextFactory = (*DummyCtxFactory)(nil)
 
 func (d DummyCtxFactory) NewKeyFinder() KeyFinder   { return nil }
 func (d DummyCtxFactory) NewUPAKFinder() UPAKFinder { return nil }
+
+// LiveLocationGeofence describes a circular region around a coordinate.
+// When a live location tracker has a geofence set, sharing stops as soon as
+// an update lands outside the region.
+type LiveLocationGeofence struct {
+	Lat          float64
+	Lon          float64
+	RadiusMeters float64
+}
+
+// LiveLocationTrackOpts collects the optional behaviors of a live location
+// share: coarse (city-level) coordinates, a scheduled start time, and a
+// geofence that ends the share on exit.
+type LiveLocationTrackOpts struct {
+	Coarse    bool
+	StartTime time.Time
+	Geofence  *LiveLocationGeofence
+}
@@ -93,8 +93,23 @@ func (e *Extractor) isWhitelistHit(ctx context.Context, convID chat1.Conversatio
 	return false
 }
 
+// unfurlModeRestrictiveness orders modes from most permissive to most
+// restrictive, so team defaults can only tighten a user's setting.
+func unfurlModeRestrictiveness(mode chat1.UnfurlMode) int {
+	switch mode {
+	case chat1.UnfurlMode_ALWAYS:
+		return 0
+	case chat1.UnfurlMode_WHITELISTED:
+		return 1
+	case chat1.UnfurlMode_NEVER:
+		return 2
+	}
+	return 0
+}
+
 func (e *Extractor) Extract(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID,
-	msgID chat1.MessageID, body string, userSettings *Settings) (res []ExtractorHit, err error) {
+	msgID chat1.MessageID, body string, userSettings *Settings,
+	tlfID chat1.TLFID) (res []ExtractorHit, err error) {
 	defer e.Trace(ctx, &err, "Extract")()
 	body = e.quoteRegexp.ReplaceAllString(body, "")
 	hits := e.urlRegexp.FindAllString(body, -1)
@@ -105,7 +120,26 @@ func (e *Extractor) Extract(ctx context.Context, uid gregor1.UID, convID chat1.C
 	if err != nil {
 		return res, err
 	}
+	if len(tlfID) > 0 {
+		teamMode, found, err := userSettings.TeamDefault(ctx, uid, tlfID.String())
+		if err != nil {
+			return res, err
+		}
+		if found && unfurlModeRestrictiveness(teamMode) >
+			unfurlModeRestrictiveness(settings.Mode) {
+			e.Debug(ctx, "Extract: applying more restrictive team default mode: %v", teamMode)
+			settings.Mode = teamMode
+		}
+	}
+	denylist, err := userSettings.Denylist(ctx, uid)
+	if err != nil {
+		return res, err
+	}
 	for _, h := range hits {
+		if domain, err := GetDomain(h); err == nil && denylist[domain] {
+			e.Debug(ctx, "Extract: domain %s denied by policy, skipping", domain)
+			continue
+		}
 		ehit := ExtractorHit{
 			URL: h,
 			Typ: ExtractorHitPrompt,
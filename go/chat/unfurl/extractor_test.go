@@ -146,7 +146,7 @@ func TestExtractor(t *testing.T) {
 			settings.Whitelist[w] = true
 		}
 		require.NoError(t, settingsMod.Set(context.TODO(), uid, settings))
-		res, err := extractor.Extract(context.TODO(), uid, convID, 1, tcase.message, settingsMod)
+		res, err := extractor.Extract(context.TODO(), uid, convID, 1, tcase.message, settingsMod, nil)
 		require.NoError(t, err)
 		require.Equal(t, tcase.result, res)
 	}
@@ -168,43 +168,89 @@ func TestExtractorExemptions(t *testing.T) {
 
 	extractor.AddWhitelistExemption(context.TODO(), uid,
 		NewOneTimeWhitelistExemption(convID, msgID, "amazon.com"))
-	res, err := extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod)
+	res, err := extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
 	extractor.AddWhitelistExemption(context.TODO(), uid,
 		NewOneTimeWhitelistExemption(convID, msgID, "cnn.com"))
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitPrompt, res[0].Typ)
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitPrompt, res[0].Typ)
 
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitPrompt, res[0].Typ)
 	extractor.AddWhitelistExemption(context.TODO(), uid,
 		NewSingleMessageWhitelistExemption(convID, msgID, "google.com"))
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://google.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
 
 	require.NoError(t, settingsMod.Set(context.TODO(), uid, settings))
-	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod)
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://amazon.com", settingsMod, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(res))
 	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
 }
+
+func TestExtractorPolicies(t *testing.T) {
+	tc := externalstest.SetupTest(t, "chat_extractor", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	convID := chat1.ConversationID([]byte{0, 1})
+	msgID := chat1.MessageID(1)
+	tlfID := chat1.TLFID([]byte{0, 2})
+	extractor := NewExtractor(g)
+	settingsMod := NewSettings(g, newMemConversationBackedStorage())
+	require.NoError(t, settingsMod.SetMode(context.TODO(), uid, chat1.UnfurlMode_ALWAYS))
+
+	// Denied domains produce no hit at all, not even a prompt.
+	require.NoError(t, settingsMod.DenylistAdd(context.TODO(), uid, "evil.com"))
+	res, err := extractor.Extract(context.TODO(), uid, convID, msgID,
+		"http://evil.com and http://cnn.com", settingsMod, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, "http://cnn.com", res[0].URL)
+	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
+
+	// A more restrictive team default tightens the user's mode for that
+	// team's conversations only.
+	require.NoError(t, settingsMod.SetTeamDefault(context.TODO(), uid, tlfID.String(),
+		chat1.UnfurlMode_NEVER))
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com",
+		settingsMod, tlfID)
+	require.NoError(t, err)
+	require.Zero(t, len(res))
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com",
+		settingsMod, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, ExtractorHitUnfurl, res[0].Typ)
+
+	// A more permissive team default does not loosen the user's mode.
+	require.NoError(t, settingsMod.SetMode(context.TODO(), uid, chat1.UnfurlMode_NEVER))
+	require.NoError(t, settingsMod.SetTeamDefault(context.TODO(), uid, tlfID.String(),
+		chat1.UnfurlMode_ALWAYS))
+	res, err = extractor.Extract(context.TODO(), uid, convID, msgID, "http://cnn.com",
+		settingsMod, tlfID)
+	require.NoError(t, err)
+	require.Zero(t, len(res))
+}
@@ -44,7 +44,14 @@ func (s *Scraper) makeCollector() *colly.Collector {
 			s.Debug(context.TODO(), "colly OnResponse: unable to instrument network request %s, %s", record, err)
 		}
 	})
-	if s.G().Env.GetProxyType() != libkb.NoProxy {
+	// A dedicated unfurl proxy takes precedence over the global proxy, so
+	// unfurl fetches can be routed through a privacy proxy even when the
+	// rest of the app connects directly.
+	if proxy, ok := s.G().Env.GetConfig().GetStringAtPath("unfurl.privacy_proxy"); ok && len(proxy) > 0 {
+		if err := c.SetProxy(proxy); err != nil {
+			s.Debug(context.TODO(), "makeCollector: error setting privacy proxy: %+v", err)
+		}
+	} else if s.G().Env.GetProxyType() != libkb.NoProxy {
 		err := c.SetProxy(libkb.BuildProxyAddressWithProtocol(s.G().Env.GetProxyType(), s.G().Env.GetProxy()))
 		if err != nil {
 			s.Debug(context.TODO(), "makeCollector: error setting proxy: %+v", err)
@@ -379,6 +379,10 @@ func (t *testingLiveLocationTracker) StartTracking(ctx context.Context, convID c
 	msgID chat1.MessageID, endTime time.Time) {
 }
 
+func (t *testingLiveLocationTracker) StartTrackingWithOpts(ctx context.Context, convID chat1.ConversationID,
+	msgID chat1.MessageID, endTime time.Time, opts types.LiveLocationTrackOpts) {
+}
+
 func (t *testingLiveLocationTracker) GetCurrentPosition(ctx context.Context, convID chat1.ConversationID,
 	msgID chat1.MessageID) {
 }
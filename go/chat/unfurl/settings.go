@@ -12,6 +12,8 @@ import (
 
 const settingsModeName = "__unfurl_settings_mode"
 const settingsWhitelistName = "__unfurl_settings_whitelist"
+const settingsDenylistName = "__unfurl_settings_denylist"
+const settingsTeamDefaultsName = "__unfurl_settings_team_defaults"
 
 type modeRecord struct {
 	Mode chat1.UnfurlMode
@@ -21,6 +23,14 @@ type whitelistRecord struct {
 	Whitelist map[string]bool
 }
 
+type denylistRecord struct {
+	Denylist map[string]bool
+}
+
+type teamDefaultsRecord struct {
+	Modes map[string]chat1.UnfurlMode // TLF ID -> default mode
+}
+
 type Settings struct {
 	utils.DebugLabeler
 
@@ -96,6 +106,88 @@ func (s *Settings) WhitelistRemove(ctx context.Context, uid gregor1.UID, domain
 	return s.storage.Put(ctx, uid, settingsWhitelistName, wr)
 }
 
+// Denylist returns the set of domains the user has blocked from ever being
+// unfurled or prompted on, regardless of mode or whitelist.
+func (s *Settings) Denylist(ctx context.Context, uid gregor1.UID) (res map[string]bool, err error) {
+	defer s.Trace(ctx, &err, "Denylist")()
+	var dr denylistRecord
+	found, err := s.storage.Get(ctx, uid, settingsDenylistName, &dr)
+	if err != nil {
+		return res, err
+	}
+	if !found {
+		return make(map[string]bool), nil
+	}
+	return dr.Denylist, nil
+}
+
+func (s *Settings) DenylistAdd(ctx context.Context, uid gregor1.UID, domain string) (err error) {
+	defer s.Trace(ctx, &err, "DenylistAdd(%s)", domain)()
+	var dr denylistRecord
+	found, err := s.storage.Get(ctx, uid, settingsDenylistName, &dr)
+	if err != nil {
+		return err
+	}
+	if !found {
+		dr.Denylist = make(map[string]bool)
+	}
+	if dr.Denylist[domain] {
+		return nil
+	}
+	dr.Denylist[domain] = true
+	return s.storage.Put(ctx, uid, settingsDenylistName, dr)
+}
+
+func (s *Settings) DenylistRemove(ctx context.Context, uid gregor1.UID, domain string) (err error) {
+	defer s.Trace(ctx, &err, "DenylistRemove(%s)", domain)()
+	var dr denylistRecord
+	found, err := s.storage.Get(ctx, uid, settingsDenylistName, &dr)
+	if err != nil {
+		return err
+	}
+	if !found || !dr.Denylist[domain] {
+		s.Debug(ctx, "DenylistRemove: not found, doing nothing")
+		return nil
+	}
+	delete(dr.Denylist, domain)
+	return s.storage.Put(ctx, uid, settingsDenylistName, dr)
+}
+
+// SetTeamDefault records a default unfurl mode for conversations in the
+// given TLF. The effective mode for those conversations is the more
+// restrictive of the user's own mode and the team default.
+func (s *Settings) SetTeamDefault(ctx context.Context, uid gregor1.UID, tlfID string,
+	mode chat1.UnfurlMode) (err error) {
+	defer s.Trace(ctx, &err, "SetTeamDefault(%s,%v)", tlfID, mode)()
+	var tr teamDefaultsRecord
+	found, err := s.storage.Get(ctx, uid, settingsTeamDefaultsName, &tr)
+	if err != nil {
+		return err
+	}
+	if !found {
+		tr.Modes = make(map[string]chat1.UnfurlMode)
+	}
+	tr.Modes[tlfID] = mode
+	return s.storage.Put(ctx, uid, settingsTeamDefaultsName, tr)
+}
+
+// TeamDefault returns the default unfurl mode recorded for the given TLF,
+// if any.
+func (s *Settings) TeamDefault(ctx context.Context, uid gregor1.UID, tlfID string) (
+	mode chat1.UnfurlMode, found bool, err error) {
+	defer s.Trace(ctx, &err, "TeamDefault(%s)", tlfID)()
+	var tr teamDefaultsRecord
+	recFound, err := s.storage.Get(ctx, uid, settingsTeamDefaultsName, &tr)
+	if err != nil {
+		return mode, false, err
+	}
+	if !recFound {
+		return mode, false, nil
+	}
+	mode, found = tr.Modes[tlfID]
+	return mode, found, nil
+}
+
 func (s *Settings) SetMode(ctx context.Context, uid gregor1.UID, mode chat1.UnfurlMode) (err error) {
 	defer s.Trace(ctx, &err, "SetMode(%v)", mode)()
 	return s.storage.Put(ctx, uid, settingsModeName, modeRecord{
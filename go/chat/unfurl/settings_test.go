@@ -83,3 +83,46 @@ func TestUnfurlSetting(t *testing.T) {
 	require.Equal(t, 1, len(res.Whitelist))
 	require.True(t, res.Whitelist["yahoo.com"])
 }
+
+func TestUnfurlSettingDenylist(t *testing.T) {
+	tc := externalstest.SetupTest(t, "chat_settings", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	settings := NewSettings(g, newMemConversationBackedStorage())
+	denylist, err := settings.Denylist(context.TODO(), uid)
+	require.NoError(t, err)
+	require.Zero(t, len(denylist))
+	require.NoError(t, settings.DenylistAdd(context.TODO(), uid, "evil.com"))
+	denylist, err = settings.Denylist(context.TODO(), uid)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(denylist))
+	require.True(t, denylist["evil.com"])
+	require.NoError(t, settings.DenylistRemove(context.TODO(), uid, "evil.com"))
+	require.NoError(t, settings.DenylistRemove(context.TODO(), uid, "notthere.com"))
+	denylist, err = settings.Denylist(context.TODO(), uid)
+	require.NoError(t, err)
+	require.Zero(t, len(denylist))
+}
+
+func TestUnfurlSettingTeamDefault(t *testing.T) {
+	tc := externalstest.SetupTest(t, "chat_settings", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+
+	uid := gregor1.UID([]byte{0, 1})
+	settings := NewSettings(g, newMemConversationBackedStorage())
+	_, found, err := settings.TeamDefault(context.TODO(), uid, "aabb")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.NoError(t, settings.SetTeamDefault(context.TODO(), uid, "aabb",
+		chat1.UnfurlMode_NEVER))
+	mode, found, err := settings.TeamDefault(context.TODO(), uid, "aabb")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, chat1.UnfurlMode_NEVER, mode)
+	_, found, err = settings.TeamDefault(context.TODO(), uid, "ccdd")
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -165,7 +165,8 @@ func (u *Unfurler) extractURLs(ctx context.Context, uid gregor1.UID, convID chat
 	}
 	switch typ {
 	case chat1.MessageType_TEXT:
-		hits, err := u.extractor.Extract(ctx, uid, convID, msg.GetMessageID(), body.Text().Body, u.settings)
+		hits, err := u.extractor.Extract(ctx, uid, convID, msg.GetMessageID(), body.Text().Body,
+			u.settings, msg.Valid().ClientHeader.Conv.Tlfid)
 		if err != nil {
 			u.Debug(ctx, "extractURLs: failed to extract: %s", err)
 			return nil
@@ -300,7 +301,7 @@ func (u *Unfurler) Prefetch(ctx context.Context, uid gregor1.UID, convID chat1.C
 	defer u.prefetchLock.Unlock()
 	defer u.Trace(ctx, nil, "Prefetch")()
 
-	hits, err := u.extractor.Extract(ctx, uid, convID, 0, msgText, u.settings)
+	hits, err := u.extractor.Extract(ctx, uid, convID, 0, msgText, u.settings, nil)
 	if err != nil {
 		u.Debug(ctx, "Prefetch: failed to extract: %s", err)
 		return 0
@@ -443,6 +444,22 @@ func (u *Unfurler) WhitelistRemove(ctx context.Context, uid gregor1.UID, domain
 	return u.settings.WhitelistRemove(ctx, uid, domain)
 }
 
+func (u *Unfurler) DenylistAdd(ctx context.Context, uid gregor1.UID, domain string) (err error) {
+	defer u.Trace(ctx, nil, "DenylistAdd")()
+	return u.settings.DenylistAdd(ctx, uid, domain)
+}
+
+func (u *Unfurler) DenylistRemove(ctx context.Context, uid gregor1.UID, domain string) (err error) {
+	defer u.Trace(ctx, nil, "DenylistRemove")()
+	return u.settings.DenylistRemove(ctx, uid, domain)
+}
+
+func (u *Unfurler) SetTeamDefaultMode(ctx context.Context, uid gregor1.UID, tlfID chat1.TLFID,
+	mode chat1.UnfurlMode) (err error) {
+	defer u.Trace(ctx, nil, "SetTeamDefaultMode")()
+	return u.settings.SetTeamDefault(ctx, uid, tlfID.String(), mode)
+}
+
 func (u *Unfurler) WhitelistAddExemption(ctx context.Context, uid gregor1.UID,
 	exemption types.WhitelistExemption) {
 	defer u.Trace(ctx, nil, "WhitelistAddExemption")()
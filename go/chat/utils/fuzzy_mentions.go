@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// mentionTypoMaxDistance is how far (in edits) an unresolved mention may
+// be from a member's username before we stop suggesting it as a typo.
+const mentionTypoMaxDistance = 2
+
+// editDistance is a plain Levenshtein distance over bytes; usernames are
+// ASCII so this is accurate enough for typo detection.
+func editDistance(a, b string) int {
+	if len(a) == 0 {
+		return len(b)
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := 0; j <= len(b); j++ {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j] + 1
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+			if prev[j-1]+cost < curr[j] {
+				curr[j] = prev[j-1] + cost
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// SimilarMentionCandidates returns conversation members whose usernames
+// are within a small edit distance of an unresolved mention, closest
+// first. An exact match returns nothing: that mention isn't a typo.
+func SimilarMentionCandidates(name string, membs []string) (res []string) {
+	nname := strings.ToLower(name)
+	type scored struct {
+		username string
+		dist     int
+	}
+	var hits []scored
+	for _, memb := range membs {
+		dist := editDistance(nname, strings.ToLower(memb))
+		if dist == 0 {
+			return nil
+		}
+		if dist <= mentionTypoMaxDistance {
+			hits = append(hits, scored{username: memb, dist: dist})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].dist < hits[j].dist })
+	for _, hit := range hits {
+		res = append(res, hit.username)
+	}
+	return res
+}
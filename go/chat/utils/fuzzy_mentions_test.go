@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("alice", "alice"))
+	require.Equal(t, 1, editDistance("alice", "alic"))
+	require.Equal(t, 2, editDistance("alice", "alcie"))
+	require.Equal(t, 5, editDistance("", "alice"))
+	require.Equal(t, editDistance("alice", "bob"), editDistance("bob", "alice"))
+}
+
+func TestSimilarMentionCandidates(t *testing.T) {
+	membs := []string{"alice", "bob", "charlie_w"}
+	require.Equal(t, []string{"alice"}, SimilarMentionCandidates("alcie", membs))
+	require.Equal(t, []string{"bob"}, SimilarMentionCandidates("bobb", membs))
+	// Exact match means the mention isn't a typo.
+	require.Nil(t, SimilarMentionCandidates("alice", membs))
+	// Too far from everyone: probably a team mention, not a typo.
+	require.Nil(t, SimilarMentionCandidates("acme.eng", membs))
+	// Case-insensitive matching.
+	require.Equal(t, []string{"charlie_w"}, SimilarMentionCandidates("Charlie_j", membs))
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/clockwork"
+	"golang.org/x/net/context"
+)
+
+// ReadVectorServerDevice is the pseudo device ID used for read
+// pointers learned from the server's merged account-level state, when
+// we don't know which device performed the read.
+const ReadVectorServerDevice = "server"
+
+type readVectorRecord struct {
+	Devices map[string]chat1.MessageID
+	Time    time.Time
+}
+
+// ReadVectors tracks the latest read message ID per device for each
+// conversation, merged with max semantics. Read pointers race between
+// devices (a read on one device can be overwritten by a stale sync
+// from another), producing phantom unread badges; keeping a per-device
+// vector and only ever merging upward makes the merged read pointer
+// monotonic no matter the order observations arrive in.
+type ReadVectors struct {
+	globals.Contextified
+	DebugLabeler
+	sync.Mutex
+
+	clock clockwork.Clock
+}
+
+func NewReadVectors(g *globals.Context) *ReadVectors {
+	return &ReadVectors{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: NewDebugLabeler(g.ExternalG(), "Utils.ReadVectors", false),
+		clock:        clockwork.NewRealClock(),
+	}
+}
+
+func (r *ReadVectors) key(uid gregor1.UID, convID chat1.ConversationID) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatReadVectors,
+		Key: fmt.Sprintf("%s:%s", uid, convID),
+	}
+}
+
+func (r *ReadVectors) getLocked(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) (rec readVectorRecord) {
+	found, err := r.G().GetKVStore().GetInto(&rec, r.key(uid, convID))
+	if err != nil {
+		r.Debug(ctx, "getLocked: failed to read vector: %s", err)
+	}
+	if !found || rec.Devices == nil {
+		rec.Devices = make(map[string]chat1.MessageID)
+	}
+	return rec
+}
+
+func (r *ReadVectors) putLocked(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, rec readVectorRecord) {
+	rec.Time = r.clock.Now()
+	if err := r.G().GetKVStore().PutObj(r.key(uid, convID), nil, rec); err != nil {
+		r.Debug(ctx, "putLocked: failed to write vector: %s", err)
+	}
+}
+
+// ObserveRead records that deviceID has read up to msgID in convID.
+// Stale observations (lower than what we already have for the device)
+// are dropped.
+func (r *ReadVectors) ObserveRead(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, deviceID string, msgID chat1.MessageID) {
+	r.Lock()
+	defer r.Unlock()
+	rec := r.getLocked(ctx, uid, convID)
+	if rec.Devices[deviceID] >= msgID {
+		return
+	}
+	rec.Devices[deviceID] = msgID
+	r.putLocked(ctx, uid, convID, rec)
+}
+
+// MergeVector merges another device vector into ours with max
+// semantics, entry by entry.
+func (r *ReadVectors) MergeVector(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, devices map[string]chat1.MessageID) {
+	if len(devices) == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	rec := r.getLocked(ctx, uid, convID)
+	updated := false
+	for deviceID, msgID := range devices {
+		if rec.Devices[deviceID] < msgID {
+			rec.Devices[deviceID] = msgID
+			updated = true
+		}
+	}
+	if updated {
+		r.putLocked(ctx, uid, convID, rec)
+	}
+}
+
+// MaxRead returns the highest read message ID any device is known to
+// have reached in convID.
+func (r *ReadVectors) MaxRead(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID) (res chat1.MessageID) {
+	r.Lock()
+	defer r.Unlock()
+	rec := r.getLocked(ctx, uid, convID)
+	for _, msgID := range rec.Devices {
+		if msgID > res {
+			res = msgID
+		}
+	}
+	return res
+}
+
+// ClampReadMsgID returns readMsgID lifted up to the highest read
+// pointer any device is known to have reached, so a stale server read
+// pointer can't resurrect unread state we know has been read.
+func (r *ReadVectors) ClampReadMsgID(ctx context.Context, uid gregor1.UID,
+	convID chat1.ConversationID, readMsgID chat1.MessageID) chat1.MessageID {
+	if maxRead := r.MaxRead(ctx, uid, convID); maxRead > readMsgID {
+		r.Debug(ctx, "ClampReadMsgID: clamping %d -> %d for %s",
+			readMsgID, maxRead, convID)
+		return maxRead
+	}
+	return readMsgID
+}
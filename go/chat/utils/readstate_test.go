@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestReadVectors(t *testing.T) {
+	tc := externalstest.SetupTest(t, "readvectors", 0)
+	defer tc.Cleanup()
+
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+	rv := NewReadVectors(g)
+
+	ctx := context.TODO()
+	convID := chat1.ConversationID([]byte{1, 2, 3, 4})
+	uid := gregor1.UID([]byte{2, 3, 4, 5})
+	devices := []string{"device1", "device2", "device3"}
+
+	require.Zero(t, rv.MaxRead(ctx, uid, convID))
+
+	t.Logf("Three devices read concurrently; the merged pointer is the max no matter the order the observations land in.")
+	var wg sync.WaitGroup
+	for i, deviceID := range devices {
+		wg.Add(1)
+		go func(deviceID string, msgID chat1.MessageID) {
+			defer wg.Done()
+			for m := chat1.MessageID(1); m <= msgID; m++ {
+				rv.ObserveRead(ctx, uid, convID, deviceID, m)
+			}
+		}(deviceID, chat1.MessageID(10*(i+1)))
+	}
+	wg.Wait()
+	require.Equal(t, chat1.MessageID(30), rv.MaxRead(ctx, uid, convID))
+
+	t.Logf("Stale observations don't move a device's pointer backwards.")
+	rv.ObserveRead(ctx, uid, convID, "device3", 5)
+	require.Equal(t, chat1.MessageID(30), rv.MaxRead(ctx, uid, convID))
+
+	t.Logf("Merging a vector uses max semantics entry by entry.")
+	rv.MergeVector(ctx, uid, convID, map[string]chat1.MessageID{
+		"device1": 40,
+		"device2": 3, // stale, dropped
+	})
+	require.Equal(t, chat1.MessageID(40), rv.MaxRead(ctx, uid, convID))
+
+	t.Logf("A stale server pointer gets clamped up, a newer one passes through.")
+	require.Equal(t, chat1.MessageID(40), rv.ClampReadMsgID(ctx, uid, convID, 12))
+	require.Equal(t, chat1.MessageID(50), rv.ClampReadMsgID(ctx, uid, convID, 50))
+
+	t.Logf("Other conversations are unaffected.")
+	otherConvID := chat1.ConversationID([]byte{9, 9, 9, 9})
+	require.Zero(t, rv.MaxRead(ctx, uid, otherConvID))
+}
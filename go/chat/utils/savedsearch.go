@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/types"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"golang.org/x/net/context"
+)
+
+// savedSearchCategoryPrefix is the gregor category prefix under which
+// saved searches live, one item per search so puts and removes of one
+// search don't race with edits of another. Gregor items sync across the
+// user's devices, which is what makes these "synced preferences".
+const savedSearchCategoryPrefix = "chat.savedsearch."
+
+// SavedSearch is a user-defined inbox search: a free-text query matched
+// against conversation names, plus structured filters. The GUI presents
+// these as smart folders.
+type SavedSearch struct {
+	Name          string `json:"name"`
+	Query         string `json:"query,omitempty"`
+	UnreadOnly    bool   `json:"unread_only,omitempty"`
+	TeamsOnly     bool   `json:"teams_only,omitempty"`
+	HasAttachment bool   `json:"has_attachment,omitempty"`
+}
+
+// InboxQuery returns the inbox query that narrows the conversation set
+// before MatchesConv applies the filters the query can't express.
+func (s SavedSearch) InboxQuery() *chat1.GetInboxQuery {
+	query := &chat1.GetInboxQuery{
+		UnreadOnly: s.UnreadOnly,
+		MemberStatus: []chat1.ConversationMemberStatus{
+			chat1.ConversationMemberStatus_ACTIVE,
+			chat1.ConversationMemberStatus_PREVIEW,
+		},
+	}
+	if s.TeamsOnly {
+		query.MembersTypes = []chat1.ConversationMembersType{
+			chat1.ConversationMembersType_TEAM,
+		}
+	}
+	return query
+}
+
+// MatchesConv applies the parts of the saved search that InboxQuery
+// can't express: the free-text query against the conversation's display
+// name, and the has-attachment filter against the max message summaries.
+func (s SavedSearch) MatchesConv(conv types.RemoteConversation) bool {
+	if s.Query != "" && !strings.Contains(
+		strings.ToLower(GetRemoteConvDisplayName(conv)),
+		strings.ToLower(s.Query)) {
+		return false
+	}
+	if s.HasAttachment {
+		if _, err := GetMsgSummaryByType(conv.Conv.MaxMsgSummaries,
+			chat1.MessageType_ATTACHMENT); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SavedSearches stores saved searches in gregor, so they follow the
+// user across devices.
+type SavedSearches struct {
+	globals.Contextified
+	DebugLabeler
+}
+
+func NewSavedSearches(g *globals.Context) *SavedSearches {
+	return &SavedSearches{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: NewDebugLabeler(g.ExternalG(), "Utils.SavedSearches", false),
+	}
+}
+
+func (s *SavedSearches) category(name string) string {
+	return savedSearchCategoryPrefix + name
+}
+
+// Put creates or replaces the saved search with search.Name.
+func (s *SavedSearches) Put(ctx context.Context, search SavedSearch) error {
+	if search.Name == "" {
+		return errors.New("saved search name required")
+	}
+	body, err := json.Marshal(search)
+	if err != nil {
+		return err
+	}
+	_, err = s.G().GregorState.UpdateCategory(
+		ctx, s.category(search.Name), body, gregor1.TimeOrOffset{})
+	return err
+}
+
+// Remove deletes the saved search with the given name, erroring if it
+// doesn't exist.
+func (s *SavedSearches) Remove(ctx context.Context, name string) error {
+	if _, err := s.Get(ctx, name); err != nil {
+		return err
+	}
+	return s.G().GregorState.DismissCategory(
+		ctx, gregor1.Category(s.category(name)))
+}
+
+// Get returns the saved search with the given name.
+func (s *SavedSearches) Get(ctx context.Context, name string) (res SavedSearch, err error) {
+	searches, err := s.List(ctx)
+	if err != nil {
+		return res, err
+	}
+	for _, search := range searches {
+		if search.Name == name {
+			return search, nil
+		}
+	}
+	return res, fmt.Errorf("no saved search named %q", name)
+}
+
+// List returns all of the user's saved searches, sorted by name.
+func (s *SavedSearches) List(ctx context.Context) (res []SavedSearch, err error) {
+	st, err := s.G().GregorState.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cat, err := gregor1.ObjFactory{}.MakeCategory(savedSearchCategoryPrefix)
+	if err != nil {
+		return nil, err
+	}
+	items, err := st.ItemsWithCategoryPrefix(cat)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		var search SavedSearch
+		if err := json.Unmarshal(item.Body().Bytes(), &search); err != nil {
+			s.Debug(ctx, "List: skipping bad saved search item: %s", err)
+			continue
+		}
+		res = append(res, search)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
@@ -22,6 +22,28 @@ If you're on the nth page and want to go back, set the previous field instead.
 
     {"method": "read", "params": {"options": {"channel": {"name": "you,them"}, "pagination": {"previous": "<result.pagination.previous from last reply>", "num": 10}}}}
 
+Alternatively, read with an opaque cursor. Pass page_size to get the first
+page, then feed each reply's result.next_cursor back in until result.last
+is true:
+
+    {"method": "read", "params": {"options": {"channel": {"name": "you,them"}, "page_size": 10}}}
+    {"method": "read", "params": {"options": {"channel": {"name": "you,them"}, "page_size": 10, "cursor": "<result.next_cursor from reply>"}}}
+
+Wait for incoming messages instead of polling. Listen streams matching
+notifications as JSON lines and returns after max_messages messages or
+timeout_seconds, whichever comes first (with neither set, after the first
+message):
+
+    {"method": "listen", "params": {"options": {"channel": {"name": "you,them"}, "message_types": ["text"], "timeout_seconds": 60}}}
+
+Broadcast the same message to several conversations, with per-target
+results. Pass "link": true to get a broadcast_id and per-target message
+IDs back; feed those back in as targets with "action": "edit" or
+"action": "delete" to bulk-edit or bulk-delete the copies:
+
+    {"method": "broadcast", "params": {"options": {"targets": [{"channel": {"name": "you,them"}}, {"channel": {"name": "treehouse", "members_type": "team", "topic_name": "general"}}], "message": {"body": "server maintenance at noon"}, "link": true}}}
+    {"method": "broadcast", "params": {"options": {"targets": [{"conversation_id": "...", "message_id": 314}, {"conversation_id": "...", "message_id": 98}], "message": {"body": "maintenance moved to 1pm"}, "action": "edit"}}}
+
 Get specific messages:
     {"method": "get", "params": {"options": {"channel": {"name": "you,them"}, "message_ids": [314, 315, 342]}}}
 
@@ -56,6 +56,8 @@ const (
 	methodEmojiList           = "emojilist"
 	methodEmojiRemove         = "emojiremove"
 	methodEmojiAddAlias       = "emojiaddalias"
+	methodListen              = "listen"
+	methodBroadcast           = "broadcast"
 )
 
 // ChatAPIHandler can handle all of the chat json api methods.
@@ -95,6 +97,8 @@ type ChatAPIHandler interface {
 	EmojiAddAliasV1(context.Context, Call, io.Writer) error
 	EmojiListV1(context.Context, Call, io.Writer) error
 	EmojiRemoveV1(context.Context, Call, io.Writer) error
+	ListenV1(context.Context, Call, io.Writer) error
+	BroadcastV1(context.Context, Call, io.Writer) error
 }
 
 // ChatAPI implements ChatAPIHandler and contains a ChatServiceHandler
@@ -219,13 +223,27 @@ type readOptionsV1 struct {
 	Channel        ChatChannel
 	ConversationID chat1.ConvIDStr   `json:"conversation_id"`
 	Pagination     *chat1.Pagination `json:"pagination,omitempty"`
-	Peek           bool
-	UnreadOnly     bool `json:"unread_only"`
-	FailOffline    bool `json:"fail_offline"`
+	// Cursor is the opaque next_cursor value from a previous cursor-mode
+	// read; PageSize requests cursor mode with a given page size. They are
+	// an alternative to the raw pagination object above.
+	Cursor      string `json:"cursor,omitempty"`
+	PageSize    int    `json:"page_size,omitempty"`
+	Peek        bool
+	UnreadOnly  bool `json:"unread_only"`
+	FailOffline bool `json:"fail_offline"`
 }
 
 func (r readOptionsV1) Check() error {
-	return checkChannelConv(methodRead, r.Channel, r.ConversationID)
+	if err := checkChannelConv(methodRead, r.Channel, r.ConversationID); err != nil {
+		return err
+	}
+	if len(r.Cursor) > 0 && r.Pagination != nil {
+		return ErrInvalidOptions{version: 1, method: methodRead, err: errors.New("include cursor or pagination, not both")}
+	}
+	if r.PageSize < 0 {
+		return ErrInvalidOptions{version: 1, method: methodRead, err: fmt.Errorf("invalid page size '%d'", r.PageSize)}
+	}
+	return nil
 }
 
 type getOptionsV1 struct {
@@ -1238,6 +1256,122 @@ func (a *ChatAPI) EmojiListV1(ctx context.Context, c Call, w io.Writer) error {
 	return a.encodeReply(c, a.svcHandler.EmojiListV1(ctx), w)
 }
 
+type listenOptionsV1 struct {
+	Channel        ChatChannel
+	ConversationID chat1.ConvIDStr `json:"conversation_id"`
+	MessageTypes   []string        `json:"message_types,omitempty"`
+	ShowLocal      bool            `json:"show_local,omitempty"`
+	HideExploding  bool            `json:"hide_exploding,omitempty"`
+	MaxMessages    int             `json:"max_messages,omitempty"`
+	TimeoutSeconds int             `json:"timeout_seconds,omitempty"`
+}
+
+func (o listenOptionsV1) Check() error {
+	if o.Channel.Valid() && len(o.ConversationID) > 0 {
+		return ErrInvalidOptions{version: 1, method: methodListen, err: errors.New("include channel or conversation_id, not both")}
+	}
+	for _, typ := range o.MessageTypes {
+		if _, ok := chat1.MessageTypeMap[strings.ToUpper(typ)]; !ok {
+			return ErrInvalidOptions{version: 1, method: methodListen, err: fmt.Errorf("unknown message type '%v'", typ)}
+		}
+	}
+	if o.MaxMessages < 0 || o.TimeoutSeconds < 0 {
+		return ErrInvalidOptions{version: 1, method: methodListen, err: errors.New("max_messages and timeout_seconds cannot be negative")}
+	}
+	return nil
+}
+
+func (a *ChatAPI) ListenV1(ctx context.Context, c Call, w io.Writer) error {
+	var opts listenOptionsV1
+	// Options are optional for listen
+	if len(c.Params.Options) != 0 {
+		if err := json.Unmarshal(c.Params.Options, &opts); err != nil {
+			return err
+		}
+	}
+	if err := opts.Check(); err != nil {
+		return err
+	}
+
+	// opts are valid for listen v1
+
+	return a.encodeReply(c, a.svcHandler.ListenV1(ctx, opts, w), w)
+}
+
+const (
+	broadcastActionSend   = "send"
+	broadcastActionEdit   = "edit"
+	broadcastActionDelete = "delete"
+)
+
+type broadcastTargetV1 struct {
+	Channel        ChatChannel
+	ConversationID chat1.ConvIDStr `json:"conversation_id,omitempty"`
+	// MessageID identifies a previously broadcast copy in this
+	// conversation, for the edit and delete actions.
+	MessageID chat1.MessageID `json:"message_id,omitempty"`
+}
+
+type broadcastOptionsV1 struct {
+	Targets []broadcastTargetV1 `json:"targets"`
+	Message ChatMessage         `json:"message,omitempty"`
+	// Action is "send" (the default), or "edit"/"delete" to apply a
+	// bulk edit or delete to copies from an earlier broadcast.
+	Action string `json:"action,omitempty"`
+	// Link asks for a broadcast_id in the reply; together with the
+	// per-target message IDs it lets the caller feed the copies back in
+	// as targets of a later bulk edit or delete.
+	Link bool `json:"link,omitempty"`
+}
+
+func (b broadcastOptionsV1) action() string {
+	if len(b.Action) == 0 {
+		return broadcastActionSend
+	}
+	return b.Action
+}
+
+func (b broadcastOptionsV1) Check() error {
+	if len(b.Targets) == 0 {
+		return ErrInvalidOptions{version: 1, method: methodBroadcast, err: errors.New("need at least one target")}
+	}
+	action := b.action()
+	switch action {
+	case broadcastActionSend, broadcastActionEdit, broadcastActionDelete:
+	default:
+		return ErrInvalidOptions{version: 1, method: methodBroadcast, err: fmt.Errorf("unknown action '%v'", b.Action)}
+	}
+	if action != broadcastActionDelete && !b.Message.Valid() {
+		return ErrInvalidOptions{version: 1, method: methodBroadcast, err: errors.New("invalid message")}
+	}
+	for _, target := range b.Targets {
+		if err := checkChannelConv(methodBroadcast, target.Channel, target.ConversationID); err != nil {
+			return err
+		}
+		if action != broadcastActionSend && target.MessageID == 0 {
+			return ErrInvalidOptions{version: 1, method: methodBroadcast, err: errors.New("need message_id on every target for edit and delete")}
+		}
+	}
+	return nil
+}
+
+func (a *ChatAPI) BroadcastV1(ctx context.Context, c Call, w io.Writer) error {
+	if len(c.Params.Options) == 0 {
+		return ErrInvalidOptions{version: 1, method: methodBroadcast, err: errors.New("empty options")}
+	}
+	var opts broadcastOptionsV1
+	if err := json.Unmarshal(c.Params.Options, &opts); err != nil {
+		return err
+	}
+	if err := opts.Check(); err != nil {
+		return err
+	}
+
+	// opts are valid for broadcast v1
+
+	return a.encodeReply(c, a.svcHandler.BroadcastV1(ctx, opts), w)
+}
+
 func (a *ChatAPI) encodeReply(call Call, reply Reply, w io.Writer) error {
 	return encodeReply(call, reply, w, a.indent)
 }
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/keybase/client/go/chat/utils"
@@ -22,6 +24,15 @@ type chatNotificationDisplay struct {
 	svc               *chatServiceHandler
 	config            chatNotificationConfig
 	filtersNormalized []chat1.ConversationID
+	// typeFilters, when non-empty, restricts notifications to the given
+	// message types.
+	typeFilters []chat1.MessageType
+	// output, when set, receives the JSON notifications instead of the
+	// terminal; the listen API method uses it to stream to its caller.
+	output io.Writer
+	// deliveries, when set, gets a (non-blocking) tick per notification
+	// written, so the listen API method can count them.
+	deliveries chan<- struct{}
 }
 
 func newChatNotificationDisplay(g *libkb.GlobalContext, config chatNotificationConfig) *chatNotificationDisplay {
@@ -131,6 +142,36 @@ func (d *chatNotificationDisplay) formatMessage(inMsg chat1.IncomingMessage) *ch
 	}
 }
 
+// emitJSON writes one notification, either to the terminal (the
+// api-listen command) or to the output writer (the listen API method).
+func (d *chatNotificationDisplay) emitJSON(data interface{}) {
+	if d.output != nil {
+		if jsonStr, err := json.Marshal(data); err == nil {
+			_, _ = d.output.Write(append(jsonStr, '\n'))
+		}
+	} else {
+		d.printJSON(data)
+	}
+	if d.deliveries != nil {
+		select {
+		case d.deliveries <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (d *chatNotificationDisplay) matchTypeFilters(msg chat1.UIMessage) bool {
+	if len(d.typeFilters) == 0 {
+		return true
+	}
+	for _, typ := range d.typeFilters {
+		if msg.GetMessageType() == typ {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *chatNotificationDisplay) matchFilters(convID chat1.ConversationID) bool {
 	if len(d.filtersNormalized) == 0 {
 		// No filters - every message is relayed.
@@ -166,6 +207,10 @@ func (d *chatNotificationDisplay) NewChatActivity(ctx context.Context, arg chat1
 			// Skip filtered out message.
 			return nil
 		}
+		if !d.matchTypeFilters(inMsg.Message) {
+			// Skip message of filtered out type.
+			return nil
+		}
 		msg := d.formatMessage(inMsg)
 		if msg == nil {
 			return nil
@@ -175,7 +220,7 @@ func (d *chatNotificationDisplay) NewChatActivity(ctx context.Context, arg chat1
 		notif.Msg = msg.Msg
 		notif.Error = msg.Error
 		notif.Pagination = inMsg.Pagination
-		d.printJSON(notif)
+		d.emitJSON(notif)
 	} else if d.config.showNewConvs && typ == chat1.ChatActivityType_NEW_CONVERSATION {
 		convInfo := activity.NewConversation()
 		notif := newConvNotification()
@@ -186,7 +231,7 @@ func (d *chatNotificationDisplay) NewChatActivity(ctx context.Context, arg chat1
 			conv := utils.ExportToSummary(*convInfo.Conv)
 			notif.Conv = &conv
 		}
-		d.printJSON(notif)
+		d.emitJSON(notif)
 	}
 	return nil
 }
@@ -204,7 +249,7 @@ func (d *chatNotificationDisplay) ChatJoinedConversation(ctx context.Context, ar
 		conv := utils.ExportToSummary(*arg.Conv)
 		notif.Conv = &conv
 	}
-	d.printJSON(notif)
+	d.emitJSON(notif)
 	return nil
 }
 
@@ -50,6 +50,8 @@ type handlerTracker struct {
 	emojiAddAliasV1     int
 	emojiListV1         int
 	emojiRemoveV1       int
+	listenV1            int
+	broadcastV1         int
 }
 
 func (h *handlerTracker) ListV1(context.Context, Call, io.Writer) error {
@@ -225,6 +227,16 @@ func (h *handlerTracker) EmojiRemoveV1(context.Context, Call, io.Writer) error {
 	return nil
 }
 
+func (h *handlerTracker) ListenV1(context.Context, Call, io.Writer) error {
+	h.listenV1++
+	return nil
+}
+
+func (h *handlerTracker) BroadcastV1(context.Context, Call, io.Writer) error {
+	h.broadcastV1++
+	return nil
+}
+
 type echoResult struct {
 	Status string `json:"status"`
 }
@@ -375,6 +387,14 @@ func (c *chatEcho) EmojiRemoveV1(context.Context, emojiRemoveOptionsV1) Reply {
 	return Reply{Result: echoOK}
 }
 
+func (c *chatEcho) ListenV1(context.Context, listenOptionsV1, io.Writer) Reply {
+	return Reply{Result: echoOK}
+}
+
+func (c *chatEcho) BroadcastV1(context.Context, broadcastOptionsV1) Reply {
+	return Reply{Result: echoOK}
+}
+
 type topTest struct {
 	input               string
 	output              string
@@ -399,24 +419,26 @@ type topTest struct {
 	unpinV1             int
 	getDeviceInfoV1     int
 	listMembersV1       int
+	listenV1            int
+	broadcastV1         int
 }
 
 var topTests = []topTest{
 	{
 		input:  `{}`,
-		output: `{"error":{"code":0,"message":"invalid v1 method \"\""}}`,
+		output: `{"error":{"code":103,"message":"invalid v1 method \"\""}}`,
 	},
 	{
 		input:  `{"params":{"version": 2}}`,
-		output: `{"error":{"code":0,"message":"invalid version 2"}}`,
+		output: `{"error":{"code":102,"message":"invalid version 2"}}`,
 	},
 	{
 		input:  `{"params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid v1 method \"\""}}`,
+		output: `{"error":{"code":103,"message":"invalid v1 method \"\""}}`,
 	},
 	{
 		input:  `{"method": "xxx", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid v1 method \"xxx\""}}`,
+		output: `{"error":{"code":103,"message":"invalid v1 method \"xxx\""}}`,
 	},
 	{input: `{"method": "list", "params":{"version": 1}}`, listV1: 1},
 	{input: `{"method": "read", "params":{"version": 1}}`, readV1: 1},
@@ -444,6 +466,8 @@ var topTests = []topTest{
 	{input: `{"id": 39, "method": "unpin", "params":{"version": 1}}`, unpinV1: 1},
 	{input: `{"id": 39, "method": "getdeviceinfo", "params":{"version": 1}}`, getDeviceInfoV1: 1},
 	{input: `{"id": 39, "method": "listmembers", "params":{"version": 1}}`, listMembersV1: 1},
+	{input: `{"id": 39, "method": "listen", "params":{"version": 1}}`, listenV1: 1},
+	{input: `{"id": 39, "method": "broadcast", "params":{"version": 1}}`, broadcastV1: 1},
 }
 
 // TestChatAPIVersionHandlerTop tests that the "top-level" of the chat json makes it to
@@ -529,6 +553,14 @@ func TestChatAPIVersionHandlerTop(t *testing.T) {
 			t.Errorf("test %d: input %s => listMembersV1 = %d, expected %d",
 				i, test.input, h.listMembersV1, test.listMembersV1)
 		}
+		if h.listenV1 != test.listenV1 {
+			t.Errorf("test %d: input %s => listenV1 = %d, expected %d",
+				i, test.input, h.listenV1, test.listenV1)
+		}
+		if h.broadcastV1 != test.broadcastV1 {
+			t.Errorf("test %d: input %s => broadcastV1 = %d, expected %d",
+				i, test.input, h.broadcastV1, test.broadcastV1)
+		}
 		if strings.TrimSpace(buf.String()) != strings.TrimSpace(test.output) {
 			t.Errorf("test %d: input %s => output %s, expected %s", i, test.input, strings.TrimSpace(buf.String()), strings.TrimSpace(test.output))
 		}
@@ -548,39 +580,39 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "list", "params":{"version": 1, "options": {"topic_type": "boozle"}}}`,
-		output: `{"error":{"code":0,"message":"invalid list v1 options: invalid topic type: 'boozle'"}}`,
+		output: `{"error":{"code":104,"message":"invalid list v1 options: invalid topic type: 'boozle'"}}`,
 	},
 	{
 		input:  `{"method": "read", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid read v1 options: empty options"}}`,
+		output: `{"error":{"code":104,"message":"invalid read v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"method": "read", "params":{"version": 1, "options": {}}}`,
-		output: `{"error":{"code":0,"message":"invalid read v1 options: need channel or conversation_id"}}`,
+		output: `{"error":{"code":104,"message":"invalid read v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"method": "read", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "conversation_id": "999111"}}}`,
-		output: `{"error":{"code":0,"message":"invalid read v1 options: include channel or conversation_id, not both"}}`,
+		output: `{"error":{"code":104,"message":"invalid read v1 options: include channel or conversation_id, not both"}}`,
 	},
 	{
 		input:  `{"method": "send", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid send v1 options: empty options"}}`,
+		output: `{"error":{"code":104,"message":"invalid send v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"method": "send", "params":{"version": 1, "options": {} }}`,
-		output: `{"error":{"code":0,"message":"invalid send v1 options: need channel or conversation_id"}}`,
+		output: `{"error":{"code":104,"message":"invalid send v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"method": "send", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid send v1 options: invalid message, body cannot be empty"}}`,
+		output: `{"error":{"code":104,"message":"invalid send v1 options: invalid message, body cannot be empty"}}`,
 	},
 	{
 		input:  `{"method": "send", "params":{"version": 1, "options": {"conversation_id": "222", "channel": {"name": "alice,bob"}, "message": {"body": "hi"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid send v1 options: include channel or conversation_id, not both"}}`,
+		output: `{"error":{"code":104,"message":"invalid send v1 options: include channel or conversation_id, not both"}}`,
 	},
 	{
 		input:  `{"method": "send", "params":{"version": 1, "options": {"conversation_id": "123", "message": {"body": "hi"}, "exploding_lifetime": "1s"}}}`,
-		output: fmt.Sprintf(`{"error":{"code":0,"message":"invalid send v1 options: invalid ephemeral lifetime: %v, must be between %v and %v"}}`, "1s", libkb.MaxEphemeralContentLifetime, libkb.MinEphemeralContentLifetime),
+		output: fmt.Sprintf(`{"error":{"code":104,"message":"invalid send v1 options: invalid ephemeral lifetime: %v, must be between %v and %v"}}`, "1s", libkb.MaxEphemeralContentLifetime, libkb.MinEphemeralContentLifetime),
 	},
 	{
 		input:  `{"method": "list", "params":{"version": 1}}{"method": "list", "params":{"version": 1}}`,
@@ -596,11 +628,11 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "read", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}}}`,
-		output: `{"error":{"code":0,"message":"invalid JSON: expected more JSON in input"}}`,
+		output: `{"error":{"code":101,"message":"invalid JSON: expected more JSON in input"}}`,
 	},
 	{
 		input:  `{"method": "read", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"`,
-		output: `{"error":{"code":0,"message":"invalid JSON: expected more JSON in input"}}`,
+		output: `{"error":{"code":101,"message":"invalid JSON: expected more JSON in input"}}`,
 	},
 	{
 		input:  `{"method": "read", "params":{'version': 1, "options": {"channel": {"name": "alice,bob"}}}`,
@@ -612,51 +644,51 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"id": 29, "method": "edit", "params":{"version": 1}}`,
-		output: `{"id":29,"error":{"code":0,"message":"invalid edit v1 options: empty options"}}`,
+		output: `{"id":29,"error":{"code":104,"message":"invalid edit v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"id": 29, "method": "edit", "params":{"version": 1, "options": {}}}`,
-		output: `{"id":29,"error":{"code":0,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
+		output: `{"id":29,"error":{"code":104,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "edit", "params":{"version": 1, "options": {"message_id": 0}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "edit", "params":{"version": 1, "options": {"message_id": 19}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid edit v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "edit", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message_id": 123, "message": {"body": ""}}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid edit v1 options: invalid message, body cannot be empty"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid edit v1 options: invalid message, body cannot be empty"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "edit", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message": {"body": "edited"}}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid edit v1 options: invalid message id '0'"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid edit v1 options: invalid message id '0'"}}`,
 	},
 	{
 		input:  `{"id": 29, "method": "reaction", "params":{"version": 1}}`,
-		output: `{"id":29,"error":{"code":0,"message":"invalid reaction v1 options: empty options"}}`,
+		output: `{"id":29,"error":{"code":104,"message":"invalid reaction v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"id": 29, "method": "reaction", "params":{"version": 1, "options": {}}}`,
-		output: `{"id":29,"error":{"code":0,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
+		output: `{"id":29,"error":{"code":104,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "reaction", "params":{"version": 1, "options": {"message_id": 0}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "reaction", "params":{"version": 1, "options": {"message_id": 19}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid reaction v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "reaction", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message_id": 123, "message": {"body": ""}}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid reaction v1 options: invalid message, body cannot be empty"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid reaction v1 options: invalid message, body cannot be empty"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "reaction", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message": {"body": ":+1:"}}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid reaction v1 options: invalid message id '0'"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid reaction v1 options: invalid message id '0'"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "reaction", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message_id": 123, "message": {"body": ":+1:"}}}}`,
@@ -668,19 +700,19 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"id": 30, "method": "delete", "params":{"version": 1}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid delete v1 options: empty options"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid delete v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "delete", "params":{"version": 1, "options": {}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "delete", "params":{"version": 1, "options": {"message_id": 0}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "delete", "params":{"version": 1, "options": {"message_id": 19}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid delete v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "delete", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message_id": 123}}}`,
@@ -696,15 +728,15 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "attach", "params":{"options": {"channel": {"name": "alice,bob"}, "filename": "photo.png", "exploding_lifetime": "1s"}}}`,
-		output: fmt.Sprintf(`{"error":{"code":0,"message":"invalid attach v1 options: invalid ephemeral lifetime: %v, must be between %v and %v"}}`, "1s", libkb.MaxEphemeralContentLifetime, libkb.MinEphemeralContentLifetime),
+		output: fmt.Sprintf(`{"error":{"code":104,"message":"invalid attach v1 options: invalid ephemeral lifetime: %v, must be between %v and %v"}}`, "1s", libkb.MaxEphemeralContentLifetime, libkb.MinEphemeralContentLifetime),
 	},
 	{
 		input:  `{"method": "attach", "params":{"options": {"filename": "photo.png"}}}`,
-		output: `{"error":{"code":0,"message":"invalid attach v1 options: need channel or conversation_id"}}`,
+		output: `{"error":{"code":104,"message":"invalid attach v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"method": "attach", "params":{"options": {"channel": {"name": "alice,bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid attach v1 options: empty filename"}}`,
+		output: `{"error":{"code":104,"message":"invalid attach v1 options: empty filename"}}`,
 	},
 	{
 		input:  `{"method": "download", "params":{"version": 1, "options": {"message_id": 34, "channel": {"name": "a123,nfnf,t_bob"}, "output": "/tmp/file"}}}`,
@@ -716,11 +748,11 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "setstatus", "params":{"version": 1, "options": {"channel": {"name": "a123,nfnf,t_bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid setstatus v1 options: unsupported status: ''"}}`,
+		output: `{"error":{"code":104,"message":"invalid setstatus v1 options: unsupported status: ''"}}`,
 	},
 	{
 		input:  `{"method": "setstatus", "params":{"version": 1, "options": {"status": "ONTARIO", "channel": {"name": "a123,nfnf,t_bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid setstatus v1 options: unsupported status: 'ONTARIO'"}}`,
+		output: `{"error":{"code":104,"message":"invalid setstatus v1 options: unsupported status: 'ONTARIO'"}}`,
 	},
 	{
 		input:  `{"method": "setstatus", "params":{"version": 1, "options": {"status": "ignored", "channel": {"name": "a123,nfnf,t_bob"}}}}`,
@@ -728,15 +760,15 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"id": 30, "method": "mark", "params":{"version": 1}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid mark v1 options: empty options"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid mark v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "mark", "params":{"version": 1, "options": {"message_id": 0}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid mark v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid mark v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "mark", "params":{"version": 1, "options": {"message_id": 19}}}`,
-		output: `{"id":30,"error":{"code":0,"message":"invalid mark v1 options: need channel or conversation_id"}}`,
+		output: `{"id":30,"error":{"code":104,"message":"invalid mark v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"id": 30, "method": "mark", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}, "message_id": 123}}}`,
@@ -744,7 +776,7 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "join", "params":{"version": 1, "options": {} }}`,
-		output: `{"error":{"code":0,"message":"invalid newconv v1 options: need channel or conversation_id"}}`,
+		output: `{"error":{"code":104,"message":"invalid newconv v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"method": "join", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}}}}`,
@@ -756,11 +788,11 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "join", "params":{"version": 1, "options": {"conversation_id": "222", "channel": {"name": "alice,bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid newconv v1 options: include channel or conversation_id, not both"}}`,
+		output: `{"error":{"code":104,"message":"invalid newconv v1 options: include channel or conversation_id, not both"}}`,
 	},
 	{
 		input:  `{"method": "leave", "params":{"version": 1, "options": {} }}`,
-		output: `{"error":{"code":0,"message":"invalid newconv v1 options: need channel or conversation_id"}}`,
+		output: `{"error":{"code":104,"message":"invalid newconv v1 options: need channel or conversation_id"}}`,
 	},
 	{
 		input:  `{"method": "leave", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}}}}`,
@@ -772,11 +804,11 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "leave", "params":{"version": 1, "options": {"conversation_id": "222", "channel": {"name": "alice,bob"}}}}`,
-		output: `{"error":{"code":0,"message":"invalid newconv v1 options: include channel or conversation_id, not both"}}`,
+		output: `{"error":{"code":104,"message":"invalid newconv v1 options: include channel or conversation_id, not both"}}`,
 	},
 	{
 		input:  `{"method": "listconvsonname", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid listconvsonname v1 options: empty options"}}`,
+		output: `{"error":{"code":104,"message":"invalid listconvsonname v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"method": "listconvsonname", "params":{"version": 1, "options": {"name": "alice,bob"}}}`,
@@ -784,7 +816,7 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "pin", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid pin v1 options: empty options"}}`,
+		output: `{"error":{"code":104,"message":"invalid pin v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"method": "pin", "params":{"version": 1, "options": {"channel": {"name": "alice,bob", "message_id": 1}}}}`,
@@ -792,7 +824,7 @@ var optTests = []optTest{
 	},
 	{
 		input:  `{"method": "unpin", "params":{"version": 1}}`,
-		output: `{"error":{"code":0,"message":"invalid unpin v1 options: empty options"}}`,
+		output: `{"error":{"code":104,"message":"invalid unpin v1 options: empty options"}}`,
 	},
 	{
 		input:  `{"method": "unpin", "params":{"version": 1, "options": {"channel": {"name": "alice,bob"}}}}`,
@@ -100,6 +100,10 @@ func (d *ChatAPIVersionHandler) handleV1(ctx context.Context, c Call, w io.Write
 		return d.handler.EmojiListV1(ctx, c, w)
 	case methodEmojiRemove:
 		return d.handler.EmojiRemoveV1(ctx, c, w)
+	case methodListen:
+		return d.handler.ListenV1(ctx, c, w)
+	case methodBroadcast:
+		return d.handler.BroadcastV1(ctx, c, w)
 	default:
 		return ErrInvalidMethod{name: c.Method, version: 1}
 	}
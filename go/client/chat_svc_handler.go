@@ -1,8 +1,10 @@
 package client
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -55,6 +57,8 @@ type ChatServiceHandler interface {
 	EmojiAddAliasV1(context.Context, emojiAddAliasOptionsV1) Reply
 	EmojiRemoveV1(context.Context, emojiRemoveOptionsV1) Reply
 	EmojiListV1(context.Context) Reply
+	ListenV1(context.Context, listenOptionsV1, io.Writer) Reply
+	BroadcastV1(context.Context, broadcastOptionsV1) Reply
 }
 
 // chatServiceHandler implements ChatServiceHandler.
@@ -617,6 +621,19 @@ func (c *chatServiceHandler) formatMessages(ctx context.Context, messages []chat
 	return ret, nil
 }
 
+// defaultReadPageSize is the page size used for cursor-mode reads when
+// the caller doesn't specify one.
+const defaultReadPageSize = 100
+
+// readResultWithCursor wraps the thread output of a cursor-mode read
+// with an opaque cursor for fetching the next (older) page, so callers
+// don't have to thumb through the raw pagination object.
+type readResultWithCursor struct {
+	chat1.Thread
+	NextCursor string `json:"next_cursor,omitempty"`
+	Last       bool   `json:"last,omitempty"`
+}
+
 // ReadV1 implements ChatServiceHandler.ReadV1.
 func (c *chatServiceHandler) ReadV1(ctx context.Context, opts readOptionsV1) Reply {
 	var rlimits []chat1.RateLimit
@@ -630,9 +647,26 @@ func (c *chatServiceHandler) ReadV1(ctx context.Context, opts readOptionsV1) Rep
 		return c.errReply(err)
 	}
 
+	pagination := opts.Pagination
+	cursorMode := len(opts.Cursor) > 0 || opts.PageSize > 0
+	if cursorMode {
+		pagination = &chat1.Pagination{Num: opts.PageSize}
+		if pagination.Num == 0 {
+			pagination.Num = defaultReadPageSize
+		}
+		if len(opts.Cursor) > 0 {
+			next, err := base64.URLEncoding.DecodeString(opts.Cursor)
+			if err != nil {
+				return c.errReply(ErrInvalidOptions{version: 1, method: methodRead,
+					err: fmt.Errorf("bad cursor: %v", err)})
+			}
+			pagination.Next = next
+		}
+	}
+
 	arg := chat1.GetThreadLocalArg{
 		ConversationID: conv.Info.Id,
-		Pagination:     opts.Pagination,
+		Pagination:     pagination,
 		Query: &chat1.GetThreadQuery{
 			MarkAsRead: !opts.Peek,
 		},
@@ -671,6 +705,16 @@ func (c *chatServiceHandler) ReadV1(ctx context.Context, opts readOptionsV1) Rep
 	}
 
 	thread.RateLimits = c.aggRateLimits(rlimits)
+	if cursorMode {
+		res := readResultWithCursor{Thread: thread}
+		if p := threadView.Thread.Pagination; p != nil {
+			res.Last = p.Last
+			if !p.Last && len(p.Next) > 0 {
+				res.NextCursor = base64.URLEncoding.EncodeToString(p.Next)
+			}
+		}
+		return Reply{Result: res}
+	}
 	return Reply{Result: thread}
 }
 
@@ -1459,6 +1503,182 @@ func (c *chatServiceHandler) EmojiListV1(ctx context.Context) Reply {
 	return Reply{Result: res}
 }
 
+// listenResultV1 is the final reply of a listen call, after the matched
+// notifications have been streamed to the output.
+type listenResultV1 struct {
+	Delivered int    `json:"delivered"`
+	Reason    string `json:"reason"`
+}
+
+// ListenV1 implements ChatServiceHandler.ListenV1. It streams incoming
+// chat notifications matching the filters to w as JSON lines, then
+// returns once max_messages have been delivered, timeout_seconds have
+// elapsed, or the context is canceled. With neither bound given it
+// behaves as a classic long poll and returns after the first match.
+func (c *chatServiceHandler) ListenV1(ctx context.Context, opts listenOptionsV1, w io.Writer) Reply {
+	display := newChatNotificationDisplay(c.G(), chatNotificationConfig{
+		showLocal:     opts.ShowLocal,
+		hideExploding: opts.HideExploding,
+	})
+	display.output = w
+	deliveries := make(chan struct{}, 64)
+	display.deliveries = deliveries
+	if opts.Channel.Valid() || len(opts.ConversationID) > 0 {
+		conv, _, err := c.findConversation(ctx, opts.ConversationID, opts.Channel)
+		if err != nil {
+			return c.errReply(err)
+		}
+		display.filtersNormalized = []chat1.ConversationID{conv.GetConvID()}
+	}
+	for _, typ := range opts.MessageTypes {
+		display.typeFilters = append(display.typeFilters,
+			chat1.MessageTypeMap[strings.ToUpper(typ)])
+	}
+
+	if err := RegisterProtocolsWithContext([]rpc.Protocol{
+		chat1.NotifyChatProtocol(display),
+	}, c.G()); err != nil {
+		return c.errReply(err)
+	}
+	cli, err := GetNotifyCtlClient(c.G())
+	if err != nil {
+		return c.errReply(err)
+	}
+	if err := cli.SetNotifications(ctx, keybase1.NotificationChannels{Chat: true}); err != nil {
+		return c.errReply(err)
+	}
+	defer func() {
+		_ = cli.SetNotifications(context.Background(), keybase1.NotificationChannels{})
+	}()
+
+	maxMessages := opts.MaxMessages
+	if maxMessages == 0 && opts.TimeoutSeconds == 0 {
+		maxMessages = 1
+	}
+	var timerCh <-chan time.Time
+	if opts.TimeoutSeconds > 0 {
+		timer := time.NewTimer(time.Duration(opts.TimeoutSeconds) * time.Second)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+	var result listenResultV1
+	for {
+		select {
+		case <-deliveries:
+			result.Delivered++
+			if maxMessages > 0 && result.Delivered >= maxMessages {
+				result.Reason = "max_messages"
+				return Reply{Result: result}
+			}
+		case <-timerCh:
+			result.Reason = "timeout"
+			return Reply{Result: result}
+		case <-ctx.Done():
+			result.Reason = "canceled"
+			return Reply{Result: result}
+		}
+	}
+}
+
+type broadcastTargetResultV1 struct {
+	ConversationID chat1.ConvIDStr  `json:"conversation_id"`
+	MessageID      *chat1.MessageID `json:"message_id,omitempty"`
+	Error          string           `json:"error,omitempty"`
+}
+
+type broadcastResultV1 struct {
+	BroadcastID string                    `json:"broadcast_id,omitempty"`
+	Targets     []broadcastTargetResultV1 `json:"targets"`
+	RateLimits  []chat1.RateLimitRes      `json:"ratelimits,omitempty"`
+}
+
+// BroadcastV1 implements ChatServiceHandler.BroadcastV1. It posts the
+// same message to every target, one at a time, reporting the outcome
+// per target. Failing one target doesn't abort the rest, with one
+// exception: once a send runs out of rate limit gas the remaining
+// targets are skipped rather than burned against the limiter.
+func (c *chatServiceHandler) BroadcastV1(ctx context.Context, opts broadcastOptionsV1) Reply {
+	action := opts.action()
+	var res broadcastResultV1
+	if opts.Link && action == broadcastActionSend {
+		broadcastID, err := libkb.RandHexString("", 8)
+		if err != nil {
+			return c.errReply(err)
+		}
+		res.BroadcastID = broadcastID
+	}
+
+	limitsByTank := make(map[string]chat1.RateLimitRes)
+	mergeLimits := func(limits []chat1.RateLimitRes) {
+		for _, limit := range limits {
+			limitsByTank[limit.Tank] = limit
+		}
+	}
+	outOfGas := false
+	for _, target := range opts.Targets {
+		tres := broadcastTargetResultV1{ConversationID: target.ConversationID}
+		if outOfGas {
+			tres.Error = "skipped: rate limited"
+			res.Targets = append(res.Targets, tres)
+			continue
+		}
+
+		convID, limits, err := c.resolveAPIConvID(ctx, target.ConversationID, target.Channel)
+		mergeLimits(c.aggRateLimits(limits))
+		if err != nil {
+			tres.Error = err.Error()
+			res.Targets = append(res.Targets, tres)
+			continue
+		}
+		tres.ConversationID = convID.ConvIDStr()
+
+		arg := sendArgV1{
+			conversationID: convID,
+			channel:        target.Channel,
+		}
+		switch action {
+		case broadcastActionSend:
+			arg.body = chat1.NewMessageBodyWithText(chat1.MessageText{Body: opts.Message.Body})
+			arg.mtype = chat1.MessageType_TEXT
+			arg.response = "message sent"
+		case broadcastActionEdit:
+			arg.body = chat1.NewMessageBodyWithEdit(chat1.MessageEdit{MessageID: target.MessageID, Body: opts.Message.Body})
+			arg.mtype = chat1.MessageType_EDIT
+			arg.supersedes = target.MessageID
+			arg.response = "message edited"
+		case broadcastActionDelete:
+			messages := []chat1.MessageID{target.MessageID}
+			arg.body = chat1.NewMessageBodyWithDelete(chat1.MessageDelete{MessageIDs: messages})
+			arg.mtype = chat1.MessageType_DELETE
+			arg.supersedes = target.MessageID
+			arg.deletes = messages
+			arg.response = "message deleted"
+		}
+
+		reply := c.sendV1(ctx, arg, utils.DummyChatUI{})
+		if reply.Error != nil {
+			tres.Error = reply.Error.Message
+			if reply.Error.Code == callErrorCodeRateLimited {
+				outOfGas = true
+			}
+		} else if sendRes, ok := reply.Result.(chat1.SendRes); ok {
+			tres.MessageID = sendRes.MessageID
+			mergeLimits(sendRes.RateLimits)
+			for _, limit := range sendRes.RateLimits {
+				if limit.Gas <= 0 {
+					outOfGas = true
+				}
+			}
+		}
+		res.Targets = append(res.Targets, tres)
+	}
+
+	for _, limit := range limitsByTank {
+		res.RateLimits = append(res.RateLimits, limit)
+	}
+	return Reply{Result: res}
+}
+
 type postHeader struct {
 	conversationID chat1.ConversationID
 	clientHeader   chat1.MessageClientHeader
@@ -1675,9 +1895,13 @@ func (c *chatServiceHandler) convertMsgBody(mb chat1.MessageBody) chat1.MsgConte
 
 func (c *chatServiceHandler) errReply(err error) Reply {
 	if rlerr, ok := err.(libkb.ChatRateLimitError); ok {
-		return Reply{Error: &CallError{Message: err.Error(), Data: rlerr.RateLimit}}
+		return Reply{Error: &CallError{Code: callErrorCodeRateLimited, Message: err.Error(), Data: rlerr.RateLimit}}
+	}
+	code := callErrorCode(err)
+	if _, ok := err.(chat.OfflineError); ok {
+		code = callErrorCodeOffline
 	}
-	return Reply{Error: &CallError{Message: err.Error()}}
+	return Reply{Error: &CallError{Code: code, Message: err.Error()}}
 }
 
 func (c *chatServiceHandler) aggRateLimits(rlimits []chat1.RateLimit) (res []chat1.RateLimitRes) {
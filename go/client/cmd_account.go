@@ -21,6 +21,8 @@ func NewCmdAccount(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
 		NewCmdPhoneNumber(cl, g),
 		newCmdUploadAvatar(cl, g, false /* hidden */),
 		NewCmdAccountResetCancel(cl, g),
+		NewCmdAccountPendingResets(cl, g),
+		newCmdAccountMigrate(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificAccountCommands(cl, g)...)
 	sort.Sort(cli.ByName(subcommands))
@@ -0,0 +1,364 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// accountMigrationDir is the staging directory inside the shared private
+// folder between the old and new accounts. Everything placed there is
+// readable by both accounts and encrypted to both accounts' keys by KBFS,
+// which is exactly the re-encryption the handoff needs.
+const accountMigrationDir = ".account-migration"
+
+// migrationManifestFile describes what an export contains.
+const migrationManifestFile = "manifest.json"
+
+type migrationManifest struct {
+	OldUser string    `json:"oldUser"`
+	NewUser string    `json:"newUser"`
+	Convs   []string  `json:"convs"`
+	Folders []string  `json:"folders"`
+	Ctime   time.Time `json:"ctime"`
+}
+
+func newCmdAccountMigrate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "migrate",
+		Usage: "Hand conversations and folders off to a new account",
+		Subcommands: []cli.Command{
+			newCmdAccountMigrateExport(cl, g),
+			newCmdAccountMigrateImport(cl, g),
+			newCmdAccountMigrateAnnounce(cl, g),
+		},
+	}
+}
+
+// migrationResolvingRequest builds a resolver request from a conversation
+// name as typed on the command line, with an optional #channel suffix.
+func migrationResolvingRequest(convName string) chatConversationResolvingRequest {
+	name, topic := convName, ""
+	if idx := strings.Index(name, "#"); idx >= 0 {
+		name, topic = name[:idx], name[idx+1:]
+	}
+	return chatConversationResolvingRequest{
+		TlfName:    name,
+		TopicName:  topic,
+		TopicType:  chat1.TopicType_CHAT,
+		Visibility: keybase1.TLFVisibility_ANY,
+	}
+}
+
+// migrationStagingPath is a path inside the old/new shared folder.
+func migrationStagingPath(userA, userB, elem string) keybase1.Path {
+	p := fmt.Sprintf("/private/%s,%s/%s", userA, userB, accountMigrationDir)
+	if elem != "" {
+		p += "/" + elem
+	}
+	return keybase1.NewPathWithKbfsPath(p)
+}
+
+// CmdAccountMigrateExport stages selected conversation history and KBFS
+// folders into the private folder shared with the new account. KBFS
+// re-encrypts everything to the new account's keys as a side effect of
+// the copy; nothing leaves Keybase unencrypted.
+type CmdAccountMigrateExport struct {
+	libkb.Contextified
+	newUser string
+	convs   []string
+	folders []string
+}
+
+func newCmdAccountMigrateExport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "export",
+		ArgumentHelp: "<new username>",
+		Usage:        "Stage conversations and folders for the new account",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountMigrateExport{Contextified: libkb.NewContextified(g)}, "export", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "conv",
+				Usage: "Conversation to export (repeatable)",
+			},
+			cli.StringSliceFlag{
+				Name:  "folder",
+				Usage: "KBFS path to copy, e.g. /keybase/private/me/docs (repeatable)",
+			},
+		},
+	}
+}
+
+func (c *CmdAccountMigrateExport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("new username required")
+	}
+	c.newUser = ctx.Args().Get(0)
+	c.convs = ctx.StringSlice("conv")
+	c.folders = ctx.StringSlice("folder")
+	if len(c.convs) == 0 && len(c.folders) == 0 {
+		return fmt.Errorf("nothing to export; pass --conv and/or --folder")
+	}
+	return nil
+}
+
+func (c *CmdAccountMigrateExport) Run() error {
+	ctx := context.TODO()
+	me := c.G().Env.GetUsername().String()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := teamTemplateMkdir(ctx, fsCli, migrationStagingPath(me, c.newUser, "")); err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+
+	for _, convName := range c.convs {
+		resolvingRequest := migrationResolvingRequest(convName)
+		if err = annotateResolvingRequest(c.G(), &resolvingRequest); err != nil {
+			return err
+		}
+		resolver, err := newChatConversationResolver(c.G())
+		if err != nil {
+			return err
+		}
+		conv, _, err := resolver.Resolve(ctx, resolvingRequest, chatConversationResolvingBehavior{
+			CreateIfNotExists: false,
+			MustNotExist:      false,
+			IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+		})
+		if err != nil {
+			return err
+		}
+		messages, err := exportConvMessages(c.G(), ctx, conv.Info.Id)
+		if err != nil {
+			return err
+		}
+		dat, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("conv-%s.json", strings.NewReplacer("/", "_", "#", "_", ",", "_").Replace(convName))
+		if err := teamTemplateWriteFile(ctx, fsCli,
+			migrationStagingPath(me, c.newUser, name), dat); err != nil {
+			return err
+		}
+		ui.Printf("Staged %d messages from %s\n", len(messages), convName)
+	}
+
+	for _, folder := range c.folders {
+		raw := strings.TrimPrefix(folder, "/keybase")
+		dest := migrationStagingPath(me, c.newUser, "folder-"+strings.ReplaceAll(strings.Trim(raw, "/"), "/", "_"))
+		if err := explodingShareCopy(ctx, fsCli,
+			keybase1.NewPathWithKbfsPath(raw), dest); err != nil {
+			return err
+		}
+		ui.Printf("Staged folder %s\n", folder)
+	}
+
+	manifest := migrationManifest{
+		OldUser: me,
+		NewUser: c.newUser,
+		Convs:   c.convs,
+		Folders: c.folders,
+		Ctime:   time.Now().UTC(),
+	}
+	dat, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := teamTemplateWriteFile(ctx, fsCli,
+		migrationStagingPath(me, c.newUser, migrationManifestFile), dat); err != nil {
+		return err
+	}
+	ui.Printf("Export staged. From the new account run `keybase account migrate import %s`.\n", me)
+	return nil
+}
+
+func (c *CmdAccountMigrateExport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdAccountMigrateImport is run by the new account: it copies staged data
+// from the shared folder into the new account's own private folder.
+type CmdAccountMigrateImport struct {
+	libkb.Contextified
+	oldUser string
+}
+
+func newCmdAccountMigrateImport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "import",
+		ArgumentHelp: "<old username>",
+		Usage:        "Pull a staged migration into this account's private folder",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountMigrateImport{Contextified: libkb.NewContextified(g)}, "import", c)
+		},
+	}
+}
+
+func (c *CmdAccountMigrateImport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("old username required")
+	}
+	c.oldUser = ctx.Args().Get(0)
+	return nil
+}
+
+func (c *CmdAccountMigrateImport) Run() error {
+	ctx := context.TODO()
+	me := c.G().Env.GetUsername().String()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	dat, err := teamTemplateReadFile(ctx, fsCli,
+		migrationStagingPath(c.oldUser, me, migrationManifestFile))
+	if err != nil {
+		return fmt.Errorf("no staged migration from %s: %s", c.oldUser, err)
+	}
+	var manifest migrationManifest
+	if err := json.Unmarshal(dat, &manifest); err != nil {
+		return err
+	}
+	if manifest.NewUser != me {
+		return fmt.Errorf("staged migration is addressed to %s, not %s", manifest.NewUser, me)
+	}
+	dest := keybase1.NewPathWithKbfsPath(fmt.Sprintf("/private/%s/migrated-from-%s", me, c.oldUser))
+	if err := explodingShareCopy(ctx, fsCli,
+		migrationStagingPath(c.oldUser, me, ""), dest); err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Imported %d conversation export(s) and %d folder(s) to %s\n",
+		len(manifest.Convs), len(manifest.Folders), dest.Kbfs().Path)
+	return nil
+}
+
+func (c *CmdAccountMigrateImport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdAccountMigrateAnnounce links the identities and notifies contacts:
+// following the new account puts a signed statement in the old account's
+// sigchain that anyone verifying the old identity will see, and a chat
+// notice goes to the most recent conversations.
+type CmdAccountMigrateAnnounce struct {
+	libkb.Contextified
+	newUser  string
+	numConvs int
+}
+
+func newCmdAccountMigrateAnnounce(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "announce",
+		ArgumentHelp: "<new username>",
+		Usage:        "Sign a follow of the new account and notify recent conversations",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountMigrateAnnounce{Contextified: libkb.NewContextified(g)}, "announce", c)
+		},
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "num-convs",
+				Usage: "How many recent conversations to notify",
+				Value: 10,
+			},
+		},
+	}
+}
+
+func (c *CmdAccountMigrateAnnounce) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("new username required")
+	}
+	c.newUser = ctx.Args().Get(0)
+	c.numConvs = ctx.Int("num-convs")
+	return nil
+}
+
+func (c *CmdAccountMigrateAnnounce) Run() error {
+	ctx := context.TODO()
+
+	// Sign the link between identities into the sigchain.
+	trackCli, err := GetTrackClient(c.G())
+	if err != nil {
+		return err
+	}
+	protocols := []rpc.Protocol{
+		NewIdentifyTrackUIProtocol(c.G()),
+		NewSecretUIProtocol(c.G()),
+	}
+	if err = RegisterProtocolsWithContext(protocols, c.G()); err != nil {
+		return err
+	}
+	if _, err = trackCli.Track(ctx, keybase1.TrackArg{
+		UserAssertion: c.newUser,
+	}); err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Signed a follow of %s into your sigchain.\n", c.newUser)
+
+	// Notify the most recently active conversations.
+	fetcher := chatCLIInboxFetcher{query: chat1.GetInboxSummaryForCLILocalQuery{
+		TopicType:           chat1.TopicType_CHAT,
+		ActivitySortedLimit: c.numConvs,
+	}}
+	convs, err := fetcher.fetch(ctx, c.G())
+	if err != nil {
+		return err
+	}
+	notice := fmt.Sprintf("I'm migrating accounts: @%s is my new identity (see the signed follow on my profile).", c.newUser)
+	var notified int
+	for _, conv := range convs {
+		if err := chatSend(ctx, c.G(), ChatSendArg{
+			resolvingRequest: chatConversationResolvingRequest{
+				TlfName:     conv.Info.TlfName,
+				TopicName:   conv.Info.TopicName,
+				TopicType:   chat1.TopicType_CHAT,
+				Visibility:  conv.Info.Visibility,
+				MembersType: conv.Info.MembersType,
+			},
+			message: notice,
+		}); err != nil {
+			ui.Printf("Warning: couldn't notify %s: %s\n", conv.Info.TlfName, err)
+			continue
+		}
+		notified++
+	}
+	ui.Printf("Notified %d conversation(s).\n", notified)
+	return nil
+}
+
+func (c *CmdAccountMigrateAnnounce) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// pendingReset is one teammate whose account is in (or through) the reset
+// pipeline, as seen from one of the caller's teams.
+type pendingReset struct {
+	Team       string `json:"team"`
+	Username   string `json:"username"`
+	Status     string `json:"status"`
+	Role       string `json:"role"`
+	UnlockTime string `json:"unlock_time,omitempty"`
+}
+
+// CmdAccountPendingResets surfaces teammates affected by account resets, so
+// admins can re-approve or remove them before a reset completion surprises
+// the team. Team membership status comes from the service; unlock times for
+// in-flight time-delayed resets come from the server's autoreset pipeline
+// when it reports them, and are omitted otherwise.
+type CmdAccountPendingResets struct {
+	libkb.Contextified
+	json bool
+}
+
+func NewCmdAccountPendingResets(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "pending-resets",
+		Usage: "List account resets affecting your teams",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountPendingResets{Contextified: libkb.NewContextified(g)}, "pending-resets", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON",
+			},
+		},
+	}
+}
+
+func (c *CmdAccountPendingResets) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("pending-resets takes no arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+// fetchUnlockTimes asks the server's autoreset pipeline for unlock times of
+// the given users' in-flight resets. Older servers don't implement the
+// endpoint; any failure just means no unlock times are shown.
+func (c *CmdAccountPendingResets) fetchUnlockTimes(ctx context.Context) map[string]string {
+	res := make(map[string]string)
+	cli, err := GetAPIServerClient(c.G())
+	if err != nil {
+		return res
+	}
+	apiRes, err := cli.GetWithSession(ctx, keybase1.GetWithSessionArg{
+		Endpoint: "autoreset/pending_for_teams",
+	})
+	if err != nil {
+		return res
+	}
+	var body struct {
+		Resets []struct {
+			Username   string `json:"username"`
+			UnlockTime int64  `json:"unlock_time"`
+		} `json:"resets"`
+	}
+	if err := json.Unmarshal([]byte(apiRes.Body), &body); err != nil {
+		return res
+	}
+	for _, reset := range body.Resets {
+		if reset.UnlockTime > 0 {
+			res[reset.Username] = time.Unix(reset.UnlockTime, 0).Format(time.RFC3339)
+		}
+	}
+	return res
+}
+
+func (c *CmdAccountPendingResets) Run() error {
+	ctx := context.Background()
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	list, err := cli.TeamListTeammates(ctx, keybase1.TeamListTeammatesArg{})
+	if err != nil {
+		return err
+	}
+	unlockTimes := c.fetchUnlockTimes(ctx)
+	var resets []pendingReset
+	for _, member := range list.Teams {
+		if member.Status == keybase1.TeamMemberStatus_ACTIVE {
+			continue
+		}
+		resets = append(resets, pendingReset{
+			Team:       member.FqName,
+			Username:   member.Username,
+			Status:     member.Status.String(),
+			Role:       member.Role.String(),
+			UnlockTime: unlockTimes[member.Username],
+		})
+	}
+	sort.Slice(resets, func(i, j int) bool {
+		if resets[i].Team != resets[j].Team {
+			return resets[i].Team < resets[j].Team
+		}
+		return resets[i].Username < resets[j].Username
+	})
+
+	ui := c.G().UI.GetTerminalUI()
+	if c.json {
+		dat, err := json.MarshalIndent(resets, "", "  ")
+		if err != nil {
+			return err
+		}
+		ui.Printf("%s\n", dat)
+		return nil
+	}
+	if len(resets) == 0 {
+		ui.Printf("No pending resets affect your teams.\n")
+		return nil
+	}
+	for _, reset := range resets {
+		ui.Printf("%s: %s (%s, was %s)", reset.Team, reset.Username, reset.Status, reset.Role)
+		if reset.UnlockTime != "" {
+			ui.Printf(", unlocks %s", reset.UnlockTime)
+		}
+		ui.Printf("\n")
+	}
+	ui.Printf("\nTo re-approve a reset member: keybase team add-member <team> -u <username> -r <role>\n")
+	ui.Printf("To remove one: keybase team remove-member <team> -u <username>\n")
+	return nil
+}
+
+func (c *CmdAccountPendingResets) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -27,6 +27,7 @@ func newCmdBotToken(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comm
 		newCmdBotTokenCreate(cl, g),
 		newCmdBotTokenList(cl, g),
 		newCmdBotTokenDelete(cl, g),
+		newCmdBotTokenRotate(cl, g),
 	}
 	return cli.Command{
 		Name:        "token",
@@ -7,6 +7,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/keybase/cli"
+	bot "github.com/keybase/client/go/bot"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
 )
@@ -18,13 +19,24 @@ func newCmdBotTokenCreate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cl
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdBotTokenCreateRunner(g), "create", c)
 		},
-		Flags: []cli.Flag{},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "name",
+				Usage: "Name the token so it can be scoped and rotated.",
+			},
+			cli.StringSliceFlag{
+				Name:  "scope",
+				Usage: "Limit what the token may do: full, read-only, or send:<conversation>. Repeatable; requires --name.",
+			},
+		},
 	}
 	return cmd
 }
 
 type CmdBotTokenCreate struct {
 	libkb.Contextified
+	name   string
+	scopes []string
 }
 
 func NewCmdBotTokenCreateRunner(g *libkb.GlobalContext) *CmdBotTokenCreate {
@@ -38,19 +50,39 @@ func (t *CmdBotTokenCreate) ParseArgv(ctx *cli.Context) (err error) {
 	if nargs != 0 {
 		return BadArgsError{"bot token create takes no arguments"}
 	}
+	t.name = ctx.String("name")
+	for _, s := range ctx.StringSlice("scope") {
+		scope, err := bot.ParseTokenScope(s)
+		if err != nil {
+			return err
+		}
+		t.scopes = append(t.scopes, string(scope))
+	}
+	if len(t.scopes) > 0 && t.name == "" {
+		return BadArgsError{"--scope requires --name"}
+	}
 	return nil
 }
 
 func (t *CmdBotTokenCreate) Run() (err error) {
-
+	ctx := context.TODO()
 	bcli, err := GetBotClient(t.G())
 	if err != nil {
 		return err
 	}
-	res, err := bcli.BotTokenCreate(context.TODO())
+	res, err := bcli.BotTokenCreate(ctx)
 	if err != nil {
 		return err
 	}
+	if t.name != "" {
+		scopes := t.scopes
+		if len(scopes) == 0 {
+			scopes = []string{string(bot.ScopeFull)}
+		}
+		if err := botTokenScopesSet(t.G(), ctx, res, t.name, scopes); err != nil {
+			return err
+		}
+	}
 	_ = t.G().UI.GetTerminalUI().Output(res.String() + "\n")
 	return nil
 }
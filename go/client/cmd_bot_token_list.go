@@ -4,11 +4,15 @@
 package client
 
 import (
+	"fmt"
+	"strings"
+
 	"golang.org/x/net/context"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
 )
 
 func newCmdBotTokenList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
@@ -47,12 +51,21 @@ func (t *CmdBotTokenList) Run() (err error) {
 	if err != nil {
 		return err
 	}
-	res, err := bcli.BotTokenList(context.TODO())
+	ctx := context.TODO()
+	res, err := bcli.BotTokenList(ctx)
 	if err != nil {
 		return err
 	}
+	annotations := make(map[keybase1.BotToken]botTokenScopeRow)
+	for _, row := range botTokenScopesFetch(t.G(), ctx) {
+		annotations[row.Token] = row
+	}
 	for _, row := range res {
-		_ = t.G().UI.GetTerminalUI().Output(row.Token.String() + "\n")
+		line := row.Token.String()
+		if ann, found := annotations[row.Token]; found {
+			line += fmt.Sprintf("\t%s\t%s", ann.Name, strings.Join(ann.Scopes, ","))
+		}
+		_ = t.G().UI.GetTerminalUI().Output(line + "\n")
 	}
 	return nil
 }
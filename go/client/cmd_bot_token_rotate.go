@@ -0,0 +1,139 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// botTokenScopeRow mirrors the server's scope metadata for one bot token.
+type botTokenScopeRow struct {
+	Token  keybase1.BotToken `json:"bot_token"`
+	Name   string            `json:"name"`
+	Scopes []string          `json:"scopes"`
+}
+
+// botTokenScopesFetch returns scope metadata for the caller's bot tokens.
+// Older servers don't implement the endpoint; any failure just means no
+// tokens are annotated.
+func botTokenScopesFetch(g *libkb.GlobalContext, ctx context.Context) []botTokenScopeRow {
+	cli, err := GetAPIServerClient(g)
+	if err != nil {
+		return nil
+	}
+	apiRes, err := cli.GetWithSession(ctx, keybase1.GetWithSessionArg{
+		Endpoint: "bot/token/scopes",
+	})
+	if err != nil {
+		return nil
+	}
+	var body struct {
+		Tokens []botTokenScopeRow `json:"bot_token_scopes"`
+	}
+	if err := json.Unmarshal([]byte(apiRes.Body), &body); err != nil {
+		return nil
+	}
+	return body.Tokens
+}
+
+// botTokenScopesSet attaches a name and scope list to a bot token.
+func botTokenScopesSet(g *libkb.GlobalContext, ctx context.Context,
+	tok keybase1.BotToken, name string, scopes []string) error {
+	cli, err := GetAPIServerClient(g)
+	if err != nil {
+		return err
+	}
+	_, err = cli.Post(ctx, keybase1.PostArg{
+		Endpoint: "bot/token/scopes",
+		Args: []keybase1.StringKVPair{
+			{Key: "bot_token", Value: tok.String()},
+			{Key: "name", Value: name},
+			{Key: "scopes", Value: strings.Join(scopes, ",")},
+		},
+	})
+	return err
+}
+
+func newCmdBotTokenRotate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := cli.Command{
+		Name:         "rotate",
+		ArgumentHelp: "<name>",
+		Usage:        "Replace a named bot token with a fresh one, keeping its name and scopes",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(NewCmdBotTokenRotateRunner(g), "rotate", c)
+		},
+		Flags: []cli.Flag{},
+	}
+	return cmd
+}
+
+type CmdBotTokenRotate struct {
+	libkb.Contextified
+	name string
+}
+
+func NewCmdBotTokenRotateRunner(g *libkb.GlobalContext) *CmdBotTokenRotate {
+	return &CmdBotTokenRotate{
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+func (t *CmdBotTokenRotate) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 1 {
+		return BadArgsError{"bot token rotate takes one argument: the token name"}
+	}
+	t.name = ctx.Args().Get(0)
+	return nil
+}
+
+func (t *CmdBotTokenRotate) Run() (err error) {
+	ctx := context.TODO()
+	var old *botTokenScopeRow
+	rows := botTokenScopesFetch(t.G(), ctx)
+	for i, row := range rows {
+		if row.Name == t.name {
+			old = &rows[i]
+			break
+		}
+	}
+	if old == nil {
+		return fmt.Errorf("no bot token named %q; name one with `keybase bot token create --name`", t.name)
+	}
+
+	bcli, err := GetBotClient(t.G())
+	if err != nil {
+		return err
+	}
+	newTok, err := bcli.BotTokenCreate(ctx)
+	if err != nil {
+		return err
+	}
+	if err := botTokenScopesSet(t.G(), ctx, newTok, old.Name, old.Scopes); err != nil {
+		return err
+	}
+	dui := t.G().UI.GetTerminalUI()
+	if err := bcli.BotTokenDelete(ctx, old.Token); err != nil {
+		dui.Printf("Warning: couldn't revoke the old token, delete it manually: %s\n", err)
+	}
+	dui.Printf("Rotated %q. New token:\n%s\n", t.name, newTok.String())
+	return nil
+}
+
+func (t *CmdBotTokenRotate) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:     true,
+		GpgKeyring: true,
+		KbKeyring:  true,
+		API:        true,
+	}
+}
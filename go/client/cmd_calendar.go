@@ -0,0 +1,403 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/calendar"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+const calendarTimeFormat = "2006-01-02 15:04"
+
+// NewCmdCalendar creates the calendar command, which is just a holder for
+// subcommands.
+func NewCmdCalendar(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "calendar",
+		Usage:        "Manage a shared team calendar",
+		ArgumentHelp: "[arguments...]",
+		Subcommands: []cli.Command{
+			NewCmdCalendarList(cl, g),
+			NewCmdCalendarAdd(cl, g),
+			NewCmdCalendarRemove(cl, g),
+			NewCmdCalendarAgenda(cl, g),
+			NewCmdCalendarExport(cl, g),
+			NewCmdCalendarImport(cl, g),
+		},
+	}
+}
+
+func calendarStore(g *libkb.GlobalContext, team string) (*calendar.Store, error) {
+	if len(team) == 0 {
+		return nil, fmt.Errorf("team name required")
+	}
+	cli, err := GetSimpleFSClient(g)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.NewStore(g, cli, team), nil
+}
+
+func calendarPrintEvents(g *libkb.GlobalContext, events []calendar.Event) {
+	ui := g.UI.GetTerminalUI()
+	for _, event := range events {
+		line := fmt.Sprintf("%s  %s  %s", event.ID, event.StartTime.Local().Format(calendarTimeFormat),
+			event.Title)
+		if len(event.Location) > 0 {
+			line += fmt.Sprintf(" (%s)", event.Location)
+		}
+		ui.Printf("%s\n", line)
+	}
+}
+
+// CmdCalendarList is the 'calendar list' command.
+type CmdCalendarList struct {
+	libkb.Contextified
+	team string
+}
+
+func NewCmdCalendarList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "list",
+		ArgumentHelp: "<team>",
+		Usage:        "List the team's events",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarList{Contextified: libkb.NewContextified(g)}, "list", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdCalendarList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdCalendarList) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	events, err := store.List(context.TODO())
+	if err != nil {
+		return err
+	}
+	calendarPrintEvents(c.G(), events)
+	return nil
+}
+
+func (c *CmdCalendarList) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdCalendarAdd is the 'calendar add' command.
+type CmdCalendarAdd struct {
+	libkb.Contextified
+	team  string
+	event calendar.Event
+}
+
+func NewCmdCalendarAdd(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "add",
+		ArgumentHelp: "<team> <title>",
+		Usage:        "Add an event to the team's calendar",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarAdd{Contextified: libkb.NewContextified(g)}, "add", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "start",
+				Usage: fmt.Sprintf("Start time in local time (%s)", calendarTimeFormat),
+			},
+			cli.StringFlag{
+				Name:  "end",
+				Usage: fmt.Sprintf("End time in local time (%s)", calendarTimeFormat),
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "Event location",
+			},
+			cli.StringFlag{
+				Name:  "description",
+				Usage: "Event description",
+			},
+		},
+	}
+}
+
+func (c *CmdCalendarAdd) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("team name and event title required")
+	}
+	c.team = ctx.Args()[0]
+	c.event.Title = ctx.Args()[1]
+	start := ctx.String("start")
+	if len(start) == 0 {
+		return fmt.Errorf("start time required")
+	}
+	var err error
+	if c.event.StartTime, err = time.ParseInLocation(calendarTimeFormat, start, time.Local); err != nil {
+		return err
+	}
+	if end := ctx.String("end"); len(end) > 0 {
+		if c.event.EndTime, err = time.ParseInLocation(calendarTimeFormat, end, time.Local); err != nil {
+			return err
+		}
+	}
+	c.event.Location = ctx.String("location")
+	c.event.Description = ctx.String("description")
+	return nil
+}
+
+func (c *CmdCalendarAdd) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	c.event.Creator = c.G().Env.GetUsername().String()
+	event, err := store.Add(context.TODO(), c.event)
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Added event %s\n", event.ID)
+	return nil
+}
+
+func (c *CmdCalendarAdd) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdCalendarRemove is the 'calendar remove' command.
+type CmdCalendarRemove struct {
+	libkb.Contextified
+	team string
+	id   string
+}
+
+func NewCmdCalendarRemove(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "remove",
+		ArgumentHelp: "<team> <event-id>",
+		Usage:        "Remove an event from the team's calendar",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarRemove{Contextified: libkb.NewContextified(g)}, "remove", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdCalendarRemove) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("team name and event ID required")
+	}
+	c.team = ctx.Args()[0]
+	c.id = ctx.Args()[1]
+	return nil
+}
+
+func (c *CmdCalendarRemove) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	return store.Remove(context.TODO(), c.id)
+}
+
+func (c *CmdCalendarRemove) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdCalendarAgenda is the 'calendar agenda' command.
+type CmdCalendarAgenda struct {
+	libkb.Contextified
+	team   string
+	window time.Duration
+}
+
+func NewCmdCalendarAgenda(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "agenda",
+		ArgumentHelp: "<team>",
+		Usage:        "Show events coming up soon",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarAgenda{Contextified: libkb.NewContextified(g)}, "agenda", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "window",
+				Value: 24 * time.Hour,
+				Usage: "How far ahead to look",
+			},
+		},
+	}
+}
+
+func (c *CmdCalendarAgenda) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	c.window = ctx.Duration("window")
+	return nil
+}
+
+func (c *CmdCalendarAgenda) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	events, err := store.List(context.TODO())
+	if err != nil {
+		return err
+	}
+	calendarPrintEvents(c.G(), calendar.Upcoming(events, time.Now(), c.window))
+	return nil
+}
+
+func (c *CmdCalendarAgenda) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdCalendarExport is the 'calendar export' command.
+type CmdCalendarExport struct {
+	libkb.Contextified
+	team string
+}
+
+func NewCmdCalendarExport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "export",
+		ArgumentHelp: "<team>",
+		Usage:        "Export the team's calendar as iCalendar to stdout",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarExport{Contextified: libkb.NewContextified(g)}, "export", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdCalendarExport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdCalendarExport) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	events, err := store.List(context.TODO())
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("%s", calendar.ExportICal(events))
+	return nil
+}
+
+func (c *CmdCalendarExport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdCalendarImport is the 'calendar import' command.
+type CmdCalendarImport struct {
+	libkb.Contextified
+	team     string
+	filename string
+}
+
+func NewCmdCalendarImport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "import",
+		ArgumentHelp: "<team> [filename]",
+		Usage:        "Import iCalendar events from a file or stdin",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCalendarImport{Contextified: libkb.NewContextified(g)}, "import", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdCalendarImport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	if len(ctx.Args()) == 2 {
+		c.filename = ctx.Args()[1]
+	}
+	return nil
+}
+
+func (c *CmdCalendarImport) Run() error {
+	store, err := calendarStore(c.G(), c.team)
+	if err != nil {
+		return err
+	}
+	var dat []byte
+	if len(c.filename) > 0 {
+		if dat, err = ioutil.ReadFile(c.filename); err != nil {
+			return err
+		}
+	} else {
+		if dat, err = ioutil.ReadAll(os.Stdin); err != nil {
+			return err
+		}
+	}
+	events, err := calendar.ImportICal(string(dat))
+	if err != nil {
+		return err
+	}
+	added, err := store.Import(context.TODO(), events)
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Imported %d event(s)\n", added)
+	return nil
+}
+
+func (c *CmdCalendarImport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
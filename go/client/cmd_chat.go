@@ -17,13 +17,16 @@ func NewCmdChat(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		newCmdChatRemoveFromChannel(cl, g),
 		newCmdChatAPI(cl, g),
 		newCmdChatAPIListen(cl, g),
+		newCmdChatChannel(cl, g),
 		newCmdChatDefaultChannels(cl, g),
 		newCmdChatDeleteChannel(cl, g),
 		newCmdChatDeleteHistory(cl, g),
 		newCmdChatDownload(cl, g),
 		newCmdChatHide(cl, g),
+		newCmdChatHistory(cl, g),
 		newCmdChatJoinChannel(cl, g),
 		newCmdChatLeaveChannel(cl, g),
+		newCmdChatLegalHoldExport(cl, g),
 		newCmdChatRenameChannel(cl, g),
 		newCmdChatCreateChannel(cl, g),
 		newCmdChatList(cl, g),
@@ -33,7 +36,9 @@ func NewCmdChat(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		newCmdChatMute(cl, g),
 		newCmdChatRead(cl, g),
 		newCmdChatReAddMember(cl, g),
+		newCmdChatRequests(cl, g),
 		newCmdChatReport(cl, g),
+		newCmdChatBurnAndRekey(cl, g),
 		newCmdChatSetRetention(cl, g),
 		newCmdChatSetConvMinWriterRole(cl, g),
 		newCmdChatSetNotificationSettings(cl, g),
@@ -0,0 +1,200 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// teamAuditNamespace is where administrative events (burn-and-rekey,
+// legal-hold exports) land in the team's encrypted key-value store, giving
+// admins a durable audit trail.
+const teamAuditNamespace = "__audit"
+
+type burnAndRekeyAuditEntry struct {
+	Event    string    `json:"event"`
+	Conv     string    `json:"conv"`
+	Admin    string    `json:"admin"`
+	PurgeAge string    `json:"purgeAge"`
+	Ctime    time.Time `json:"ctime"`
+}
+
+// CmdChatBurnAndRekey handles a lost unlocked device: it rotates the team
+// key immediately so new messages use fresh application keys, tightens the
+// conversation's retention policy so prior content (including ephemeral
+// messages the device could still decrypt) purges across members' devices
+// on an accelerated schedule, and records the event in the team's audit
+// log. Admin-only, since both the rotation and the policy change require
+// admin.
+type CmdChatBurnAndRekey struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	purgeAge         time.Duration
+}
+
+func newCmdChatBurnAndRekey(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "burn-and-rekey",
+		ArgumentHelp: "<team conversation>",
+		Usage:        "Rotate a team conversation's keys and accelerate purge of prior content",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatBurnAndRekey{Contextified: libkb.NewContextified(g)}, "burn-and-rekey", c)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+		Flags: append(getConversationResolverFlags(), []cli.Flag{
+			cli.DurationFlag{
+				Name:  "purge-age",
+				Usage: "Exploding lifetime to enforce on prior and future messages",
+				Value: time.Hour,
+			},
+		}...),
+	}
+}
+
+func (c *CmdChatBurnAndRekey) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team conversation required")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	c.purgeAge = ctx.Duration("purge-age")
+	if c.purgeAge < 30*time.Second {
+		return fmt.Errorf("--purge-age must be at least 30s")
+	}
+	return nil
+}
+
+func (c *CmdChatBurnAndRekey) Run() (err error) {
+	ctx := context.TODO()
+	if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
+		return err
+	}
+	if c.resolvingRequest.Visibility == keybase1.TLFVisibility_ANY {
+		c.resolvingRequest.Visibility = keybase1.TLFVisibility_PRIVATE
+	}
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	conv, _, err := resolver.Resolve(ctx, c.resolvingRequest, chatConversationResolvingBehavior{
+		CreateIfNotExists: false,
+		MustNotExist:      false,
+		IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+	if conv.Info.MembersType != chat1.ConversationMembersType_TEAM {
+		return fmt.Errorf("burn-and-rekey only works on team conversations")
+	}
+	team := conv.Info.TlfName
+	ui := c.G().UI.GetTerminalUI()
+
+	// 1. Rotate the team key so the next message uses a fresh generation.
+	teamsCli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	teamID, err := teamsCli.GetTeamID(ctx, team)
+	if err != nil {
+		return err
+	}
+	if err := teamsCli.TeamRotateKey(ctx, keybase1.TeamRotateKeyArg{
+		TeamID: teamID,
+		Rt:     keybase1.RotationType_VISIBLE,
+	}); err != nil {
+		return err
+	}
+	ui.Printf("Rotated team key for %s.\n", team)
+
+	// 2. Tighten retention so members' devices purge prior content soon.
+	lcli, err := GetChatLocalClient(c.G())
+	if err != nil {
+		return err
+	}
+	policy := chat1.NewRetentionPolicyWithEphemeral(chat1.RpEphemeral{
+		Age: gregor1.DurationSec(c.purgeAge.Seconds()),
+	})
+	if err := postRetentionPolicy(ctx, lcli, ui, conv, policy,
+		true /*setChannel*/, false /*doPrompt*/); err != nil {
+		return err
+	}
+	ui.Printf("Retention tightened: all content now explodes after %s.\n", c.purgeAge)
+
+	// 3. Record the event in the team audit log.
+	admin := c.G().Env.GetUsername().String()
+	entry := burnAndRekeyAuditEntry{
+		Event:    "burn-and-rekey",
+		Conv:     conv.Info.TopicName,
+		Admin:    admin,
+		PurgeAge: c.purgeAge.String(),
+		Ctime:    time.Now().UTC(),
+	}
+	key := fmt.Sprintf("%d-%s", entry.Ctime.Unix(), entry.Event)
+	if err := writeTeamAuditEntry(c.G(), ctx, team, key, entry); err != nil {
+		ui.Printf("Warning: couldn't write audit entry: %s\n", err)
+	}
+	chatSendErr := chatSend(ctx, c.G(), ChatSendArg{
+		resolvingRequest: c.resolvingRequest,
+		message: fmt.Sprintf(
+			"@%s performed burn-and-rekey: team key rotated, retention set to explode after %s",
+			admin, c.purgeAge),
+		team: true,
+	})
+	if chatSendErr != nil {
+		ui.Printf("Warning: couldn't announce in channel: %s\n", chatSendErr)
+	}
+	ui.Printf("Done. Revoke the lost device with `keybase device remove` if you haven't.\n")
+	return nil
+}
+
+// writeTeamAuditEntry records a JSON event in the team's encrypted
+// key-value audit namespace.
+func writeTeamAuditEntry(g *libkb.GlobalContext, ctx context.Context, team,
+	key string, entry interface{}) error {
+	cli, err := GetKVStoreClient(g)
+	if err != nil {
+		return err
+	}
+	dat, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	res, err := cli.GetKVEntry(ctx, keybase1.GetKVEntryArg{
+		TeamName:  team,
+		Namespace: teamAuditNamespace,
+		EntryKey:  key,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = cli.PutKVEntry(ctx, keybase1.PutKVEntryArg{
+		TeamName:   team,
+		Namespace:  teamAuditNamespace,
+		EntryKey:   key,
+		Revision:   res.Revision + 1,
+		EntryValue: string(dat),
+	})
+	return err
+}
+
+func (c *CmdChatBurnAndRekey) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
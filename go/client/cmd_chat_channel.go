@@ -0,0 +1,138 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// channelModes maps a channel mode name to the minimum role required to
+// write in that mode. Announcement channels are enforced through the
+// existing min-writer-role conversation setting, so older clients respect
+// them too.
+var channelModes = map[string]keybase1.TeamRole{
+	"announcement": keybase1.TeamRole_ADMIN,
+	"normal":       keybase1.TeamRole_NONE,
+}
+
+func newCmdChatChannel(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "channel",
+		Usage: "Manage team channel settings",
+		Subcommands: []cli.Command{
+			newCmdChatChannelSetMode(cl, g),
+		},
+	}
+}
+
+type CmdChatChannelSetMode struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	mode             string
+}
+
+func newCmdChatChannelSetMode(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "set-mode",
+		Usage: "Set the posting mode of a team channel",
+		Description: `In announcement mode only team admins and owners can post to the
+channel; everyone else can still read it. Normal mode restores the default
+where any member can post. Announcement mode is stored as the channel's
+min-writer-role policy, so it is enforced for all clients.`,
+		Examples: `
+Make a channel announcement-only:
+    keybase chat channel set-mode keybasefriends announcement --channel '#announcements'
+
+Restore normal posting:
+    keybase chat channel set-mode keybasefriends normal --channel '#announcements'
+`,
+		ArgumentHelp: "<conversation> <announcement|normal>",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatChannelSetMode{
+				Contextified: libkb.NewContextified(g),
+			}, "set-mode", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatChannelSetMode) Run() (err error) {
+	if c.resolvingRequest.TlfName != "" {
+		if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
+			return err
+		}
+	}
+	// TLFVisibility_ANY doesn't make any sense for send, so switch that to PRIVATE:
+	if c.resolvingRequest.Visibility == keybase1.TLFVisibility_ANY {
+		c.resolvingRequest.Visibility = keybase1.TLFVisibility_PRIVATE
+	}
+
+	if c.G().Standalone {
+		switch c.resolvingRequest.MembersType {
+		case chat1.ConversationMembersType_TEAM, chat1.ConversationMembersType_IMPTEAMNATIVE, chat1.ConversationMembersType_IMPTEAMUPGRADE:
+			c.G().StartStandaloneChat()
+		default:
+			return CantRunInStandaloneError{}
+		}
+	}
+
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	conv, _, err := resolver.Resolve(context.TODO(), c.resolvingRequest, chatConversationResolvingBehavior{
+		CreateIfNotExists: false,
+		MustNotExist:      false,
+		IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+
+	if conv.Info.MembersType != chat1.ConversationMembersType_TEAM {
+		return errors.New("can only set the channel mode in team conversations")
+	}
+
+	lcli, err := GetChatLocalClient(c.G())
+	if err != nil {
+		return err
+	}
+	role := channelModes[c.mode]
+	if err = postConvMinWriterRole(context.TODO(), lcli, c.G().UI.GetTerminalUI(),
+		conv, role, true /*doPrompt*/); err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Channel mode set to %s.\n", c.mode)
+	return nil
+}
+
+func (c *CmdChatChannelSetMode) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("usage: set-mode <conversation> <announcement|normal>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	c.mode = ctx.Args().Get(1)
+	if _, ok := channelModes[c.mode]; !ok {
+		return fmt.Errorf("unknown channel mode %q; must be announcement or normal", c.mode)
+	}
+	return nil
+}
+
+func (c *CmdChatChannelSetMode) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// chatMessageVersion is one entry in a message's edit history.
+type chatMessageVersion struct {
+	editID chat1.MessageID
+	sender string
+	ctime  time.Time
+	body   string
+}
+
+// CmdChatHistory shows every version a message has gone through: the
+// original text plus each edit in its supersedes chain, with timestamps.
+// Versions that have been purged by retention or exploded are simply gone
+// from the thread, so the history honors those policies by construction.
+type CmdChatHistory struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	msgID            chat1.MessageID
+}
+
+func newCmdChatHistory(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "history",
+		ArgumentHelp: "<conversation> <msg-id>",
+		Usage:        "Show the edit history of a message",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatHistory{Contextified: libkb.NewContextified(g)}, "history", c)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatHistory) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("conversation and message ID required")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	rawID, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message ID %q", ctx.Args().Get(1))
+	}
+	c.msgID = chat1.MessageID(rawID)
+	return nil
+}
+
+func (c *CmdChatHistory) Run() (err error) {
+	ctx := context.TODO()
+	if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
+		return err
+	}
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	conv, _, err := resolver.Resolve(ctx, c.resolvingRequest, chatConversationResolvingBehavior{
+		CreateIfNotExists: false,
+		MustNotExist:      false,
+		IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+	lcli, err := GetChatLocalClient(c.G())
+	if err != nil {
+		return err
+	}
+	// Fetch the raw thread with supersedes resolution off, so superseded
+	// versions are still visible.
+	thread, err := lcli.GetThreadLocal(ctx, chat1.GetThreadLocalArg{
+		ConversationID: conv.Info.Id,
+		Query: &chat1.GetThreadQuery{
+			MessageTypes: []chat1.MessageType{
+				chat1.MessageType_TEXT, chat1.MessageType_EDIT,
+			},
+			DisableResolveSupersedes: true,
+		},
+		IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+
+	versions := c.collectVersions(thread.Thread.Messages)
+	if len(versions) == 0 {
+		return fmt.Errorf("no readable versions of message %d (wrong ID, or purged by retention)", c.msgID)
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("%d version(s) of message %d:\n", len(versions), c.msgID)
+	for i, v := range versions {
+		label := "original"
+		if v.editID != c.msgID {
+			label = fmt.Sprintf("edit %d", v.editID)
+		}
+		ui.Printf("%d. [%s] %s (%s):\n   %s\n", i+1, v.ctime.Format(time.RFC3339),
+			v.sender, label, v.body)
+	}
+	return nil
+}
+
+// collectVersions pulls the target message and any edits superseding it
+// out of the raw thread, oldest first.
+func (c *CmdChatHistory) collectVersions(msgs []chat1.MessageUnboxed) (res []chatMessageVersion) {
+	for _, msg := range msgs {
+		if !msg.IsValid() {
+			continue
+		}
+		valid := msg.Valid()
+		switch msg.GetMessageType() {
+		case chat1.MessageType_TEXT:
+			if msg.GetMessageID() != c.msgID {
+				continue
+			}
+			res = append(res, chatMessageVersion{
+				editID: msg.GetMessageID(),
+				sender: valid.SenderUsername,
+				ctime:  gregor1.FromTime(valid.ServerHeader.Ctime),
+				body:   valid.MessageBody.Text().Body,
+			})
+		case chat1.MessageType_EDIT:
+			edit := valid.MessageBody.Edit()
+			if edit.MessageID != c.msgID {
+				continue
+			}
+			res = append(res, chatMessageVersion{
+				editID: msg.GetMessageID(),
+				sender: valid.SenderUsername,
+				ctime:  gregor1.FromTime(valid.ServerHeader.Ctime),
+				body:   edit.Body,
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ctime.Before(res[j].ctime) })
+	return res
+}
+
+func (c *CmdChatHistory) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
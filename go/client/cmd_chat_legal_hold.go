@@ -0,0 +1,249 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// legalHoldAnnouncePolicyKey is the team kvstore key (in the audit
+// namespace) that controls whether exports are announced to the channel.
+// Absent or anything other than "never" means announce.
+const legalHoldAnnouncePolicyKey = "legal-hold-announce"
+
+type legalHoldAuditEntry struct {
+	Event    string    `json:"event"`
+	Conv     string    `json:"conv"`
+	Owner    string    `json:"owner"`
+	Dest     string    `json:"dest"`
+	Messages int       `json:"messages"`
+	Ctime    time.Time `json:"ctime"`
+}
+
+// exportedChatMessage is one message in a JSON history export, shared by
+// legal-hold exports and account migration.
+type exportedChatMessage struct {
+	MsgID    chat1.MessageID `json:"msgID"`
+	Type     string          `json:"type"`
+	Sender   string          `json:"sender"`
+	Ctime    time.Time       `json:"ctime"`
+	Body     string          `json:"body,omitempty"`
+	Filename string          `json:"filename,omitempty"`
+}
+
+// CmdChatLegalHoldExport produces a compliance export of one team
+// channel's history into the owner's private KBFS folder, where it stays
+// encrypted at rest. The export is owner-gated, recorded in the team's
+// audit log, and announced to the channel unless team policy disables the
+// announcement. Messages already purged by retention or exploded are not
+// recoverable and so are absent from the export.
+type CmdChatLegalHoldExport struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+}
+
+func newCmdChatLegalHoldExport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "legal-hold-export",
+		ArgumentHelp: "<team conversation>",
+		Usage:        "Export a team channel's history for compliance (owners only)",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatLegalHoldExport{Contextified: libkb.NewContextified(g)}, "legal-hold-export", c)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatLegalHoldExport) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team conversation required")
+	}
+	c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0))
+	return err
+}
+
+// checkOwner verifies the caller owns the team.
+func (c *CmdChatLegalHoldExport) checkOwner(ctx context.Context, team string) error {
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	list, err := cli.TeamListTeammates(ctx, keybase1.TeamListTeammatesArg{})
+	if err != nil {
+		return err
+	}
+	me := c.G().Env.GetUsername().String()
+	for _, member := range list.Teams {
+		if member.FqName == team && member.Username == me &&
+			member.Role == keybase1.TeamRole_OWNER {
+			return nil
+		}
+	}
+	return fmt.Errorf("legal-hold-export requires the owner role in %s", team)
+}
+
+// announcePerPolicy checks the team's announcement policy before posting
+// to the channel.
+func (c *CmdChatLegalHoldExport) announcePerPolicy(ctx context.Context, team string,
+	message string) error {
+	cli, err := GetKVStoreClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.GetKVEntry(ctx, keybase1.GetKVEntryArg{
+		TeamName:  team,
+		Namespace: teamAuditNamespace,
+		EntryKey:  legalHoldAnnouncePolicyKey,
+	})
+	if err == nil && res.EntryValue != nil && *res.EntryValue == "never" {
+		return nil
+	}
+	return chatSend(ctx, c.G(), ChatSendArg{
+		resolvingRequest: c.resolvingRequest,
+		message:          message,
+		team:             true,
+	})
+}
+
+// exportConvMessages pages through the raw thread, oldest versions
+// included.
+func exportConvMessages(g *libkb.GlobalContext, ctx context.Context,
+	convID chat1.ConversationID) (res []exportedChatMessage, err error) {
+	lcli, err := GetChatLocalClient(g)
+	if err != nil {
+		return nil, err
+	}
+	pagination := &chat1.Pagination{Num: 100}
+	for {
+		thread, err := lcli.GetThreadLocal(ctx, chat1.GetThreadLocalArg{
+			ConversationID: convID,
+			Pagination:     pagination,
+			Query: &chat1.GetThreadQuery{
+				DisableResolveSupersedes: true,
+			},
+			IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range thread.Thread.Messages {
+			if !msg.IsValid() {
+				continue
+			}
+			valid := msg.Valid()
+			out := exportedChatMessage{
+				MsgID:  msg.GetMessageID(),
+				Type:   msg.GetMessageType().String(),
+				Sender: valid.SenderUsername,
+				Ctime:  gregor1.FromTime(valid.ServerHeader.Ctime),
+			}
+			switch msg.GetMessageType() {
+			case chat1.MessageType_TEXT:
+				out.Body = valid.MessageBody.Text().Body
+			case chat1.MessageType_EDIT:
+				out.Body = valid.MessageBody.Edit().Body
+			case chat1.MessageType_ATTACHMENT:
+				out.Filename = valid.MessageBody.Attachment().Object.Filename
+			}
+			res = append(res, out)
+		}
+		if thread.Thread.Pagination == nil || thread.Thread.Pagination.Last {
+			break
+		}
+		pagination = &chat1.Pagination{Num: 100, Next: thread.Thread.Pagination.Next}
+	}
+	return res, nil
+}
+
+func (c *CmdChatLegalHoldExport) Run() (err error) {
+	ctx := context.TODO()
+	if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
+		return err
+	}
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	conv, _, err := resolver.Resolve(ctx, c.resolvingRequest, chatConversationResolvingBehavior{
+		CreateIfNotExists: false,
+		MustNotExist:      false,
+		IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+	if conv.Info.MembersType != chat1.ConversationMembersType_TEAM {
+		return fmt.Errorf("legal-hold-export only works on team conversations")
+	}
+	team := conv.Info.TlfName
+	if err := c.checkOwner(ctx, team); err != nil {
+		return err
+	}
+
+	messages, err := exportConvMessages(c.G(), ctx, conv.Info.Id)
+	if err != nil {
+		return err
+	}
+	dat, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Land the export in the owner's private folder; KBFS keeps it
+	// encrypted at rest.
+	owner := c.G().Env.GetUsername().String()
+	now := time.Now().UTC()
+	destName := fmt.Sprintf("legal-hold-%s-%s-%s.json", team, conv.Info.TopicName,
+		now.Format("20060102T150405Z"))
+	dest := keybase1.NewPathWithKbfsPath(fmt.Sprintf("/private/%s/%s", owner, destName))
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := teamTemplateWriteFile(ctx, fsCli, dest, dat); err != nil {
+		return err
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Exported %d messages to %s\n", len(messages), dest.Kbfs().Path)
+
+	entry := legalHoldAuditEntry{
+		Event:    "legal-hold-export",
+		Conv:     conv.Info.TopicName,
+		Owner:    owner,
+		Dest:     dest.Kbfs().Path,
+		Messages: len(messages),
+		Ctime:    now,
+	}
+	key := fmt.Sprintf("%d-%s", now.Unix(), entry.Event)
+	if err := writeTeamAuditEntry(c.G(), ctx, team, key, entry); err != nil {
+		ui.Printf("Warning: couldn't write audit entry: %s\n", err)
+	}
+	announceErr := c.announcePerPolicy(ctx, team, fmt.Sprintf(
+		"@%s produced a legal-hold export of this channel (%d messages)", owner, len(messages)))
+	if announceErr != nil {
+		ui.Printf("Warning: couldn't announce in channel: %s\n", announceErr)
+	}
+	return nil
+}
+
+func (c *CmdChatLegalHoldExport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// newCmdChatRequests manages the message-request queue, where first
+// messages from strangers are held when chat.message_requests is
+// enabled. Accepted conversations notify normally again; declining or
+// blocking reuses the regular conversation statuses.
+func newCmdChatRequests(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "requests",
+		Usage: "Manage message requests from users you don't follow",
+		Subcommands: []cli.Command{
+			newCmdChatRequestsList(cl, g),
+			newCmdChatRequestsAccept(cl, g),
+			newCmdChatRequestsSetStatus(cl, g, "decline",
+				"Hide a message request", chat1.ConversationStatus_IGNORED),
+			newCmdChatRequestsSetStatus(cl, g, "block",
+				"Block a message request", chat1.ConversationStatus_BLOCKED),
+		},
+	}
+}
+
+type CmdChatRequestsList struct {
+	libkb.Contextified
+}
+
+func newCmdChatRequestsList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "List pending message requests",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatRequestsList{Contextified: libkb.NewContextified(g)}, "list", c)
+		},
+	}
+}
+
+func (c *CmdChatRequestsList) Run() error {
+	cli, err := GetDebuggingClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.Script(context.TODO(), keybase1.ScriptArg{
+		Script: "chat-requests-list",
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+	return nil
+}
+
+func (c *CmdChatRequestsList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("list takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdChatRequestsList) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
+
+type CmdChatRequestsAccept struct {
+	libkb.Contextified
+	convID string
+}
+
+func newCmdChatRequestsAccept(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "accept",
+		ArgumentHelp: "<conv-id>",
+		Usage:        "Accept a message request so it notifies normally",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatRequestsAccept{Contextified: libkb.NewContextified(g)}, "accept", c)
+		},
+	}
+}
+
+func (c *CmdChatRequestsAccept) Run() error {
+	cli, err := GetDebuggingClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.Script(context.TODO(), keybase1.ScriptArg{
+		Script: "chat-requests-accept",
+		Args:   []string{c.convID},
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+	return nil
+}
+
+func (c *CmdChatRequestsAccept) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("accept takes one argument: the conversation ID")
+	}
+	c.convID = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdChatRequestsAccept) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
+
+type CmdChatRequestsSetStatus struct {
+	libkb.Contextified
+	name   string
+	convID string
+	status chat1.ConversationStatus
+}
+
+func newCmdChatRequestsSetStatus(cl *libcmdline.CommandLine, g *libkb.GlobalContext,
+	name, usage string, status chat1.ConversationStatus) cli.Command {
+	return cli.Command{
+		Name:         name,
+		ArgumentHelp: "<conv-id>",
+		Usage:        usage,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatRequestsSetStatus{
+				Contextified: libkb.NewContextified(g),
+				name:         name,
+				status:       status,
+			}, name, c)
+		},
+	}
+}
+
+func (c *CmdChatRequestsSetStatus) Run() error {
+	convID, err := chat1.MakeConvID(c.convID)
+	if err != nil {
+		return err
+	}
+	client, err := GetChatLocalClient(c.G())
+	if err != nil {
+		return err
+	}
+	_, err = client.SetConversationStatusLocal(context.TODO(), chat1.SetConversationStatusLocalArg{
+		ConversationID: convID,
+		Status:         c.status,
+	})
+	return err
+}
+
+func (c *CmdChatRequestsSetStatus) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("%s takes one argument: the conversation ID", c.name)
+	}
+	c.convID = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdChatRequestsSetStatus) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
@@ -17,9 +17,14 @@ func NewCmdCtl(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 		NewCmdCtlReload(cl, g),
 		NewCmdCtlRestart(cl, g),
 		NewCmdCtlLogRotate(cl, g),
+		NewCmdCtlStartupTrace(cl, g),
+		NewCmdCtlTorStatus(cl, g),
 		NewCmdWatchdog(cl, g),
 		NewCmdCtlAppExit(cl, g),
 		NewCmdWait(cl, g),
+		NewCmdCtlGregor(cl, g),
+		NewCmdCtlStorage(cl, g),
+		NewCmdCtlAPIBudget(cl, g),
 	}
 
 	commands = append(commands, platformSpecificCtlCommands(cl, g)...)
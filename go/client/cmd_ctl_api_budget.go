@@ -0,0 +1,111 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdCtlAPIBudget shows the per-category API rate limit state the
+// service has observed, so bot operators can self-throttle before
+// hitting server limits.
+type CmdCtlAPIBudget struct {
+	libkb.Contextified
+	json bool
+}
+
+func NewCmdCtlAPIBudget(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "api-budget",
+		Usage: "Show remaining API request budget per endpoint category",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlAPIBudget{Contextified: libkb.NewContextified(g)}, "api-budget", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output the raw budget state as JSON.",
+			},
+		},
+	}
+}
+
+func (c *CmdCtlAPIBudget) Run() error {
+	cli, err := GetDebuggingClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.Script(context.TODO(), keybase1.ScriptArg{
+		Script: "api-budget",
+	})
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.TrimSpace(res), "[") {
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+		return nil
+	}
+	if c.json {
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+		return nil
+	}
+	var budgets []libkb.APIBudget
+	if err := json.Unmarshal([]byte(res), &budgets); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.G().UI.GetTerminalUI().OutputWriter(), 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "CATEGORY\tREMAINING\tLIMIT\tRESETS\tOBSERVED\n")
+	for _, budget := range budgets {
+		reset := "unknown"
+		if !budget.Reset.IsZero() {
+			reset = budget.Reset.Format("2006-01-02 15:04:05")
+		}
+		remaining := fmt.Sprintf("%d", budget.Remaining)
+		if budget.Exhausted() {
+			remaining += " (low)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", budget.Category, remaining, budget.Limit,
+			reset, humanizeDuration(time.Since(budget.UpdatedAt)))
+	}
+	return w.Flush()
+}
+
+// humanizeDuration renders a duration as a short "ago" string for the
+// budget table.
+func humanizeDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+}
+
+func (c *CmdCtlAPIBudget) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("api-budget takes no arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdCtlAPIBudget) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+func NewCmdCtlGregor(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "gregor",
+		Usage: "Inspect and replay the service's gregor state",
+		Subcommands: []cli.Command{
+			newCmdCtlGregorDump(cl, g),
+			newCmdCtlGregorReplay(cl, g),
+		},
+	}
+}
+
+// CmdCtlGregorDump lists the in-band gregor items the service currently
+// holds, including their dismissal times.
+type CmdCtlGregorDump struct {
+	libkb.Contextified
+	json bool
+}
+
+func newCmdCtlGregorDump(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "dump",
+		Usage: "List the in-band gregor items currently held by the service",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlGregorDump{Contextified: libkb.NewContextified(g)}, "dump", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output the raw state as JSON.",
+			},
+		},
+	}
+}
+
+func (c *CmdCtlGregorDump) Run() error {
+	cli, err := GetGregorClient(c.G())
+	if err != nil {
+		return err
+	}
+	state, err := cli.GetState(context.TODO())
+	if err != nil {
+		return err
+	}
+	if c.json {
+		b, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", string(b))
+		return nil
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("%d item(s) held\n\n", len(state.Items_))
+	w := tabwriter.NewWriter(c.G().UI.GetTerminalUI().OutputWriter(), 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "MSG ID\tCATEGORY\tCTIME\tDISMISSAL\tBODY BYTES\n")
+	for _, item := range state.Items_ {
+		var msgID, category, ctime string
+		if item.Md_ != nil {
+			msgID = hex.EncodeToString(item.Md_.MsgID_)
+			ctime = gregor1.FromTime(item.Md_.Ctime_).Format("2006-01-02 15:04:05")
+		}
+		dismissal := "none"
+		bodyBytes := 0
+		if item.Item_ != nil {
+			category = item.Item_.Category_.String()
+			bodyBytes = len(item.Item_.Body_)
+			switch {
+			case item.Item_.Dtime_.Time_ != 0:
+				dismissal = gregor1.FromTime(item.Item_.Dtime_.Time_).Format("2006-01-02 15:04:05")
+			case item.Item_.Dtime_.Offset_ != 0:
+				dismissal = fmt.Sprintf("+%dms", item.Item_.Dtime_.Offset_)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", msgID, category, ctime, dismissal, bodyBytes)
+	}
+	return w.Flush()
+}
+
+func (c *CmdCtlGregorDump) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("dump takes no arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdCtlGregorDump) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
+
+// CmdCtlGregorReplay reruns the held items in a category through the
+// service's local gregor handlers, for when a notification got stuck
+// and would otherwise require a logout/login cycle.
+type CmdCtlGregorReplay struct {
+	libkb.Contextified
+	category string
+}
+
+func newCmdCtlGregorReplay(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "replay",
+		ArgumentHelp: "<category-prefix>",
+		Usage:        "Replay held gregor items in a category through local handlers",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlGregorReplay{Contextified: libkb.NewContextified(g)}, "replay", c)
+		},
+	}
+}
+
+func (c *CmdCtlGregorReplay) Run() error {
+	cli, err := GetDebuggingClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.Script(context.TODO(), keybase1.ScriptArg{
+		Script: "gregor-replay",
+		Args:   []string{c.category},
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+	return nil
+}
+
+func (c *CmdCtlGregorReplay) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("replay takes one argument: a category prefix")
+	}
+	c.category = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdCtlGregorReplay) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
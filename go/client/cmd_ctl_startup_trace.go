@@ -0,0 +1,51 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+func NewCmdCtlStartupTrace(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "startup-trace",
+		Usage: "Show timing of the service's most recent startup",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlStartupTrace{libkb.NewContextified(g)}, "startup-trace", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+type CmdCtlStartupTrace struct {
+	libkb.Contextified
+}
+
+func (s *CmdCtlStartupTrace) ParseArgv(ctx *cli.Context) error {
+	return nil
+}
+
+func (s *CmdCtlStartupTrace) Run() error {
+	trace, err := libkb.LoadStartupTrace(s.G())
+	if err != nil {
+		return err
+	}
+	dui := s.G().UI.GetDumbOutputUI()
+	if trace.FinishedAt.IsZero() {
+		dui.Printf("Startup still in progress (or service died mid-startup); partial trace:\n")
+	} else {
+		dui.Printf("Startup completed in %s:\n", trace.FinishedAt.Sub(trace.ProcessStart))
+	}
+	for _, stage := range trace.Stages {
+		dui.Printf("  %-30s %12s  (at +%s)\n", stage.Name, stage.Duration, stage.OffsetFrom)
+	}
+	return nil
+}
+
+func (s *CmdCtlStartupTrace) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
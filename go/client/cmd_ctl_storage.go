@@ -0,0 +1,223 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// storageItem is one subsystem's slice of local disk, described by a
+// set of path globs under the standard keybase directories.
+type storageItem struct {
+	name      string
+	desc      string
+	purgeable bool
+	globs     func(g *libkb.GlobalContext) []string
+}
+
+func storageItems() []storageItem {
+	return []storageItem{
+		{
+			name: "chat",
+			desc: "Chat message cache and search index",
+			globs: func(g *libkb.GlobalContext) []string {
+				return []string{g.Env.GetChatDbFilename()}
+			},
+		},
+		{
+			name:      "attachments",
+			desc:      "Chat attachment and preview cache",
+			purgeable: true,
+			globs: func(g *libkb.GlobalContext) []string {
+				cacheDir := g.GetCacheDir()
+				return []string{
+					filepath.Join(cacheDir, "kbchat*"),
+					filepath.Join(cacheDir, "prev*"),
+					filepath.Join(cacheDir, "uploadtemps"),
+				}
+			},
+		},
+		{
+			name:      "kbfs-block-cache",
+			desc:      "KBFS working set block cache",
+			purgeable: true,
+			globs: func(g *libkb.GlobalContext) []string {
+				return []string{filepath.Join(g.GetCacheDir(), "kbfs_block_cache")}
+			},
+		},
+		{
+			name:      "kbfs-sync-cache",
+			desc:      "KBFS synced folder cache",
+			purgeable: true,
+			globs: func(g *libkb.GlobalContext) []string {
+				return []string{filepath.Join(g.GetCacheDir(), "kbfs_sync_cache")}
+			},
+		},
+		{
+			name:      "logs",
+			desc:      "Service, KBFS, and client logs",
+			purgeable: true,
+			globs: func(g *libkb.GlobalContext) []string {
+				return []string{filepath.Join(g.Env.GetLogDir(), "*.log*")}
+			},
+		},
+	}
+}
+
+func (s storageItem) paths(g *libkb.GlobalContext) (res []string) {
+	for _, glob := range s.globs(g) {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			continue
+		}
+		res = append(res, matches...)
+	}
+	return res
+}
+
+func (s storageItem) size(g *libkb.GlobalContext) (res int64) {
+	for _, path := range s.paths(g) {
+		_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() {
+				res += info.Size()
+			}
+			return nil
+		})
+	}
+	return res
+}
+
+func NewCmdCtlStorage(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "storage",
+		Usage: "Show local disk usage by subsystem",
+		Subcommands: []cli.Command{
+			newCmdCtlStoragePurge(cl, g),
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlStorage{Contextified: libkb.NewContextified(g)}, "storage", c)
+		},
+	}
+}
+
+type CmdCtlStorage struct {
+	libkb.Contextified
+}
+
+func (c *CmdCtlStorage) Run() error {
+	w := tabwriter.NewWriter(c.G().UI.GetTerminalUI().OutputWriter(), 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "ITEM\tSIZE\tPURGEABLE\tDESCRIPTION\n")
+	var total int64
+	for _, item := range storageItems() {
+		size := item.size(c.G())
+		total += size
+		purgeable := ""
+		if item.purgeable {
+			purgeable = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.name, humanize.Bytes(uint64(size)), purgeable, item.desc)
+	}
+	fmt.Fprintf(w, "total\t%s\t\t\n", humanize.Bytes(uint64(total)))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("\nUse `keybase ctl storage purge <item>` to reclaim purgeable items.\n")
+	return nil
+}
+
+func (c *CmdCtlStorage) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("storage takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdCtlStorage) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
+
+type CmdCtlStoragePurge struct {
+	libkb.Contextified
+	item  string
+	force bool
+}
+
+func newCmdCtlStoragePurge(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "purge",
+		ArgumentHelp: "<item>",
+		Usage:        "Delete a purgeable storage item to reclaim disk space",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlStoragePurge{Contextified: libkb.NewContextified(g)}, "purge", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "f, force",
+				Usage: "Don't prompt for confirmation.",
+			},
+		},
+	}
+}
+
+func (c *CmdCtlStoragePurge) Run() error {
+	var item storageItem
+	found := false
+	for _, candidate := range storageItems() {
+		if candidate.name == c.item {
+			item = candidate
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown storage item %q; see `keybase ctl storage`", c.item)
+	}
+	if !item.purgeable {
+		return fmt.Errorf("%s cannot be purged: it holds data the service needs", item.name)
+	}
+	size := item.size(c.G())
+	if !c.force {
+		err := c.G().UI.GetTerminalUI().PromptForConfirmation(
+			fmt.Sprintf("Delete %s of %s (%s)? The cache will be rebuilt as needed.",
+				humanize.Bytes(uint64(size)), item.name, item.desc))
+		if err != nil {
+			return err
+		}
+	}
+	for _, path := range item.paths(c.G()) {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %s", path, err)
+		}
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Purged %s, reclaiming %s.\n", item.name, humanize.Bytes(uint64(size)))
+	return nil
+}
+
+func (c *CmdCtlStoragePurge) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("purge takes one argument: the item to purge")
+	}
+	c.item = ctx.Args()[0]
+	c.force = ctx.Bool("force")
+	return nil
+}
+
+func (c *CmdCtlStoragePurge) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
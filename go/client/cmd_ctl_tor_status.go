@@ -0,0 +1,67 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// NewCmdCtlTorStatus reports how traffic is isolated under the current Tor
+// configuration. The report is computed from the same Env the service reads
+// its proxy settings from, so it reflects what the service is doing.
+func NewCmdCtlTorStatus(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "tor-status",
+		Usage: "Show Tor mode and per-subsystem circuit isolation",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlTorStatus{libkb.NewContextified(g)}, "tor-status", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+type CmdCtlTorStatus struct {
+	libkb.Contextified
+}
+
+func (s *CmdCtlTorStatus) ParseArgv(ctx *cli.Context) error {
+	return nil
+}
+
+func (s *CmdCtlTorStatus) Run() (err error) {
+	ui := s.G().UI.GetTerminalUI()
+	status := libkb.GetTorIsolationStatus(s.G().Env)
+	switch status.Mode {
+	case libkb.TorNone:
+		ui.Printf("Tor mode: off\n")
+		return nil
+	case libkb.TorStrict:
+		ui.Printf("Tor mode: strict\n")
+	case libkb.TorLeaky:
+		ui.Printf("Tor mode: leaky\n")
+	}
+	ui.Printf("SOCKS proxy: %s\n", status.ProxyAddress)
+	ui.Printf("API endpoint: %s", status.HiddenAddress)
+	if status.OnionAPI {
+		ui.Printf(" (onion)")
+	}
+	ui.Printf("\n")
+	if status.DNSLeakSafe {
+		ui.Printf("DNS resolution: via proxy (no leak)\n")
+	}
+	ui.Printf("Isolated circuits:\n")
+	for _, circuit := range libkb.TorIsolationCircuits {
+		ui.Printf("  %-18s socks user %s\n", circuit, status.CircuitUsers[circuit])
+	}
+	return nil
+}
+
+func (s *CmdCtlTorStatus) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
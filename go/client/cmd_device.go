@@ -20,6 +20,7 @@ func NewCmdDevice(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comman
 			NewCmdDeviceRemove(cl, g),
 			NewCmdDeviceList(cl, g),
 			NewCmdDeviceAdd(cl, g),
+			NewCmdDeviceCleanup(cl, g),
 		},
 	}
 }
@@ -0,0 +1,170 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+)
+
+// deviceCleanupDefaultAge is how long a device can sit idle before the
+// advisor flags it.
+const deviceCleanupDefaultAge = 180 * 24 * time.Hour
+
+// staleDeviceAdvice is the advisor's verdict on one device.
+type staleDeviceAdvice struct {
+	detail   keybase1.DeviceDetail
+	idleFor  time.Duration
+	warnings []string
+}
+
+// adviseStaleDevices flags active, non-current devices idle longer than age
+// and annotates each with the security impact of keeping it around. Pure so
+// it can be tested without a service.
+func adviseStaleDevices(details []keybase1.DeviceDetail, now time.Time,
+	age time.Duration) (res []staleDeviceAdvice) {
+	for _, detail := range details {
+		if detail.CurrentDevice || detail.RevokedAt != nil {
+			continue
+		}
+		lastUsed := keybase1.FromTime(detail.Device.LastUsedTime)
+		if lastUsed.IsZero() {
+			lastUsed = keybase1.FromTime(detail.Device.CTime)
+		}
+		idle := now.Sub(lastUsed)
+		if idle < age {
+			continue
+		}
+		advice := staleDeviceAdvice{detail: detail, idleFor: idle}
+		advice.warnings = append(advice.warnings,
+			"holds keys that can read your private folders and chats")
+		if detail.Eldest {
+			advice.warnings = append(advice.warnings,
+				"is your eldest device; revoking it rotates your per-user key")
+		}
+		if detail.Device.Type == keybase1.DeviceTypeV2_PAPER {
+			advice.warnings = append(advice.warnings,
+				"is a paper key; keep at least one paper key for account recovery")
+		}
+		res = append(res, advice)
+	}
+	return res
+}
+
+// CmdDeviceCleanup is the 'device cleanup' command: an interactive sweep of
+// stale devices. Each confirmed device is revoked through the service,
+// which performs the per-user key rotation for every revocation before the
+// next one starts.
+type CmdDeviceCleanup struct {
+	libkb.Contextified
+	age   time.Duration
+	force bool
+}
+
+// NewCmdDeviceCleanup creates a new cli.Command.
+func NewCmdDeviceCleanup(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "cleanup",
+		Usage: "Review and revoke devices you haven't used in a while",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDeviceCleanup{Contextified: libkb.NewContextified(g)}, "cleanup", c)
+		},
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "age",
+				Usage: "Flag devices idle for at least this long",
+				Value: deviceCleanupDefaultAge,
+			},
+			cli.BoolFlag{
+				Name:  "f, force",
+				Usage: "Revoke all stale devices without prompting",
+			},
+		},
+	}
+}
+
+func (c *CmdDeviceCleanup) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("cleanup takes no arguments")
+	}
+	c.age = ctx.Duration("age")
+	if c.age <= 0 {
+		return fmt.Errorf("--age must be positive")
+	}
+	c.force = ctx.Bool("force")
+	return nil
+}
+
+func (c *CmdDeviceCleanup) Run() error {
+	ctx := context.TODO()
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocolsWithContext(protocols, c.G()); err != nil {
+		return err
+	}
+	devCli, err := GetDeviceClient(c.G())
+	if err != nil {
+		return err
+	}
+	details, err := devCli.DeviceHistoryList(ctx, 0)
+	if err != nil {
+		return err
+	}
+	advices := adviseStaleDevices(details, time.Now(), c.age)
+	ui := c.G().UI.GetTerminalUI()
+	if len(advices) == 0 {
+		ui.Printf("No devices have been idle for more than %s.\n", c.age)
+		return nil
+	}
+	revokeCli, err := GetRevokeClient(c.G())
+	if err != nil {
+		return err
+	}
+	var revoked, kept int
+	for _, advice := range advices {
+		device := advice.detail.Device
+		ui.Printf("%s (%s): idle for %d days\n", device.Name, device.Type,
+			int(advice.idleFor.Hours()/24))
+		for _, warning := range advice.warnings {
+			ui.Printf("  - %s\n", warning)
+		}
+		if !c.force {
+			ok, err := ui.PromptYesNo(PromptDescriptorDeviceRevoke,
+				fmt.Sprintf("Revoke %s?", device.Name), libkb.PromptDefaultNo)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				kept++
+				continue
+			}
+		}
+		if err := revokeCli.RevokeDevice(ctx, keybase1.RevokeDeviceArg{
+			DeviceID: device.DeviceID,
+		}); err != nil {
+			return fmt.Errorf("revoking %s: %s", device.Name, err)
+		}
+		ui.Printf("Revoked %s.\n", device.Name)
+		revoked++
+	}
+	ui.Printf("Cleanup done: %d revoked, %d kept.\n", revoked, kept)
+	return nil
+}
+
+func (c *CmdDeviceCleanup) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func cleanupDevice(name string, typ keybase1.DeviceTypeV2, lastUsed time.Time) keybase1.DeviceDetail {
+	return keybase1.DeviceDetail{
+		Device: keybase1.Device{
+			Name:         name,
+			Type:         typ,
+			LastUsedTime: keybase1.ToTime(lastUsed),
+		},
+	}
+}
+
+func TestAdviseStaleDevices(t *testing.T) {
+	now := time.Now()
+	age := 180 * 24 * time.Hour
+	fresh := cleanupDevice("laptop", keybase1.DeviceTypeV2_DESKTOP, now.Add(-24*time.Hour))
+	stale := cleanupDevice("old-phone", keybase1.DeviceTypeV2_MOBILE, now.Add(-200*24*time.Hour))
+	paper := cleanupDevice("paper", keybase1.DeviceTypeV2_PAPER, now.Add(-300*24*time.Hour))
+	current := cleanupDevice("this-one", keybase1.DeviceTypeV2_DESKTOP, now.Add(-400*24*time.Hour))
+	current.CurrentDevice = true
+	revokedAt := keybase1.ToTime(now.Add(-10 * 24 * time.Hour))
+	revoked := cleanupDevice("gone", keybase1.DeviceTypeV2_DESKTOP, now.Add(-400*24*time.Hour))
+	revoked.RevokedAt = &revokedAt
+
+	advices := adviseStaleDevices([]keybase1.DeviceDetail{
+		fresh, stale, paper, current, revoked}, now, age)
+	require.Len(t, advices, 2)
+	require.Equal(t, "old-phone", advices[0].detail.Device.Name)
+	require.Equal(t, "paper", advices[1].detail.Device.Name)
+
+	// the paper key gets an extra recovery warning
+	require.Len(t, advices[0].warnings, 1)
+	require.Len(t, advices[1].warnings, 2)
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// doctorCheckStatus grades one check's outcome.
+type doctorCheckStatus string
+
+const (
+	doctorOK   doctorCheckStatus = "ok"
+	doctorWarn doctorCheckStatus = "warn"
+	doctorFail doctorCheckStatus = "fail"
+	doctorSkip doctorCheckStatus = "skip"
+)
+
+// doctorResult is one check's machine-readable outcome. Fix, when set, is
+// a concrete command or action the user can take.
+type doctorResult struct {
+	Name   string            `json:"name"`
+	Status doctorCheckStatus `json:"status"`
+	Detail string            `json:"detail,omitempty"`
+	Fix    string            `json:"fix,omitempty"`
+}
+
+// CmdDoctor runs layered self-diagnosis checks: service reachability,
+// version agreement, session and keyring state, KBFS mount health, API
+// and gregor connectivity, and clock skew. Checks are ordered so that an
+// early failure explains the later ones.
+type CmdDoctor struct {
+	libkb.Contextified
+	json bool
+}
+
+func NewCmdDoctor(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "doctor",
+		Usage: "Diagnose common problems with the Keybase installation",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDoctor{Contextified: libkb.NewContextified(g)}, "doctor", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output results as JSON",
+			},
+		},
+	}
+}
+
+func (c *CmdDoctor) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return UnexpectedArgsError("doctor")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdDoctor) Run() error {
+	var results []doctorResult
+	add := func(r doctorResult) {
+		results = append(results, r)
+	}
+
+	// Service socket and versions first: if the service is unreachable,
+	// everything downstream fails for the same reason.
+	fstatus := c.checkService(add)
+	if fstatus != nil {
+		c.checkSession(add, fstatus)
+		c.checkKeyring(add, fstatus)
+		c.checkMount(add, fstatus)
+	}
+	c.checkAPIServer(add)
+	c.checkGregor(add)
+	c.checkClockSkew(add)
+
+	return c.output(results)
+}
+
+func (c *CmdDoctor) checkService(add func(doctorResult)) *keybase1.FullStatus {
+	cli, err := GetConfigClient(c.G())
+	if err != nil {
+		add(doctorResult{
+			Name:   "service-socket",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "run `keybase ctl restart`",
+		})
+		return nil
+	}
+	fstatus, err := cli.GetFullStatus(context.TODO(), 0)
+	if err != nil || fstatus == nil {
+		detail := "no status returned"
+		if err != nil {
+			detail = err.Error()
+		}
+		add(doctorResult{
+			Name:   "service-socket",
+			Status: doctorFail,
+			Detail: detail,
+			Fix:    "run `keybase ctl restart`",
+		})
+		return nil
+	}
+	add(doctorResult{Name: "service-socket", Status: doctorOK})
+
+	clientVersion := libkb.VersionString()
+	if fstatus.Service.Version != clientVersion {
+		add(doctorResult{
+			Name:   "versions",
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("client %s but service %s", clientVersion, fstatus.Service.Version),
+			Fix:    "run `keybase ctl restart` so the service picks up the new binary",
+		})
+	} else {
+		add(doctorResult{Name: "versions", Status: doctorOK, Detail: clientVersion})
+	}
+	return fstatus
+}
+
+func (c *CmdDoctor) checkSession(add func(doctorResult), fstatus *keybase1.FullStatus) {
+	switch {
+	case !fstatus.CurStatus.LoggedIn:
+		add(doctorResult{
+			Name:   "session",
+			Status: doctorFail,
+			Detail: "not logged in",
+			Fix:    "run `keybase login`",
+		})
+	case !fstatus.CurStatus.SessionIsValid:
+		add(doctorResult{
+			Name:   "session",
+			Status: doctorWarn,
+			Detail: "logged in but session invalid",
+			Fix:    "run `keybase logout` then `keybase login`",
+		})
+	default:
+		add(doctorResult{Name: "session", Status: doctorOK, Detail: fstatus.Username})
+	}
+}
+
+func (c *CmdDoctor) checkKeyring(add func(doctorResult), fstatus *keybase1.FullStatus) {
+	if !fstatus.CurStatus.LoggedIn {
+		add(doctorResult{Name: "keyring", Status: doctorSkip, Detail: "not logged in"})
+		return
+	}
+	ext := fstatus.ExtStatus
+	if !ext.DeviceSigKeyCached || !ext.DeviceEncKeyCached {
+		fix := "run `keybase unlock`"
+		if !ext.StoredSecret {
+			fix = "run `keybase unlock`; consider `keybase config set remember_passphrase true`"
+		}
+		add(doctorResult{
+			Name:   "keyring",
+			Status: doctorWarn,
+			Detail: "device keys are not decrypted in memory",
+			Fix:    fix,
+		})
+		return
+	}
+	add(doctorResult{Name: "keyring", Status: doctorOK})
+}
+
+func (c *CmdDoctor) checkMount(add func(doctorResult), fstatus *keybase1.FullStatus) {
+	switch {
+	case !fstatus.Kbfs.Running:
+		add(doctorResult{
+			Name:   "kbfs-mount",
+			Status: doctorWarn,
+			Detail: "KBFS is not running",
+			Fix:    "run `keybase ctl restart`",
+		})
+	case fstatus.Kbfs.Mount == "":
+		add(doctorResult{
+			Name:   "kbfs-mount",
+			Status: doctorWarn,
+			Detail: "KBFS is running but nothing is mounted",
+		})
+	default:
+		add(doctorResult{Name: "kbfs-mount", Status: doctorOK, Detail: fstatus.Kbfs.Mount})
+	}
+}
+
+func (c *CmdDoctor) checkAPIServer(add func(doctorResult)) {
+	mctx := libkb.NewMetaContextBackground(c.G())
+	if _, err := c.G().API.Get(mctx, libkb.APIArg{Endpoint: "ping"}); err != nil {
+		add(doctorResult{
+			Name:   "api-server",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "check network and proxy settings (`keybase config get proxy`)",
+		})
+		return
+	}
+	add(doctorResult{Name: "api-server", Status: doctorOK})
+}
+
+func (c *CmdDoctor) checkGregor(add func(doctorResult)) {
+	if err := PingGregor(c.G()); err != nil {
+		add(doctorResult{
+			Name:   "gregor",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "check that outbound connections to the gregor port are allowed",
+		})
+		return
+	}
+	add(doctorResult{Name: "gregor", Status: doctorOK})
+}
+
+// checkClockSkew reports the skew measured from the Date header of the
+// API ping in checkAPIServer, against the configured tolerance
+// (clock_skew_tolerance).
+func (c *CmdDoctor) checkClockSkew(add func(doctorResult)) {
+	skew := c.G().MeasuredClockSkew()
+	if skew == 0 {
+		add(doctorResult{Name: "clock-skew", Status: doctorSkip, Detail: "no API response observed"})
+		return
+	}
+	if err := c.G().CheckClockSkew(); err != nil {
+		add(doctorResult{
+			Name:   "clock-skew",
+			Status: doctorWarn,
+			Detail: err.Error(),
+			Fix:    "enable NTP time synchronization",
+		})
+		return
+	}
+	add(doctorResult{Name: "clock-skew", Status: doctorOK, Detail: skew.Round(time.Second).String()})
+}
+
+func (c *CmdDoctor) output(results []doctorResult) error {
+	if c.json {
+		dat, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		ui := c.G().UI.GetTerminalUI()
+		_, err = ui.Printf("%s\n", dat)
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	var fails int
+	for _, r := range results {
+		mark := map[doctorCheckStatus]string{
+			doctorOK:   "✔",
+			doctorWarn: "!",
+			doctorFail: "✖",
+			doctorSkip: "-",
+		}[r.Status]
+		line := fmt.Sprintf("%s %-14s", mark, r.Name)
+		if r.Detail != "" {
+			line += " " + r.Detail
+		}
+		ui.Printf("%s\n", line)
+		if r.Fix != "" {
+			ui.Printf("    fix: %s\n", r.Fix)
+		}
+		if r.Status == doctorFail {
+			fails++
+		}
+	}
+	if fails > 0 {
+		return fmt.Errorf("%d check(s) failed", fails)
+	}
+	return nil
+}
+
+func (c *CmdDoctor) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
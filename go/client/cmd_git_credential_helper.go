@@ -0,0 +1,255 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+const gitCredentialNamespace = "git-credentials"
+const gitCredentialAuditFile = "git-credential-audit.log"
+
+// gitHostCredential is the vault entry for one external git host. Repos is
+// a list of path globs (e.g. "acme/*"); an empty list allows every repo on
+// the host.
+type gitHostCredential struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Repos    []string `json:"repos,omitempty"`
+}
+
+// CmdGitCredentialHelper implements the git credential helper protocol
+// (https://git-scm.com/docs/gitcredentials), serving credentials for
+// external git hosts out of a team's encrypted key-value store instead of
+// plaintext files. Wire it up with:
+//
+//	git config credential.helper '!keybase git credential-helper --team acme.eng'
+type CmdGitCredentialHelper struct {
+	libkb.Contextified
+	team      string
+	operation string
+	stdin     io.Reader
+}
+
+func newCmdGitCredentialHelper(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "credential-helper",
+		ArgumentHelp: "<get|store|erase>",
+		Usage:        "Serve git credentials for external hosts from a team's encrypted store",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdGitCredentialHelper{Contextified: libkb.NewContextified(g)},
+				"credential-helper", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "team",
+				Usage: "Team whose vault holds the credentials",
+			},
+		},
+	}
+}
+
+func (c *CmdGitCredentialHelper) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("operation required: get, store or erase")
+	}
+	c.operation = ctx.Args()[0]
+	switch c.operation {
+	case "get", "store", "erase":
+	default:
+		return fmt.Errorf("unknown operation: %s", c.operation)
+	}
+	c.team = ctx.String("team")
+	if len(c.team) == 0 {
+		return fmt.Errorf("team name required")
+	}
+	return nil
+}
+
+// parseGitCredentialInput reads the key=value lines git feeds a credential
+// helper, up to EOF or a blank line.
+func parseGitCredentialInput(r io.Reader) (res map[string]string, err error) {
+	res = make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			break
+		}
+		toks := strings.SplitN(line, "=", 2)
+		if len(toks) != 2 {
+			return nil, fmt.Errorf("malformed credential input line: %s", line)
+		}
+		res[toks[0]] = toks[1]
+	}
+	return res, scanner.Err()
+}
+
+// repoAllowed applies the entry's per-repo policy to the path git gave us.
+func repoAllowed(cred gitHostCredential, repoPath string) bool {
+	if len(cred.Repos) == 0 {
+		return true
+	}
+	repoPath = strings.TrimSuffix(strings.TrimPrefix(repoPath, "/"), ".git")
+	for _, pattern := range cred.Repos {
+		if matched, err := path.Match(pattern, repoPath); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CmdGitCredentialHelper) audit(host, repoPath, outcome string) {
+	line := fmt.Sprintf("%s op=%s team=%s host=%s repo=%s outcome=%s\n",
+		time.Now().UTC().Format(time.RFC3339), c.operation, c.team, host, repoPath, outcome)
+	logPath := filepath.Join(c.G().Env.GetLogDir(), gitCredentialAuditFile)
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, libkb.PermFile)
+	if err != nil {
+		c.G().Log.Debug("credential-helper: failed to open audit log: %s", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		c.G().Log.Debug("credential-helper: failed to write audit log: %s", err)
+	}
+}
+
+func (c *CmdGitCredentialHelper) Run() error {
+	stdin := c.stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	input, err := parseGitCredentialInput(stdin)
+	if err != nil {
+		return err
+	}
+	host := input["host"]
+	if len(host) == 0 {
+		return fmt.Errorf("no host in credential input")
+	}
+	cli, err := GetKVStoreClient(c.G())
+	if err != nil {
+		return err
+	}
+	switch c.operation {
+	case "get":
+		return c.runGet(cli, host, input["path"])
+	case "store":
+		return c.runStore(cli, host, input)
+	case "erase":
+		return c.runErase(cli, host)
+	}
+	return nil
+}
+
+func (c *CmdGitCredentialHelper) fetch(cli keybase1.KvstoreClient, host string) (
+	cred gitHostCredential, revision int, found bool, err error) {
+	res, err := cli.GetKVEntry(context.TODO(), keybase1.GetKVEntryArg{
+		TeamName:  c.team,
+		Namespace: gitCredentialNamespace,
+		EntryKey:  host,
+	})
+	if err != nil {
+		return cred, 0, false, err
+	}
+	if res.EntryValue == nil {
+		return cred, res.Revision, false, nil
+	}
+	if err := json.Unmarshal([]byte(*res.EntryValue), &cred); err != nil {
+		return cred, 0, false, err
+	}
+	return cred, res.Revision, true, nil
+}
+
+func (c *CmdGitCredentialHelper) runGet(cli keybase1.KvstoreClient, host, repoPath string) error {
+	cred, _, found, err := c.fetch(cli, host)
+	if err != nil {
+		return err
+	}
+	if !found {
+		c.audit(host, repoPath, "miss")
+		return nil
+	}
+	if !repoAllowed(cred, repoPath) {
+		c.audit(host, repoPath, "denied")
+		return nil
+	}
+	c.audit(host, repoPath, "served")
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("username=%s\n", cred.Username)
+	ui.Printf("password=%s\n", cred.Password)
+	return nil
+}
+
+func (c *CmdGitCredentialHelper) runStore(cli keybase1.KvstoreClient, host string,
+	input map[string]string) error {
+	cred, revision, found, err := c.fetch(cli, host)
+	if err != nil {
+		return err
+	}
+	cred.Username = input["username"]
+	cred.Password = input["password"]
+	dat, err := json.Marshal(cred)
+	if err != nil {
+		return err
+	}
+	if !found {
+		revision = 0
+	}
+	if _, err := cli.PutKVEntry(context.TODO(), keybase1.PutKVEntryArg{
+		TeamName:   c.team,
+		Namespace:  gitCredentialNamespace,
+		EntryKey:   host,
+		Revision:   revision + 1,
+		EntryValue: string(dat),
+	}); err != nil {
+		return err
+	}
+	c.audit(host, "", "stored")
+	return nil
+}
+
+func (c *CmdGitCredentialHelper) runErase(cli keybase1.KvstoreClient, host string) error {
+	_, revision, found, err := c.fetch(cli, host)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	if _, err := cli.DelKVEntry(context.TODO(), keybase1.DelKVEntryArg{
+		TeamName:  c.team,
+		Namespace: gitCredentialNamespace,
+		EntryKey:  host,
+		Revision:  revision + 1,
+	}); err != nil {
+		return err
+	}
+	c.audit(host, "", "erased")
+	return nil
+}
+
+func (c *CmdGitCredentialHelper) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
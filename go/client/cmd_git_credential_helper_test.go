@@ -0,0 +1,36 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCredentialInputParsing(t *testing.T) {
+	input, err := parseGitCredentialInput(strings.NewReader(
+		"protocol=https\nhost=github.com\npath=acme/widgets.git\n\nignored=yes\n"))
+	require.NoError(t, err)
+	require.Equal(t, "github.com", input["host"])
+	require.Equal(t, "acme/widgets.git", input["path"])
+	_, ok := input["ignored"]
+	require.False(t, ok)
+
+	_, err = parseGitCredentialInput(strings.NewReader("no equals sign\n"))
+	require.Error(t, err)
+}
+
+func TestGitCredentialRepoPolicy(t *testing.T) {
+	open := gitHostCredential{Username: "bot", Password: "x"}
+	require.True(t, repoAllowed(open, "anything/goes"))
+
+	scoped := gitHostCredential{Repos: []string{"acme/*", "infra/terraform"}}
+	require.True(t, repoAllowed(scoped, "acme/widgets"))
+	require.True(t, repoAllowed(scoped, "/acme/widgets.git"))
+	require.True(t, repoAllowed(scoped, "infra/terraform"))
+	require.False(t, repoAllowed(scoped, "infra/secrets"))
+	require.False(t, repoAllowed(scoped, "acme/widgets/nested"))
+}
@@ -4,8 +4,11 @@
 package client
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
@@ -24,8 +27,14 @@ type CmdID struct {
 	useDelegateUI  bool
 	skipProofCache bool
 	forceDisplay   bool
+	batch          bool
 }
 
+// idBatchInterval spaces out identifies in batch mode so scripts piping in
+// long user lists don't hammer the proof checkers. The identify engine's
+// identity cache absorbs repeats within its freshness window.
+const idBatchInterval = 250 * time.Millisecond
+
 func (v *CmdID) ParseArgv(ctx *cli.Context) error {
 	nargs := len(ctx.Args())
 	if nargs > 1 {
@@ -39,6 +48,18 @@ func (v *CmdID) ParseArgv(ctx *cli.Context) error {
 	v.useDelegateUI = ctx.Bool("ui")
 	v.skipProofCache = ctx.Bool("skip-proof-cache")
 	v.forceDisplay = ctx.Bool("force-display")
+	v.batch = ctx.Bool("batch")
+	if v.batch {
+		if !v.json {
+			return fmt.Errorf("--batch requires --json")
+		}
+		if nargs > 0 {
+			return fmt.Errorf("--batch reads usernames from stdin; don't pass one as an argument")
+		}
+		if v.useDelegateUI || v.forceDisplay {
+			return fmt.Errorf("--batch is incompatible with --ui and --force-display")
+		}
+	}
 	return nil
 }
 
@@ -58,6 +79,9 @@ func (v *CmdID) makeArg() keybase1.Identify2Arg {
 }
 
 func (v *CmdID) Run() error {
+	if v.batch {
+		return v.runBatch()
+	}
 	var cli keybase1.IdentifyClient
 	protocols := []rpc.Protocol{}
 
@@ -91,6 +115,93 @@ Or log in once on this device and run "keybase id" again.
 	return err
 }
 
+// idBatchResult is one line of `keybase id --json --batch` output.
+type idBatchResult struct {
+	Input    string           `json:"input"`
+	Error    string           `json:"error,omitempty"`
+	Identity *idCmdIdentifyUI `json:"identity,omitempty"`
+}
+
+// runBatch resolves one username or assertion per stdin line, spacing out
+// calls and deduping repeated inputs. Each identify rides the usual engine
+// path, so the identity cache serves anything looked up recently. Output is
+// a JSON array in input order; per-user failures land in the array rather
+// than aborting the run.
+func (v *CmdID) runBatch() error {
+	cli, err := GetIdentifyClient(v.G())
+	if err != nil {
+		return err
+	}
+	wrapper := &idCmdBatchUIWrapper{UI: v.G().UI}
+	v.G().UI = wrapper
+	protocols := []rpc.Protocol{NewIdentifyUIProtocol(v.G())}
+	if err := RegisterProtocolsWithContext(protocols, v.G()); err != nil {
+		return err
+	}
+
+	var results []idBatchResult
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(os.Stdin)
+	first := true
+	for scanner.Scan() {
+		input := strings.TrimSpace(scanner.Text())
+		if len(input) == 0 {
+			continue
+		}
+		if index, ok := seen[input]; ok {
+			results = append(results, results[index])
+			continue
+		}
+		if !first {
+			time.Sleep(idBatchInterval)
+		}
+		first = false
+		v.user = input
+		arg := v.makeArg()
+		arg.CanSuppressUI = true
+		wrapper.reset()
+		_, err := cli.Identify2(context.TODO(), arg)
+		result := idBatchResult{Input: input, Identity: wrapper.last()}
+		if err != nil {
+			result = idBatchResult{Input: input, Error: err.Error()}
+		}
+		seen[input] = len(results)
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = v.G().UI.GetDumbOutputUI().Printf(string(b) + "\n")
+	return err
+}
+
+// idCmdBatchUIWrapper hands out collecting identify UIs that don't print,
+// keeping the last finished one for the batch loop to pick up.
+type idCmdBatchUIWrapper struct {
+	libkb.UI
+	collector *idCmdIdentifyUI
+}
+
+func (i *idCmdBatchUIWrapper) reset() {
+	i.collector = nil
+}
+
+func (i *idCmdBatchUIWrapper) last() *idCmdIdentifyUI {
+	return i.collector
+}
+
+func (i *idCmdBatchUIWrapper) GetIdentifyUI() libkb.IdentifyUI {
+	i.collector = &idCmdIdentifyUI{
+		parent: i.UI,
+		silent: true,
+	}
+	return i.collector
+}
+
 type idCmdJSONUIWrapper struct {
 	libkb.UI
 }
@@ -103,6 +214,7 @@ func (i idCmdJSONUIWrapper) GetIdentifyUI() libkb.IdentifyUI {
 
 type idCmdIdentifyUI struct {
 	parent           libkb.UI
+	silent           bool
 	Username         string                     `json:"username"`
 	LastTrack        *idCmdIdentifyUILastTrack  `json:"lastTrack"`
 	Cryptocurrencies []keybase1.Cryptocurrency  `json:"cryptocurrencies"`
@@ -143,6 +255,9 @@ func (ui *idCmdIdentifyUI) Cancel(_ libkb.MetaContext) error {
 }
 
 func (ui *idCmdIdentifyUI) Finish(_ libkb.MetaContext) error {
+	if ui.silent {
+		return nil
+	}
 	b, err := json.MarshalIndent(ui, "", "    ")
 	if err != nil {
 		return err
@@ -234,6 +349,10 @@ func NewCmdID(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 				Name:  "s, skip-proof-cache",
 				Usage: "Skip cached proofs, force re-check",
 			},
+			cli.BoolFlag{
+				Name:  "batch",
+				Usage: "Read usernames from stdin, one per line; requires --json",
+			},
 		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdIDRunner(g), "id", c)
@@ -12,6 +12,9 @@ import (
 func NewCmdKVStore(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 	subcommands := []cli.Command{
 		newCmdKVStoreAPI(cl, g),
+		newCmdKVStoreGet(cl, g),
+		newCmdKVStorePut(cl, g),
+		newCmdKVStoreDel(cl, g),
 	}
 	return cli.Command{
 		Name:         "kvstore",
@@ -0,0 +1,220 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Simple single-entry kvstore commands, for small tools that want to
+// persist a bit of state without speaking the JSON API. An empty
+// --team targets the caller's per-user store.
+
+func kvStoreSimpleFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "t, team",
+			Usage: "Team name; defaults to your per-user store.",
+		},
+		cli.StringFlag{
+			Name:  "n, namespace",
+			Usage: "Namespace for the entry.",
+			Value: "default",
+		},
+	}
+}
+
+type CmdKVStoreGet struct {
+	libkb.Contextified
+	team      string
+	namespace string
+	entryKey  string
+}
+
+func newCmdKVStoreGet(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "get",
+		ArgumentHelp: "<key>",
+		Usage:        "Get the value for a key",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdKVStoreGet{Contextified: libkb.NewContextified(g)}, "get", c)
+		},
+		Flags: kvStoreSimpleFlags(),
+	}
+}
+
+func (c *CmdKVStoreGet) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("get requires exactly one key argument")
+	}
+	c.entryKey = ctx.Args()[0]
+	c.team = ctx.String("team")
+	c.namespace = ctx.String("namespace")
+	return nil
+}
+
+func (c *CmdKVStoreGet) Run() error {
+	cli, err := GetKVStoreClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.GetKVEntry(context.TODO(), keybase1.GetKVEntryArg{
+		TeamName:  c.team,
+		Namespace: c.namespace,
+		EntryKey:  c.entryKey,
+	})
+	if err != nil {
+		return err
+	}
+	if res.EntryValue == nil {
+		return fmt.Errorf("entry not found: %s", c.entryKey)
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", *res.EntryValue)
+	return nil
+}
+
+func (c *CmdKVStoreGet) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
+
+type CmdKVStorePut struct {
+	libkb.Contextified
+	team       string
+	namespace  string
+	entryKey   string
+	entryValue string
+	revision   int
+}
+
+func newCmdKVStorePut(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "put",
+		ArgumentHelp: "<key> <value>",
+		Usage:        "Write an encrypted value for a key",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdKVStorePut{Contextified: libkb.NewContextified(g)}, "put", c)
+		},
+		Flags: append(kvStoreSimpleFlags(), cli.IntFlag{
+			Name:  "r, revision",
+			Usage: "Expected next revision, for optimistic concurrency.",
+		}),
+	}
+}
+
+func (c *CmdKVStorePut) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New("put requires a key and a value argument")
+	}
+	c.entryKey = ctx.Args()[0]
+	c.entryValue = ctx.Args()[1]
+	c.team = ctx.String("team")
+	c.namespace = ctx.String("namespace")
+	c.revision = ctx.Int("revision")
+	if c.revision < 0 {
+		return errors.New("--revision must be non-negative")
+	}
+	return nil
+}
+
+func (c *CmdKVStorePut) Run() error {
+	cli, err := GetKVStoreClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.PutKVEntry(context.TODO(), keybase1.PutKVEntryArg{
+		TeamName:   c.team,
+		Namespace:  c.namespace,
+		EntryKey:   c.entryKey,
+		EntryValue: c.entryValue,
+		Revision:   c.revision,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("wrote %s at revision %d\n", res.EntryKey, res.Revision)
+	return nil
+}
+
+func (c *CmdKVStorePut) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
+
+type CmdKVStoreDel struct {
+	libkb.Contextified
+	team      string
+	namespace string
+	entryKey  string
+	revision  int
+}
+
+func newCmdKVStoreDel(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "del",
+		ArgumentHelp: "<key>",
+		Usage:        "Delete the entry for a key",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdKVStoreDel{Contextified: libkb.NewContextified(g)}, "del", c)
+		},
+		Flags: append(kvStoreSimpleFlags(), cli.IntFlag{
+			Name:  "r, revision",
+			Usage: "Expected next revision, for optimistic concurrency.",
+		}),
+	}
+}
+
+func (c *CmdKVStoreDel) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("del requires exactly one key argument")
+	}
+	c.entryKey = ctx.Args()[0]
+	c.team = ctx.String("team")
+	c.namespace = ctx.String("namespace")
+	c.revision = ctx.Int("revision")
+	if c.revision < 0 {
+		return errors.New("--revision must be non-negative")
+	}
+	return nil
+}
+
+func (c *CmdKVStoreDel) Run() error {
+	cli, err := GetKVStoreClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.DelKVEntry(context.TODO(), keybase1.DelKVEntryArg{
+		TeamName:  c.team,
+		Namespace: c.namespace,
+		EntryKey:  c.entryKey,
+		Revision:  c.revision,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("deleted %s at revision %d\n", res.EntryKey, res.Revision)
+	return nil
+}
+
+func (c *CmdKVStoreDel) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
@@ -17,6 +17,8 @@ type CmdListTrackers struct {
 	libkb.Contextified
 	assertion string
 	verbose   bool
+	json      bool
+	opts      followListOptions
 }
 
 // NewCmdListTrackers creates a new cli.Command.
@@ -25,12 +27,16 @@ func NewCmdListTrackers(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.
 		Name:         "list-followers",
 		ArgumentHelp: "<username>",
 		Usage:        "List those who follow you",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			cli.BoolFlag{
 				Name:  "v, verbose",
 				Usage: "A full dump, with more gory details.",
 			},
-		},
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+		}, followListFlags()...),
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdListTrackers{Contextified: libkb.NewContextified(g)}, "list-followers", c)
 		},
@@ -52,17 +58,29 @@ func (c *CmdListTrackers) Run() error {
 	if err != nil {
 		return err
 	}
-	return c.output(uss)
+	users, err := c.opts.apply(uss.Users, func() ([]keybase1.UserSummary, error) {
+		return fetchFollowing(c.G(), c.assertion)
+	})
+	if err != nil {
+		return err
+	}
+	return c.output(users)
 }
 
-func (c *CmdListTrackers) output(uss keybase1.UserSummarySet) (err error) {
+func (c *CmdListTrackers) output(users []keybase1.UserSummary) (err error) {
+	if c.opts.csv {
+		return exportFollowListCSV(c.G(), users)
+	}
+	if c.json {
+		return exportFollowListJSON(c.G(), users)
+	}
 	dui := c.G().UI.GetDumbOutputUI()
-	if len(uss.Users) == 0 {
+	if len(users) == 0 {
 		dui.Printf("no followers\n")
 		return nil
 	}
 
-	for _, user := range uss.Users {
+	for _, user := range users {
 		dui.Printf("%s", user.Username)
 		dui.Printf("\n")
 	}
@@ -76,7 +94,8 @@ func (c *CmdListTrackers) ParseArgv(ctx *cli.Context) error {
 	}
 
 	c.verbose = ctx.Bool("verbose")
-	return nil
+	c.json = ctx.Bool("json")
+	return c.opts.parse(ctx)
 }
 
 // GetUsage says what this command needs to operate.
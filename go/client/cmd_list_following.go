@@ -21,6 +21,7 @@ type CmdListTracking struct {
 	json      bool
 	verbose   bool
 	headers   bool
+	opts      followListOptions
 }
 
 func (s *CmdListTracking) ParseArgv(ctx *cli.Context) error {
@@ -35,7 +36,7 @@ func (s *CmdListTracking) ParseArgv(ctx *cli.Context) error {
 	s.headers = ctx.Bool("headers")
 	s.filter = ctx.String("filter")
 
-	return nil
+	return s.opts.parse(ctx)
 }
 
 func displayTable(g *libkb.GlobalContext, entries []keybase1.UserSummary, verbose bool, headers bool) (err error) {
@@ -114,7 +115,16 @@ func (s *CmdListTracking) Run() error {
 	if err != nil {
 		return err
 	}
-	return displayTable(s.G(), ret.Users, s.verbose, s.headers)
+	users, err := s.opts.apply(ret.Users, func() ([]keybase1.UserSummary, error) {
+		return fetchFollowers(s.G(), s.assertion)
+	})
+	if err != nil {
+		return err
+	}
+	if s.opts.csv {
+		return exportFollowListCSV(s.G(), users)
+	}
+	return displayTable(s.G(), users, s.verbose, s.headers)
 }
 
 func NewCmdListTracking(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
@@ -125,7 +135,7 @@ func NewCmdListTracking(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdListTracking{Contextified: libkb.NewContextified(g)}, "following", c)
 		},
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			cli.StringFlag{
 				Name:  "f, filter",
 				Usage: "Provide a regex filter.",
@@ -142,7 +152,7 @@ func NewCmdListTracking(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.
 				Name:  "v, verbose",
 				Usage: "A full dump, with more gory details.",
 			},
-		},
+		}, followListFlags()...),
 	}
 }
 
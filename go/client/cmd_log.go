@@ -15,6 +15,8 @@ func NewCmdLog(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 	subcommands := []cli.Command{
 		NewCmdLogSend(cl, g),
 		NewCmdLogProfile(cl, g),
+		NewCmdLogSetLevel(cl, g),
+		NewCmdLogKeyUsage(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificLogCommands(cl, g)...)
 	sort.Sort(cli.ByName(subcommands))
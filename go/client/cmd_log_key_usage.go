@@ -0,0 +1,88 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdLogKeyUsage dumps the service's audit trail of local secret key
+// usage, for users investigating suspicious activity on their account.
+type CmdLogKeyUsage struct {
+	libkb.Contextified
+	json bool
+}
+
+func NewCmdLogKeyUsage(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "key-usage",
+		Usage: "Show recent local secret key usage",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdLogKeyUsage{Contextified: libkb.NewContextified(g)}, "key-usage", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output the raw audit trail as JSON.",
+			},
+		},
+	}
+}
+
+func (c *CmdLogKeyUsage) Run() error {
+	cli, err := GetDebuggingClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.Script(context.TODO(), keybase1.ScriptArg{
+		Script: "key-usage",
+	})
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.TrimSpace(res), "[") {
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+		return nil
+	}
+	if c.json {
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", res)
+		return nil
+	}
+	var entries []libkb.KeyUsageEntry
+	if err := json.Unmarshal([]byte(res), &entries); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.G().UI.GetTerminalUI().OutputWriter(), 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "TIME\tOPERATION\tKID\tDETAIL\n")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Time.Format("2006-01-02 15:04:05"),
+			entry.Operation, entry.KID, entry.Detail)
+	}
+	return w.Flush()
+}
+
+func (c *CmdLogKeyUsage) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("key-usage takes no arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdLogKeyUsage) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
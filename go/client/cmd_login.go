@@ -33,6 +33,10 @@ func NewCmdLogin(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 			Name:  "devicename",
 			Usage: "Device name used in automated provisioning",
 		},
+		cli.BoolFlag{
+			Name:  "headless",
+			Usage: "Provision without prompting: print the verification code and wait for 'keybase device add' on an existing device. Requires -devicename.",
+		},
 	}
 	cmd := cli.Command{
 		Name:         "login",
@@ -70,6 +74,7 @@ type CmdLogin struct {
 
 	PaperKey   string
 	DeviceName string
+	Headless   bool
 
 	clientType keybase1.ClientType
 	cancel     func()
@@ -86,8 +91,12 @@ func NewCmdLoginRunner(g *libkb.GlobalContext) *CmdLogin {
 }
 
 func (c *CmdLogin) Run() error {
+	provisionUI := NewProvisionUIProtocol(c.G(), libkb.KexRoleProvisionee)
+	if c.Headless {
+		provisionUI = NewHeadlessProvisionUIProtocol(c.G(), c.DeviceName)
+	}
 	protocols := []rpc.Protocol{
-		NewProvisionUIProtocol(c.G(), libkb.KexRoleProvisionee),
+		provisionUI,
 		NewLoginUIProtocol(c.G()),
 		NewSecretUIProtocol(c.G()),
 		NewGPGUIProtocol(c.G()),
@@ -111,7 +120,7 @@ func (c *CmdLogin) Run() error {
 	}()
 
 	var paperKey string
-	if c.DeviceName != "" {
+	if c.DeviceName != "" && !c.Headless {
 		paperKey, err = c.getPaperKey()
 		if err != nil {
 			return err
@@ -169,6 +178,15 @@ func (c *CmdLogin) ParseArgv(ctx *cli.Context) error {
 
 	c.PaperKey = c.getOption(ctx, "paperkey")
 	c.DeviceName = c.getOption(ctx, "devicename")
+	c.Headless = ctx.Bool("headless")
+	if c.Headless {
+		if c.DeviceName == "" {
+			return errors.New("-headless requires a device name; pass it with -devicename")
+		}
+		if !libkb.CheckDeviceName.F(c.DeviceName) {
+			return fmt.Errorf("Invalid device name. %s", libkb.CheckDeviceName.Hint)
+		}
+	}
 
 	return nil
 }
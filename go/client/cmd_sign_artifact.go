@@ -0,0 +1,510 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/kbcrypto"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/go-crypto/ed25519"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// artifact signatures live in the team's KBFS folder: a signers.json with
+// the designated signing members, and one record per signed artifact hash.
+const artifactSigDir = ".signatures"
+const artifactSignersFile = "signers.json"
+
+type artifactSigners struct {
+	Team       string   `json:"team"`
+	Signers    []string `json:"signers"`
+	Designator string   `json:"designator"`
+	PublicKey  string   `json:"publicKey"`
+	Sig        string   `json:"sig"`
+}
+
+// signedMsg is the message the designator signs over the signers list. It
+// includes the team name so a list can't be replayed into another team's
+// folder.
+func (s artifactSigners) signedMsg() []byte {
+	return []byte(fmt.Sprintf("keybase artifact signers %s: %s",
+		s.Team, strings.Join(s.Signers, ",")))
+}
+
+type artifactSignature struct {
+	Artifact  string    `json:"artifact"`
+	Sha256    string    `json:"sha256"`
+	Signer    string    `json:"signer"`
+	PublicKey string    `json:"publicKey"`
+	Sig       string    `json:"sig"`
+	Ctime     time.Time `json:"ctime"`
+}
+
+func artifactSigPath(team, name string) keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s/%s", team, artifactSigDir, name))
+}
+
+func artifactHashFile(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func artifactReadFile(ctx context.Context, cli keybase1.SimpleFSClient, path keybase1.Path) (
+	res []byte, err error) {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	}); err != nil {
+		return nil, err
+	}
+	var offset int64
+	for {
+		content, err := cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   1024 * 1024,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(content.Data) == 0 {
+			break
+		}
+		res = append(res, content.Data...)
+		offset += int64(len(content.Data))
+	}
+	return res, nil
+}
+
+func artifactWriteFile(ctx context.Context, cli keybase1.SimpleFSClient, team string,
+	path keybase1.Path, dat []byte) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	if err := cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s", team, artifactSigDir)),
+		Flags: keybase1.OpenFlags_DIRECTORY,
+	}); err != nil {
+		return err
+	}
+	cli.SimpleFSClose(ctx, opid)
+	if opid, err = cli.SimpleFSMakeOpid(ctx); err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_WRITE | keybase1.OpenFlags_REPLACE,
+	}); err != nil {
+		return err
+	}
+	return cli.SimpleFSWrite(ctx, keybase1.SimpleFSWriteArg{
+		OpID:    opid,
+		Content: dat,
+	})
+}
+
+// artifactTeamAdmin reports whether username currently holds the admin or
+// owner role in the team.
+func artifactTeamAdmin(ctx context.Context, g *libkb.GlobalContext, team,
+	username string) (bool, error) {
+	teamsCli, err := GetTeamsClient(g)
+	if err != nil {
+		return false, err
+	}
+	details, err := teamsCli.TeamGet(ctx, keybase1.TeamGetArg{Name: team})
+	if err != nil {
+		return false, err
+	}
+	nu := libkb.NewNormalizedUsername(username)
+	for _, members := range [][]keybase1.TeamMemberDetails{
+		details.Members.Owners, details.Members.Admins} {
+		for _, member := range members {
+			if libkb.NewNormalizedUsername(member.Username).Eq(nu) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// artifactLoadSignersVerified loads the signers list and authenticates it:
+// the list must carry a valid signature from a currently-valid key
+// belonging to its designator, and the designator must hold the admin or
+// owner role in the team. Without these checks, anyone with write access
+// to the team folder could install their own list.
+func artifactLoadSignersVerified(ctx context.Context, g *libkb.GlobalContext,
+	fsCli keybase1.SimpleFSClient, team string) (res artifactSigners, err error) {
+	dat, err := artifactReadFile(ctx, fsCli, artifactSigPath(team, artifactSignersFile))
+	if err != nil {
+		return res, fmt.Errorf("no designated signers for %s", team)
+	}
+	if err := json.Unmarshal(dat, &res); err != nil {
+		return res, err
+	}
+	if !libkb.NewNormalizedUsername(res.Team).Eq(libkb.NewNormalizedUsername(team)) {
+		return res, fmt.Errorf("signers list is for team %q, not %q", res.Team, team)
+	}
+	pub, err := hex.DecodeString(res.PublicKey)
+	if err != nil {
+		return res, err
+	}
+	sig, err := hex.DecodeString(res.Sig)
+	if err != nil {
+		return res, err
+	}
+	if err := artifactCheckUserKey(ctx, g, res.Designator, pub); err != nil {
+		return res, err
+	}
+	if !ed25519.Verify(pub, res.signedMsg(), sig) {
+		return res, fmt.Errorf("signers list signature verification failed for %s", team)
+	}
+	isAdmin, err := artifactTeamAdmin(ctx, g, team, res.Designator)
+	if err != nil {
+		return res, err
+	}
+	if !isAdmin {
+		return res, fmt.Errorf(
+			"signers list for %s was designated by %s, who is not an admin or owner",
+			team, res.Designator)
+	}
+	return res, nil
+}
+
+func (s artifactSigners) allowed(username string) bool {
+	for _, signer := range s.Signers {
+		if libkb.NewNormalizedUsername(signer).Eq(libkb.NewNormalizedUsername(username)) {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactCheckUserKey checks that pub is a currently-valid signing key
+// belonging to the given user, per their sigchain. A key embedded in a
+// record in the team folder proves nothing by itself, since anyone who can
+// write the record can write the key.
+func artifactCheckUserKey(ctx context.Context, g *libkb.GlobalContext,
+	username string, pub []byte) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("bad public key size for %s", username)
+	}
+	userCli, err := GetUserClient(g)
+	if err != nil {
+		return err
+	}
+	user, err := userCli.LoadUserByName(ctx, keybase1.LoadUserByNameArg{Username: username})
+	if err != nil {
+		return err
+	}
+	them, err := userCli.LoadUserPlusKeysV2(ctx, keybase1.LoadUserPlusKeysV2Arg{Uid: user.Uid})
+	if err != nil {
+		return err
+	}
+	var naclKey kbcrypto.NaclSigningKeyPublic
+	copy(naclKey[:], pub)
+	key, found := them.Current.DeviceKeys[naclKey.GetKID()]
+	switch {
+	case !found:
+		return fmt.Errorf("signing key does not belong to %s", username)
+	case key.Base.Revocation != nil:
+		return fmt.Errorf("signing key for %s has been revoked", username)
+	case !key.Base.IsSibkey:
+		return fmt.Errorf("signing key for %s is not a sibkey", username)
+	}
+	return nil
+}
+
+func artifactAnnounce(g *libkb.GlobalContext, team, message string) {
+	err := chatSend(context.TODO(), g, ChatSendArg{
+		resolvingRequest: chatConversationResolvingRequest{
+			TlfName:     team,
+			TopicName:   globals.DefaultTeamTopic,
+			MembersType: chat1.ConversationMembersType_TEAM,
+			TopicType:   chat1.TopicType_CHAT,
+			Visibility:  keybase1.TLFVisibility_PRIVATE,
+		},
+		message: message,
+		team:    true,
+	})
+	if err != nil {
+		g.Log.Warning("failed to post to team chat: %s", err)
+	}
+}
+
+// CmdSignArtifact is the 'sign-artifact' command.
+type CmdSignArtifact struct {
+	libkb.Contextified
+	team      string
+	filename  string
+	request   bool
+	designate []string
+}
+
+// NewCmdSignArtifact creates the sign-artifact command. Signing requests and
+// completed signatures are announced in the team's chat channel, so
+// approvals happen where the team already talks.
+func NewCmdSignArtifact(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "sign-artifact",
+		ArgumentHelp: "<team> [filename]",
+		Usage:        "Sign an artifact hash with a team-designated signing key",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSignArtifact{Contextified: libkb.NewContextified(g)}, "sign-artifact", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "request",
+				Usage: "Post a signing request to team chat instead of signing",
+			},
+			cli.StringFlag{
+				Name:  "designate",
+				Usage: "Comma-separated usernames allowed to sign for this team",
+			},
+		},
+	}
+}
+
+func (c *CmdSignArtifact) ParseArgv(ctx *cli.Context) error {
+	if designate := ctx.String("designate"); len(designate) > 0 {
+		if len(ctx.Args()) != 1 {
+			return fmt.Errorf("team name required")
+		}
+		c.team = ctx.Args()[0]
+		c.designate = strings.Split(designate, ",")
+		return nil
+	}
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("team name and filename required")
+	}
+	c.team = ctx.Args()[0]
+	c.filename = ctx.Args()[1]
+	c.request = ctx.Bool("request")
+	return nil
+}
+
+func (c *CmdSignArtifact) Run() error {
+	ctx := context.TODO()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	if len(c.designate) > 0 {
+		username := c.G().Env.GetUsername().String()
+		isAdmin, err := artifactTeamAdmin(ctx, c.G(), c.team, username)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return fmt.Errorf(
+				"designating signers requires the admin or owner role in %s", c.team)
+		}
+		signers := artifactSigners{
+			Team:       c.team,
+			Signers:    c.designate,
+			Designator: username,
+		}
+		rcli, _, err := GetRPCClientWithContext(c.G())
+		if err != nil {
+			return err
+		}
+		sigInfo, err := keybase1.CryptoClient{Cli: rcli}.SignED25519(ctx, keybase1.SignED25519Arg{
+			Msg:    signers.signedMsg(),
+			Reason: fmt.Sprintf("designate artifact signers for team %s", c.team),
+		})
+		if err != nil {
+			return err
+		}
+		signers.PublicKey = hex.EncodeToString(sigInfo.PublicKey[:])
+		signers.Sig = hex.EncodeToString(sigInfo.Sig[:])
+		dat, err := json.MarshalIndent(signers, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := artifactWriteFile(ctx, fsCli, c.team,
+			artifactSigPath(c.team, artifactSignersFile), dat); err != nil {
+			return err
+		}
+		c.G().UI.GetTerminalUI().Printf("Designated signers for %s: %s\n", c.team,
+			strings.Join(c.designate, ", "))
+		return nil
+	}
+	hash, err := artifactHashFile(c.filename)
+	if err != nil {
+		return err
+	}
+	username := c.G().Env.GetUsername().String()
+	if c.request {
+		artifactAnnounce(c.G(), c.team, fmt.Sprintf(
+			"Signature requested for %s (sha256 %s). A designated signer can run: keybase sign-artifact %s <path>",
+			c.filename, hash, c.team))
+		return nil
+	}
+	signers, err := artifactLoadSignersVerified(ctx, c.G(), fsCli, c.team)
+	if err != nil {
+		return err
+	}
+	if !signers.allowed(username) {
+		return fmt.Errorf("%s is not a designated signer for %s", username, c.team)
+	}
+	rcli, _, err := GetRPCClientWithContext(c.G())
+	if err != nil {
+		return err
+	}
+	sigInfo, err := keybase1.CryptoClient{Cli: rcli}.SignED25519(ctx, keybase1.SignED25519Arg{
+		Msg:    []byte(hash),
+		Reason: fmt.Sprintf("sign artifact %s for team %s", c.filename, c.team),
+	})
+	if err != nil {
+		return err
+	}
+	record := artifactSignature{
+		Artifact:  c.filename,
+		Sha256:    hash,
+		Signer:    username,
+		PublicKey: hex.EncodeToString(sigInfo.PublicKey[:]),
+		Sig:       hex.EncodeToString(sigInfo.Sig[:]),
+		Ctime:     time.Now().UTC(),
+	}
+	dat, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := artifactWriteFile(ctx, fsCli, c.team,
+		artifactSigPath(c.team, hash+".json"), dat); err != nil {
+		return err
+	}
+	artifactAnnounce(c.G(), c.team, fmt.Sprintf("%s signed %s (sha256 %s)", username,
+		c.filename, hash))
+	c.G().UI.GetTerminalUI().Printf("Signed %s (sha256 %s)\n", c.filename, hash)
+	return nil
+}
+
+func (c *CmdSignArtifact) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdVerifyArtifact is the 'verify-artifact' command.
+type CmdVerifyArtifact struct {
+	libkb.Contextified
+	team     string
+	filename string
+}
+
+func NewCmdVerifyArtifact(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "verify-artifact",
+		ArgumentHelp: "<team> <filename>",
+		Usage:        "Verify an artifact against the team's stored signatures",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdVerifyArtifact{Contextified: libkb.NewContextified(g)}, "verify-artifact", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdVerifyArtifact) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("team name and filename required")
+	}
+	c.team = ctx.Args()[0]
+	c.filename = ctx.Args()[1]
+	return nil
+}
+
+func (c *CmdVerifyArtifact) Run() error {
+	ctx := context.TODO()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	hash, err := artifactHashFile(c.filename)
+	if err != nil {
+		return err
+	}
+	dat, err := artifactReadFile(ctx, fsCli, artifactSigPath(c.team, hash+".json"))
+	if err != nil {
+		return fmt.Errorf("no signature found for %s (sha256 %s)", c.filename, hash)
+	}
+	var record artifactSignature
+	if err := json.Unmarshal(dat, &record); err != nil {
+		return err
+	}
+	if record.Sha256 != hash {
+		return fmt.Errorf("signature record hash mismatch")
+	}
+	pub, err := hex.DecodeString(record.PublicKey)
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(record.Sig)
+	if err != nil {
+		return err
+	}
+	// The record's embedded key is writable by anyone with access to the
+	// team folder, so bind it to the claimed signer's identity before
+	// trusting the signature.
+	if err := artifactCheckUserKey(ctx, c.G(), record.Signer, pub); err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, []byte(hash), sig) {
+		return fmt.Errorf("signature verification failed for %s", c.filename)
+	}
+	signers, err := artifactLoadSignersVerified(ctx, c.G(), fsCli, c.team)
+	if err != nil {
+		return err
+	}
+	if !signers.allowed(record.Signer) {
+		return fmt.Errorf("signature valid but %s is not a designated signer for %s",
+			record.Signer, c.team)
+	}
+	c.G().UI.GetTerminalUI().Printf("Verified: signed by %s at %s\n", record.Signer,
+		record.Ctime.Format(time.RFC3339))
+	return nil
+}
+
+func (c *CmdVerifyArtifact) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
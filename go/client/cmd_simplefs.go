@@ -49,12 +49,18 @@ func NewCmdSimpleFS(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comm
 			NewCmdSimpleFSQuota(cl, g),
 			NewCmdSimpleFSRecover(cl, g),
 			NewCmdSimpleFSReset(cl, g),
+			NewCmdSimpleFSConflicts(cl, g),
 			NewCmdSimpleFSClearConflicts(cl, g),
 			NewCmdSimpleFSFinishResolvingConflicts(cl, g),
 			NewCmdSimpleFSSync(cl, g),
+			NewCmdSimpleFSSyncDir(cl, g),
 			NewCmdSimpleFSUploads(cl, g),
 			NewCmdSimpleFSCancelUploads(cl, g),
 			NewCmdSimpleFSSearch(cl, g),
+			NewCmdSimpleFSShare(cl, g),
+			NewCmdSimpleFSShareFile(cl, g),
+			NewCmdSimpleFSShareFetch(cl, g),
+			NewCmdSimpleFSShareExpire(cl, g),
 			NewCmdSimpleFSResetIndex(cl, g),
 			NewCmdSimpleFSIndexProgress(cl, g),
 		}, getBuildSpecificFSCommands(cl, g)...),
@@ -0,0 +1,408 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Conflict copies come in two flavors: file-level copies made by conflict
+// resolution ("foo.conflicted (alice's laptop copy 2026-01-02).txt") and
+// names carrying the TLF-style "(conflicted copy ...)" extension. Both are
+// matched here so the scanner finds everything a user might have lying
+// around after a resolved conflict.
+var conflictCopyRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`^(.*)\.conflicted \([^)]* copy \d{4}-\d{2}-\d{2}(?: #\d+)?\)(\.[^/]*)?$`),
+	regexp.MustCompile(`^(.*) \((?:local )?conflicted copy [^)]*\)(\.[^/]*)?$`),
+}
+
+// conflictCopyOriginal returns the name the conflict copy was made from,
+// and whether the given name is a conflict copy at all.
+func conflictCopyOriginal(name string) (original string, ok bool) {
+	for _, re := range conflictCopyRegexps {
+		if m := re.FindStringSubmatch(name); m != nil {
+			return m[1] + m[2], true
+		}
+	}
+	return "", false
+}
+
+type conflictCopy struct {
+	// conflictPath and originalPath are full KBFS path strings.
+	conflictPath string
+	originalPath string
+	conflictSize int
+	// originalExists is false for orphans whose original was removed.
+	originalExists bool
+	originalSize   int
+	// identical is only meaningful when originalExists is true.
+	identical bool
+}
+
+func (c conflictCopy) state() string {
+	switch {
+	case !c.originalExists:
+		return "orphaned"
+	case c.identical:
+		return "identical"
+	default:
+		return "differs"
+	}
+}
+
+// NewCmdSimpleFSConflicts creates the conflicts command, which holds the
+// subcommands for finding and cleaning up conflict copies.
+func NewCmdSimpleFSConflicts(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "conflicts",
+		Usage:        "Find and clean up conflict copies",
+		ArgumentHelp: "[arguments...]",
+		Subcommands: []cli.Command{
+			NewCmdSimpleFSConflictsList(cl, g),
+			NewCmdSimpleFSConflictsCleanup(cl, g),
+		},
+	}
+}
+
+// CmdSimpleFSConflictsList is the 'fs conflicts list' command.
+type CmdSimpleFSConflictsList struct {
+	libkb.Contextified
+	path keybase1.Path
+}
+
+// NewCmdSimpleFSConflictsList creates a new cli.Command.
+func NewCmdSimpleFSConflictsList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "list",
+		ArgumentHelp: "<path>",
+		Usage:        "list conflict copies under a KBFS path",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSConflictsList{Contextified: libkb.NewContextified(g)}, "list", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdSimpleFSConflictsList) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	copies, err := scanConflictCopies(ctx, cli, c.path)
+	if err != nil {
+		return err
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	if len(copies) == 0 {
+		ui.Printf("No conflict copies found.\n")
+		return nil
+	}
+	w := tabwriter.NewWriter(ui.OutputWriter(), 2, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "CONFLICT COPY\tORIGINAL\tSTATE\n")
+	for _, cc := range copies {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", cc.conflictPath, cc.originalPath, cc.state())
+	}
+	return w.Flush()
+}
+
+// ParseArgv gets the required path argument.
+func (c *CmdSimpleFSConflictsList) ParseArgv(ctx *cli.Context) error {
+	var err error
+	c.path, err = parseConflictsPathArg(ctx, "list")
+	return err
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdSimpleFSConflictsList) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdSimpleFSConflictsCleanup is the 'fs conflicts cleanup' command.
+type CmdSimpleFSConflictsCleanup struct {
+	libkb.Contextified
+	path   keybase1.Path
+	dryRun bool
+	force  bool
+}
+
+// NewCmdSimpleFSConflictsCleanup creates a new cli.Command.
+func NewCmdSimpleFSConflictsCleanup(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "cleanup",
+		ArgumentHelp: "<path>",
+		Usage:        "remove conflict copies whose contents match the original",
+		Description: `"keybase fs conflicts cleanup" scans the given KBFS path for conflict
+copies and removes the ones that are byte-for-byte identical to their
+originals. Copies that differ from the original, or whose original no
+longer exists, are never removed; resolve those by hand.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSConflictsCleanup{Contextified: libkb.NewContextified(g)}, "cleanup", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "n, dry-run",
+				Usage: "print what would be removed without removing anything",
+			},
+			cli.BoolFlag{
+				Name:  "f, force",
+				Usage: "remove identical copies without prompting",
+			},
+		},
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdSimpleFSConflictsCleanup) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	copies, err := scanConflictCopies(ctx, cli, c.path)
+	if err != nil {
+		return err
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	var removed, kept int
+	for _, cc := range copies {
+		if !cc.originalExists || !cc.identical {
+			ui.Printf("Keeping %s (%s)\n", cc.conflictPath, cc.state())
+			kept++
+			continue
+		}
+		if c.dryRun {
+			ui.Printf("Would remove %s (identical to %s)\n",
+				cc.conflictPath, cc.originalPath)
+			removed++
+			continue
+		}
+		if !c.force {
+			prompt := fmt.Sprintf("Remove %s (identical to %s)?",
+				cc.conflictPath, cc.originalPath)
+			doIt, err := ui.PromptYesNo(
+				PromptDescriptorFSRemoveConflictCopy, prompt, libkb.PromptDefaultNo)
+			if err != nil {
+				return err
+			}
+			if !doIt {
+				kept++
+				continue
+			}
+		}
+		opid, err := cli.SimpleFSMakeOpid(ctx)
+		if err != nil {
+			return err
+		}
+		err = cli.SimpleFSRemove(ctx, keybase1.SimpleFSRemoveArg{
+			OpID: opid,
+			Path: keybase1.NewPathWithKbfsPath(cc.conflictPath),
+		})
+		if err != nil {
+			return err
+		}
+		err = cli.SimpleFSWait(ctx, opid)
+		if err != nil {
+			return err
+		}
+		ui.Printf("Removed %s\n", cc.conflictPath)
+		removed++
+	}
+	if c.dryRun {
+		ui.Printf("%d conflict copies would be removed, %d kept.\n", removed, kept)
+	} else {
+		ui.Printf("%d conflict copies removed, %d kept.\n", removed, kept)
+	}
+	return nil
+}
+
+// ParseArgv gets the required path argument and the flags.
+func (c *CmdSimpleFSConflictsCleanup) ParseArgv(ctx *cli.Context) error {
+	var err error
+	c.path, err = parseConflictsPathArg(ctx, "cleanup")
+	if err != nil {
+		return err
+	}
+	c.dryRun = ctx.Bool("dry-run")
+	c.force = ctx.Bool("force")
+	return nil
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdSimpleFSConflictsCleanup) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+func parseConflictsPathArg(ctx *cli.Context, name string) (keybase1.Path, error) {
+	if len(ctx.Args()) != 1 {
+		return keybase1.Path{}, fmt.Errorf("%s requires a path argument", name)
+	}
+	p, err := makeSimpleFSPath(ctx.Args()[0])
+	if err != nil {
+		return keybase1.Path{}, err
+	}
+	pathType, err := p.PathType()
+	if err != nil {
+		return keybase1.Path{}, err
+	}
+	if pathType != keybase1.PathType_KBFS {
+		return keybase1.Path{}, fmt.Errorf("%s requires a KBFS path", name)
+	}
+	return p, nil
+}
+
+// scanConflictCopies lists everything under root recursively, pairs up
+// conflict copies with their originals, and compares contents of the pairs
+// where the original still exists.
+func scanConflictCopies(
+	ctx context.Context, cli keybase1.SimpleFSClient, root keybase1.Path) (
+	copies []conflictCopy, err error) {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	err = cli.SimpleFSListRecursive(ctx, keybase1.SimpleFSListRecursiveArg{
+		OpID: opid,
+		Path: root,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = cli.SimpleFSWait(ctx, opid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Entry names are relative to root; sizes let us skip content
+	// comparison for pairs that can't possibly match.
+	sizes := make(map[string]int)
+	var conflictNames []string
+	for {
+		listResult, err := cli.SimpleFSReadList(ctx, opid)
+		if err != nil || len(listResult.Entries) == 0 {
+			break
+		}
+		for _, e := range listResult.Entries {
+			switch e.DirentType {
+			case keybase1.DirentType_FILE, keybase1.DirentType_EXEC:
+			default:
+				continue
+			}
+			sizes[e.Name] = e.Size
+			if _, ok := conflictCopyOriginal(path.Base(e.Name)); ok {
+				conflictNames = append(conflictNames, e.Name)
+			}
+		}
+	}
+
+	rootString := root.Kbfs().Path
+	for _, name := range conflictNames {
+		original, _ := conflictCopyOriginal(path.Base(name))
+		originalName := path.Join(path.Dir(name), original)
+		cc := conflictCopy{
+			conflictPath: path.Join(rootString, name),
+			originalPath: path.Join(rootString, originalName),
+			conflictSize: sizes[name],
+		}
+		if originalSize, ok := sizes[originalName]; ok {
+			cc.originalExists = true
+			cc.originalSize = originalSize
+			if cc.conflictSize == cc.originalSize {
+				cc.identical, err = conflictContentsIdentical(
+					ctx, cli, cc.conflictPath, cc.originalPath)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		copies = append(copies, cc)
+	}
+	return copies, nil
+}
+
+// conflictContentsIdentical compares the contents of two same-sized KBFS
+// files chunk by chunk.
+func conflictContentsIdentical(
+	ctx context.Context, cli keybase1.SimpleFSClient, path1, path2 string) (
+	identical bool, err error) {
+	const compareBufSize = 64 * 1024
+	open := func(p string) (keybase1.OpID, error) {
+		opid, err := cli.SimpleFSMakeOpid(ctx)
+		if err != nil {
+			return keybase1.OpID{}, err
+		}
+		err = cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+			OpID:  opid,
+			Dest:  keybase1.NewPathWithKbfsPath(p),
+			Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+		})
+		if err != nil {
+			return keybase1.OpID{}, err
+		}
+		return opid, nil
+	}
+	opid1, err := open(path1)
+	if err != nil {
+		return false, err
+	}
+	defer cli.SimpleFSClose(ctx, opid1)
+	opid2, err := open(path2)
+	if err != nil {
+		return false, err
+	}
+	defer cli.SimpleFSClose(ctx, opid2)
+
+	var offset int64
+	for {
+		content1, err := cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid1,
+			Offset: offset,
+			Size:   compareBufSize,
+		})
+		if err != nil {
+			return false, err
+		}
+		content2, err := cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid2,
+			Offset: offset,
+			Size:   compareBufSize,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(content1.Data, content2.Data) {
+			return false, nil
+		}
+		if len(content1.Data) == 0 {
+			return true, nil
+		}
+		offset += int64(len(content1.Data))
+	}
+}
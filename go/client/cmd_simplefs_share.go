@@ -0,0 +1,192 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// NewCmdSimpleFSShare creates the `keybase fs share-invite` command tree
+// for explicit TLF share invitations.
+func NewCmdSimpleFSShare(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "share-invite",
+		Usage: "Send, list, and respond to folder share invitations",
+		Subcommands: []cli.Command{
+			newCmdSimpleFSShareSend(cl, g),
+			newCmdSimpleFSShareList(cl, g),
+			newCmdSimpleFSShareRespond(cl, g, true),
+			newCmdSimpleFSShareRespond(cl, g, false),
+		},
+	}
+}
+
+func newCmdSimpleFSShareSend(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "send",
+		Usage:        "Invite someone to a shared folder with a message",
+		ArgumentHelp: "<folder> <user> [-m message]",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareSend{
+				Contextified: libkb.NewContextified(g)}, "send", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "m, message",
+				Usage: "Message to show with the invitation",
+			},
+		},
+	}
+}
+
+type CmdSimpleFSShareSend struct {
+	libkb.Contextified
+	tlf       string
+	assertion string
+	message   string
+}
+
+func (c *CmdSimpleFSShareSend) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("send requires a folder and a user")
+	}
+	c.tlf = ctx.Args()[0]
+	c.assertion = ctx.Args()[1]
+	c.message = ctx.String("message")
+	return nil
+}
+
+func (c *CmdSimpleFSShareSend) Run() error {
+	cli, err := GetAPIServerClient(c.G())
+	if err != nil {
+		return err
+	}
+	_, err = cli.Post(context.TODO(), keybase1.PostArg{
+		Endpoint: "kbfs/share_invite/send",
+		Args: []keybase1.StringKVPair{
+			{Key: "tlf", Value: c.tlf},
+			{Key: "assertion", Value: c.assertion},
+			{Key: "message", Value: c.message},
+		},
+	})
+	return err
+}
+
+func (c *CmdSimpleFSShareSend) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+func newCmdSimpleFSShareList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "List pending and past share invitations",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareList{
+				Contextified: libkb.NewContextified(g)}, "list", c)
+		},
+	}
+}
+
+type CmdSimpleFSShareList struct {
+	libkb.Contextified
+}
+
+func (c *CmdSimpleFSShareList) ParseArgv(ctx *cli.Context) error {
+	return nil
+}
+
+func (c *CmdSimpleFSShareList) Run() error {
+	cli, err := GetAPIServerClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.GetWithSession(context.TODO(), keybase1.GetWithSessionArg{
+		Endpoint: "kbfs/share_invite/list",
+	})
+	if err != nil {
+		return err
+	}
+	var parsed struct {
+		Invites []libkb.TlfInvite `json:"invites"`
+	}
+	if err := json.Unmarshal([]byte(res.Body), &parsed); err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(parsed.Invites) == 0 {
+		dui.Printf("No share invitations.\n")
+		return nil
+	}
+	for _, invite := range parsed.Invites {
+		dui.Printf("%s  %-8s  %s -> %s", invite.ID, invite.State,
+			invite.Inviter, invite.Tlf)
+		if invite.Message != "" {
+			dui.Printf("  %q", invite.Message)
+		}
+		dui.Printf("\n")
+	}
+	return nil
+}
+
+func (c *CmdSimpleFSShareList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+func newCmdSimpleFSShareRespond(cl *libcmdline.CommandLine, g *libkb.GlobalContext, accept bool) cli.Command {
+	name := "decline"
+	usage := "Decline a share invitation"
+	if accept {
+		name = "accept"
+		usage = "Accept a share invitation"
+	}
+	return cli.Command{
+		Name:         name,
+		Usage:        usage,
+		ArgumentHelp: "<invite-id>",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareRespond{
+				Contextified: libkb.NewContextified(g), accept: accept}, name, c)
+		},
+	}
+}
+
+type CmdSimpleFSShareRespond struct {
+	libkb.Contextified
+	inviteID string
+	accept   bool
+}
+
+func (c *CmdSimpleFSShareRespond) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("an invite ID is required")
+	}
+	c.inviteID = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdSimpleFSShareRespond) Run() error {
+	cli, err := GetAPIServerClient(c.G())
+	if err != nil {
+		return err
+	}
+	_, err = cli.Post(context.TODO(), keybase1.PostArg{
+		Endpoint: "kbfs/share_invite/respond",
+		Args: []keybase1.StringKVPair{
+			{Key: "invite_id", Value: c.inviteID},
+			{Key: "accept", Value: fmt.Sprintf("%v", c.accept)},
+		},
+	})
+	return err
+}
+
+func (c *CmdSimpleFSShareRespond) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
@@ -0,0 +1,337 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Exploding file shares copy a file into the sharer/recipient private TLF
+// under a hidden directory, together with an expiry record. The recipient
+// fetches it with `fs share-fetch` until expiry; fetching a one-time share,
+// or sweeping with `fs share-expire`, removes the copy so its blocks become
+// GC-eligible. The payload rides KBFS's normal encryption for that TLF — no
+// extra key material changes hands.
+const explodingShareDir = ".exploding-shares"
+const explodingShareMetaFile = "meta.json"
+
+type explodingShareMeta struct {
+	Name    string    `json:"name"`
+	Sharer  string    `json:"sharer"`
+	Expiry  time.Time `json:"expiry"`
+	OneTime bool      `json:"oneTime"`
+}
+
+func (m explodingShareMeta) expired(now time.Time) bool {
+	return now.After(m.Expiry)
+}
+
+func explodingSharePath(sharer, recipient, elem string) keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/private/%s,%s/%s/%s",
+		sharer, recipient, explodingShareDir, elem))
+}
+
+func explodingShareRemove(ctx context.Context, cli keybase1.SimpleFSClient,
+	path keybase1.Path) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSRemove(ctx, keybase1.SimpleFSRemoveArg{
+		OpID:      opid,
+		Path:      path,
+		Recursive: true,
+	}); err != nil {
+		return err
+	}
+	return cli.SimpleFSWait(ctx, opid)
+}
+
+func explodingShareCopy(ctx context.Context, cli keybase1.SimpleFSClient,
+	src, dest keybase1.Path) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSCopyRecursive(ctx, keybase1.SimpleFSCopyRecursiveArg{
+		OpID: opid,
+		Src:  src,
+		Dest: dest,
+	}); err != nil {
+		return err
+	}
+	return cli.SimpleFSWait(ctx, opid)
+}
+
+// CmdSimpleFSShareFile is the 'fs share' command.
+type CmdSimpleFSShareFile struct {
+	libkb.Contextified
+	src       keybase1.Path
+	recipient string
+	expires   time.Duration
+	oneTime   bool
+}
+
+// NewCmdSimpleFSShareFile creates a new cli.Command.
+func NewCmdSimpleFSShareFile(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "share",
+		ArgumentHelp: "<kbfs-path> <username>",
+		Usage:        "Share a file with a user via a time-limited copy",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareFile{
+				Contextified: libkb.NewContextified(g)}, "share", c)
+		},
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "expires",
+				Usage: "How long the share stays fetchable",
+				Value: 24 * time.Hour,
+			},
+			cli.BoolFlag{
+				Name:  "one-time",
+				Usage: "Remove the share after the first fetch",
+			},
+		},
+	}
+}
+
+func (c *CmdSimpleFSShareFile) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("share requires a KBFS path and a username")
+	}
+	src, err := makeSimpleFSPath(ctx.Args()[0])
+	if err != nil {
+		return err
+	}
+	c.src = src
+	c.recipient = ctx.Args()[1]
+	c.expires = ctx.Duration("expires")
+	if c.expires <= 0 {
+		return fmt.Errorf("--expires must be positive")
+	}
+	c.oneTime = ctx.Bool("one-time")
+	return nil
+}
+
+func (c *CmdSimpleFSShareFile) Run() error {
+	ctx := context.TODO()
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	sharer := c.G().Env.GetUsername().String()
+	shareID, err := libkb.RandHexString("", 8)
+	if err != nil {
+		return err
+	}
+	name := path.Base(c.src.Kbfs().Path)
+	meta := explodingShareMeta{
+		Name:    name,
+		Sharer:  sharer,
+		Expiry:  time.Now().Add(c.expires).UTC(),
+		OneTime: c.oneTime,
+	}
+	dat, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := explodingShareCopy(ctx, cli, c.src,
+		explodingSharePath(sharer, c.recipient, shareID+"/"+name)); err != nil {
+		return err
+	}
+	if err := teamTemplateWriteFile(ctx, cli,
+		explodingSharePath(sharer, c.recipient, shareID+"/"+explodingShareMetaFile), dat); err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Shared %s with %s until %s\n", name, c.recipient,
+		meta.Expiry.Format(time.RFC3339))
+	ui.Printf("They can fetch it with: keybase fs share-fetch %s %s <local-path>\n",
+		sharer, shareID)
+	return nil
+}
+
+func (c *CmdSimpleFSShareFile) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
+
+// CmdSimpleFSShareFetch is the 'fs share-fetch' command.
+type CmdSimpleFSShareFetch struct {
+	libkb.Contextified
+	sharer  string
+	shareID string
+	dest    keybase1.Path
+}
+
+// NewCmdSimpleFSShareFetch creates a new cli.Command.
+func NewCmdSimpleFSShareFetch(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "share-fetch",
+		ArgumentHelp: "<sharer> <share-id> <local-path>",
+		Usage:        "Fetch a file that was shared with you, if it hasn't expired",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareFetch{
+				Contextified: libkb.NewContextified(g)}, "share-fetch", c)
+		},
+	}
+}
+
+func (c *CmdSimpleFSShareFetch) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		return fmt.Errorf("share-fetch requires a sharer, a share ID and a local path")
+	}
+	c.sharer = ctx.Args()[0]
+	c.shareID = ctx.Args()[1]
+	dest, err := makeSimpleFSPath(ctx.Args()[2])
+	if err != nil {
+		return err
+	}
+	c.dest = dest
+	return nil
+}
+
+func (c *CmdSimpleFSShareFetch) Run() error {
+	ctx := context.TODO()
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	recipient := c.G().Env.GetUsername().String()
+	metaPath := explodingSharePath(c.sharer, recipient, c.shareID+"/"+explodingShareMetaFile)
+	dat, err := teamTemplateReadFile(ctx, cli, metaPath)
+	if err != nil {
+		return fmt.Errorf("no share %s from %s", c.shareID, c.sharer)
+	}
+	var meta explodingShareMeta
+	if err := json.Unmarshal(dat, &meta); err != nil {
+		return err
+	}
+	sharePath := explodingSharePath(c.sharer, recipient, c.shareID)
+	if meta.expired(time.Now()) {
+		// expired before anyone swept it; take the copy with us
+		if err := explodingShareRemove(ctx, cli, sharePath); err != nil {
+			c.G().Log.Debug("failed to remove expired share %s: %s", c.shareID, err)
+		}
+		return fmt.Errorf("share %s expired at %s", c.shareID, meta.Expiry.Format(time.RFC3339))
+	}
+	if err := explodingShareCopy(ctx, cli,
+		explodingSharePath(c.sharer, recipient, c.shareID+"/"+meta.Name), c.dest); err != nil {
+		return err
+	}
+	if meta.OneTime {
+		if err := explodingShareRemove(ctx, cli, sharePath); err != nil {
+			return fmt.Errorf("fetched, but failed to remove one-time share: %s", err)
+		}
+	}
+	c.G().UI.GetTerminalUI().Printf("Fetched %s from %s\n", meta.Name, c.sharer)
+	return nil
+}
+
+func (c *CmdSimpleFSShareFetch) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
+
+// CmdSimpleFSShareExpire is the 'fs share-expire' command.
+type CmdSimpleFSShareExpire struct {
+	libkb.Contextified
+	recipient string
+}
+
+// NewCmdSimpleFSShareExpire creates a new cli.Command.
+func NewCmdSimpleFSShareExpire(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "share-expire",
+		ArgumentHelp: "<username>",
+		Usage:        "Remove expired shares with a user so their blocks can be GC'd",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSShareExpire{
+				Contextified: libkb.NewContextified(g)}, "share-expire", c)
+		},
+	}
+}
+
+func (c *CmdSimpleFSShareExpire) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("share-expire requires a username")
+	}
+	c.recipient = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdSimpleFSShareExpire) Run() error {
+	ctx := context.TODO()
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	sharer := c.G().Env.GetUsername().String()
+	dirPath := keybase1.NewPathWithKbfsPath(fmt.Sprintf("/private/%s,%s/%s",
+		sharer, c.recipient, explodingShareDir))
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSList(ctx, keybase1.SimpleFSListArg{
+		OpID: opid,
+		Path: dirPath,
+	}); err != nil {
+		return fmt.Errorf("no shares with %s", c.recipient)
+	}
+	if err := cli.SimpleFSWait(ctx, opid); err != nil {
+		return err
+	}
+	var shareIDs []string
+	for {
+		listResult, err := cli.SimpleFSReadList(ctx, opid)
+		if err != nil || len(listResult.Entries) == 0 {
+			break
+		}
+		for _, entry := range listResult.Entries {
+			if entry.DirentType == keybase1.DirentType_DIR &&
+				!strings.HasPrefix(entry.Name, ".") {
+				shareIDs = append(shareIDs, entry.Name)
+			}
+		}
+	}
+	now := time.Now()
+	var removed int
+	for _, shareID := range shareIDs {
+		dat, err := teamTemplateReadFile(ctx, cli,
+			explodingSharePath(sharer, c.recipient, shareID+"/"+explodingShareMetaFile))
+		if err != nil {
+			continue
+		}
+		var meta explodingShareMeta
+		if err := json.Unmarshal(dat, &meta); err != nil || !meta.expired(now) {
+			continue
+		}
+		if err := explodingShareRemove(ctx, cli,
+			explodingSharePath(sharer, c.recipient, shareID)); err != nil {
+			return err
+		}
+		removed++
+	}
+	c.G().UI.GetTerminalUI().Printf("Removed %d expired share(s) with %s\n",
+		removed, c.recipient)
+	return nil
+}
+
+func (c *CmdSimpleFSShareExpire) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
@@ -0,0 +1,525 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// syncDirMtimeTolerance allows for filesystems that truncate mtimes to
+// whole seconds.
+const syncDirMtimeTolerance = 2 * time.Second
+
+// CmdSimpleFSSyncDir is the 'fs sync-dir' command, an rsync-style
+// synchronizer between a local directory and a KBFS directory.
+type CmdSimpleFSSyncDir struct {
+	libkb.Contextified
+	localRoot  string
+	remoteRoot string
+	twoWay     bool
+	delete     bool
+	dryRun     bool
+	checksum   bool
+	maxDelete  int
+	includes   []string
+	excludes   []string
+}
+
+// NewCmdSimpleFSSyncDir creates a new cli.Command.
+func NewCmdSimpleFSSyncDir(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "sync-dir",
+		ArgumentHelp: "<local-dir> <kbfs-path>",
+		Usage:        "synchronize a local directory with a KBFS directory",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSSyncDir{
+				Contextified: libkb.NewContextified(g)}, "sync-dir", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "n, dry-run",
+				Usage: "Show what would be done without changing anything",
+			},
+			cli.BoolFlag{
+				Name:  "two-way",
+				Usage: "Propagate newer files in both directions",
+			},
+			cli.BoolFlag{
+				Name:  "delete",
+				Usage: "Remove remote files that no longer exist locally",
+			},
+			cli.BoolFlag{
+				Name: "checksum",
+				Usage: "Compare file contents when sizes match but " +
+					"times differ",
+			},
+			cli.IntFlag{
+				Name:  "max-delete",
+				Value: 50,
+				Usage: "Abort if more than this many deletions are needed",
+			},
+			cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "Only sync paths matching the pattern (repeatable)",
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "Skip paths matching the pattern (repeatable)",
+			},
+		},
+	}
+}
+
+// syncDirEntry describes one file or directory on either side of the
+// sync, keyed by its slash-separated path relative to the sync root.
+type syncDirEntry struct {
+	size  int64
+	mtime time.Time
+	isDir bool
+}
+
+// syncDirAction is one planned operation.
+type syncDirAction struct {
+	verb string // "upload", "download", "mkdir", "mkdir-local", "delete"
+	rel  string
+}
+
+func syncDirPatternMatches(patterns []string, rel string) bool {
+	base := path.Base(rel)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, rel); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CmdSimpleFSSyncDir) included(rel string, isDir bool) bool {
+	if syncDirPatternMatches(c.excludes, rel) {
+		return false
+	}
+	// Directories are never filtered by includes so that included files
+	// deeper down can still be reached.
+	if len(c.includes) > 0 && !isDir {
+		return syncDirPatternMatches(c.includes, rel)
+	}
+	return true
+}
+
+func (c *CmdSimpleFSSyncDir) walkLocal() (
+	entries map[string]syncDirEntry, err error) {
+	entries = make(map[string]syncDirEntry)
+	err = filepath.Walk(c.localRoot,
+		func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(c.localRoot, p)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+			rel = filepath.ToSlash(rel)
+			if !c.included(rel, info.IsDir()) {
+				if info.IsDir() && syncDirPatternMatches(c.excludes, rel) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			entries[rel] = syncDirEntry{
+				size:  info.Size(),
+				mtime: info.ModTime(),
+				isDir: info.IsDir(),
+			}
+			return nil
+		})
+	return entries, err
+}
+
+func (c *CmdSimpleFSSyncDir) walkRemote(
+	ctx context.Context, cli keybase1.SimpleFSClient) (
+	entries map[string]syncDirEntry, err error) {
+	remotePath, err := makeSimpleFSPath(c.remoteRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+
+	err = cli.SimpleFSListRecursive(ctx, keybase1.SimpleFSListRecursiveArg{
+		OpID:   opid,
+		Path:   remotePath,
+		Filter: keybase1.ListFilter_NO_FILTER,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = cli.SimpleFSWait(ctx, opid); err != nil {
+		return nil, err
+	}
+
+	entries = make(map[string]syncDirEntry)
+	for {
+		listResult, err := cli.SimpleFSReadList(ctx, opid)
+		if err != nil || len(listResult.Entries) == 0 {
+			break
+		}
+		for _, e := range listResult.Entries {
+			rel := path.Clean(filepath.ToSlash(e.Name))
+			if rel == "." || rel == "/" {
+				continue
+			}
+			isDir := e.DirentType == keybase1.DirentType_DIR
+			if !c.included(rel, isDir) {
+				continue
+			}
+			entries[rel] = syncDirEntry{
+				size:  int64(e.Size),
+				mtime: keybase1.FromTime(e.Time),
+				isDir: isDir,
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (c *CmdSimpleFSSyncDir) localPath(rel string) keybase1.Path {
+	return keybase1.NewPathWithLocal(
+		filepath.Join(c.localRoot, filepath.FromSlash(rel)))
+}
+
+func (c *CmdSimpleFSSyncDir) remotePath(rel string) (keybase1.Path, error) {
+	return makeSimpleFSPath(c.remoteRoot + "/" + rel)
+}
+
+func syncDirLocalFileHash(p string) ([]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func syncDirRemoteFileHash(
+	ctx context.Context, cli keybase1.SimpleFSClient, p keybase1.Path) (
+	[]byte, error) {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	err = cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  p,
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	const bufSize = 1024 * 1024
+	var offset int64
+	for {
+		content, err := cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   bufSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(content.Data) == 0 {
+			break
+		}
+		_, _ = h.Write(content.Data)
+		offset += int64(len(content.Data))
+	}
+	return h.Sum(nil), nil
+}
+
+// contentsMatch reports whether a local and remote file with equal sizes
+// have the same contents, by hashing both sides.
+func (c *CmdSimpleFSSyncDir) contentsMatch(
+	ctx context.Context, cli keybase1.SimpleFSClient, rel string) (
+	bool, error) {
+	localHash, err := syncDirLocalFileHash(
+		filepath.Join(c.localRoot, filepath.FromSlash(rel)))
+	if err != nil {
+		return false, err
+	}
+	remotePath, err := c.remotePath(rel)
+	if err != nil {
+		return false, err
+	}
+	remoteHash, err := syncDirRemoteFileHash(ctx, cli, remotePath)
+	if err != nil {
+		return false, err
+	}
+	return string(localHash) == string(remoteHash), nil
+}
+
+func (c *CmdSimpleFSSyncDir) plan(
+	ctx context.Context, cli keybase1.SimpleFSClient,
+	local, remote map[string]syncDirEntry) (actions []syncDirAction, err error) {
+	rels := make([]string, 0, len(local)+len(remote))
+	seen := make(map[string]bool, len(local)+len(remote))
+	for rel := range local {
+		rels = append(rels, rel)
+		seen[rel] = true
+	}
+	for rel := range remote {
+		if !seen[rel] {
+			rels = append(rels, rel)
+		}
+	}
+	// Sorting keeps parent directories ahead of their contents, so
+	// mkdirs happen before uploads into them.
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		l, inLocal := local[rel]
+		r, inRemote := remote[rel]
+		switch {
+		case inLocal && !inRemote:
+			if l.isDir {
+				actions = append(actions, syncDirAction{"mkdir", rel})
+			} else {
+				actions = append(actions, syncDirAction{"upload", rel})
+			}
+		case !inLocal && inRemote:
+			if c.twoWay {
+				if r.isDir {
+					actions = append(actions, syncDirAction{"mkdir-local", rel})
+				} else {
+					actions = append(actions, syncDirAction{"download", rel})
+				}
+			} else if c.delete {
+				actions = append(actions, syncDirAction{"delete", rel})
+			}
+		case l.isDir && r.isDir:
+			// Nothing to do for a directory on both sides.
+		case l.isDir != r.isDir:
+			return nil, fmt.Errorf(
+				"%s is a directory on one side and a file on the other; "+
+					"resolve manually", rel)
+		default:
+			mtimeDiff := l.mtime.Sub(r.mtime)
+			if l.size == r.size &&
+				mtimeDiff < syncDirMtimeTolerance &&
+				mtimeDiff > -syncDirMtimeTolerance {
+				continue
+			}
+			if c.checksum && l.size == r.size {
+				match, err := c.contentsMatch(ctx, cli, rel)
+				if err != nil {
+					return nil, err
+				}
+				if match {
+					continue
+				}
+			}
+			if c.twoWay && r.mtime.After(l.mtime) {
+				actions = append(actions, syncDirAction{"download", rel})
+			} else {
+				actions = append(actions, syncDirAction{"upload", rel})
+			}
+		}
+	}
+	return actions, nil
+}
+
+func (c *CmdSimpleFSSyncDir) apply(
+	ctx context.Context, cli keybase1.SimpleFSClient,
+	action syncDirAction) error {
+	switch action.verb {
+	case "mkdir-local":
+		return os.MkdirAll(
+			filepath.Join(c.localRoot, filepath.FromSlash(action.rel)), 0700)
+	case "mkdir":
+		remotePath, err := c.remotePath(action.rel)
+		if err != nil {
+			return err
+		}
+		opid, err := cli.SimpleFSMakeOpid(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.SimpleFSClose(ctx, opid)
+		return cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+			OpID:  opid,
+			Dest:  remotePath,
+			Flags: keybase1.OpenFlags_DIRECTORY,
+		})
+	case "upload", "download":
+		remotePath, err := c.remotePath(action.rel)
+		if err != nil {
+			return err
+		}
+		src, dest := c.localPath(action.rel), remotePath
+		if action.verb == "download" {
+			src, dest = remotePath, c.localPath(action.rel)
+		}
+		opid, err := cli.SimpleFSMakeOpid(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.SimpleFSClose(ctx, opid)
+		err = cli.SimpleFSCopy(ctx, keybase1.SimpleFSCopyArg{
+			OpID: opid,
+			Src:  src,
+			Dest: dest,
+		})
+		if err != nil {
+			return err
+		}
+		return cli.SimpleFSWait(ctx, opid)
+	case "delete":
+		remotePath, err := c.remotePath(action.rel)
+		if err != nil {
+			return err
+		}
+		opid, err := cli.SimpleFSMakeOpid(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.SimpleFSClose(ctx, opid)
+		err = cli.SimpleFSRemove(ctx, keybase1.SimpleFSRemoveArg{
+			OpID:      opid,
+			Path:      remotePath,
+			Recursive: true,
+		})
+		if err != nil {
+			return err
+		}
+		return cli.SimpleFSWait(ctx, opid)
+	default:
+		return fmt.Errorf("unknown sync action %q", action.verb)
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdSimpleFSSyncDir) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+
+	local, err := c.walkLocal()
+	if err != nil {
+		return err
+	}
+	remote, err := c.walkRemote(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	actions, err := c.plan(ctx, cli, local, remote)
+	if err != nil {
+		return err
+	}
+
+	numDeletes := 0
+	for _, action := range actions {
+		if action.verb == "delete" {
+			numDeletes++
+		}
+	}
+	if numDeletes > c.maxDelete {
+		return fmt.Errorf(
+			"%d deletions needed, more than --max-delete=%d; aborting",
+			numDeletes, c.maxDelete)
+	}
+
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(actions) == 0 {
+		dui.Printf("Already in sync.\n")
+		return nil
+	}
+	for _, action := range actions {
+		if c.dryRun {
+			dui.Printf("would %s %s\n", action.verb, action.rel)
+			continue
+		}
+		dui.Printf("%s %s\n", action.verb, action.rel)
+		if err := c.apply(ctx, cli, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseArgv gets the required arguments for this command.
+func (c *CmdSimpleFSSyncDir) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New(
+			"sync-dir requires a local directory and a KBFS path")
+	}
+	localRoot, err := filepath.Abs(ctx.Args()[0])
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(localRoot)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", localRoot)
+	}
+	c.localRoot = localRoot
+	c.remoteRoot = ctx.Args()[1]
+	c.twoWay = ctx.Bool("two-way")
+	c.delete = ctx.Bool("delete")
+	c.dryRun = ctx.Bool("dry-run")
+	c.checksum = ctx.Bool("checksum")
+	c.maxDelete = ctx.Int("max-delete")
+	c.includes = ctx.StringSlice("include")
+	c.excludes = ctx.StringSlice("exclude")
+	if c.twoWay && c.delete {
+		return errors.New(
+			"--delete requires one-way sync; it cannot be combined " +
+				"with --two-way")
+	}
+	return nil
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdSimpleFSSyncDir) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncDirIncluded(t *testing.T) {
+	c := &CmdSimpleFSSyncDir{
+		includes: []string{"*.go"},
+		excludes: []string{".git", "*.tmp"},
+	}
+	require.True(t, c.included("main.go", false))
+	require.True(t, c.included("pkg/util.go", false))
+	require.False(t, c.included("README.md", false))
+	require.False(t, c.included("scratch.tmp", false))
+	require.False(t, c.included(".git", true))
+	// Directories pass the include filter so files below them can match.
+	require.True(t, c.included("pkg", true))
+}
+
+func TestSyncDirPlan(t *testing.T) {
+	now := time.Now()
+	local := map[string]syncDirEntry{
+		"same.txt":  {size: 10, mtime: now},
+		"newer.txt": {size: 11, mtime: now},
+		"only.txt":  {size: 5, mtime: now},
+		"sub":       {isDir: true},
+	}
+	remote := map[string]syncDirEntry{
+		"same.txt":   {size: 10, mtime: now.Add(time.Second)},
+		"newer.txt":  {size: 11, mtime: now.Add(-time.Hour)},
+		"orphan.txt": {size: 7, mtime: now},
+	}
+
+	c := &CmdSimpleFSSyncDir{}
+	actions, err := c.plan(nil, keybase1.SimpleFSClient{}, local, remote)
+	require.NoError(t, err)
+	require.Equal(t, []syncDirAction{
+		{"upload", "newer.txt"},
+		{"upload", "only.txt"},
+		{"mkdir", "sub"},
+	}, actions)
+
+	c = &CmdSimpleFSSyncDir{delete: true}
+	actions, err = c.plan(nil, keybase1.SimpleFSClient{}, local, remote)
+	require.NoError(t, err)
+	require.Contains(t, actions, syncDirAction{"delete", "orphan.txt"})
+
+	c = &CmdSimpleFSSyncDir{twoWay: true}
+	remote["newer.txt"] = syncDirEntry{size: 11, mtime: now.Add(time.Hour)}
+	actions, err = c.plan(nil, keybase1.SimpleFSClient{}, local, remote)
+	require.NoError(t, err)
+	require.Contains(t, actions, syncDirAction{"download", "newer.txt"})
+	require.Contains(t, actions, syncDirAction{"download", "orphan.txt"})
+
+	local["clash"] = syncDirEntry{isDir: true}
+	remote["clash"] = syncDirEntry{size: 1, mtime: now}
+	_, err = c.plan(nil, keybase1.SimpleFSClient{}, local, remote)
+	require.Error(t, err)
+}
@@ -35,6 +35,9 @@ func NewCmdTeam(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		newCmdTeamSearch(cl, g),
 		newCmdTeamGenerateSeitan(cl, g),
 		newCmdTeamGenerateInvitelink(cl, g),
+		newCmdTeamFolderTemplate(cl, g),
+		newCmdTeamExportConfig(cl, g),
+		newCmdTeamApplyConfig(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificTeamCommands(cl, g)...)
 	sort.Sort(cli.ByName(subcommands))
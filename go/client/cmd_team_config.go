@@ -0,0 +1,719 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// teamConfig is the reviewable on-disk description of a team that
+// `keybase team export-config` writes and `keybase team apply-config`
+// consumes: membership by role, open-team settings, retention policies
+// and the channel list.
+type teamConfig struct {
+	Team      string
+	Open      bool
+	JoinAs    string
+	Retention string
+	Members   map[string][]string
+	Channels  []teamChannelConfig
+}
+
+type teamChannelConfig struct {
+	Name      string
+	Retention string
+}
+
+// teamConfigRoles lists the member sections in the order they're
+// exported.
+var teamConfigRoles = []string{
+	"owners", "admins", "writers", "readers", "bots", "restricted-bots",
+}
+
+var teamConfigRoleMap = map[string]keybase1.TeamRole{
+	"owners":          keybase1.TeamRole_OWNER,
+	"admins":          keybase1.TeamRole_ADMIN,
+	"writers":         keybase1.TeamRole_WRITER,
+	"readers":         keybase1.TeamRole_READER,
+	"bots":            keybase1.TeamRole_BOT,
+	"restricted-bots": keybase1.TeamRole_RESTRICTEDBOT,
+}
+
+// yaml serializes the config. The output is deliberately a small,
+// regular subset of YAML (two-space indent, scalar lists) so that
+// parseTeamConfig can read back everything this writes.
+func (tc teamConfig) yaml() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "team: %s\n", tc.Team)
+	fmt.Fprintf(&b, "settings:\n")
+	fmt.Fprintf(&b, "  open: %v\n", tc.Open)
+	if tc.Open {
+		fmt.Fprintf(&b, "  join-as: %s\n", tc.JoinAs)
+	}
+	if tc.Retention != "" {
+		fmt.Fprintf(&b, "retention: %s\n", tc.Retention)
+	}
+	fmt.Fprintf(&b, "members:\n")
+	for _, role := range teamConfigRoles {
+		users := tc.Members[role]
+		if len(users) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s:\n", role)
+		for _, username := range users {
+			fmt.Fprintf(&b, "    - %s\n", username)
+		}
+	}
+	if len(tc.Channels) > 0 {
+		fmt.Fprintf(&b, "channels:\n")
+		for _, ch := range tc.Channels {
+			fmt.Fprintf(&b, "  - name: %s\n", ch.Name)
+			if ch.Retention != "" {
+				fmt.Fprintf(&b, "    retention: %s\n", ch.Retention)
+			}
+		}
+	}
+	return b.String()
+}
+
+// parseTeamConfig reads the subset of YAML emitted by
+// (teamConfig).yaml. Blank lines and '#' comments are ignored.
+func parseTeamConfig(data string) (tc teamConfig, err error) {
+	tc.Members = make(map[string][]string)
+	var section string
+	var memberRole string
+	for i, raw := range strings.Split(data, "\n") {
+		line := strings.TrimRight(raw, " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineErr := func(format string, args ...interface{}) error {
+			return fmt.Errorf("line %d: %s", i+1, fmt.Sprintf(format, args...))
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		var key, value string
+		if strings.HasPrefix(trimmed, "- ") {
+			value = strings.TrimSpace(trimmed[2:])
+			if parts := strings.SplitN(value, ":", 2); len(parts) == 2 {
+				key = strings.TrimSpace(parts[0])
+				value = strings.TrimSpace(parts[1])
+			}
+		} else {
+			parts := strings.SplitN(trimmed, ":", 2)
+			if len(parts) != 2 {
+				return tc, lineErr("expected \"key: value\", got %q", trimmed)
+			}
+			key = strings.TrimSpace(parts[0])
+			value = strings.TrimSpace(parts[1])
+		}
+
+		switch {
+		case indent == 0:
+			switch key {
+			case "team":
+				tc.Team = value
+			case "retention":
+				tc.Retention = value
+			case "settings", "members", "channels":
+				section = key
+				memberRole = ""
+			default:
+				return tc, lineErr("unknown key %q", key)
+			}
+		case section == "settings":
+			switch key {
+			case "open":
+				tc.Open, err = strconv.ParseBool(value)
+				if err != nil {
+					return tc, lineErr("bad value for open: %q", value)
+				}
+			case "join-as":
+				tc.JoinAs = value
+			default:
+				return tc, lineErr("unknown settings key %q", key)
+			}
+		case section == "members":
+			if key != "" {
+				if _, ok := teamConfigRoleMap[key]; !ok {
+					return tc, lineErr("unknown member role %q", key)
+				}
+				memberRole = key
+				continue
+			}
+			if memberRole == "" {
+				return tc, lineErr("member %q outside of a role section", value)
+			}
+			tc.Members[memberRole] = append(tc.Members[memberRole], value)
+		case section == "channels":
+			switch key {
+			case "name":
+				tc.Channels = append(tc.Channels, teamChannelConfig{Name: value})
+			case "retention":
+				if len(tc.Channels) == 0 {
+					return tc, lineErr("channel retention before any channel")
+				}
+				tc.Channels[len(tc.Channels)-1].Retention = value
+			default:
+				return tc, lineErr("unknown channel key %q", key)
+			}
+		default:
+			return tc, lineErr("unexpected line %q", trimmed)
+		}
+	}
+	if tc.Team == "" {
+		return tc, fmt.Errorf("config has no \"team:\" entry")
+	}
+	return tc, nil
+}
+
+// formatRetentionConfig renders a retention policy in the config's
+// round-trippable syntax: keep, inherit, expire:<age> or explode:<age>.
+// An unset policy renders as the empty string, which apply-config
+// treats as "not managed".
+func formatRetentionConfig(policy *chat1.RetentionPolicy) (string, error) {
+	if policy == nil {
+		return "", nil
+	}
+	typ, err := policy.Typ()
+	if err != nil {
+		return "", err
+	}
+	switch typ {
+	case chat1.RetentionPolicyType_NONE:
+		return "", nil
+	case chat1.RetentionPolicyType_RETAIN:
+		return "keep", nil
+	case chat1.RetentionPolicyType_INHERIT:
+		return "inherit", nil
+	case chat1.RetentionPolicyType_EXPIRE:
+		return "expire:" + formatRetentionConfigAge(policy.Expire().Age), nil
+	case chat1.RetentionPolicyType_EPHEMERAL:
+		return "explode:" + formatRetentionConfigAge(policy.Ephemeral().Age), nil
+	default:
+		return "", fmt.Errorf("unknown retention policy type %v", typ)
+	}
+}
+
+func formatRetentionConfigAge(age gregor1.DurationSec) string {
+	d := time.Duration(age) * time.Second
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+func parseRetentionConfig(s string) (*chat1.RetentionPolicy, error) {
+	var policy chat1.RetentionPolicy
+	switch {
+	case s == "":
+		return nil, nil
+	case s == "keep":
+		policy = chat1.NewRetentionPolicyWithRetain(chat1.RpRetain{})
+	case s == "inherit":
+		policy = chat1.NewRetentionPolicyWithInherit(chat1.RpInherit{})
+	case strings.HasPrefix(s, "expire:"):
+		age, err := parseRetentionConfigAge(strings.TrimPrefix(s, "expire:"))
+		if err != nil {
+			return nil, err
+		}
+		policy = chat1.NewRetentionPolicyWithExpire(chat1.RpExpire{Age: age})
+	case strings.HasPrefix(s, "explode:"):
+		age, err := parseRetentionConfigAge(strings.TrimPrefix(s, "explode:"))
+		if err != nil {
+			return nil, err
+		}
+		policy = chat1.NewRetentionPolicyWithEphemeral(chat1.RpEphemeral{Age: age})
+	default:
+		return nil, fmt.Errorf(
+			"invalid retention %q (expected keep, inherit, expire:<age> or explode:<age>)", s)
+	}
+	return &policy, nil
+}
+
+func parseRetentionConfigAge(s string) (gregor1.DurationSec, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("invalid retention age %q", s)
+		}
+		return gregor1.DurationSec(days * 24 * 60 * 60), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid retention age %q", s)
+	}
+	return gregor1.DurationSec(d.Seconds()), nil
+}
+
+// teamConfigState carries the IDs needed to apply changes alongside the
+// exported config.
+type teamConfigState struct {
+	teamID  keybase1.TeamID
+	convIDs map[string]chat1.ConversationID
+}
+
+// loadTeamConfig reads the team's current configuration from the
+// service.
+func loadTeamConfig(ctx context.Context, g *libkb.GlobalContext, team string) (
+	tc teamConfig, st teamConfigState, err error) {
+	cli, err := GetTeamsClient(g)
+	if err != nil {
+		return tc, st, err
+	}
+	details, err := cli.TeamGet(ctx, keybase1.TeamGetArg{Name: team})
+	if err != nil {
+		return tc, st, err
+	}
+	st.teamID, err = cli.GetTeamID(ctx, team)
+	if err != nil {
+		return tc, st, err
+	}
+
+	tc.Team = team
+	tc.Open = details.Settings.Open
+	tc.JoinAs = strings.ToLower(details.Settings.JoinAs.String())
+	tc.Members = make(map[string][]string)
+	addRole := func(role string, members []keybase1.TeamMemberDetails) {
+		for _, member := range members {
+			if member.Status != keybase1.TeamMemberStatus_ACTIVE {
+				continue
+			}
+			tc.Members[role] = append(tc.Members[role], member.Username)
+		}
+		sort.Strings(tc.Members[role])
+	}
+	addRole("owners", details.Members.Owners)
+	addRole("admins", details.Members.Admins)
+	addRole("writers", details.Members.Writers)
+	addRole("readers", details.Members.Readers)
+	addRole("bots", details.Members.Bots)
+	addRole("restricted-bots", details.Members.RestrictedBots)
+
+	if err = CheckAndStartStandaloneChat(g, chat1.ConversationMembersType_TEAM); err != nil {
+		return tc, st, err
+	}
+	lcli, err := GetChatLocalClient(g)
+	if err != nil {
+		return tc, st, err
+	}
+	teamPolicy, err := lcli.GetTeamRetentionLocal(ctx, st.teamID)
+	if err != nil {
+		return tc, st, err
+	}
+	if tc.Retention, err = formatRetentionConfig(teamPolicy); err != nil {
+		return tc, st, err
+	}
+	listRes, err := lcli.GetTLFConversationsLocal(ctx, chat1.GetTLFConversationsLocalArg{
+		TlfName:     team,
+		TopicType:   chat1.TopicType_CHAT,
+		MembersType: chat1.ConversationMembersType_TEAM,
+	})
+	if err != nil {
+		return tc, st, err
+	}
+	st.convIDs = make(map[string]chat1.ConversationID)
+	for _, conv := range listRes.Convs {
+		retention, err := formatRetentionConfig(conv.ConvRetention)
+		if err != nil {
+			return tc, st, err
+		}
+		tc.Channels = append(tc.Channels, teamChannelConfig{
+			Name:      conv.Channel,
+			Retention: retention,
+		})
+		convID, err := chat1.MakeConvID(string(conv.ConvID))
+		if err != nil {
+			return tc, st, err
+		}
+		st.convIDs[conv.Channel] = convID
+	}
+	sort.Slice(tc.Channels, func(i, j int) bool {
+		return tc.Channels[i].Name < tc.Channels[j].Name
+	})
+	return tc, st, nil
+}
+
+type CmdTeamExportConfig struct {
+	libkb.Contextified
+	Team   keybase1.TeamName
+	Output string
+}
+
+func newCmdTeamExportConfig(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "export-config",
+		ArgumentHelp: "<team name>",
+		Usage:        "Export a team's configuration as reviewable YAML.",
+		Examples: `
+Export a team's membership, settings, channels and retention policies:
+    keybase team export-config acme
+Write the config to a file for review or version control:
+    keybase team export-config acme -o acme.yaml
+`,
+		Action: func(c *cli.Context) {
+			cmd := NewCmdTeamExportConfigRunner(g)
+			cl.ChooseCommand(cmd, "export-config", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "o, output",
+				Usage: "Write the config to a file instead of stdout",
+			},
+		},
+	}
+}
+
+func NewCmdTeamExportConfigRunner(g *libkb.GlobalContext) *CmdTeamExportConfig {
+	return &CmdTeamExportConfig{Contextified: libkb.NewContextified(g)}
+}
+
+func (c *CmdTeamExportConfig) ParseArgv(ctx *cli.Context) (err error) {
+	c.Team, err = ParseOneTeamNameK1(ctx)
+	if err != nil {
+		return err
+	}
+	c.Output = ctx.String("output")
+	return nil
+}
+
+func (c *CmdTeamExportConfig) Run() error {
+	ctx := context.Background()
+	tc, _, err := loadTeamConfig(ctx, c.G(), c.Team.String())
+	if err != nil {
+		return err
+	}
+	out := fmt.Sprintf("# Team configuration for %s, exported by `keybase team export-config`.\n%s",
+		tc.Team, tc.yaml())
+	if c.Output != "" {
+		return os.WriteFile(c.Output, []byte(out), 0644)
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s", out)
+	return nil
+}
+
+func (c *CmdTeamExportConfig) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
+
+type CmdTeamApplyConfig struct {
+	libkb.Contextified
+	File   string
+	DryRun bool
+}
+
+func newCmdTeamApplyConfig(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "apply-config",
+		ArgumentHelp: "<config file>",
+		Usage:        "Apply a team configuration exported by export-config.",
+		Examples: `
+Preview the changes a config would make:
+    keybase team apply-config acme.yaml --dry-run
+Apply the config, changing only what differs:
+    keybase team apply-config acme.yaml
+`,
+		Action: func(c *cli.Context) {
+			cmd := NewCmdTeamApplyConfigRunner(g)
+			cl.ChooseCommand(cmd, "apply-config", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "d, dry-run",
+				Usage: "Print the changes that would be made without applying them",
+			},
+		},
+	}
+}
+
+func NewCmdTeamApplyConfigRunner(g *libkb.GlobalContext) *CmdTeamApplyConfig {
+	return &CmdTeamApplyConfig{Contextified: libkb.NewContextified(g)}
+}
+
+func (c *CmdTeamApplyConfig) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("config file required")
+	}
+	c.File = ctx.Args().Get(0)
+	c.DryRun = ctx.Bool("dry-run")
+	return nil
+}
+
+// teamConfigAction is one idempotent change apply-config wants to make.
+type teamConfigAction struct {
+	desc  string
+	apply func(ctx context.Context) error
+}
+
+func (c *CmdTeamApplyConfig) Run() error {
+	ctx := context.Background()
+	dui := c.G().UI.GetDumbOutputUI()
+
+	data, err := os.ReadFile(c.File)
+	if err != nil {
+		return err
+	}
+	desired, err := parseTeamConfig(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %s", c.File, err)
+	}
+	current, st, err := loadTeamConfig(ctx, c.G(), desired.Team)
+	if err != nil {
+		return err
+	}
+
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	lcli, err := GetChatLocalClient(c.G())
+	if err != nil {
+		return err
+	}
+
+	actions, err := c.diff(desired, current, st, cli, lcli)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		dui.Printf("Team %q already matches the config; nothing to do.\n", desired.Team)
+		return nil
+	}
+	for _, action := range actions {
+		if c.DryRun {
+			dui.Printf("Would %s\n", action.desc)
+			continue
+		}
+		dui.Printf("%s\n", action.desc)
+		if err := action.apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s: %s", action.desc, err)
+		}
+	}
+	if c.DryRun {
+		dui.Printf("Dry run: %d change(s) not applied.\n", len(actions))
+	} else {
+		dui.Printf("Applied %d change(s).\n", len(actions))
+	}
+	return nil
+}
+
+func (c *CmdTeamApplyConfig) diff(desired, current teamConfig, st teamConfigState,
+	cli keybase1.TeamsClient, lcli chat1.LocalClient) (actions []teamConfigAction, err error) {
+	team := desired.Team
+	dui := c.G().UI.GetDumbOutputUI()
+
+	// Membership: desired and current as user -> role.
+	desiredRoles := make(map[string]string)
+	for role, users := range desired.Members {
+		for _, username := range users {
+			if prev, ok := desiredRoles[username]; ok {
+				return nil, fmt.Errorf("user %q listed under both %s and %s",
+					username, prev, role)
+			}
+			desiredRoles[username] = role
+		}
+	}
+	currentRoles := make(map[string]string)
+	for role, users := range current.Members {
+		for _, username := range users {
+			currentRoles[username] = role
+		}
+	}
+	usernames := make([]string, 0, len(desiredRoles)+len(currentRoles))
+	for username := range desiredRoles {
+		usernames = append(usernames, username)
+	}
+	for username := range currentRoles {
+		if _, ok := desiredRoles[username]; !ok {
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+	for _, username := range usernames {
+		username := username
+		desiredRole, inDesired := desiredRoles[username]
+		currentRole, inCurrent := currentRoles[username]
+		switch {
+		case inDesired && !inCurrent:
+			role := teamConfigRoleMap[desiredRole]
+			var botSettings *keybase1.TeamBotSettings
+			if role == keybase1.TeamRole_RESTRICTEDBOT {
+				botSettings = &keybase1.TeamBotSettings{}
+			}
+			actions = append(actions, teamConfigAction{
+				desc: fmt.Sprintf("add %s as %s", username, role.HumanString()),
+				apply: func(ctx context.Context) error {
+					_, err := cli.TeamAddMember(ctx, keybase1.TeamAddMemberArg{
+						TeamID:      st.teamID,
+						Username:    username,
+						Role:        role,
+						BotSettings: botSettings,
+					})
+					return err
+				},
+			})
+		case !inDesired && inCurrent:
+			actions = append(actions, teamConfigAction{
+				desc: fmt.Sprintf("remove %s", username),
+				apply: func(ctx context.Context) error {
+					return cli.TeamRemoveMember(ctx, keybase1.TeamRemoveMemberArg{
+						TeamID: st.teamID,
+						Member: keybase1.NewTeamMemberToRemoveWithAssertion(
+							keybase1.AssertionTeamMemberToRemove{Assertion: username}),
+					})
+				},
+			})
+		case desiredRole != currentRole:
+			role := teamConfigRoleMap[desiredRole]
+			var botSettings *keybase1.TeamBotSettings
+			if role == keybase1.TeamRole_RESTRICTEDBOT {
+				botSettings = &keybase1.TeamBotSettings{}
+			}
+			actions = append(actions, teamConfigAction{
+				desc: fmt.Sprintf("change %s from %s to %s", username,
+					teamConfigRoleMap[currentRole].HumanString(), role.HumanString()),
+				apply: func(ctx context.Context) error {
+					return cli.TeamEditMember(ctx, keybase1.TeamEditMemberArg{
+						Name:        team,
+						Username:    username,
+						Role:        role,
+						BotSettings: botSettings,
+					})
+				},
+			})
+		}
+	}
+
+	// Open-team settings.
+	if desired.Open != current.Open ||
+		(desired.Open && desired.JoinAs != current.JoinAs) {
+		joinAs := keybase1.TeamRole_NONE
+		if desired.Open {
+			var ok bool
+			joinAs, ok = keybase1.TeamRoleMap[strings.ToUpper(desired.JoinAs)]
+			if !ok || (joinAs != keybase1.TeamRole_READER && joinAs != keybase1.TeamRole_WRITER) {
+				return nil, fmt.Errorf("join-as must be reader or writer, got %q", desired.JoinAs)
+			}
+		}
+		desc := "close the team"
+		if desired.Open {
+			desc = fmt.Sprintf("open the team to %ss", strings.ToLower(joinAs.String()))
+		}
+		actions = append(actions, teamConfigAction{
+			desc: desc,
+			apply: func(ctx context.Context) error {
+				return cli.TeamSetSettings(ctx, keybase1.TeamSetSettingsArg{
+					TeamID: st.teamID,
+					Settings: keybase1.TeamSettings{
+						Open:   desired.Open,
+						JoinAs: joinAs,
+					},
+				})
+			},
+		})
+	}
+
+	// Team-wide retention. An empty retention in the config means the
+	// policy isn't managed, so we leave whatever is set alone.
+	if desired.Retention != "" && desired.Retention != current.Retention {
+		policy, err := parseRetentionConfig(desired.Retention)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, teamConfigAction{
+			desc: fmt.Sprintf("set team retention to %s", desired.Retention),
+			apply: func(ctx context.Context) error {
+				return lcli.SetTeamRetentionLocal(ctx, chat1.SetTeamRetentionLocalArg{
+					TeamID: st.teamID,
+					Policy: *policy,
+				})
+			},
+		})
+	}
+
+	// Channels: create the missing ones and reconcile per-channel
+	// retention. Channels that exist but aren't in the config are left
+	// alone, since deleting a channel destroys history.
+	currentChannels := make(map[string]teamChannelConfig)
+	for _, ch := range current.Channels {
+		currentChannels[ch.Name] = ch
+	}
+	desiredChannels := make(map[string]bool)
+	for _, ch := range desired.Channels {
+		ch := ch
+		desiredChannels[ch.Name] = true
+		existing, exists := currentChannels[ch.Name]
+		policy, err := parseRetentionConfig(ch.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("channel #%s: %s", ch.Name, err)
+		}
+		if !exists {
+			actions = append(actions, teamConfigAction{
+				desc: fmt.Sprintf("create channel #%s", ch.Name),
+				apply: func(ctx context.Context) error {
+					topicName := ch.Name
+					res, err := lcli.NewConversationLocal(ctx, chat1.NewConversationLocalArg{
+						TlfName:          team,
+						TopicName:        &topicName,
+						TopicType:        chat1.TopicType_CHAT,
+						TlfVisibility:    keybase1.TLFVisibility_PRIVATE,
+						MembersType:      chat1.ConversationMembersType_TEAM,
+						IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+					})
+					if err != nil {
+						return err
+					}
+					if policy == nil {
+						return nil
+					}
+					return lcli.SetConvRetentionLocal(ctx, chat1.SetConvRetentionLocalArg{
+						ConvID: res.Conv.Info.Id,
+						Policy: *policy,
+					})
+				},
+			})
+			continue
+		}
+		if ch.Retention != "" && ch.Retention != existing.Retention {
+			actions = append(actions, teamConfigAction{
+				desc: fmt.Sprintf("set #%s retention to %s", ch.Name, ch.Retention),
+				apply: func(ctx context.Context) error {
+					return lcli.SetConvRetentionLocal(ctx, chat1.SetConvRetentionLocalArg{
+						ConvID: st.convIDs[ch.Name],
+						Policy: *policy,
+					})
+				},
+			})
+		}
+	}
+	for _, ch := range current.Channels {
+		if !desiredChannels[ch.Name] {
+			dui.Printf("Note: channel #%s is not in the config; leaving it alone.\n", ch.Name)
+		}
+	}
+
+	return actions, nil
+}
+
+func (c *CmdTeamApplyConfig) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
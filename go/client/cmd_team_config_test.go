@@ -0,0 +1,69 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamConfigRoundTrip(t *testing.T) {
+	tc := teamConfig{
+		Team:      "acme",
+		Open:      true,
+		JoinAs:    "reader",
+		Retention: "expire:30d",
+		Members: map[string][]string{
+			"owners":  {"alice"},
+			"writers": {"bob", "carol"},
+			"bots":    {"deploybot"},
+		},
+		Channels: []teamChannelConfig{
+			{Name: "general"},
+			{Name: "logs", Retention: "explode:7d"},
+			{Name: "random", Retention: "inherit"},
+		},
+	}
+	parsed, err := parseTeamConfig(tc.yaml())
+	require.NoError(t, err)
+	require.Equal(t, tc, parsed)
+}
+
+func TestTeamConfigParseErrors(t *testing.T) {
+	for _, bad := range []string{
+		// Missing team name, unknown keys and roles, members outside a
+		// role section, bad scalars, retention before any channel.
+		"",
+		"settings:\n  open: true\n",
+		"team: acme\nbogus: x\n",
+		"team: acme\nmembers:\n  wizards:\n",
+		"team: acme\nmembers:\n  - alice\n",
+		"team: acme\nsettings:\n  open: maybe\n",
+		"team: acme\nchannels:\n  - retention: keep\n",
+	} {
+		_, err := parseTeamConfig(bad)
+		require.Error(t, err, "config: %q", bad)
+	}
+}
+
+func TestTeamConfigRetention(t *testing.T) {
+	for _, s := range []string{"keep", "inherit", "expire:30d", "explode:1h0m0s"} {
+		policy, err := parseRetentionConfig(s)
+		require.NoError(t, err)
+		require.NotNil(t, policy)
+		out, err := formatRetentionConfig(policy)
+		require.NoError(t, err)
+		require.Equal(t, s, out)
+	}
+
+	policy, err := parseRetentionConfig("")
+	require.NoError(t, err)
+	require.Nil(t, policy)
+
+	_, err = parseRetentionConfig("expire:never")
+	require.Error(t, err)
+	_, err = parseRetentionConfig("shred")
+	require.Error(t, err)
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// A folder template describes the standard skeleton a team wants in its KBFS
+// space: directories to create and seed files to drop in if absent. The
+// template itself lives in the team folder, so admins edit it with team
+// write access and members can apply it after joining. Apply is idempotent:
+// directories are created if missing and seed files are never overwritten.
+const teamFolderTemplateFile = ".kbfs_folder_template.json"
+
+type teamFolderTemplate struct {
+	Dirs  []string           `json:"dirs"`
+	Files []teamTemplateFile `json:"files,omitempty"`
+}
+
+type teamTemplateFile struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+}
+
+func (t teamFolderTemplate) validate() error {
+	check := func(p string) error {
+		if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
+			return fmt.Errorf("invalid template path %q: must be relative with no '..'", p)
+		}
+		return nil
+	}
+	for _, dir := range t.Dirs {
+		if err := check(dir); err != nil {
+			return err
+		}
+	}
+	for _, file := range t.Files {
+		if err := check(file.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func teamFolderPath(team, name string) keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s", team, name))
+}
+
+func teamTemplateExists(ctx context.Context, cli keybase1.SimpleFSClient, path keybase1.Path) bool {
+	_, err := cli.SimpleFSStat(ctx, keybase1.SimpleFSStatArg{Path: path})
+	return err == nil
+}
+
+func teamTemplateMkdir(ctx context.Context, cli keybase1.SimpleFSClient, path keybase1.Path) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	return cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_DIRECTORY,
+	})
+}
+
+func teamTemplateWriteFile(ctx context.Context, cli keybase1.SimpleFSClient,
+	path keybase1.Path, dat []byte) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_WRITE | keybase1.OpenFlags_REPLACE,
+	}); err != nil {
+		return err
+	}
+	return cli.SimpleFSWrite(ctx, keybase1.SimpleFSWriteArg{
+		OpID:    opid,
+		Content: dat,
+	})
+}
+
+func teamTemplateReadFile(ctx context.Context, cli keybase1.SimpleFSClient,
+	path keybase1.Path) (res []byte, err error) {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	if err := cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	}); err != nil {
+		return nil, err
+	}
+	var offset int64
+	for {
+		content, err := cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   1024 * 1024,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(content.Data) == 0 {
+			break
+		}
+		res = append(res, content.Data...)
+		offset += int64(len(content.Data))
+	}
+	return res, nil
+}
+
+func loadTeamFolderTemplate(ctx context.Context, cli keybase1.SimpleFSClient, team string) (
+	res teamFolderTemplate, err error) {
+	dat, err := teamTemplateReadFile(ctx, cli, teamFolderPath(team, teamFolderTemplateFile))
+	if err != nil {
+		return res, fmt.Errorf("no folder template set for %s", team)
+	}
+	if err := json.Unmarshal(dat, &res); err != nil {
+		return res, err
+	}
+	return res, res.validate()
+}
+
+func newCmdTeamFolderTemplate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	subcommands := []cli.Command{
+		newCmdTeamFolderTemplateSet(cl, g),
+		newCmdTeamFolderTemplateShow(cl, g),
+		newCmdTeamFolderTemplateApply(cl, g),
+	}
+	sort.Sort(cli.ByName(subcommands))
+	return cli.Command{
+		Name:         "folder-template",
+		Usage:        "Manage the team's standard KBFS folder skeleton",
+		ArgumentHelp: "[arguments...]",
+		Subcommands:  subcommands,
+	}
+}
+
+// CmdTeamFolderTemplateSet is the 'team folder-template set' command.
+type CmdTeamFolderTemplateSet struct {
+	libkb.Contextified
+	team     string
+	dirs     []string
+	fromFile string
+}
+
+func newCmdTeamFolderTemplateSet(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "set",
+		ArgumentHelp: "<team>",
+		Usage:        "Define the team's folder template",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamFolderTemplateSet{Contextified: libkb.NewContextified(g)}, "set", c)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "dirs",
+				Usage: "Comma-separated directories to provision (e.g. docs,onboarding)",
+			},
+			cli.StringFlag{
+				Name:  "from-file",
+				Usage: "Read a full template (dirs and seed files) from a local JSON file",
+			},
+		},
+	}
+}
+
+func (c *CmdTeamFolderTemplateSet) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	if dirs := ctx.String("dirs"); len(dirs) > 0 {
+		c.dirs = strings.Split(dirs, ",")
+	}
+	c.fromFile = ctx.String("from-file")
+	if len(c.dirs) == 0 && len(c.fromFile) == 0 {
+		return fmt.Errorf("one of --dirs or --from-file required")
+	}
+	if len(c.dirs) > 0 && len(c.fromFile) > 0 {
+		return fmt.Errorf("--dirs and --from-file are exclusive")
+	}
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateSet) Run() error {
+	ctx := context.TODO()
+	var template teamFolderTemplate
+	if len(c.fromFile) > 0 {
+		dat, err := os.ReadFile(c.fromFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(dat, &template); err != nil {
+			return err
+		}
+	} else {
+		template.Dirs = c.dirs
+	}
+	if err := template.validate(); err != nil {
+		return err
+	}
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	dat, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := teamTemplateWriteFile(ctx, fsCli,
+		teamFolderPath(c.team, teamFolderTemplateFile), dat); err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Folder template set for %s (%d dirs, %d seed files)\n",
+		c.team, len(template.Dirs), len(template.Files))
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateSet) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdTeamFolderTemplateShow is the 'team folder-template show' command.
+type CmdTeamFolderTemplateShow struct {
+	libkb.Contextified
+	team string
+}
+
+func newCmdTeamFolderTemplateShow(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "show",
+		ArgumentHelp: "<team>",
+		Usage:        "Show the team's folder template",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamFolderTemplateShow{Contextified: libkb.NewContextified(g)}, "show", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdTeamFolderTemplateShow) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateShow) Run() error {
+	ctx := context.TODO()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	template, err := loadTeamFolderTemplate(ctx, fsCli, c.team)
+	if err != nil {
+		return err
+	}
+	dat, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("%s\n", dat)
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateShow) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+// CmdTeamFolderTemplateApply is the 'team folder-template apply' command.
+type CmdTeamFolderTemplateApply struct {
+	libkb.Contextified
+	team string
+}
+
+func newCmdTeamFolderTemplateApply(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "apply",
+		ArgumentHelp: "<team>",
+		Usage:        "Provision the team's folder skeleton (idempotent)",
+		Description: `"keybase team folder-template apply" creates the template's directories in
+   the team's KBFS folder if they are missing and writes seed files that do
+   not exist yet. Existing files are never touched, so it is safe to run
+   after creating a subteam or joining a team.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamFolderTemplateApply{Contextified: libkb.NewContextified(g)}, "apply", c)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdTeamFolderTemplateApply) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("team name required")
+	}
+	c.team = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateApply) Run() error {
+	ctx := context.TODO()
+	fsCli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	template, err := loadTeamFolderTemplate(ctx, fsCli, c.team)
+	if err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	var created, skipped int
+	for _, dir := range template.Dirs {
+		if err := teamTemplateMkdir(ctx, fsCli, teamFolderPath(c.team, dir)); err != nil {
+			return fmt.Errorf("creating %s: %s", dir, err)
+		}
+		created++
+	}
+	for _, file := range template.Files {
+		path := teamFolderPath(c.team, file.Path)
+		if teamTemplateExists(ctx, fsCli, path) {
+			skipped++
+			continue
+		}
+		if err := teamTemplateWriteFile(ctx, fsCli, path, []byte(file.Contents)); err != nil {
+			return fmt.Errorf("writing %s: %s", file.Path, err)
+		}
+		created++
+	}
+	ui.Printf("Applied template to %s: %d provisioned, %d already present\n",
+		c.team, created, skipped)
+	return nil
+}
+
+func (c *CmdTeamFolderTemplateApply) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamFolderTemplateValidate(t *testing.T) {
+	good := teamFolderTemplate{
+		Dirs: []string{"docs", "onboarding/week1"},
+		Files: []teamTemplateFile{
+			{Path: "docs/README.md", Contents: "welcome"},
+		},
+	}
+	require.NoError(t, good.validate())
+
+	require.Error(t, teamFolderTemplate{Dirs: []string{"/abs"}}.validate())
+	require.Error(t, teamFolderTemplate{Dirs: []string{"../escape"}}.validate())
+	require.Error(t, teamFolderTemplate{Dirs: []string{""}}.validate())
+	require.Error(t, teamFolderTemplate{
+		Files: []teamTemplateFile{{Path: "a/../../b"}},
+	}.validate())
+}
@@ -6,6 +6,8 @@ package client
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
@@ -23,6 +25,14 @@ type CmdTeamListMemberships struct {
 	verbose              bool
 	showInviteID         bool
 	verified             bool
+	filterRole           keybase1.TeamRole
+	hasFilterRole        bool
+	openOnly             bool
+	closedOnly           bool
+	explicitOnly         bool
+	impliedOnly          bool
+	limit                int
+	offset               int
 }
 
 func (c *CmdTeamListMemberships) SetTeam(s string) {
@@ -59,6 +69,34 @@ func newCmdTeamListMemberships(cl *libcmdline.CommandLine, g *libkb.GlobalContex
 			Name:  "v, verbose",
 			Usage: "Include more verbose output",
 		},
+		cli.StringFlag{
+			Name:  "role",
+			Usage: "Only show memberships with the given role (owner, admin, writer, reader, bot, restrictedbot)",
+		},
+		cli.BoolFlag{
+			Name:  "open",
+			Usage: "Only show open teams",
+		},
+		cli.BoolFlag{
+			Name:  "closed",
+			Usage: "Only show closed teams",
+		},
+		cli.BoolFlag{
+			Name:  "explicit-only",
+			Usage: "Only show explicit memberships, not implied admin rights",
+		},
+		cli.BoolFlag{
+			Name:  "implied-only",
+			Usage: "Only show memberships with implied admin rights",
+		},
+		cli.IntFlag{
+			Name:  "n, limit",
+			Usage: "Show at most this many memberships",
+		},
+		cli.IntFlag{
+			Name:  "offset",
+			Usage: "Skip this many memberships (for paging with --limit)",
+		},
 	}
 	if develUsage {
 		flags = append(flags, cli.BoolFlag{
@@ -104,6 +142,37 @@ func (c *CmdTeamListMemberships) ParseArgv(ctx *cli.Context) error {
 		}
 	}
 
+	if srole := ctx.String("role"); srole != "" {
+		role, ok := keybase1.TeamRoleMap[strings.ToUpper(srole)]
+		if !ok {
+			return errors.New("invalid team role, please use owner, admin, writer, reader, bot, or restrictedbot")
+		}
+		c.filterRole = role
+		c.hasFilterRole = true
+	}
+	c.openOnly = ctx.Bool("open")
+	c.closedOnly = ctx.Bool("closed")
+	if c.openOnly && c.closedOnly {
+		return errors.New("cannot specify both --open and --closed")
+	}
+	c.explicitOnly = ctx.Bool("explicit-only")
+	c.impliedOnly = ctx.Bool("implied-only")
+	if c.explicitOnly && c.impliedOnly {
+		return errors.New("cannot specify both --explicit-only and --implied-only")
+	}
+	c.limit = ctx.Int("limit")
+	if c.limit < 0 {
+		return errors.New("--limit must be non-negative")
+	}
+	c.offset = ctx.Int("offset")
+	if c.offset < 0 {
+		return errors.New("--offset must be non-negative")
+	}
+	if c.team != "" && (c.hasFilterRole || c.openOnly || c.closedOnly ||
+		c.explicitOnly || c.impliedOnly || c.limit > 0 || c.offset > 0) {
+		return errors.New("filtering and paging flags only apply when listing teams, not a single team's members")
+	}
+
 	c.json = ctx.Bool("json")
 	c.verbose = ctx.Bool("verbose")
 
@@ -159,10 +228,55 @@ func (c *CmdTeamListMemberships) runUser(cli keybase1.TeamsClient) error {
 		return err
 	}
 
+	list.Teams = c.filterTeams(list.Teams)
+
 	renderer := newTeamMembersRenderer(c.G(), c.json, c.showInviteID)
 	return renderer.outputTeams(list, c.showAll)
 }
 
+// filterTeams applies the role/implied/open filters and then pages
+// through the result with --offset/--limit. It sorts with the same
+// key the renderer uses, so pages are stable across invocations.
+func (c *CmdTeamListMemberships) filterTeams(teams []keybase1.AnnotatedMemberInfo) []keybase1.AnnotatedMemberInfo {
+	filtered := teams[:0]
+	for _, t := range teams {
+		if c.hasFilterRole && t.Role != c.filterRole {
+			continue
+		}
+		if c.openOnly && !t.IsOpenTeam {
+			continue
+		}
+		if c.closedOnly && t.IsOpenTeam {
+			continue
+		}
+		if c.explicitOnly && t.Implicit != nil {
+			continue
+		}
+		if c.impliedOnly && t.Implicit == nil {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].FqName == filtered[j].FqName {
+			return filtered[i].Username < filtered[j].Username
+		}
+		return filtered[i].FqName < filtered[j].FqName
+	})
+
+	if c.offset > 0 {
+		if c.offset >= len(filtered) {
+			return nil
+		}
+		filtered = filtered[c.offset:]
+	}
+	if c.limit > 0 && c.limit < len(filtered) {
+		filtered = filtered[:c.limit]
+	}
+	return filtered
+}
+
 func (c *CmdTeamListMemberships) GetUsage() libkb.Usage {
 	return libkb.Usage{
 		Config:    true,
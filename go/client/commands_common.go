@@ -21,6 +21,7 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdBot(cl, g),
 		NewCmdBTC(cl, g),
 		NewCmdCA(cl, g),
+		NewCmdCalendar(cl, g),
 		NewCmdChat(cl, g),
 		NewCmdCompatDir(cl, g),
 		NewCmdCompatPush(cl, g),
@@ -33,6 +34,7 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdDevice(cl, g),
 		NewCmdDismiss(cl, g),
 		NewCmdDismissCategory(cl, g),
+		NewCmdDoctor(cl, g),
 		NewCmdDumpKeyfamily(cl, g),
 		NewCmdDumpPushNotifications(cl, g),
 		NewCmdEncrypt(cl, g),
@@ -59,6 +61,8 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		newCmdScript(cl, g, !libkb.BuildTagProduction),
 		NewCmdSelfProvision(cl, g),
 		NewCmdSign(cl, g),
+		NewCmdSignArtifact(cl, g),
+		NewCmdVerifyArtifact(cl, g),
 		NewCmdSigs(cl, g),
 		NewCmdSignup(cl, g),
 		NewCmdSimpleFS(cl, g),
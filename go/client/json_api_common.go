@@ -51,6 +51,34 @@ func (e ErrInvalidJSON) Error() string {
 	return fmt.Sprintf("invalid JSON: %s", e.message)
 }
 
+// Structured error codes reported in CallError.Code, so API consumers
+// can branch on a failure without parsing its message. Errors that
+// predate these codes, and anything unclassified, keep code 0.
+const (
+	callErrorCodeInvalidJSON    = 101
+	callErrorCodeInvalidVersion = 102
+	callErrorCodeInvalidMethod  = 103
+	callErrorCodeInvalidOptions = 104
+	callErrorCodeOffline        = 201
+	callErrorCodeRateLimited    = 202
+)
+
+// callErrorCode classifies an error into one of the structured codes
+// above.
+func callErrorCode(err error) int {
+	switch err.(type) {
+	case ErrInvalidJSON:
+		return callErrorCodeInvalidJSON
+	case ErrInvalidVersion:
+		return callErrorCodeInvalidVersion
+	case ErrInvalidMethod:
+		return callErrorCodeInvalidMethod
+	case ErrInvalidOptions:
+		return callErrorCodeInvalidOptions
+	}
+	return 0
+}
+
 // Call represents a JSON api call.
 type Call struct {
 	Jsonrpc string
@@ -228,7 +256,7 @@ func encodeResult(call Call, result interface{}, wr io.Writer, indent bool) erro
 
 // encodeErr JSON encodes an error.
 func encodeErr(call Call, err error, wr io.Writer, indent bool) error {
-	reply := Reply{Error: &CallError{Message: err.Error()}}
+	reply := Reply{Error: &CallError{Code: callErrorCode(err), Message: err.Error()}}
 	return encodeReply(call, reply, wr, indent)
 }
 
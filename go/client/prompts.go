@@ -74,6 +74,7 @@ const (
 	PromptDescriptorStellarURIAmount
 	PromptDescriptorAccountDeleteConfirmation
 	PromptDescriptorChatEmojiRemove
+	PromptDescriptorFSRemoveConflictCopy
 )
 
 const (
@@ -0,0 +1,106 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/qrcode"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+)
+
+// headlessProvisionUI is a ProvisionUI for devices with no keyboard or
+// display beyond a boot console (IoT boxes, servers provisioned over
+// serial). It never prompts: it always provisions from an existing
+// device, prints the kex2 verification code and QR code to the console,
+// and takes the new device name from a flag. The human completes the
+// whole exchange on the existing device with `keybase device add`.
+type headlessProvisionUI struct {
+	libkb.Contextified
+	parent     libkb.TerminalUI
+	deviceName string
+}
+
+func NewHeadlessProvisionUIProtocol(g *libkb.GlobalContext, deviceName string) rpc.Protocol {
+	return keybase1.ProvisionUiProtocol(headlessProvisionUI{
+		Contextified: libkb.NewContextified(g),
+		parent:       g.UI.GetTerminalUI(),
+		deviceName:   deviceName,
+	})
+}
+
+func (p headlessProvisionUI) ChooseProvisioningMethod(ctx context.Context, arg keybase1.ChooseProvisioningMethodArg) (keybase1.ProvisionMethod, error) {
+	return keybase1.ProvisionMethod_DEVICE, nil
+}
+
+func (p headlessProvisionUI) ChooseGPGMethod(ctx context.Context, arg keybase1.ChooseGPGMethodArg) (keybase1.GPGMethod, error) {
+	return keybase1.GPGMethod_GPG_NONE, errors.New("headless provisioning does not support GPG")
+}
+
+func (p headlessProvisionUI) SwitchToGPGSignOK(ctx context.Context, arg keybase1.SwitchToGPGSignOKArg) (bool, error) {
+	return false, errors.New("headless provisioning does not support GPG")
+}
+
+// ChooseDevice picks the first desktop or mobile device, since there is
+// no keyboard here to pick with. Paper keys are skipped: entering one
+// requires typing on this device.
+func (p headlessProvisionUI) ChooseDevice(ctx context.Context, arg keybase1.ChooseDeviceArg) (keybase1.DeviceID, error) {
+	for _, d := range arg.Devices {
+		switch d.Type {
+		case keybase1.DeviceTypeV2_DESKTOP, keybase1.DeviceTypeV2_MOBILE:
+			p.parent.Printf("Provisioning from your existing device %q.\n", d.Name)
+			return d.DeviceID, nil
+		}
+	}
+	return keybase1.DeviceID(""), errors.New("headless provisioning requires an existing desktop or mobile device")
+}
+
+func (p headlessProvisionUI) ChooseDeviceType(ctx context.Context, arg keybase1.ChooseDeviceTypeArg) (keybase1.DeviceType, error) {
+	return keybase1.DeviceType_DESKTOP, nil
+}
+
+// DisplayAndPromptSecret prints the verification code and QR code to the
+// console and returns without prompting; the code is entered on the
+// existing device.
+func (p headlessProvisionUI) DisplayAndPromptSecret(ctx context.Context, arg keybase1.DisplayAndPromptSecretArg) (resp keybase1.SecretResponse, err error) {
+	_ = p.parent.Output("\nOn your existing device, run:\n\n")
+	_ = p.parent.Output("\tkeybase device add\n\n")
+	_ = p.parent.Output("and enter this verification code when prompted:\n\n")
+	_ = p.parent.Output("\t" + arg.Phrase + "\n\n")
+	encodings, qerr := qrcode.Encode([]byte(arg.Phrase))
+	// ignoring any of these errors...phrase above will suffice.
+	if qerr == nil {
+		_ = p.parent.Output("Or, scan this QR Code with the keybase app on your mobile phone:\n\n")
+		_, _ = p.parent.PrintfUnescaped(encodings.Terminal)
+	}
+	return resp, nil
+}
+
+func (p headlessProvisionUI) PromptNewDeviceName(ctx context.Context, arg keybase1.PromptNewDeviceNameArg) (string, error) {
+	for _, existing := range arg.ExistingDevices {
+		if libkb.NameCmp(p.deviceName, existing) {
+			return "", fmt.Errorf("device name %q is already taken; pass a unique name with -devicename", p.deviceName)
+		}
+	}
+	return p.deviceName, nil
+}
+
+func (p headlessProvisionUI) DisplaySecretExchanged(ctx context.Context, sessionID int) error {
+	_ = p.parent.Output("\nVerification code received.\n\n")
+	return nil
+}
+
+func (p headlessProvisionUI) ProvisioneeSuccess(ctx context.Context, arg keybase1.ProvisioneeSuccessArg) error {
+	p.parent.Printf("Success! Provisioned device %q for %s.\n", arg.DeviceName, arg.Username)
+	return nil
+}
+
+func (p headlessProvisionUI) ProvisionerSuccess(ctx context.Context, arg keybase1.ProvisionerSuccessArg) error {
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package deeplinks
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// The deep link registry is the one canonical parser for keybase:// links.
+// Every frontend resolves links through it instead of growing its own
+// ad-hoc parsing, so validation and security checks live in one place.
+
+// TargetKind says what a link points at.
+type TargetKind string
+
+const (
+	TargetChat    TargetKind = "chat-conversation"
+	TargetTeam    TargetKind = "team-page"
+	TargetKbfs    TargetKind = "kbfs-path"
+	TargetProfile TargetKind = "user-profile"
+)
+
+// Target is the structured navigation target a link resolves to. Only the
+// fields relevant to Kind are set.
+type Target struct {
+	Kind     TargetKind `json:"kind"`
+	Team     string     `json:"team,omitempty"`
+	Channel  string     `json:"channel,omitempty"`
+	User     string     `json:"user,omitempty"`
+	KbfsPath string     `json:"kbfsPath,omitempty"`
+}
+
+// HandlerFunc resolves the path elements after the link's first segment.
+type HandlerFunc func(elems []string) (Target, error)
+
+// maxLinkLength bounds what we'll even look at; anything longer is
+// certainly not a link a human meant to follow.
+const maxLinkLength = 4096
+
+// Registry maps the first path segment of a keybase:// link to a handler.
+type Registry struct {
+	libkb.Contextified
+	sync.Mutex
+	handlers map[string]HandlerFunc
+}
+
+func NewRegistry(g *libkb.GlobalContext) *Registry {
+	r := &Registry{
+		Contextified: libkb.NewContextified(g),
+		handlers:     make(map[string]HandlerFunc),
+	}
+	r.Register("chat", handleChat)
+	r.Register("team", handleTeam)
+	r.Register("profile", handleProfile)
+	r.Register("private", handleKbfs("private"))
+	r.Register("public", handleKbfs("public"))
+	r.Register("teamfolder", handleKbfs("team"))
+	return r
+}
+
+// Register installs a handler for one link type, replacing any existing
+// handler for the same segment.
+func (r *Registry) Register(segment string, handler HandlerFunc) {
+	r.Lock()
+	defer r.Unlock()
+	r.handlers[segment] = handler
+}
+
+// Resolve validates a keybase:// link and resolves it into a navigation
+// target.
+func (r *Registry) Resolve(link string) (Target, error) {
+	if len(link) > maxLinkLength {
+		return Target{}, fmt.Errorf("link too long")
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return Target{}, err
+	}
+	if parsed.Scheme != "keybase" {
+		return Target{}, fmt.Errorf("not a keybase:// link: scheme %q", parsed.Scheme)
+	}
+	// keybase://chat/foo parses with Host "chat"; fold it into the path.
+	elems := strings.Split(strings.Trim(parsed.EscapedPath(), "/"), "/")
+	if parsed.Host != "" {
+		elems = append([]string{parsed.Host}, elems...)
+	}
+	for i, elem := range elems {
+		if elems[i], err = url.PathUnescape(elem); err != nil {
+			return Target{}, err
+		}
+		if elems[i] == ".." || elems[i] == "." {
+			return Target{}, fmt.Errorf("path traversal in link")
+		}
+	}
+	if len(elems) == 0 || elems[0] == "" {
+		return Target{}, fmt.Errorf("empty link")
+	}
+	if parsed.Fragment != "" {
+		// url.Parse strips "#channel" off into Fragment; hand it back to
+		// the last path element for handlers that want it.
+		elems[len(elems)-1] += "#" + parsed.Fragment
+	}
+	r.Lock()
+	handler, found := r.handlers[elems[0]]
+	r.Unlock()
+	if !found {
+		return Target{}, fmt.Errorf("unknown link type %q", elems[0])
+	}
+	return handler(elems[1:])
+}
+
+func checkUsername(name string) error {
+	if !libkb.CheckUsername.F(name) {
+		return fmt.Errorf("invalid username %q", name)
+	}
+	return nil
+}
+
+func checkTeamName(name string) error {
+	if _, err := keybase1.TeamNameFromString(name); err != nil {
+		return fmt.Errorf("invalid team name %q: %s", name, err)
+	}
+	return nil
+}
+
+// handleChat resolves keybase://chat/<team>[#channel] and
+// keybase://chat/<user1,user2>.
+func handleChat(elems []string) (Target, error) {
+	if len(elems) != 1 || elems[0] == "" {
+		return Target{}, fmt.Errorf("chat link needs a conversation name")
+	}
+	name := elems[0]
+	channel := ""
+	if idx := strings.Index(name, "#"); idx >= 0 {
+		name, channel = name[:idx], name[idx+1:]
+	}
+	if strings.Contains(name, ",") {
+		for _, user := range strings.Split(name, ",") {
+			if err := checkUsername(user); err != nil {
+				return Target{}, err
+			}
+		}
+	} else if err := checkTeamName(name); err != nil {
+		return Target{}, err
+	}
+	return Target{Kind: TargetChat, Team: name, Channel: channel}, nil
+}
+
+// handleTeam resolves keybase://team/<name>.
+func handleTeam(elems []string) (Target, error) {
+	if len(elems) != 1 || elems[0] == "" {
+		return Target{}, fmt.Errorf("team link needs a team name")
+	}
+	if err := checkTeamName(elems[0]); err != nil {
+		return Target{}, err
+	}
+	return Target{Kind: TargetTeam, Team: elems[0]}, nil
+}
+
+// handleProfile resolves keybase://profile/<username>.
+func handleProfile(elems []string) (Target, error) {
+	if len(elems) != 1 || elems[0] == "" {
+		return Target{}, fmt.Errorf("profile link needs a username")
+	}
+	if err := checkUsername(elems[0]); err != nil {
+		return Target{}, err
+	}
+	return Target{Kind: TargetProfile, User: elems[0]}, nil
+}
+
+// handleKbfs resolves keybase://private/..., keybase://public/... and
+// keybase://teamfolder/... into KBFS paths.
+func handleKbfs(folderList string) HandlerFunc {
+	return func(elems []string) (Target, error) {
+		if len(elems) == 0 || elems[0] == "" {
+			return Target{}, fmt.Errorf("kbfs link needs a folder name")
+		}
+		return Target{
+			Kind:     TargetKbfs,
+			KbfsPath: "/keybase/" + folderList + "/" + strings.Join(elems, "/"),
+		}, nil
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package deeplinks
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDeepLinks(t *testing.T) {
+	tc := libkb.SetupTest(t, "deeplinks", 1)
+	defer tc.Cleanup()
+	r := NewRegistry(tc.G)
+
+	target, err := r.Resolve("keybase://chat/acme.eng#general")
+	require.NoError(t, err)
+	require.Equal(t, TargetChat, target.Kind)
+	require.Equal(t, "acme.eng", target.Team)
+	require.Equal(t, "general", target.Channel)
+
+	target, err = r.Resolve("keybase://chat/alice,bob")
+	require.NoError(t, err)
+	require.Equal(t, "alice,bob", target.Team)
+
+	target, err = r.Resolve("keybase://team/acme")
+	require.NoError(t, err)
+	require.Equal(t, TargetTeam, target.Kind)
+	require.Equal(t, "acme", target.Team)
+
+	target, err = r.Resolve("keybase://profile/alice")
+	require.NoError(t, err)
+	require.Equal(t, TargetProfile, target.Kind)
+	require.Equal(t, "alice", target.User)
+
+	target, err = r.Resolve("keybase://private/alice/docs/notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, TargetKbfs, target.Kind)
+	require.Equal(t, "/keybase/private/alice/docs/notes.txt", target.KbfsPath)
+}
+
+func TestResolveDeepLinkErrors(t *testing.T) {
+	tc := libkb.SetupTest(t, "deeplinks", 1)
+	defer tc.Cleanup()
+	r := NewRegistry(tc.G)
+
+	for _, link := range []string{
+		"https://keybase.io/alice",
+		"keybase://",
+		"keybase://unknown/thing",
+		"keybase://profile/not a user!",
+		"keybase://team/Not..Valid",
+		"keybase://private/alice/../bob/secret",
+	} {
+		_, err := r.Resolve(link)
+		require.Error(t, err, "link %q", link)
+	}
+}
+
+func TestRegisterCustomHandler(t *testing.T) {
+	tc := libkb.SetupTest(t, "deeplinks", 1)
+	defer tc.Cleanup()
+	r := NewRegistry(tc.G)
+	r.Register("git", func(elems []string) (Target, error) {
+		return Target{Kind: TargetKind("git-repo"), Team: elems[0]}, nil
+	})
+	target, err := r.Resolve("keybase://git/acme")
+	require.NoError(t, err)
+	require.EqualValues(t, "git-repo", target.Kind)
+}
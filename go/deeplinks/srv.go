@@ -0,0 +1,58 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package deeplinks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/keybase/client/go/kbhttp/manager"
+	"github.com/keybase/client/go/libkb"
+)
+
+// Srv resolves deep links over the local HTTP server: frontends call
+// `GET /deeplink?link=keybase://...` and get the structured target back.
+type Srv struct {
+	libkb.Contextified
+	httpSrv  *manager.Srv
+	registry *Registry
+}
+
+func NewSrv(g *libkb.GlobalContext, httpSrv *manager.Srv, registry *Registry) *Srv {
+	s := &Srv{
+		Contextified: libkb.NewContextified(g),
+		httpSrv:      httpSrv,
+		registry:     registry,
+	}
+	s.httpSrv.HandleFunc("deeplink", manager.SrvTokenModeDefault, s.serve)
+	return s
+}
+
+func (s *Srv) serve(w http.ResponseWriter, req *http.Request) {
+	link := req.URL.Query().Get("link")
+	target, err := s.registry.Resolve(link)
+	res := struct {
+		Target *Target `json:"target,omitempty"`
+		Error  string  `json:"error,omitempty"`
+	}{}
+	if err != nil {
+		s.G().GetLog().Debug("Deeplinks.Srv: resolve: %s", err)
+		w.WriteHeader(http.StatusBadRequest)
+		res.Error = err.Error()
+	} else {
+		res.Target = &target
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.G().GetLog().Debug("Deeplinks.Srv: encode: %s", err)
+	}
+}
+
+// ServiceInit creates the registry and its HTTP endpoint. The returned
+// Registry is what subsystems add custom link types to.
+func ServiceInit(g *libkb.GlobalContext, httpSrv *manager.Srv) *Registry {
+	registry := NewRegistry(g)
+	NewSrv(g, httpSrv, registry)
+	return registry
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// MerklePrefetch keeps a rolling window of recent merkle roots (with their
+// skips) fetched and pinned locally, so that identify and team loads
+// performed shortly after the device goes offline can still verify
+// freshness within a configurable tolerance. Consumers ask libkb for the
+// last pinned root and get an explicit staleness along with it.
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+var MerklePrefetchSettings = BackgroundTaskSettings{
+	Start:        30 * time.Second,
+	StartStagger: 1 * time.Minute,
+	Interval:     5 * time.Minute,
+	Limit:        30 * time.Second,
+}
+
+// MerklePrefetch is an engine.
+type MerklePrefetch struct {
+	libkb.Contextified
+	sync.Mutex
+
+	args *MerklePrefetchArgs
+	task *BackgroundTask
+}
+
+type MerklePrefetchArgs struct {
+	// Channels used for testing. Normally nil.
+	testingMetaCh     chan<- string
+	testingRoundResCh chan<- error
+}
+
+// NewMerklePrefetch creates a new MerklePrefetch engine.
+func NewMerklePrefetch(g *libkb.GlobalContext, args *MerklePrefetchArgs) *MerklePrefetch {
+	task := NewBackgroundTask(g, &BackgroundTaskArgs{
+		Name:     "MerklePrefetch",
+		F:        MerklePrefetchRound,
+		Settings: MerklePrefetchSettings,
+
+		testingMetaCh:     args.testingMetaCh,
+		testingRoundResCh: args.testingRoundResCh,
+	})
+	return &MerklePrefetch{
+		Contextified: libkb.NewContextified(g),
+		args:         args,
+		task:         task,
+	}
+}
+
+// Name is the unique engine name.
+func (e *MerklePrefetch) Name() string {
+	return "MerklePrefetch"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *MerklePrefetch) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *MerklePrefetch) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *MerklePrefetch) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+// Run starts the engine.
+// Returns immediately, kicks off a background goroutine.
+func (e *MerklePrefetch) Run(mctx libkb.MetaContext) (err error) {
+	return RunEngine2(mctx, e.task)
+}
+
+func (e *MerklePrefetch) Shutdown(mctx libkb.MetaContext) error {
+	mctx.Debug("stopping merkle root prefetch engine")
+	e.task.Shutdown()
+	return nil
+}
+
+// MerklePrefetchRound refreshes the last-fetched root from the server and
+// pins it into the local rolling window.
+func MerklePrefetchRound(mctx libkb.MetaContext) error {
+	mctx = mctx.WithLogTag("MPF")
+
+	if mctx.G().ConnectivityMonitor.IsConnected(mctx.Ctx()) == libkb.ConnectivityMonitorNo {
+		mctx.Debug("MerklePrefetchRound: offline, keeping existing pinned window")
+		return nil
+	}
+
+	// Force a fresh fetch; this also refreshes the skip table that
+	// FetchRootFromServer verifies on the way in.
+	root, err := mctx.G().MerkleClient.FetchRootFromServer(mctx, 0)
+	if err != nil {
+		mctx.Debug("MerklePrefetchRound: fetch failed: %s", err)
+		return err
+	}
+	return libkb.PinMerkleRoot(mctx, root)
+}
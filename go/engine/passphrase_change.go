@@ -316,7 +316,7 @@ func (c *PassphraseChange) commonArgs(m libkb.MetaContext, oldClientHalf libkb.L
 		return nil, err
 	}
 
-	tsec, newPPStream, err := libkb.StretchPassphrase(c.G(), c.arg.Passphrase, salt)
+	tsec, newPPStream, err := libkb.StretchPassphraseContext(m.Ctx(), c.G(), c.arg.Passphrase, salt)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,127 @@
+// Copyright 2020 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// SessionRefreshBackground proactively renews the device's session
+// token (NIST) in the background, instead of waiting for an API call
+// to need one. The NIST factory only mints a new token once the old
+// one is inside its expiration margin, so frequent rounds are cheap.
+// When a renewal round fails, an optional hook lets the service kick
+// its connectivity/gregor layer and prompt for re-login before API
+// calls start erroring.
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+var SessionRefreshBackgroundSettings = BackgroundTaskSettings{
+	Start:        10 * time.Second, // Wait after starting the app
+	StartStagger: 30 * time.Second, // Wait an additional random amount.
+	WakeUp:       10 * time.Second, // Additional delay after waking from sleep.
+	Interval:     6 * time.Hour,    // Wait between checks
+	Limit:        1 * time.Minute,  // Time limit on each round
+}
+
+// SessionRefreshBackground is an engine.
+type SessionRefreshBackground struct {
+	libkb.Contextified
+	sync.Mutex
+
+	args *SessionRefreshBackgroundArgs
+	task *BackgroundTask
+}
+
+type SessionRefreshBackgroundArgs struct {
+	// OnRenewFailure is called when a renewal round fails, so the
+	// service can notify its connectivity/gregor layer and let the
+	// UI prompt for re-login. Normally nil in tests.
+	OnRenewFailure func(m libkb.MetaContext, err error)
+
+	// Channels used for testing. Normally nil.
+	testingMetaCh     chan<- string
+	testingRoundResCh chan<- error
+}
+
+// NewSessionRefreshBackground creates a SessionRefreshBackground engine.
+func NewSessionRefreshBackground(g *libkb.GlobalContext, args *SessionRefreshBackgroundArgs) *SessionRefreshBackground {
+	task := NewBackgroundTask(g, &BackgroundTaskArgs{
+		Name: "SessionRefreshBackground",
+		F: func(m libkb.MetaContext) error {
+			err := SessionRefreshBackgroundRound(m)
+			if err != nil && args.OnRenewFailure != nil {
+				args.OnRenewFailure(m, err)
+			}
+			return err
+		},
+		Settings: SessionRefreshBackgroundSettings,
+
+		testingMetaCh:     args.testingMetaCh,
+		testingRoundResCh: args.testingRoundResCh,
+	})
+	return &SessionRefreshBackground{
+		Contextified: libkb.NewContextified(g),
+		args:         args,
+		// Install the task early so that Shutdown can be called before RunEngine.
+		task: task,
+	}
+}
+
+// Name is the unique engine name.
+func (e *SessionRefreshBackground) Name() string {
+	return "SessionRefreshBackground"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *SessionRefreshBackground) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *SessionRefreshBackground) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *SessionRefreshBackground) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+// Run starts the engine.
+// Returns immediately, kicks off a background goroutine.
+func (e *SessionRefreshBackground) Run(m libkb.MetaContext) (err error) {
+	return RunEngine2(m, e.task)
+}
+
+func (e *SessionRefreshBackground) Shutdown() {
+	e.task.Shutdown()
+}
+
+func SessionRefreshBackgroundRound(m libkb.MetaContext) error {
+	if m.G().ConnectivityMonitor.IsConnected(m.Ctx()) == libkb.ConnectivityMonitorNo {
+		m.Debug("SessionRefreshBackgroundRound giving up offline")
+		return nil
+	}
+
+	if !m.G().ActiveDevice.Valid() {
+		m.Debug("SessionRefreshBackgroundRound not logged in")
+		return nil
+	}
+
+	// The NIST factory hands back the existing token while it's
+	// comfortably within its lifetime, and mints a fresh one once it
+	// enters the expiration margin; calling it on a schedule keeps a
+	// valid token warm for upcoming API calls.
+	nist, err := m.G().ActiveDevice.NIST(m.Ctx())
+	if err != nil {
+		m.Debug("SessionRefreshBackgroundRound error renewing session token: %s", err)
+		return err
+	}
+	if nist == nil {
+		m.Debug("SessionRefreshBackgroundRound no NIST available")
+	}
+	return nil
+}
@@ -243,7 +243,7 @@ func (s *SignupEngine) genPassphraseStream(m libkb.MetaContext, passphrase strin
 		return err
 	}
 	s.pwsalt = salt
-	s.tsec, s.ppStream, err = libkb.StretchPassphrase(m.G(), passphrase, salt)
+	s.tsec, s.ppStream, err = libkb.StretchPassphraseContext(m.Ctx(), m.G(), passphrase, salt)
 	if err != nil {
 		return err
 	}
@@ -471,6 +471,10 @@ func (d *FakeGregorState) LocalDismissItem(ctx context.Context, id gregor.MsgID)
 	return nil
 }
 
+func (d *FakeGregorState) ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (int, error) {
+	return 0, nil
+}
+
 func TestTrackWithTokenDismissesGregor(t *testing.T) {
 	tc := SetupEngineTest(t, "track")
 	defer tc.Cleanup()
@@ -185,6 +185,11 @@ func (e *EKLib) KeygenIfNeeded(mctx libkb.MetaContext) (err error) {
 		case <-time.After(20 * time.Millisecond):
 		}
 	}
+	// Ephemeral key lifetimes are short enough that a skewed local clock
+	// can be the real reason keygen keeps failing; say so if it is.
+	if err != nil && mctx.G().CheckClockSkew() != nil {
+		err = libkb.NewClockSkewError(mctx.G().MeasuredClockSkew(), err)
+	}
 	return err
 }
 
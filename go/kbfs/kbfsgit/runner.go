@@ -65,9 +65,10 @@ const (
 	// Debug tag ID for an individual git command passed to the process.
 	ctxCommandOpID = "GITCMDID"
 
-	kbfsgitPrefix = "keybase://"
-	repoSplitter  = "/"
-	kbfsRepoDir   = ".kbfs_git"
+	kbfsgitPrefix    = "keybase://"
+	repoSplitter     = "/"
+	repoTimeSplitter = "@"
+	kbfsRepoDir      = ".kbfs_git"
 
 	publicName  = "public"
 	privateName = "private"
@@ -127,6 +128,7 @@ type runner struct {
 	h           *tlfhandle.Handle
 	remote      string
 	repo        string
+	archivedRev kbfsmd.Revision
 	gitDir      string
 	uniqID      string
 	input       io.Reader
@@ -179,6 +181,21 @@ func newRunnerWithType(ctx context.Context, config libkbfs.Config,
 		return nil, err
 	}
 
+	// The repo name may carry an optional "@<time>" suffix, which
+	// pins all reads to the latest TLF revision at or before that
+	// time (e.g., `keybase://team/acme/repo@2023-01-01`).  Repo names
+	// themselves can never contain '@'.
+	repoName := parts[2]
+	archivedRev := kbfsmd.RevisionUninitialized
+	if i := strings.Index(repoName, repoTimeSplitter); i >= 0 {
+		archivedRev, err = libfs.RevFromTimeString(
+			ctx, config, h, repoName[i+len(repoTimeSplitter):])
+		if err != nil {
+			return nil, err
+		}
+		repoName = repoName[:i]
+	}
+
 	// Use the device ID and PID to make a unique ID (for generating
 	// temp files in KBFS).
 	session, err := idutil.GetCurrentSessionIfPossible(
@@ -193,7 +210,8 @@ func newRunnerWithType(ctx context.Context, config libkbfs.Config,
 		log:         config.MakeLogger(""),
 		h:           h,
 		remote:      remote,
-		repo:        parts[2],
+		repo:        repoName,
+		archivedRev: archivedRev,
 		gitDir:      gitDir,
 		uniqID:      uniqID,
 		input:       input,
@@ -206,7 +224,9 @@ func newRunnerWithType(ctx context.Context, config libkbfs.Config,
 }
 
 // newRunner creates a new runner for git commands.  It expects `repo`
-// to be in the form "keybase://private/user/reponame".  `remote`
+// to be in the form "keybase://private/user/reponame", with an
+// optional "@<time>" suffix on the repo name to fetch from the
+// archived revision of the repo at that time.  `remote`
 // is the local name assigned to that URL, while `gitDir` is the
 // filepath leading to the .git directory of the caller's local
 // on-disk repo.
@@ -316,10 +336,14 @@ func (r *runner) isManagedByApp() bool {
 func (r *runner) makeFS(ctx context.Context) (fs *libfs.FS, err error) {
 	// Only allow lazy creates for TLFs that aren't managed by the
 	// Keybase app.
-	if r.isManagedByApp() {
+	switch {
+	case r.archivedRev != kbfsmd.RevisionUninitialized:
+		fs, _, err = libgit.GetRepoAndIDAtRevision(
+			ctx, r.config, r.h, r.repo, r.uniqID, r.archivedRev)
+	case r.isManagedByApp():
 		fs, _, err = libgit.GetRepoAndID(
 			ctx, r.config, r.h, r.repo, r.uniqID)
-	} else {
+	default:
 		fs, _, err = libgit.GetOrCreateRepoAndID(
 			ctx, r.config, r.h, r.repo, r.uniqID)
 	}
@@ -1760,6 +1784,11 @@ func (r *runner) pushSome(
 // an LF.
 func (r *runner) handlePushBatch(ctx context.Context, args [][]string) (
 	commits libgit.RefDataByName, err error) {
+	if r.archivedRev != kbfsmd.RevisionUninitialized {
+		return nil, errors.New(
+			"cannot push to a repo pinned to an archived revision")
+	}
+
 	repo, fs, err := r.initRepoIfNeeded(ctx, gitCmdPush)
 	if err != nil {
 		return nil, err
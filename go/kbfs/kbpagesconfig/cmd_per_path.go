@@ -7,6 +7,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"text/tabwriter"
 
 	"github.com/urfave/cli"
@@ -180,7 +181,10 @@ func getPerPathSetCmdUsageText() string {
 	if customPagesEnabled {
 		customPage = "\n   set <403|404> <path_relative_to_site_root> <path> [path ...]"
 	}
-	return "set Access-Control-Allow-Origin <''|'*'> <path> [path ...]" + customPage
+	return "set Access-Control-Allow-Origin <''|'*'> <path> [path ...]" +
+		customPage +
+		"\n   set index-file <filename> <path> [path ...]" +
+		"\n   set disable-listing <true|false> <path> [path ...]"
 }
 
 var perPathSetCmd = cli.Command{
@@ -229,6 +233,32 @@ var perPathSetCmd = cli.Command{
 					os.Exit(1)
 				}
 			}
+		case c.Args()[0] == "index-file":
+			for _, p := range c.Args()[2:] {
+				err := editor.setIndexFile(p, c.Args()[1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr,
+						"setting index file %q on %q error: %v\n",
+						c.Args()[1], p, err)
+					os.Exit(1)
+				}
+			}
+		case c.Args()[0] == "disable-listing":
+			disabled, err := strconv.ParseBool(c.Args()[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr,
+					"parsing bool %q error: %v\n", c.Args()[1], err)
+				os.Exit(1)
+			}
+			for _, p := range c.Args()[2:] {
+				err := editor.setDisableDirectoryListing(p, disabled)
+				if err != nil {
+					fmt.Fprintf(os.Stderr,
+						"setting disable-listing %v on %q error: %v\n",
+						disabled, p, err)
+					os.Exit(1)
+				}
+			}
 		default:
 			fmt.Fprintf(os.Stderr, "unknown parameter: %s\n", c.Args()[0])
 			os.Exit(1)
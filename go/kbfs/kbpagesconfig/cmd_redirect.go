@@ -0,0 +1,103 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+)
+
+var redirectSetCmd = cli.Command{
+	Name:      "set",
+	Usage:     "set a redirect from a path to a location",
+	UsageText: "set <path> <location>",
+	Action: func(c *cli.Context) {
+		if len(c.Args()) != 2 {
+			fmt.Fprintln(os.Stderr, "need exactly 2 args")
+			os.Exit(1)
+		}
+		editor, err := newKBPConfigEditor(c.GlobalString("dir"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr,
+				"creating config editor error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := editor.setRedirect(c.Args()[0], c.Args()[1]); err != nil {
+			fmt.Fprintf(os.Stderr,
+				"setting redirect %q -> %q error: %v\n",
+				c.Args()[0], c.Args()[1], err)
+			os.Exit(1)
+		}
+		if err := editor.confirmAndWrite(); err != nil {
+			fmt.Fprintf(os.Stderr, "writing new config error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var redirectRemoveCmd = cli.Command{
+	Name:      "remove",
+	Usage:     "remove the redirect configured for the given path(s)",
+	UsageText: "remove <path> [path ...]",
+	Action: func(c *cli.Context) {
+		if len(c.Args()) < 1 {
+			fmt.Fprintln(os.Stderr, "need at least 1 arg")
+			os.Exit(1)
+		}
+		editor, err := newKBPConfigEditor(c.GlobalString("dir"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr,
+				"creating config editor error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, p := range c.Args() {
+			editor.removeRedirect(p)
+		}
+		if err := editor.confirmAndWrite(); err != nil {
+			fmt.Fprintf(os.Stderr, "writing new config error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var redirectListCmd = cli.Command{
+	Name:      "list",
+	Usage:     "list all configured redirects",
+	UsageText: "list",
+	Action: func(c *cli.Context) {
+		editor, err := newKBPConfigEditor(c.GlobalString("dir"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr,
+				"creating config editor error: %v\n", err)
+			os.Exit(1)
+		}
+		paths := make([]string, 0, len(editor.kbpConfig.Redirects))
+		for p := range editor.kbpConfig.Redirects {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "path\tlocation")
+		for _, p := range paths {
+			fmt.Fprintf(w, "%s\t%s\n", p, editor.kbpConfig.Redirects[p])
+		}
+		w.Flush()
+	},
+}
+
+var redirectCmd = cli.Command{
+	Name:      "redirect",
+	Usage:     "make changes to the 'redirects' section of the config",
+	UsageText: "redirect <set|remove|list> [args]",
+	Subcommands: []cli.Command{
+		redirectSetCmd,
+		redirectRemoveCmd,
+		redirectListCmd,
+	},
+}
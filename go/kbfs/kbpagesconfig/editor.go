@@ -209,3 +209,29 @@ func (e *kbpConfigEditor) set404(
 		c.Custom404NotFound = p
 	})
 }
+
+func (e *kbpConfigEditor) setIndexFile(
+	pathStr string, name string) error {
+	return e.setFieldSimple(pathStr, func(c *config.PerPathConfigV1) {
+		c.IndexFile = name
+	})
+}
+
+func (e *kbpConfigEditor) setDisableDirectoryListing(
+	pathStr string, disabled bool) error {
+	return e.setFieldSimple(pathStr, func(c *config.PerPathConfigV1) {
+		c.DisableDirectoryListing = disabled
+	})
+}
+
+func (e *kbpConfigEditor) setRedirect(pathStr string, location string) error {
+	if e.kbpConfig.Redirects == nil {
+		e.kbpConfig.Redirects = make(map[string]string)
+	}
+	e.kbpConfig.Redirects[pathStr] = location
+	return e.kbpConfig.Validate()
+}
+
+func (e *kbpConfigEditor) removeRedirect(pathStr string) {
+	delete(e.kbpConfig.Redirects, pathStr)
+}
@@ -0,0 +1,135 @@
+package ldbutils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+const (
+	nameCryptKeyFilename = "cache_name_key"
+	nameCryptKeySize     = 32
+	// nameCryptPrefix marks filenames already encrypted, so migration is
+	// idempotent.
+	nameCryptPrefix = "enc1-"
+)
+
+// NameCryptor deterministically encrypts cache keys and on-disk cache
+// filenames with a device-local secret, so caches on shared machines don't
+// leak TLF or directory names. Determinism (keyed HMAC) keeps lookups a
+// single hash away from the plaintext name.
+type NameCryptor struct {
+	key []byte
+}
+
+// NewNameCryptor loads the device-local name key from storageRoot,
+// creating it on first use.
+func NewNameCryptor(storageRoot string) (*NameCryptor, error) {
+	keyPath := filepath.Join(storageRoot, nameCryptKeyFilename)
+	key, err := os.ReadFile(keyPath)
+	switch {
+	case err == nil:
+		if len(key) != nameCryptKeySize {
+			return nil, errors.Errorf(
+				"bad cache name key length: %d", len(key))
+		}
+	case os.IsNotExist(err):
+		key = make([]byte, nameCryptKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(storageRoot, 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(keyPath, key, 0600); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	return &NameCryptor{key: key}, nil
+}
+
+// EncryptName maps a plaintext cache name to its encrypted form. The same
+// name always maps to the same output for a given device key.
+func (c *NameCryptor) EncryptName(name string) string {
+	if IsEncryptedName(name) {
+		return name
+	}
+	mac := hmac.New(sha256.New, c.key)
+	_, _ = mac.Write([]byte(name))
+	return nameCryptPrefix + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EncryptKey is EncryptName for raw leveldb keys.
+func (c *NameCryptor) EncryptKey(key []byte) []byte {
+	return []byte(c.EncryptName(string(key)))
+}
+
+// IsEncryptedName reports whether a filename is already in encrypted form.
+func IsEncryptedName(name string) bool {
+	return len(name) > len(nameCryptPrefix) &&
+		name[:len(nameCryptPrefix)] == nameCryptPrefix
+}
+
+// KeyEncryptedLevelDb wraps a LevelDb so that all keys are run through a
+// NameCryptor before hitting disk. Values are already encrypted by the
+// callers that need it; this hides the keys themselves (which may be TLF or
+// directory names) from anyone reading the cache files directly.
+type KeyEncryptedLevelDb struct {
+	*LevelDb
+	cryptor *NameCryptor
+}
+
+// NewKeyEncryptedLevelDb wraps db with key encryption under cryptor.
+func NewKeyEncryptedLevelDb(db *LevelDb, cryptor *NameCryptor) *KeyEncryptedLevelDb {
+	return &KeyEncryptedLevelDb{LevelDb: db, cryptor: cryptor}
+}
+
+// Get gets data from the DB.
+func (ldb *KeyEncryptedLevelDb) Get(key []byte, ro *opt.ReadOptions) (
+	value []byte, err error) {
+	return ldb.LevelDb.Get(ldb.cryptor.EncryptKey(key), ro)
+}
+
+// Put puts data into the DB.
+func (ldb *KeyEncryptedLevelDb) Put(key, value []byte, wo *opt.WriteOptions) error {
+	return ldb.LevelDb.Put(ldb.cryptor.EncryptKey(key), value, wo)
+}
+
+// Delete deletes data from the DB.
+func (ldb *KeyEncryptedLevelDb) Delete(key []byte, wo *opt.WriteOptions) error {
+	return ldb.LevelDb.Delete(ldb.cryptor.EncryptKey(key), wo)
+}
+
+// MigrateDirNames renames any plaintext-named entries in dir to their
+// encrypted names. It is safe to call on every startup; already-encrypted
+// entries and the key file itself are left alone.
+func (c *NameCryptor) MigrateDirNames(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == nameCryptKeyFilename || IsEncryptedName(name) {
+			continue
+		}
+		err := os.Rename(
+			filepath.Join(dir, name),
+			filepath.Join(dir, c.EncryptName(name)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package ldbutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+type testBufferSizeGetter struct{}
+
+func (testBufferSizeGetter) DbWriteBufferSize() int { return 1 << 16 }
+
+func TestNameCryptorDeterminism(t *testing.T) {
+	root := t.TempDir()
+	c, err := NewNameCryptor(root)
+	require.NoError(t, err)
+
+	enc := c.EncryptName("private/alice,bob")
+	require.True(t, IsEncryptedName(enc))
+	require.Equal(t, enc, c.EncryptName("private/alice,bob"))
+	require.NotEqual(t, enc, c.EncryptName("private/alice"))
+	// Double encryption is a no-op.
+	require.Equal(t, enc, c.EncryptName(enc))
+
+	// Key persists across instances.
+	c2, err := NewNameCryptor(root)
+	require.NoError(t, err)
+	require.Equal(t, enc, c2.EncryptName("private/alice,bob"))
+
+	// A different device key gives different names.
+	c3, err := NewNameCryptor(t.TempDir())
+	require.NoError(t, err)
+	require.NotEqual(t, enc, c3.EncryptName("private/alice,bob"))
+}
+
+func TestKeyEncryptedLevelDb(t *testing.T) {
+	root := t.TempDir()
+	c, err := NewNameCryptor(root)
+	require.NoError(t, err)
+
+	db, err := OpenLevelDb(storage.NewMemStorage(), testBufferSizeGetter{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	edb := NewKeyEncryptedLevelDb(db, c)
+	key := []byte("private/alice,bob")
+	require.NoError(t, edb.Put(key, []byte("v"), nil))
+
+	value, err := edb.Get(key, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v"), value)
+
+	// The plaintext key never hits the underlying DB.
+	_, err = db.Get(key, nil)
+	require.Error(t, err)
+	_, err = db.Get(c.EncryptKey(key), nil)
+	require.NoError(t, err)
+
+	require.NoError(t, edb.Delete(key, nil))
+	_, err = edb.Get(key, nil)
+	require.Error(t, err)
+}
+
+func TestNameCryptorMigrateDirNames(t *testing.T) {
+	root := t.TempDir()
+	c, err := NewNameCryptor(root)
+	require.NoError(t, err)
+
+	dir := filepath.Join(root, "cache")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secretdir"), []byte("x"), 0600))
+
+	require.NoError(t, c.MigrateDirNames(dir))
+	_, err = os.Stat(filepath.Join(dir, "secretdir"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, c.EncryptName("secretdir")))
+	require.NoError(t, err)
+
+	// Idempotent.
+	require.NoError(t, c.MigrateDirNames(dir))
+	_, err = os.Stat(filepath.Join(dir, c.EncryptName("secretdir")))
+	require.NoError(t, err)
+}
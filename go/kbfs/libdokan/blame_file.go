@@ -0,0 +1,25 @@
+// Copyright 2020 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libdokan
+
+import (
+	"time"
+
+	"github.com/keybase/client/go/kbfs/libfs"
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	"golang.org/x/net/context"
+)
+
+// NewBlameFile returns a special file that attributes each line of a
+// file to the archived revision and writer that last changed it.
+func NewBlameFile(
+	fs *FS, dir libkbfs.Node, name string) *SpecialReadFile {
+	return &SpecialReadFile{
+		read: func(ctx context.Context) ([]byte, time.Time, error) {
+			return libfs.GetBlame(ctx, fs.config, dir, name)
+		},
+		fs: fs,
+	}
+}
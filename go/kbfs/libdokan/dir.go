@@ -336,6 +336,16 @@ func (d *Dir) open(ctx context.Context, oc *openContext, path []string) (dokan.F
 			return NewFileInfoFile(d.folder.fs, d.node, name), 0, nil
 		}
 
+		// Check if this is a per-file blame file, if so return the
+		// corresponding SpecialReadFile.
+		if leaf && strings.HasPrefix(path[0], libfs.BlameFilePrefix) {
+			if err := oc.ReturningFileAllowed(); err != nil {
+				return nil, 0, err
+			}
+			name := path[0][len(libfs.BlameFilePrefix):]
+			return NewBlameFile(d.folder.fs, d.node, name), 0, nil
+		}
+
 		newNode, de, err := d.folder.fs.config.KBFSOps().Lookup(
 			ctx, d.node, d.node.ChildName(path[0]))
 
@@ -0,0 +1,265 @@
+// Copyright 2020 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libfs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/kbfs/data"
+	"github.com/keybase/client/go/kbfs/kbfsmd"
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	"golang.org/x/net/context"
+)
+
+// blameMaxFileSize bounds how much data we're willing to read per
+// revision when computing blame for a file.
+const blameMaxFileSize = 10 * 1024 * 1024
+
+// blameRange attributes a contiguous range of lines to the revision
+// (and writer) that last changed them.
+type blameRange struct {
+	// StartLine and EndLine are 1-based and inclusive.
+	StartLine int             `json:"start_line"`
+	EndLine   int             `json:"end_line"`
+	Revision  kbfsmd.Revision `json:"revision"`
+	Writer    string          `json:"writer,omitempty"`
+}
+
+// blameFile is the JSON-serialized payload of a blame magic file.
+type blameFile struct {
+	Path string `json:"path"`
+	// Revisions lists the revisions consulted, oldest first; the
+	// last entry is the current revision.
+	Revisions []kbfsmd.Revision `json:"revisions"`
+	// Truncated is true when the file may have history older than
+	// the archived-revision window; lines attributed to the oldest
+	// revision listed may really date from before it.
+	Truncated bool         `json:"truncated"`
+	Ranges    []blameRange `json:"ranges"`
+}
+
+// blameLines splits file contents into lines, dropping the empty
+// trailing element produced by a final newline so that line numbers
+// match what editors show.
+func blameLines(content []byte) []string {
+	lines := strings.Split(string(content), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// readFullNode reads the entire contents of a file node, up to
+// blameMaxFileSize.
+func readFullNode(
+	ctx context.Context, config libkbfs.Config, node libkbfs.Node,
+	size uint64) ([]byte, error) {
+	if size > blameMaxFileSize {
+		return nil, fmt.Errorf(
+			"File too large for blame: %d > %d", size, blameMaxFileSize)
+	}
+	buf := make([]byte, size)
+	var off int64
+	for off < int64(size) {
+		n, err := config.KBFSOps().Read(ctx, node, buf[off:], off)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			break
+		}
+		off += n
+	}
+	return buf[:off], nil
+}
+
+// lookupAtRevision finds the file at the given in-TLF path components
+// in the archived version of the TLF at `rev`, and returns its
+// contents and last writer.  It returns a nil slice (and no error) if
+// the file didn't exist at that revision.
+func lookupAtRevision(
+	ctx context.Context, config libkbfs.Config, dir libkbfs.Node,
+	rev kbfsmd.Revision, components []string) (
+	content []byte, writer string, err error) {
+	h, err := config.KBFSOps().GetTLFHandle(ctx, dir)
+	if err != nil {
+		return nil, "", err
+	}
+	node, _, err := config.KBFSOps().GetRootNode(
+		ctx, h, data.MakeRevBranchName(rev))
+	if err != nil {
+		return nil, "", err
+	}
+	if node == nil {
+		return nil, "", nil
+	}
+	var ei data.EntryInfo
+	for _, c := range components {
+		node, ei, err = config.KBFSOps().Lookup(ctx, node, node.ChildName(c))
+		if err != nil {
+			// The file (or a parent) didn't exist yet at this
+			// revision, or was since renamed; just skip it.
+			return nil, "", nil
+		}
+		if node == nil {
+			return nil, "", nil
+		}
+	}
+	if ei.Type != data.File && ei.Type != data.Exec {
+		return nil, "", nil
+	}
+	content, err = readFullNode(ctx, config, node, ei.Size)
+	if err != nil {
+		return nil, "", err
+	}
+	nmd, err := config.KBFSOps().GetNodeMetadata(ctx, node)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, nmd.LastWriterUnverified.String(), nil
+}
+
+// attributeLines carries per-line attributions from one revision of a
+// file to the next.  Lines in the common prefix and suffix of the two
+// versions keep their old attribution; everything in between is
+// attributed to `rev`.  This is cheaper than a real diff and errs on
+// the side of attributing too much to the newer revision, which
+// matches what a "last edited by" column wants.
+func attributeLines(
+	oldLines, newLines []string, oldAttr []kbfsmd.Revision,
+	rev kbfsmd.Revision) []kbfsmd.Revision {
+	minLen := len(oldLines)
+	if len(newLines) < minLen {
+		minLen = len(newLines)
+	}
+	prefix := 0
+	for prefix < minLen && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < minLen-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	attr := make([]kbfsmd.Revision, len(newLines))
+	copy(attr[:prefix], oldAttr[:prefix])
+	for i := prefix; i < len(newLines)-suffix; i++ {
+		attr[i] = rev
+	}
+	copy(attr[len(newLines)-suffix:], oldAttr[len(oldLines)-suffix:])
+	return attr
+}
+
+// GetBlame returns serialized JSON attributing each line of a file to
+// the archived revision (and writer) that last changed it.  Only
+// revisions still in the entry's archived-revision window are
+// considered; older lines collapse into the oldest revision in the
+// window, and the output is marked truncated when that happens.
+func GetBlame(
+	ctx context.Context, config libkbfs.Config, dir libkbfs.Node,
+	name string) (blameData []byte, t time.Time, err error) {
+	node, ei, err := config.KBFSOps().Lookup(ctx, dir, dir.ChildName(name))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if ei.Type != data.File && ei.Type != data.Exec {
+		return nil, time.Time{}, fmt.Errorf(
+			"Blame is only available for files; %s is a %s", name, ei.Type)
+	}
+	plainPath, ok := node.GetPathPlaintextSansTlf()
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf(
+			"Couldn't get a plaintext path for %s", name)
+	}
+	components := strings.Split(strings.TrimPrefix(plainPath, "/"), "/")
+
+	currContent, err := readFullNode(ctx, config, node, ei.Size)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	currLines := blameLines(currContent)
+
+	status, _, err := config.KBFSOps().FolderStatus(
+		ctx, node.GetFolderBranch())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	currRev := status.Revision
+
+	nmd, err := config.KBFSOps().GetNodeMetadata(ctx, node)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	writers := map[kbfsmd.Revision]string{
+		currRev: nmd.LastWriterUnverified.String(),
+	}
+
+	// `ei.PrevRevisions` is ordered newest-first; walk it
+	// oldest-first, carrying attributions forward revision by
+	// revision.
+	var attr []kbfsmd.Revision
+	var prevLines []string
+	res := blameFile{Path: plainPath}
+	for i := len(ei.PrevRevisions) - 1; i >= 0; i-- {
+		rev := ei.PrevRevisions[i].Revision
+		content, writer, err := lookupAtRevision(
+			ctx, config, dir, rev, components)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if content == nil {
+			continue
+		}
+		lines := blameLines(content)
+		if attr == nil {
+			// Oldest revision we can see; everything starts out
+			// attributed to it, even though some lines may really
+			// date from before the archived window.
+			attr = make([]kbfsmd.Revision, len(lines))
+			for j := range attr {
+				attr[j] = rev
+			}
+			res.Truncated = true
+		} else {
+			attr = attributeLines(prevLines, lines, attr, rev)
+		}
+		prevLines = lines
+		writers[rev] = writer
+		res.Revisions = append(res.Revisions, rev)
+	}
+
+	if attr == nil {
+		attr = make([]kbfsmd.Revision, len(currLines))
+		for j := range attr {
+			attr[j] = currRev
+		}
+	} else {
+		attr = attributeLines(prevLines, currLines, attr, currRev)
+	}
+	res.Revisions = append(res.Revisions, currRev)
+
+	// Coalesce runs of lines with the same attribution into ranges.
+	for i := 0; i < len(attr); {
+		j := i
+		for j < len(attr) && attr[j] == attr[i] {
+			j++
+		}
+		res.Ranges = append(res.Ranges, blameRange{
+			StartLine: i + 1,
+			EndLine:   j,
+			Revision:  attr[i],
+			Writer:    writers[attr[i]],
+		})
+		i = j
+	}
+
+	blameData, err = PrettyJSON(res)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return blameData, time.Unix(0, ei.Mtime), nil
+}
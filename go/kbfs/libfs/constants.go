@@ -97,6 +97,11 @@ const UpdateHistoryFileName = ".kbfs_update_history"
 // FileInfoPrefix is the prefix of the per-file metadata files.
 const FileInfoPrefix = ".kbfs_fileinfo_"
 
+// BlameFilePrefix is the prefix of the per-file blame files, which
+// attribute line ranges of a file to the archived revisions (and
+// writers) that last changed them.
+const BlameFilePrefix = ".kbfs_blame_"
+
 // EnableSyncFileName is the name of the file to enable the sync cache for a
 // TLF. It can be reached anywhere within a TLF.
 const EnableSyncFileName = ".kbfs_enable_sync"
@@ -113,6 +118,19 @@ const ArchivedRevDirPrefix = ".kbfs_archived_rev="
 // TLF that links to a version of that TLF at the specified time.
 const ArchivedTimeLinkPrefix = ".kbfs_archived_time="
 
+// TagsFileName is the name of the file at the root of a TLF that
+// holds the TLF's named tags: a JSON object mapping tag names to
+// revision numbers. Since the registry is an ordinary file in the
+// TLF, writes are gated by the folder's write permissions, and its
+// full history remains browsable through the archived-revision
+// directories.
+const TagsFileName = ".kbfs_tags"
+
+// ArchivedTagLinkPrefix is the prefix to the symlink at the root of a
+// TLF that links to the version of that TLF at the revision named by
+// the given tag.
+const ArchivedTagLinkPrefix = ".kbfs_tag="
+
 // ArchivedRelTimeFilePrefix is the prefix to the file at the root of
 // a TLF that contains the directory name of an archived revision
 // described by the given relative time.
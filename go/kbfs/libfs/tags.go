@@ -0,0 +1,174 @@
+// Copyright 2020 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/keybase/client/go/kbfs/data"
+	"github.com/keybase/client/go/kbfs/idutil"
+	"github.com/keybase/client/go/kbfs/kbfsmd"
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	"github.com/keybase/client/go/kbfs/tlfhandle"
+	"github.com/pkg/errors"
+)
+
+// tagsMaxFileSize bounds the size of the tag registry file; it only
+// holds tag names and revision numbers, so this is generous.
+const tagsMaxFileSize = 1 << 20
+
+// TlfTags maps the named tags of a TLF to the revisions they pin.
+// Tags work like lightweight git tags: setting one records the
+// current (or any given) revision under a stable name, which can then
+// be browsed via an ArchivedTagLinkPrefix symlink without copying any
+// trees.
+type TlfTags map[string]kbfsmd.Revision
+
+// ReadTlfTags returns the tag registry of the given TLF, or an empty
+// registry if none has been created yet.
+func ReadTlfTags(
+	ctx context.Context, config libkbfs.Config, h *tlfhandle.Handle) (
+	TlfTags, error) {
+	root, _, err := config.KBFSOps().GetRootNode(ctx, h, data.MasterBranch)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return TlfTags{}, nil
+	}
+	node, ei, err := config.KBFSOps().Lookup(
+		ctx, root, root.ChildName(TagsFileName))
+	switch errors.Cause(err).(type) {
+	case nil:
+	case idutil.NoSuchNameError:
+		return TlfTags{}, nil
+	default:
+		return nil, err
+	}
+	if ei.Size > tagsMaxFileSize {
+		return nil, fmt.Errorf(
+			"Tag registry too large: %d > %d", ei.Size, tagsMaxFileSize)
+	}
+	buf, err := readFullNode(ctx, config, node, ei.Size)
+	if err != nil {
+		return nil, err
+	}
+	var tags TlfTags
+	if err := json.Unmarshal(buf, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func writeTlfTags(
+	ctx context.Context, config libkbfs.Config, h *tlfhandle.Handle,
+	tags TlfTags) error {
+	buf, err := json.MarshalIndent(tags, "", "  ")
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+
+	root, _, err := config.KBFSOps().GetOrCreateRootNode(
+		ctx, h, data.MasterBranch)
+	if err != nil {
+		return err
+	}
+	filename := root.ChildName(TagsFileName)
+	node, _, err := config.KBFSOps().Lookup(ctx, root, filename)
+	switch errors.Cause(err).(type) {
+	case nil:
+	case idutil.NoSuchNameError:
+		node, _, err = config.KBFSOps().CreateFile(
+			ctx, root, filename, false, libkbfs.NoExcl)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	if err := config.KBFSOps().Truncate(ctx, node, 0); err != nil {
+		return err
+	}
+	if err := config.KBFSOps().Write(ctx, node, buf, 0); err != nil {
+		return err
+	}
+	return config.KBFSOps().SyncAll(ctx, node.GetFolderBranch())
+}
+
+// checkTagName rejects names that wouldn't survive as a path
+// component of an ArchivedTagLinkPrefix symlink.
+func checkTagName(name string) error {
+	if name == "" {
+		return errors.New("empty tag name")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("invalid tag name %q", name)
+	}
+	return nil
+}
+
+// SetTlfTag records `rev` under `name` in the TLF's tag registry,
+// overwriting any existing tag of that name. Earlier states of the
+// registry stay reachable through the archived-revision directories,
+// so tag history comes for free.
+func SetTlfTag(
+	ctx context.Context, config libkbfs.Config, h *tlfhandle.Handle,
+	name string, rev kbfsmd.Revision) error {
+	if err := checkTagName(name); err != nil {
+		return err
+	}
+	if rev < kbfsmd.RevisionInitial {
+		return fmt.Errorf("invalid revision %d for tag %q", rev, name)
+	}
+	tags, err := ReadTlfTags(ctx, config, h)
+	if err != nil {
+		return err
+	}
+	tags[name] = rev
+	return writeTlfTags(ctx, config, h, tags)
+}
+
+// DeleteTlfTag removes `name` from the TLF's tag registry.
+func DeleteTlfTag(
+	ctx context.Context, config libkbfs.Config, h *tlfhandle.Handle,
+	name string) error {
+	tags, err := ReadTlfTags(ctx, config, h)
+	if err != nil {
+		return err
+	}
+	if _, ok := tags[name]; !ok {
+		return fmt.Errorf("no such tag: %q", name)
+	}
+	delete(tags, name)
+	return writeTlfTags(ctx, config, h, tags)
+}
+
+// LinkTargetFromTagString returns the name of a by-revision archive
+// directory, and true, if the given link specifies a valid by-tag
+// link name.
+func LinkTargetFromTagString(
+	ctx context.Context, config libkbfs.Config, h *tlfhandle.Handle,
+	link string) (string, bool, error) {
+	if !strings.HasPrefix(link, ArchivedTagLinkPrefix) {
+		return "", false, nil
+	}
+
+	name := link[len(ArchivedTagLinkPrefix):]
+	tags, err := ReadTlfTags(ctx, config, h)
+	if err != nil {
+		return "", false, err
+	}
+	rev, ok := tags[name]
+	if !ok {
+		return "", false, fmt.Errorf("no such tag: %q", name)
+	}
+
+	return ArchivedRevDirPrefix + strconv.FormatInt(int64(rev), 10), true, nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+//
+// +build !windows
+
+package libfuse
+
+import (
+	"time"
+
+	"github.com/keybase/client/go/kbfs/libfs"
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	"golang.org/x/net/context"
+)
+
+// NewBlameFile returns a special file that attributes each line of a
+// file to the archived revision and writer that last changed it.
+func NewBlameFile(
+	fs *FS, dir libkbfs.Node, name string,
+	entryValid *time.Duration) *SpecialReadFile {
+	*entryValid = 0
+	return &SpecialReadFile{
+		read: func(ctx context.Context) ([]byte, time.Time, error) {
+			return libfs.GetBlame(ctx, fs.config, dir, name)
+		},
+	}
+}
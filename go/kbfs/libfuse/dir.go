@@ -584,6 +584,13 @@ func (d *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		return NewFileInfoFile(d.folder.fs, d.node, name, &resp.EntryValid), nil
 	}
 
+	// Check if this is a per-file blame file, if so return the
+	// corresponding SpecialReadFile.
+	if strings.HasPrefix(req.Name, libfs.BlameFilePrefix) {
+		name := req.Name[len(libfs.BlameFilePrefix):]
+		return NewBlameFile(d.folder.fs, d.node, name, &resp.EntryValid), nil
+	}
+
 	newNode, de, err := d.folder.fs.config.KBFSOps().Lookup(
 		ctx, d.node, namePPS)
 	if err != nil {
@@ -506,6 +506,13 @@ func (r *Root) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.L
 		return r.team, nil
 	}
 
+	// Mount namespace aliases surface as symlinks into the real
+	// folderlists, so shells and tools follow them naturally.
+	if target, found := libkbfs.ResolveMountAlias(
+		r.private.fs.config, req.Name); found {
+		return &Alias{realPath: target}, nil
+	}
+
 	// Don't want to pop up errors on special OS files.
 	if strings.HasPrefix(req.Name, ".") {
 		return nil, fuse.ENOENT
@@ -578,6 +585,9 @@ func (r *Root) ReadDirAll(ctx context.Context) (res []fuse.Dirent, err error) {
 	if r.private.fs.platformParams.shouldAppendPlatformRootDirs() {
 		res = append(res, platformRootDirs...)
 	}
+	for name := range libkbfs.MountAliases(r.private.fs.config) {
+		res = append(res, fuse.Dirent{Type: fuse.DT_Link, Name: name})
+	}
 
 	if name := r.private.fs.remoteStatus.ExtraFileName(); name != "" {
 		res = append(res, fuse.Dirent{Type: fuse.DT_File, Name: name})
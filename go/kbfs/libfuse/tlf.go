@@ -257,6 +257,18 @@ func (tlf *TLF) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.
 			tlf.folder.fs, tlf.folder.h, req.Name, &resp.EntryValid), nil
 	}
 
+	linkTarget, isTagLink, err := libfs.LinkTargetFromTagString(
+		ctx, tlf.folder.fs.config, tlf.folder.h, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if isTagLink {
+		return &Alias{
+			realPath: linkTarget,
+			inode:    0,
+		}, nil
+	}
+
 	return dir.Lookup(ctx, req, resp)
 }
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/keybase/client/go/kbfs/data"
 	"github.com/keybase/client/go/kbfs/idutil"
+	"github.com/keybase/client/go/kbfs/kbfsmd"
 	"github.com/keybase/client/go/kbfs/libfs"
 	"github.com/keybase/client/go/kbfs/libkbfs"
 	"github.com/keybase/client/go/kbfs/tlfhandle"
@@ -412,7 +413,7 @@ const (
 
 func getOrCreateRepoAndID(
 	ctx context.Context, config libkbfs.Config, tlfHandle *tlfhandle.Handle,
-	repoName string, uniqID string, op repoOpType) (
+	repoName string, uniqID string, op repoOpType, branch data.BranchName) (
 	fs *libfs.FS, id ID, err error) {
 	if !checkValidRepoName(repoName, config) {
 		return nil, NullID,
@@ -420,7 +421,7 @@ func getOrCreateRepoAndID(
 	}
 
 	rootNode, _, err := config.KBFSOps().GetOrCreateRootNode(
-		ctx, tlfHandle, data.MasterBranch)
+		ctx, tlfHandle, branch)
 	if err != nil {
 		return nil, NullID, err
 	}
@@ -477,7 +478,7 @@ func getOrCreateRepoAndID(
 	repoExists = true
 
 	fs, err = libfs.NewFS(
-		ctx, config, tlfHandle, data.MasterBranch,
+		ctx, config, tlfHandle, branch,
 		path.Join(kbfsRepoDir, normalizedRepoName),
 		uniqID, keybase1.MDPriorityGit)
 	if err != nil {
@@ -535,7 +536,8 @@ func GetOrCreateRepoAndID(
 	ctx context.Context, config libkbfs.Config, tlfHandle *tlfhandle.Handle,
 	repoName string, uniqID string) (*libfs.FS, ID, error) {
 	return getOrCreateRepoAndID(
-		ctx, config, tlfHandle, repoName, uniqID, getOrCreate)
+		ctx, config, tlfHandle, repoName, uniqID, getOrCreate,
+		data.MasterBranch)
 }
 
 // GetRepoAndID returns a filesystem object rooted at the
@@ -545,7 +547,20 @@ func GetRepoAndID(
 	ctx context.Context, config libkbfs.Config, tlfHandle *tlfhandle.Handle,
 	repoName string, uniqID string) (*libfs.FS, ID, error) {
 	return getOrCreateRepoAndID(
-		ctx, config, tlfHandle, repoName, uniqID, getOnly)
+		ctx, config, tlfHandle, repoName, uniqID, getOnly, data.MasterBranch)
+}
+
+// GetRepoAndIDAtRevision returns a read-only filesystem object rooted
+// at the specified repo, as it existed at the given TLF revision,
+// along with the stable repo ID.  The repo must have already existed
+// at that revision.
+func GetRepoAndIDAtRevision(
+	ctx context.Context, config libkbfs.Config, tlfHandle *tlfhandle.Handle,
+	repoName string, uniqID string, rev kbfsmd.Revision) (
+	*libfs.FS, ID, error) {
+	return getOrCreateRepoAndID(
+		ctx, config, tlfHandle, repoName, uniqID, getOnly,
+		data.MakeRevBranchName(rev))
 }
 
 func makeUniqueID(ctx context.Context, config libkbfs.Config) (string, error) {
@@ -575,7 +590,7 @@ func CreateRepoAndID(
 	}
 
 	fs, id, err := getOrCreateRepoAndID(
-		ctx, config, tlfHandle, repoName, uniqID, createOnly)
+		ctx, config, tlfHandle, repoName, uniqID, createOnly, data.MasterBranch)
 	if err != nil {
 		return NullID, err
 	}
@@ -971,7 +986,7 @@ func GCRepo(
 	}
 
 	fs, _, err := getOrCreateRepoAndID(
-		ctx, config, tlfHandle, repoName, uniqID, getOnly)
+		ctx, config, tlfHandle, repoName, uniqID, getOnly, data.MasterBranch)
 	if err != nil {
 		return err
 	}
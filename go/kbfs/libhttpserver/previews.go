@@ -0,0 +1,203 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libhttpserver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // for preview decoding
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/nfnt/resize"
+	_ "golang.org/x/image/bmp"  // for preview decoding
+	_ "golang.org/x/image/tiff" // for preview decoding
+)
+
+const previewCacheSize = 256
+
+// maxPreviewSourceSize limits how much file data we're willing to pull
+// out of KBFS to generate a single preview. Anything larger and the GUI
+// should fall back to a generic file icon.
+const maxPreviewSourceSize = 32 * 1024 * 1024
+
+const defaultPreviewSize = 256
+const minPreviewSize = 16
+const maxPreviewSize = 1024
+
+// previewableMimeTypes are the types we can decode and downscale
+// locally. PDFs and videos are detected but not yet rendered; requests
+// for them get http.StatusUnsupportedMediaType so the GUI can fall back
+// without downloading the full file.
+var previewableMimeTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/bmp":  true,
+	"image/tiff": true,
+}
+
+// previewMimeType returns the sniffed or extension-derived content type
+// for a file, preferring the extension since sniffing only sees the
+// first 512 bytes.
+func previewMimeType(requestPath string, head []byte) string {
+	if byExt := mime.TypeByExtension(strings.ToLower(path.Ext(requestPath))); byExt != "" {
+		return beforeSemicolon(byExt)
+	}
+	return beforeSemicolon(http.DetectContentType(head))
+}
+
+func parsePreviewSize(sizeStr string) (uint, error) {
+	if len(sizeStr) == 0 {
+		return defaultPreviewSize, nil
+	}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil {
+		return 0, err
+	}
+	if size < minPreviewSize || size > maxPreviewSize {
+		return 0, fmt.Errorf("preview size %d out of range [%d, %d]",
+			size, minPreviewSize, maxPreviewSize)
+	}
+	return uint(size), nil
+}
+
+// renderPreview decodes src and downscales it to fit in a size x size
+// box, preserving aspect ratio. PNG and GIF sources are re-encoded as
+// PNG to keep transparency; everything else becomes JPEG.
+func renderPreview(src []byte, size uint) (preview []byte, mimeType string, err error) {
+	img, format, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, "", err
+	}
+	thumb := resize.Thumbnail(size, size, img, resize.Bicubic)
+	var buf bytes.Buffer
+	switch format {
+	case "png", "gif":
+		if err = png.Encode(&buf, thumb); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	default:
+		if err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+}
+
+type cachedPreview struct {
+	data     []byte
+	mimeType string
+}
+
+// servePreview accepts "/<fs path>?token=<token>&size=<pixels>" and
+// responds with a downscaled rendition of the file, generating and
+// caching it if needed. It shares the token and filesystem plumbing
+// with serve.
+func (s *Server) servePreview(w http.ResponseWriter, req *http.Request) {
+	s.vlog.Log(libkb.VLog1, "Incoming preview request from %q: %s",
+		req.UserAgent(), req.URL)
+	addr, err := s.server.Addr()
+	if err != nil {
+		s.logger.Error("servePreview: failed to get HTTP server address: %s", err)
+		s.handleInternalServerError(w)
+		return
+	}
+	if req.Host != addr {
+		s.logger.Warning("Host %s didn't match addr %s, failing request to protect against DNS rebinding", req.Host, addr)
+		s.handleBadRequest(w)
+		return
+	}
+	token := req.URL.Query().Get("token")
+	currentToken, err := s.CurrentToken()
+	if err != nil {
+		s.logger.Error("servePreview: failed to get current token: %s", err)
+		s.handleInternalServerError(w)
+		return
+	}
+	if len(token) == 0 || token != currentToken {
+		s.vlog.Log(libkb.VLog1, "Invalid token %q", token)
+		s.handleInvalidToken(w)
+		return
+	}
+	size, err := parsePreviewSize(req.URL.Query().Get("size"))
+	if err != nil {
+		s.logger.Warning("Bad preview request; error=%v", err)
+		s.handleBadRequest(w)
+		return
+	}
+
+	toStrip, fs, err := s.getHTTPFileSystem(req.Context(), req.URL.Path)
+	if err != nil {
+		s.logger.Warning("Bad preview request; error=%v", err)
+		s.handleBadRequest(w)
+		return
+	}
+	name := strings.TrimPrefix(req.URL.Path, toStrip)
+	f, err := fs.Open(name)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		s.handleBadRequest(w)
+		return
+	}
+
+	cacheKey := fmt.Sprintf("%s|%d|%d|%d",
+		req.URL.Path, size, fi.Size(), fi.ModTime().UnixNano())
+	if cached, ok := s.previews.Get(cacheKey); ok {
+		if p, ok := cached.(cachedPreview); ok {
+			s.writePreview(w, p)
+			return
+		}
+	}
+
+	if fi.Size() > maxPreviewSourceSize {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	src := make([]byte, fi.Size())
+	if _, err = io.ReadFull(f, src); err != nil {
+		s.logger.Warning("servePreview: read failed: %v", err)
+		s.handleInternalServerError(w)
+		return
+	}
+	mimeType := previewMimeType(req.URL.Path, src)
+	if !previewableMimeTypes[mimeType] {
+		w.Header().Set("X-Keybase-Preview-Content-Type", mimeType)
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	data, previewType, err := renderPreview(src, size)
+	if err != nil {
+		s.logger.Warning("servePreview: render failed for %s: %v",
+			req.URL.Path, err)
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	p := cachedPreview{data: data, mimeType: previewType}
+	s.previews.Add(cacheKey, p)
+	s.writePreview(w, p)
+}
+
+func (s *Server) writePreview(w http.ResponseWriter, p cachedPreview) {
+	w.Header().Set("Content-Type", p.mimeType)
+	w.Header().Set("Content-Disposition", "inline")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	_, _ = w.Write(p.data)
+}
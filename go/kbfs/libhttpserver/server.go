@@ -42,7 +42,8 @@ type Server struct {
 	token           string
 	tokenExpireTime time.Time
 
-	fs *lru.Cache
+	fs       *lru.Cache
+	previews *lru.Cache
 
 	serverLock sync.RWMutex
 	server     *kbhttp.Srv
@@ -210,6 +211,7 @@ func (s *Server) serve(w http.ResponseWriter, req *http.Request) {
 const portStart = 16723
 const portEnd = 60000
 const requestPathRoot = "/files/"
+const previewPathRoot = "/previews/"
 
 func (s *Server) restart() (err error) {
 	s.serverLock.Lock()
@@ -232,6 +234,8 @@ func (s *Server) restart() (err error) {
 	// Have to start this first to populate the ServeMux object.
 	s.server.Handle(requestPathRoot,
 		http.StripPrefix(requestPathRoot, http.HandlerFunc(s.serve)))
+	s.server.Handle(previewPathRoot,
+		http.StripPrefix(previewPathRoot, http.HandlerFunc(s.servePreview)))
 	return nil
 }
 
@@ -274,6 +278,9 @@ func New(appStateUpdater env.AppStateUpdater, config libkbfs.Config) (
 	if s.fs, err = lru.New(fsCacheSize); err != nil {
 		return nil, err
 	}
+	if s.previews, err = lru.New(previewCacheSize); err != nil {
+		return nil, err
+	}
 	if err = s.restart(); err != nil {
 		return nil, err
 	}
@@ -141,6 +141,10 @@ func (config testBlockOpsConfig) SubscriptionManager(
 	return config.subscriptionManager
 }
 
+func (config testBlockOpsConfig) DumpSubscriptionManagers() []SubscriptionManagerDump {
+	return nil
+}
+
 func (config testBlockOpsConfig) SubscriptionManagerPublisher() SubscriptionManagerPublisher {
 	return config.subscriptionManagerPublisher
 }
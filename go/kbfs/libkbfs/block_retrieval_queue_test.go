@@ -95,6 +95,10 @@ func (c testBlockRetrievalConfig) SubscriptionManager(
 	return c.subscriptionManager
 }
 
+func (c testBlockRetrievalConfig) DumpSubscriptionManagers() []SubscriptionManagerDump {
+	return nil
+}
+
 func (c testBlockRetrievalConfig) SubscriptionManagerPublisher() SubscriptionManagerPublisher {
 	return c.subscriptionManagerPublisher
 }
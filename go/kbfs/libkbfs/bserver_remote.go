@@ -44,6 +44,10 @@ type blockServerRemoteClientHandler struct {
 	rpcLogFactory rpc.LogFactory
 	pinger        pinger
 
+	// qualityPublisher, if non-nil, receives ping RTTs and connection
+	// failures so the subscription manager can track connection quality.
+	qualityPublisher connectionQualityPublisher
+
 	connMu sync.RWMutex
 	conn   *rpc.Connection
 	client keybase1.BlockInterface
@@ -235,6 +239,21 @@ func (b *blockServerRemoteClientHandler) OnConnect(ctx context.Context,
 	return nil
 }
 
+// publishConnectionQuality reports a ping result or connection failure
+// to the subscription manager, if one was wired up.
+func (b *blockServerRemoteClientHandler) publishConnectionQuality(
+	rtt time.Duration, failed, inBackoff bool) {
+	if b.qualityPublisher == nil {
+		return
+	}
+	b.qualityPublisher.PublishConnectionQuality(connectionQualitySample{
+		service:   BServiceName,
+		rtt:       rtt,
+		failed:    failed,
+		inBackoff: inBackoff,
+	})
+}
+
 // OnConnectError implements the ConnectionHandler interface.
 func (b *blockServerRemoteClientHandler) OnConnectError(err error, wait time.Duration) {
 	b.log.Warning("%s: connection error: %v; retrying in %s", b.name, err, wait)
@@ -242,6 +261,7 @@ func (b *blockServerRemoteClientHandler) OnConnectError(err error, wait time.Dur
 		b.authToken.Shutdown()
 	}
 	b.pinger.cancelTicker()
+	b.publishConnectionQuality(0, true, true)
 	// TODO: it might make sense to show something to the user if this is
 	// due to authentication, for example.
 }
@@ -285,15 +305,20 @@ func (b *blockServerRemoteClientHandler) ShouldRetryOnConnect(err error) bool {
 var _ rpc.ConnectionHandler = (*blockServerRemoteClientHandler)(nil)
 
 func (b *blockServerRemoteClientHandler) pingOnce(ctx context.Context) {
+	beforePing := time.Now()
 	_, err := b.getClient().BlockPing(ctx)
 	if err == context.DeadlineExceeded {
 		b.log.CDebugf(
 			ctx, "%s: Ping timeout -- reinitializing connection", b.name)
+		b.publishConnectionQuality(0, true, false)
 		if err = b.reconnect(); err != nil {
 			b.log.CDebugf(ctx, "reconnect error: %v", err)
 		}
 	} else if err != nil {
 		b.log.CDebugf(ctx, "%s: ping error %s", b.name, err)
+		b.publishConnectionQuality(0, true, false)
+	} else {
+		b.publishConnectionQuality(time.Since(beforePing), false, false)
 	}
 }
 
@@ -351,6 +376,16 @@ func NewBlockServerRemote(kbCtx Context, config blockServerRemoteConfig,
 		"BlockServerRemoteGet", log, config.Signer(),
 		config.CurrentSessionGetter(), blkSrvRemote, rpcLogFactory)
 
+	// The narrow config interface doesn't promise a subscription
+	// manager; wire up quality reporting only when the full config can
+	// provide a publisher that tracks it.
+	if smpg, ok := config.(subscriptionManagerPublisherGetter); ok {
+		if cqp, ok := smpg.SubscriptionManagerPublisher().(connectionQualityPublisher); ok {
+			bs.putConn.qualityPublisher = cqp
+			bs.getConn.qualityPublisher = cqp
+		}
+	}
+
 	bs.shutdownFn = func() {
 		bs.putConn.shutdown()
 		bs.getConn.shutdown()
@@ -150,6 +150,12 @@ type ConfigLocal struct {
 	// before syncing a set of changes to the servers.
 	bgFlushPeriod time.Duration
 
+	// subscriptionManagerPurgePolicy controls when purgeable
+	// subscription managers are evicted.
+	subscriptionManagerPurgePolicy SubscriptionManagerPurgePolicy
+	journalStatusThresholds        JournalStatusThresholds
+	subscriptionNotificationQPS    float64
+
 	// metadataVersion is the version to use when creating new metadata.
 	metadataVersion kbfsmd.MetadataVer
 
@@ -292,6 +298,9 @@ func NewConfigLocal(mode InitMode,
 	config.tlfValidDuration = tlfValidDurationDefault
 	config.bgFlushDirOpBatchSize = bgFlushDirOpBatchSizeDefault
 	config.bgFlushPeriod = bgFlushPeriodDefault
+	config.subscriptionManagerPurgePolicy = defaultSubscriptionManagerPurgePolicy()
+	config.journalStatusThresholds = defaultJournalStatusThresholds()
+	config.subscriptionNotificationQPS = defaultSubscriptionNotificationQPS
 	config.metadataVersion = defaultClientMetadataVer
 	config.defaultBlockType = defaultBlockTypeDefault
 	config.quotaUsage =
@@ -1054,6 +1063,56 @@ func (c *ConfigLocal) BGFlushPeriod() time.Duration {
 	return c.bgFlushPeriod
 }
 
+// SetSubscriptionManagerPurgePolicy implements the Config interface
+// for ConfigLocal.
+func (c *ConfigLocal) SetSubscriptionManagerPurgePolicy(
+	policy SubscriptionManagerPurgePolicy) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.subscriptionManagerPurgePolicy = policy
+}
+
+// SubscriptionManagerPurgePolicy implements the Config interface for
+// ConfigLocal.
+func (c *ConfigLocal) SubscriptionManagerPurgePolicy() SubscriptionManagerPurgePolicy {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.subscriptionManagerPurgePolicy
+}
+
+// SetJournalStatusThresholds implements the Config interface for
+// ConfigLocal.
+func (c *ConfigLocal) SetJournalStatusThresholds(
+	thresholds JournalStatusThresholds) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.journalStatusThresholds = thresholds
+}
+
+// JournalStatusThresholds implements the Config interface for
+// ConfigLocal.
+func (c *ConfigLocal) JournalStatusThresholds() JournalStatusThresholds {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.journalStatusThresholds
+}
+
+// SetSubscriptionNotificationQPS implements the Config interface for
+// ConfigLocal.
+func (c *ConfigLocal) SetSubscriptionNotificationQPS(qps float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.subscriptionNotificationQPS = qps
+}
+
+// SubscriptionNotificationQPS implements the Config interface for
+// ConfigLocal.
+func (c *ConfigLocal) SubscriptionNotificationQPS() float64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.subscriptionNotificationQPS
+}
+
 // Shutdown implements the Config interface for ConfigLocal.
 func (c *ConfigLocal) Shutdown(ctx context.Context) error {
 	c.RekeyQueue().Shutdown()
@@ -1846,6 +1905,11 @@ func (c *ConfigLocal) SubscriptionManagerPublisher() SubscriptionManagerPublishe
 	return c.subscriptionManagerManager
 }
 
+// DumpSubscriptionManagers implements the Config interface.
+func (c *ConfigLocal) DumpSubscriptionManagers() []SubscriptionManagerDump {
+	return c.subscriptionManagerManager.DumpSubscriptions()
+}
+
 // KbEnv implements the Config interface.
 func (c *ConfigLocal) KbEnv() *libkb.Env {
 	c.lock.RLock()
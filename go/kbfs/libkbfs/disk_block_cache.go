@@ -345,7 +345,20 @@ func newDiskBlockCacheLocal(config diskBlockCacheConfig,
 	if err != nil {
 		return nil, err
 	}
-	blockDbPath := filepath.Join(versionPath, blockDbFilename)
+	dbName := func(name string) string { return name }
+	if mode.DiskCacheNameEncryptionEnabled() {
+		nameCryptor, err := ldbutils.NewNameCryptor(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		// Rename any DBs written before name encryption was enabled.
+		err = nameCryptor.MigrateDirNames(versionPath)
+		if err != nil {
+			return nil, err
+		}
+		dbName = nameCryptor.EncryptName
+	}
+	blockDbPath := filepath.Join(versionPath, dbName(blockDbFilename))
 	blockStorage, err := storage.OpenFile(blockDbPath, false)
 	if err != nil {
 		return nil, err
@@ -355,7 +368,7 @@ func newDiskBlockCacheLocal(config diskBlockCacheConfig,
 			blockStorage.Close()
 		}
 	}()
-	metaDbPath := filepath.Join(versionPath, metaDbFilename)
+	metaDbPath := filepath.Join(versionPath, dbName(metaDbFilename))
 	metadataStorage, err := storage.OpenFile(metaDbPath, false)
 	if err != nil {
 		return nil, err
@@ -365,7 +378,7 @@ func newDiskBlockCacheLocal(config diskBlockCacheConfig,
 			metadataStorage.Close()
 		}
 	}()
-	tlfDbPath := filepath.Join(versionPath, tlfDbFilename)
+	tlfDbPath := filepath.Join(versionPath, dbName(tlfDbFilename))
 	tlfStorage, err := storage.OpenFile(tlfDbPath, false)
 	if err != nil {
 		return nil, err
@@ -375,7 +388,7 @@ func newDiskBlockCacheLocal(config diskBlockCacheConfig,
 			tlfStorage.Close()
 		}
 	}()
-	lastUnrefDbPath := filepath.Join(versionPath, lastUnrefDbFilename)
+	lastUnrefDbPath := filepath.Join(versionPath, dbName(lastUnrefDbFilename))
 	lastUnrefStorage, err := storage.OpenFile(lastUnrefDbPath, false)
 	if err != nil {
 		return nil, err
@@ -191,7 +191,20 @@ func newDiskMDCacheLocal(
 	if err != nil {
 		return nil, err
 	}
-	headsDbPath := filepath.Join(versionPath, headsDbFilename)
+	headsDbName := headsDbFilename
+	if mode.DiskCacheNameEncryptionEnabled() {
+		nameCryptor, err := ldbutils.NewNameCryptor(cachePath)
+		if err != nil {
+			return nil, err
+		}
+		// Rename any DBs written before name encryption was enabled.
+		err = nameCryptor.MigrateDirNames(versionPath)
+		if err != nil {
+			return nil, err
+		}
+		headsDbName = nameCryptor.EncryptName(headsDbFilename)
+	}
+	headsDbPath := filepath.Join(versionPath, headsDbName)
 	headsStorage, err := storage.OpenFile(headsDbPath, false)
 	if err != nil {
 		return nil, err
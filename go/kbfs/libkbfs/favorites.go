@@ -408,6 +408,19 @@ func favoriteToFolder(fav favorites.Folder, data favorites.Data) keybase1.Folder
 	}
 }
 
+// appendFavoriteDeltaName records fav in a FavoritesDelta name list,
+// rendered as "<type>/<name>", skipping names already recorded.
+func appendFavoriteDeltaName(
+	names []string, fav favorites.Folder) []string {
+	name := fav.Type.PathString() + "/" + fav.Name
+	for _, existing := range names {
+		if existing == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
 func (f *Favorites) doIDRefresh(id tlf.ID) bool {
 	f.idLock.Lock()
 	defer f.idLock.Unlock()
@@ -429,10 +442,16 @@ func (f *Favorites) handleReq(req *favReq) (err error) {
 	defer f.wg.Done()
 
 	changed := false
+	var delta FavoritesDelta
 	defer func() {
 		f.closeReq(req, err)
 		if changed {
-			f.config.SubscriptionManagerPublisher().PublishChange(keybase1.SubscriptionTopic_FAVORITES)
+			publisher := f.config.SubscriptionManagerPublisher()
+			if favPublisher, ok := publisher.(favoritesPublisher); ok {
+				favPublisher.PublishFavoritesChange(delta)
+			} else {
+				publisher.PublishChange(keybase1.SubscriptionTopic_FAVORITES)
+			}
 			f.config.Reporter().NotifyFavoritesChanged(req.ctx)
 		}
 	}()
@@ -478,6 +497,10 @@ func (f *Favorites) handleReq(req *favReq) (err error) {
 		}
 		needFetch = true
 		changed = true
+		delta.Added = appendFavoriteDeltaName(delta.Added, fav.Folder)
+		if fav.Created {
+			delta.Created = appendFavoriteDeltaName(delta.Created, fav.Folder)
+		}
 	}
 
 	for _, fav := range req.toDel {
@@ -490,6 +513,7 @@ func (f *Favorites) handleReq(req *favReq) (err error) {
 		}
 		f.config.UserHistory().ClearTLF(tlf.CanonicalName(fav.Name), fav.Type)
 		changed = true
+		delta.Removed = appendFavoriteDeltaName(delta.Removed, fav)
 		// Simply delete here instead of triggering another list as an
 		// optimization because there's nothing additional we need from core.
 		delete(f.favCache, fav)
@@ -599,6 +623,26 @@ func (f *Favorites) handleReq(req *favReq) (err error) {
 			}
 			if oldCache != nil {
 				changed = f.sendChangesToEditHistory(oldCache)
+				for fav := range f.favCache {
+					if _, ok := oldCache[fav]; !ok {
+						delta.Added = appendFavoriteDeltaName(delta.Added, fav)
+					}
+				}
+				for fav := range oldCache {
+					if _, ok := f.favCache[fav]; ok {
+						continue
+					}
+					if _, ok := f.ignoredCache[fav]; ok {
+						delta.Ignored = appendFavoriteDeltaName(
+							delta.Ignored, fav)
+					} else {
+						delta.Removed = appendFavoriteDeltaName(
+							delta.Removed, fav)
+					}
+				}
+				if !delta.isEmpty() {
+					changed = true
+				}
 			}
 		}
 	} else if req.clear {
@@ -0,0 +1,109 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libkbfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"golang.org/x/net/context"
+)
+
+// Settings key holding the per-user favorites preferences blob.
+const favoritePrefsKey = "favoritePrefs"
+
+// FavoritePrefs are user preferences for one favorite folder: free-form
+// tags ("work", "personal", "archive"), a position in a custom sort order
+// (lower sorts first; 0 means unordered), and whether the folder is hidden
+// from the default view.
+type FavoritePrefs struct {
+	Tags   []string `json:"tags,omitempty"`
+	Order  int      `json:"order,omitempty"`
+	Hidden bool     `json:"hidden,omitempty"`
+}
+
+// FavoritePrefsKey returns the map key used for a folder's preferences.
+func FavoritePrefsKey(folder keybase1.Folder) string {
+	return fmt.Sprintf("%d/%s", folder.FolderType, folder.Name)
+}
+
+// FavoritePrefs returns the logged-in user's favorites preferences, keyed
+// by FavoritePrefsKey.
+func (db *SettingsDB) FavoritePrefs(ctx context.Context) (
+	map[string]FavoritePrefs, error) {
+	uid := db.getUID(ctx)
+	if uid == keybase1.UID("") {
+		return nil, errNoSession
+	}
+	data, err := db.Get(getSettingsDbKey(uid, favoritePrefsKey), nil)
+	switch errors.Cause(err) {
+	case leveldb.ErrNotFound:
+		return make(map[string]FavoritePrefs), nil
+	case nil:
+	default:
+		return nil, err
+	}
+	prefs := make(map[string]FavoritePrefs)
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// SetFavoritePrefs stores preferences for one favorite. Zero-value prefs
+// delete the entry.
+func (db *SettingsDB) SetFavoritePrefs(
+	ctx context.Context, key string, prefs FavoritePrefs) error {
+	uid := db.getUID(ctx)
+	if uid == keybase1.UID("") {
+		return errNoSession
+	}
+	all, err := db.FavoritePrefs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(prefs.Tags) == 0 && prefs.Order == 0 && !prefs.Hidden {
+		delete(all, key)
+	} else {
+		all[key] = prefs
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return db.Put(getSettingsDbKey(uid, favoritePrefsKey), data, nil)
+}
+
+// ApplyFavoritePrefs filters hidden folders out of the list (unless
+// includeHidden is set) and sorts it: custom-ordered folders first by their
+// order, then the rest by name.
+func ApplyFavoritePrefs(prefs map[string]FavoritePrefs,
+	folders []keybase1.Folder, includeHidden bool) []keybase1.Folder {
+	ret := make([]keybase1.Folder, 0, len(folders))
+	for _, folder := range folders {
+		if !includeHidden && prefs[FavoritePrefsKey(folder)].Hidden {
+			continue
+		}
+		ret = append(ret, folder)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		oi := prefs[FavoritePrefsKey(ret[i])].Order
+		oj := prefs[FavoritePrefsKey(ret[j])].Order
+		switch {
+		case oi != 0 && oj != 0:
+			return oi < oj
+		case oi != oj:
+			// Ordered folders come before unordered ones.
+			return oi != 0
+		default:
+			return ret[i].Name < ret[j].Name
+		}
+	})
+	return ret
+}
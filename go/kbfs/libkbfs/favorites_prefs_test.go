@@ -0,0 +1,43 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libkbfs
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFavoritePrefs(t *testing.T) {
+	folders := []keybase1.Folder{
+		{Name: "zzz", FolderType: keybase1.FolderType_PRIVATE},
+		{Name: "aaa", FolderType: keybase1.FolderType_PRIVATE},
+		{Name: "work", FolderType: keybase1.FolderType_TEAM},
+		{Name: "old", FolderType: keybase1.FolderType_PRIVATE},
+	}
+	prefs := map[string]FavoritePrefs{
+		FavoritePrefsKey(folders[2]): {Order: 1, Tags: []string{"work"}},
+		FavoritePrefsKey(folders[0]): {Order: 2},
+		FavoritePrefsKey(folders[3]): {Hidden: true},
+	}
+
+	got := ApplyFavoritePrefs(prefs, folders, false)
+	require.Len(t, got, 3)
+	require.Equal(t, "work", got[0].Name)
+	require.Equal(t, "zzz", got[1].Name)
+	require.Equal(t, "aaa", got[2].Name)
+
+	// includeHidden keeps hidden folders, sorted with the unordered ones.
+	got = ApplyFavoritePrefs(prefs, folders, true)
+	require.Len(t, got, 4)
+	require.Equal(t, []string{"work", "zzz", "aaa", "old"},
+		[]string{got[0].Name, got[1].Name, got[2].Name, got[3].Name})
+
+	// No prefs leaves order stable aside from the name sort.
+	got = ApplyFavoritePrefs(nil, folders, false)
+	require.Len(t, got, 4)
+	require.Equal(t, "aaa", got[0].Name)
+}
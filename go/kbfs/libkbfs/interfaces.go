@@ -169,6 +169,11 @@ type subscriptionManagerGetter interface {
 	// effective.
 	SubscriptionManager(clientID SubscriptionManagerClientID, purgeable bool,
 		notifier SubscriptionNotifier) SubscriptionManager
+
+	// DumpSubscriptionManagers returns a snapshot of every live
+	// subscription manager and its registrations, to help debug clients
+	// that never unsubscribe.
+	DumpSubscriptionManagers() []SubscriptionManagerDump
 }
 
 type subscriptionManagerPublisherGetter interface {
@@ -2105,6 +2110,9 @@ type InitMode interface {
 	// DiskCacheCompactionEnabled indicates whether the local disk
 	// block cache should trigger compaction automatically.
 	DiskCacheCompactionEnabled() bool
+	// DiskCacheNameEncryptionEnabled indicates whether on-disk cache
+	// names should be encrypted with a device-local key.
+	DiskCacheNameEncryptionEnabled() bool
 	// EditHistoryPrefetchingEnabled indicates whether we should
 	// auto-prefetch the most recently-edited files.
 	EditHistoryPrefetchingEnabled() bool
@@ -2147,13 +2155,187 @@ type SubscriptionNotifier interface {
 		topic keybase1.SubscriptionTopic)
 }
 
+// PathChangeType is a bitmask of the kinds of change that happened at a
+// path since the last notification.
+type PathChangeType int
+
+const (
+	// PathChangeTypeWrite indicates file content was written or truncated.
+	PathChangeTypeWrite PathChangeType = 1 << iota
+	// PathChangeTypeChildren indicates entries were added to or removed
+	// from a directory.
+	PathChangeTypeChildren
+	// PathChangeTypeHandleChange indicates the TLF's handle changed, so
+	// the path is now reachable under a new canonical name (e.g. an SBS
+	// assertion resolved, or a conflict folder got finalized).
+	PathChangeTypeHandleChange
+)
+
+// PathChangeDetails describes what actually changed at a path, so a
+// consumer can update its view incrementally instead of re-statting and
+// re-listing the whole directory on every event.
+type PathChangeDetails struct {
+	ChangeType PathChangeType
+	// EntryNames are the basenames of directory entries that were added or
+	// removed, when ChangeType includes PathChangeTypeChildren.
+	EntryNames []string
+	// WriteRanges are the file regions written, when ChangeType includes
+	// PathChangeTypeWrite. A range with Len == 0 denotes a truncate at Off.
+	WriteRanges []WriteRange
+	// NewTlfPath is the new canonical path of the TLF, when ChangeType
+	// includes PathChangeTypeHandleChange.
+	NewTlfPath string
+}
+
+func (d *PathChangeDetails) merge(other PathChangeDetails) {
+	d.ChangeType |= other.ChangeType
+	d.EntryNames = append(d.EntryNames, other.EntryNames...)
+	d.WriteRanges = append(d.WriteRanges, other.WriteRanges...)
+	if other.NewTlfPath != "" {
+		d.NewTlfPath = other.NewTlfPath
+	}
+}
+
+// SubscriptionNotifierDetailed is an optional interface a
+// SubscriptionNotifier may implement to receive structured details about
+// path changes in addition to the plain OnPathChange callbacks. Details
+// are accumulated between debounced notifications, so a single callback
+// may describe several coalesced changes.
+type SubscriptionNotifierDetailed interface {
+	// OnPathChangeDetailed is called with the same arguments as
+	// OnPathChange, plus the accumulated details of the changes since the
+	// last notification for the path.
+	OnPathChangeDetailed(
+		clientID SubscriptionManagerClientID, subscriptionIDs []SubscriptionID,
+		path string, topics []keybase1.PathSubscriptionTopic,
+		details PathChangeDetails)
+}
+
+// JournalStatusDetails carries the per-TLF journal counters delivered
+// with a JOURNAL_STATUS notification, so a consumer can show upload
+// progress without polling the full FSStatus on every event.
+type JournalStatusDetails struct {
+	// UnflushedBytes is the number of journaled block bytes that
+	// haven't been flushed to the server yet.
+	UnflushedBytes int64
+	// UnflushedBlocks is the number of journaled block entries that
+	// haven't been flushed to the server yet.
+	UnflushedBlocks int64
+}
+
+// SubscriptionNotifierJournalStatus is an optional interface a
+// SubscriptionNotifier may implement to receive the journal counters
+// along with JOURNAL_STATUS notifications, instead of having to call
+// back into KBFS for them.
+type SubscriptionNotifierJournalStatus interface {
+	// OnJournalStatusChange is called right after the plain
+	// OnNonPathChange callback for a JOURNAL_STATUS notification, with
+	// the counters from the most recent journal change.
+	OnJournalStatusChange(
+		clientID SubscriptionManagerClientID, subscriptionIDs []SubscriptionID,
+		details JournalStatusDetails)
+}
+
+// FavoritesDelta describes what changed in the favorites list, for
+// delivery with FAVORITES notifications. Folders are rendered as
+// "<type>/<name>", e.g. "private/alice,bob" or "team/acme". Between
+// deliveries the deltas from multiple changes are merged, so one
+// notification may describe several coalesced changes; an empty delta
+// means the list was refreshed without a visible difference.
+type FavoritesDelta struct {
+	// Added lists folders newly present in the favorites list.
+	Added []string
+	// Removed lists folders no longer in the favorites list.
+	Removed []string
+	// Ignored lists folders the user explicitly ignored.
+	Ignored []string
+	// Created lists folders the user just created, e.g. a new team
+	// folder; these also appear in Added.
+	Created []string
+}
+
+func (d FavoritesDelta) isEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.Ignored) == 0 && len(d.Created) == 0
+}
+
+func (d *FavoritesDelta) merge(other FavoritesDelta) {
+	d.Added = append(d.Added, other.Added...)
+	d.Removed = append(d.Removed, other.Removed...)
+	d.Ignored = append(d.Ignored, other.Ignored...)
+	d.Created = append(d.Created, other.Created...)
+}
+
+// SubscriptionNotifierFavorites is an optional interface a
+// SubscriptionNotifier may implement to receive the favorites delta
+// along with FAVORITES notifications, instead of having to re-fetch
+// the full favorites list on every event.
+type SubscriptionNotifierFavorites interface {
+	// OnFavoritesChange is called right after the plain OnNonPathChange
+	// callback for a FAVORITES notification, with the merged delta
+	// since the last delivery.
+	OnFavoritesChange(
+		clientID SubscriptionManagerClientID, subscriptionIDs []SubscriptionID,
+		delta FavoritesDelta)
+}
+
+// ConnectionQualityDetails describes how good the connections to the
+// KBFS servers currently are, beyond the binary online/offline status.
+// RTTs come from the most recent successful pings; a zero RTT means no
+// ping has succeeded yet, or the connection is down.
+type ConnectionQualityDetails struct {
+	// MDServerRTT is the round-trip time of the last successful ping to
+	// the mdserver.
+	MDServerRTT time.Duration
+	// BServerRTT is the round-trip time of the last successful ping to
+	// the block server.
+	BServerRTT time.Duration
+	// ConsecutiveFailures counts failed pings and connection attempts
+	// since the last success from any server.
+	ConsecutiveFailures int
+	// InBackoff is true while we're waiting out a reconnect backoff.
+	InBackoff bool
+}
+
+// SubscriptionNotifierConnectionQuality is an optional interface a
+// SubscriptionNotifier may implement to receive connection quality
+// details along with ONLINE_STATUS notifications, so the GUI can
+// distinguish "offline" from "slow/flaky". There's no dedicated
+// subscription topic for this, so the details ride along with the
+// online status the same clients already subscribe to.
+type SubscriptionNotifierConnectionQuality interface {
+	// OnConnectionQualityChange is called right after the plain
+	// OnNonPathChange callback for an ONLINE_STATUS notification.
+	OnConnectionQualityChange(
+		clientID SubscriptionManagerClientID, subscriptionIDs []SubscriptionID,
+		details ConnectionQualityDetails)
+}
+
 // OnlineStatusTracker tracks the online status for the GUI.
 type OnlineStatusTracker interface {
 	GetOnlineStatus() keybase1.KbfsOnlineStatus
+	// GetConnectionQuality returns details on how good the current
+	// server connections are; see ConnectionQualityDetails.
+	GetConnectionQuality() ConnectionQualityDetails
 	UserIn(ctx context.Context, clientKey string)
 	UserOut(ctx context.Context, clientKey string)
 }
 
+// PathSubscriptionRequest describes one subscription in a
+// SubscriptionManager.SubscribePaths batch.
+type PathSubscriptionRequest struct {
+	SubscriptionID      SubscriptionID
+	Path                string
+	Topic               keybase1.PathSubscriptionTopic
+	DeduplicateInterval *time.Duration
+	// Recursive, if set, makes the subscription cover the whole
+	// subtree rooted at Path, as in SubscribePathRecursive.
+	Recursive bool
+	// Coalesce, if set, requests trailing-edge debouncing, as in the
+	// coalesce arg of SubscribePath.
+	Coalesce bool
+}
+
 // SubscriptionManager manages subscriptions associated with one clientID.
 // Multiple subscribers can be used with the same SubscriptionManager.
 // If multiple subscriptions exist on the same topic (and for the same path, if
@@ -2170,10 +2352,32 @@ type OnlineStatusTracker interface {
 //    all events will be delivered.
 type SubscriptionManager interface {
 	// SubscribePath subscribes to changes about path, when topic happens.
+	// If path contains glob metacharacters ("*", "?", "["), it's treated
+	// as a pattern and the subscription fires for any path in the TLF
+	// matching it; a "**" segment matches any number of path segments.
+	// If coalesce is set, debouncing is trailing-edge: notifications
+	// fire after the deduplicate interval closes instead of as soon as
+	// the rate limiter allows, so the last change of a burst is always
+	// delivered.
 	SubscribePath(
 		ctx context.Context, subscriptionID SubscriptionID,
 		path string, topic keybase1.PathSubscriptionTopic,
-		deduplicateInterval *time.Duration) error
+		deduplicateInterval *time.Duration, coalesce bool) error
+	// SubscribePathRecursive is like SubscribePath, except that the
+	// subscription covers changes anywhere under the subtree rooted at path,
+	// not just path itself. This lets a client watch a whole directory with
+	// one subscription instead of one per entry.
+	SubscribePathRecursive(
+		ctx context.Context, subscriptionID SubscriptionID,
+		path string, topic keybase1.PathSubscriptionTopic,
+		deduplicateInterval *time.Duration, coalesce bool) error
+	// SubscribePaths is a batch version of SubscribePath and
+	// SubscribePathRecursive: all the requests are performed under a
+	// single lock acquisition, with a single folder branch
+	// registration per folder branch involved. Use it when subscribing
+	// to many paths at once, e.g. when the GUI opens a folder.
+	SubscribePaths(
+		ctx context.Context, requests []PathSubscriptionRequest) error
 	// SubscribeNonPath subscribes to changes when topic happens.
 	SubscribeNonPath(ctx context.Context, subscriptionID SubscriptionID,
 		topic keybase1.SubscriptionTopic,
@@ -2181,6 +2385,9 @@ type SubscriptionManager interface {
 	// Unsubscribe unsubscribes a previsous subscription. The subscriptionID
 	// should be the same as when caller subscribed. Otherwise, it's a no-op.
 	Unsubscribe(context.Context, SubscriptionID)
+	// UnsubscribeBatch unsubscribes all the given subscriptions under
+	// a single lock acquisition.
+	UnsubscribeBatch(ctx context.Context, subscriptionIDs []SubscriptionID)
 	// OnlineStatusTracker returns the OnlineStatusTracker for getting the
 	// current online status for GUI.
 	OnlineStatusTracker() OnlineStatusTracker
@@ -2354,6 +2561,29 @@ type Config interface {
 	// before syncing a set of changes to the servers.
 	SetBGFlushPeriod(p time.Duration)
 
+	// SubscriptionManagerPurgePolicy returns the policy for evicting
+	// the subscription managers of purgeable clients.
+	SubscriptionManagerPurgePolicy() SubscriptionManagerPurgePolicy
+	// SetSubscriptionManagerPurgePolicy sets the policy for evicting
+	// the subscription managers of purgeable clients.
+	SetSubscriptionManagerPurgePolicy(policy SubscriptionManagerPurgePolicy)
+
+	// JournalStatusThresholds returns the granularity at which
+	// JOURNAL_STATUS subscription notifications are published.
+	JournalStatusThresholds() JournalStatusThresholds
+	// SetJournalStatusThresholds sets the granularity at which
+	// JOURNAL_STATUS subscription notifications are published.
+	SetJournalStatusThresholds(thresholds JournalStatusThresholds)
+
+	// SubscriptionNotificationQPS returns the per-client cap on
+	// delivered subscription notifications per second. Non-positive
+	// means no cap.
+	SubscriptionNotificationQPS() float64
+	// SetSubscriptionNotificationQPS sets the per-client cap on
+	// delivered subscription notifications per second. It only affects
+	// subscription managers created afterwards.
+	SetSubscriptionNotificationQPS(qps float64)
+
 	// Shutdown is called to free config resources.
 	Shutdown(context.Context) error
 	// CheckStateOnShutdown tells the caller whether or not it is safe
@@ -354,11 +354,27 @@ func (md *MDServerRemote) RefreshAuthToken(ctx context.Context) {
 	}
 }
 
+// publishConnectionQuality reports a ping result or connection failure
+// to the subscription manager, if there is one that tracks quality.
+func (md *MDServerRemote) publishConnectionQuality(
+	rtt time.Duration, failed, inBackoff bool) {
+	publisher := md.config.SubscriptionManagerPublisher()
+	if cqp, ok := publisher.(connectionQualityPublisher); ok {
+		cqp.PublishConnectionQuality(connectionQualitySample{
+			service:   MDServiceName,
+			rtt:       rtt,
+			failed:    failed,
+			inBackoff: inBackoff,
+		})
+	}
+}
+
 func (md *MDServerRemote) pingOnce(ctx context.Context) {
 	clock := md.config.Clock()
 	beforePing := clock.Now()
 	resp, err := md.getClient().Ping2(ctx)
 	if err == context.DeadlineExceeded {
+		md.publishConnectionQuality(0, true, false)
 		if md.getIsAuthenticated() {
 			md.log.CInfof(ctx, "Ping timeout -- reinitializing connection")
 			if err = md.reconnect(); err != nil {
@@ -370,10 +386,12 @@ func (md *MDServerRemote) pingOnce(ctx context.Context) {
 		return
 	} else if err != nil {
 		md.log.CInfof(ctx, "MDServerRemote: ping error %s", err)
+		md.publishConnectionQuality(0, true, false)
 		return
 	}
 	afterPing := clock.Now()
 	pingLatency := afterPing.Sub(beforePing)
+	md.publishConnectionQuality(pingLatency, false, false)
 	if md.serverOffset > 0 && pingLatency > 5*time.Second {
 		md.log.CDebugf(ctx, "Ignoring large ping time: %s",
 			pingLatency)
@@ -409,6 +427,7 @@ func (md *MDServerRemote) OnConnectError(err error, wait time.Duration) {
 
 	md.config.KBFSOps().PushConnectionStatusChange(MDServiceName, err)
 	md.config.Reporter().OnlineStatusChanged(context.Background(), false)
+	md.publishConnectionQuality(0, true, true)
 }
 
 // OnDoCommandError implements the ConnectionHandler interface.
@@ -211,6 +211,10 @@ func (md modeDefault) DiskCacheCompactionEnabled() bool {
 	return true
 }
 
+func (md modeDefault) DiskCacheNameEncryptionEnabled() bool {
+	return true
+}
+
 func (md modeDefault) EditHistoryPrefetchingEnabled() bool {
 	return false
 }
@@ -401,6 +405,10 @@ func (mm modeMinimal) DiskCacheCompactionEnabled() bool {
 	return false
 }
 
+func (mm modeMinimal) DiskCacheNameEncryptionEnabled() bool {
+	return false
+}
+
 func (mm modeMinimal) EditHistoryPrefetchingEnabled() bool {
 	return false
 }
@@ -503,6 +511,10 @@ func (mso modeSingleOp) DiskCacheCompactionEnabled() bool {
 	return false
 }
 
+func (mso modeSingleOp) DiskCacheNameEncryptionEnabled() bool {
+	return false
+}
+
 // Single-op mode with QR:
 
 type modeSingleOpWithQR struct {
@@ -647,6 +659,10 @@ func (mc modeConstrained) DiskCacheCompactionEnabled() bool {
 	return false
 }
 
+func (mc modeConstrained) DiskCacheNameEncryptionEnabled() bool {
+	return false
+}
+
 // Memory limited mode
 
 type modeMemoryLimited struct {
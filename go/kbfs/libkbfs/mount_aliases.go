@@ -0,0 +1,102 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libkbfs
+
+import (
+	"path"
+	"strings"
+)
+
+// Mount namespace aliases let users map a short top-level mount name to a
+// deep folder, e.g. `/keybase/work` -> `/keybase/team/acme.eng.projects`.
+// They are defined in the user's config file under `mount.aliases` (set
+// with `keybase config set mount.aliases.work team/acme.eng.projects`) and
+// resolved in the path-parsing layer, so both SimpleFS and FUSE see them.
+
+// mountAliasesConfigPath is where aliases live in the config file: an
+// object mapping alias name to target path.
+const mountAliasesConfigPath = "mount.aliases"
+
+// reservedMountAlias rejects alias names that would shadow real top-level
+// folderlists or special files.
+func reservedMountAlias(name string) bool {
+	switch name {
+	case "private", "public", "team", "":
+		return true
+	}
+	return strings.HasPrefix(name, ".")
+}
+
+// MountAliases returns the user's mount aliases, with targets normalized
+// to paths relative to the mount root (e.g. "team/acme.eng.projects").
+// Invalid entries are dropped.
+func MountAliases(config Config) map[string]string {
+	env := config.KbEnv()
+	if env == nil {
+		return nil
+	}
+	reader := env.GetConfig()
+	if reader == nil {
+		return nil
+	}
+	raw, err := reader.GetInterfaceAtPath(mountAliasesConfigPath)
+	if err != nil {
+		return nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	res := make(map[string]string)
+	for name, targetRaw := range obj {
+		target, ok := targetRaw.(string)
+		if !ok || reservedMountAlias(name) || strings.Contains(name, "/") {
+			continue
+		}
+		target, ok = normalizeMountAliasTarget(target)
+		if !ok {
+			continue
+		}
+		res[name] = target
+	}
+	return res
+}
+
+// normalizeMountAliasTarget turns a target like "/keybase/team/acme" or
+// "team/acme" into "team/acme", rejecting targets that don't point inside
+// a real folderlist.
+func normalizeMountAliasTarget(target string) (string, bool) {
+	target = path.Clean(target)
+	target = strings.TrimPrefix(target, "/keybase/")
+	target = strings.TrimPrefix(target, "/")
+	elems := strings.Split(target, "/")
+	if len(elems) < 2 {
+		return "", false
+	}
+	switch elems[0] {
+	case "private", "public", "team":
+		return target, true
+	}
+	return "", false
+}
+
+// ResolveMountAlias returns the normalized target for one alias name.
+func ResolveMountAlias(config Config, name string) (string, bool) {
+	target, found := MountAliases(config)[name]
+	return target, found
+}
+
+// ResolveMountAliasElems rewrites the first element of a split mount path
+// if it names an alias; otherwise the input is returned unchanged.
+func ResolveMountAliasElems(config Config, elems []string) []string {
+	if len(elems) == 0 || reservedMountAlias(elems[0]) {
+		return elems
+	}
+	target, found := ResolveMountAlias(config, elems[0])
+	if !found {
+		return elems
+	}
+	return append(strings.Split(target, "/"), elems[1:]...)
+}
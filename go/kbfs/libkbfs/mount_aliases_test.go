@@ -0,0 +1,40 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libkbfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMountAliasTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+		ok     bool
+	}{
+		{"/keybase/team/acme.eng.projects", "team/acme.eng.projects", true},
+		{"team/acme.eng.projects", "team/acme.eng.projects", true},
+		{"/private/alice/docs", "private/alice/docs", true},
+		{"public/bob", "public/bob", true},
+		{"team", "", false},
+		{"/keybase/team", "", false},
+		{"work/stuff", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		got, ok := normalizeMountAliasTarget(test.target)
+		require.Equal(t, test.ok, ok, "target %q", test.target)
+		require.Equal(t, test.want, got, "target %q", test.target)
+	}
+}
+
+func TestReservedMountAlias(t *testing.T) {
+	for _, name := range []string{"private", "public", "team", "", ".kbfs_status"} {
+		require.True(t, reservedMountAlias(name), "name %q", name)
+	}
+	require.False(t, reservedMountAlias("work"))
+}
@@ -20,9 +20,10 @@ type onlineStatusTracker struct {
 	onChange func()
 	vlog     *libkb.VDebugLog
 
-	lock          sync.RWMutex
-	currentStatus keybase1.KbfsOnlineStatus
-	userIsLooking map[string]bool
+	lock              sync.RWMutex
+	currentStatus     keybase1.KbfsOnlineStatus
+	connectionQuality ConnectionQualityDetails
+	userIsLooking     map[string]bool
 
 	userIn  chan struct{}
 	userOut chan struct{}
@@ -421,6 +422,55 @@ func (ost *onlineStatusTracker) GetOnlineStatus() keybase1.KbfsOnlineStatus {
 	return ost.currentStatus
 }
 
+// GetConnectionQuality implements the OnlineStatusTracker interface.
+func (ost *onlineStatusTracker) GetConnectionQuality() ConnectionQualityDetails {
+	ost.lock.RLock()
+	defer ost.lock.RUnlock()
+	return ost.connectionQuality
+}
+
+// ostRTTChanged reports whether an RTT moved enough to be worth a
+// notification: from or to unknown, or by at least a factor of two.
+// Pings repeat on a timer, so reporting every small wobble would turn
+// the ONLINE_STATUS subscription into a metronome.
+func ostRTTChanged(before, now time.Duration) bool {
+	if (before == 0) != (now == 0) {
+		return true
+	}
+	if before == 0 {
+		return false
+	}
+	return now > before*2 || now < before/2
+}
+
+// absorbConnectionQualitySample folds one sample from a server
+// connection into the tracked quality, and reports whether the result
+// changed enough that subscribers should hear about it.
+func (ost *onlineStatusTracker) absorbConnectionQualitySample(
+	sample connectionQualitySample) (changed bool) {
+	ost.lock.Lock()
+	defer ost.lock.Unlock()
+	before := ost.connectionQuality
+	quality := &ost.connectionQuality
+	if sample.failed {
+		quality.ConsecutiveFailures++
+		quality.InBackoff = sample.inBackoff
+	} else {
+		quality.ConsecutiveFailures = 0
+		quality.InBackoff = false
+	}
+	switch sample.service {
+	case MDServiceName:
+		quality.MDServerRTT = sample.rtt
+	case BServiceName:
+		quality.BServerRTT = sample.rtt
+	}
+	return quality.ConsecutiveFailures != before.ConsecutiveFailures ||
+		quality.InBackoff != before.InBackoff ||
+		ostRTTChanged(before.MDServerRTT, quality.MDServerRTT) ||
+		ostRTTChanged(before.BServerRTT, quality.BServerRTT)
+}
+
 func newOnlineStatusTracker(
 	config Config, onChange func()) *onlineStatusTracker {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -5,7 +5,9 @@
 package libkbfs
 
 import (
+	"encoding/json"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,15 +17,46 @@ import (
 	"github.com/keybase/client/go/logger"
 	"github.com/keybase/client/go/protocol/keybase1"
 	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
 	"golang.org/x/net/context"
 	"golang.org/x/time/rate"
 )
 
 const (
-	folderBranchPollingInterval           = time.Second
-	maxPurgeableSubscriptionManagerClient = 3
+	folderBranchPollingInterval = time.Second
+
+	defaultMaxPurgeableSubscriptionManagerClients = 3
+	defaultSubscriptionManagerIdleTimeout         = 10 * time.Minute
+
+	// subscriptionsSettingsDbKeyPrefix prefixes the per-client settings
+	// DB key under which live subscription registrations are persisted.
+	subscriptionsSettingsDbKeyPrefix = "subscriptions:"
+
+	// defaultSubscriptionNotificationQPS is the default per-client cap
+	// on delivered notifications per second; see
+	// Config.SubscriptionNotificationQPS.
+	defaultSubscriptionNotificationQPS = float64(100)
 )
 
+// SubscriptionManagerPurgePolicy controls when the subscription
+// managers of purgeable clients are shut down and evicted.
+type SubscriptionManagerPurgePolicy struct {
+	// MaxClients is the maximum number of purgeable clients that may
+	// have live subscription managers at once.
+	MaxClients int
+	// IdleTimeout is how long a purgeable manager may sit with zero
+	// live subscriptions before it's purged, even if we're under
+	// MaxClients.
+	IdleTimeout time.Duration
+}
+
+func defaultSubscriptionManagerPurgePolicy() SubscriptionManagerPurgePolicy {
+	return SubscriptionManagerPurgePolicy{
+		MaxClients:  defaultMaxPurgeableSubscriptionManagerClients,
+		IdleTimeout: defaultSubscriptionManagerIdleTimeout,
+	}
+}
+
 // userPath is always the full path including the /keybase prefix, but may
 // not be canonical or cleaned. The goal is to track whatever the user of this
 // type is dealing with without needing them to know if a path is canonicalized
@@ -71,7 +104,58 @@ func getChSender(ch chan<- struct{}, blocking bool) func() {
 	}
 }
 
-func debounce(do func(), limit rate.Limit) *debouncedNotify {
+const (
+	notifyWorkerPoolWorkers   = 4
+	notifyWorkerPoolQueueSize = 64
+)
+
+// notifyWorkerPool delivers notifications for a single client on a
+// small fixed set of workers. Debouncers used to fire "go do()" on
+// every tick, so a slow SubscriptionNotifier (a stalled Electron RPC,
+// say) could pile up a goroutine per tick without bound. With the pool,
+// once the delivery queue fills the debouncer goroutines block instead;
+// that backpressure keeps a burst bounded while the non-blocking notify
+// channels upstream keep coalescing events.
+type notifyWorkerPool struct {
+	jobs     chan func()
+	ctx      context.Context
+	shutdown context.CancelFunc
+}
+
+func newNotifyWorkerPool(workers, queueSize int) *notifyWorkerPool {
+	ctx, shutdown := context.WithCancel(context.Background())
+	p := &notifyWorkerPool{
+		jobs:     make(chan func(), queueSize),
+		ctx:      ctx,
+		shutdown: shutdown,
+	}
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *notifyWorkerPool) worker() {
+	for {
+		select {
+		case job := <-p.jobs:
+			job()
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// run delivers do on a pool worker, blocking the caller while the
+// delivery queue is full.
+func (p *notifyWorkerPool) run(do func()) {
+	select {
+	case p.jobs <- do:
+	case <-p.ctx.Done():
+	}
+}
+
+func debounce(pool *notifyWorkerPool, do func(), limit rate.Limit) *debouncedNotify {
 	ctx, shutdown := context.WithCancel(context.Background())
 	ch := make(chan struct{}, 1)
 	limiter := rate.NewLimiter(limit, 1)
@@ -83,7 +167,7 @@ func debounce(do func(), limit rate.Limit) *debouncedNotify {
 			}
 			select {
 			case <-ch:
-				go do()
+				pool.run(do)
 			case <-ctx.Done():
 				return
 			}
@@ -95,9 +179,133 @@ func debounce(do func(), limit rate.Limit) *debouncedNotify {
 	}
 }
 
+// debounceTrailing is like debounce, but fires on the trailing edge of
+// the rate window instead of the leading edge: after an event arrives
+// it waits out a full window, coalescing everything that comes in
+// during the wait into a single notification. That guarantees the last
+// change of a burst is always delivered, at the cost of delaying the
+// first one by up to the window.
+func debounceTrailing(pool *notifyWorkerPool, do func(), limit rate.Limit) *debouncedNotify {
+	if limit == rate.Inf {
+		// No window to wait out; plain debounce delivers every event.
+		return debounce(pool, do, limit)
+	}
+	interval := debounceIntervalFromLimit(limit)
+	ctx, shutdown := context.WithCancel(context.Background())
+	ch := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case <-ch:
+			case <-ctx.Done():
+				return
+			}
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+				// Absorb anything that arrived while the window was
+				// open; it's covered by this notification.
+				select {
+				case <-ch:
+				default:
+				}
+				pool.run(do)
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return &debouncedNotify{
+		notify:   getChSender(ch, false),
+		shutdown: shutdown,
+	}
+}
+
 type pathSubscriptionRef struct {
 	folderBranch data.FolderBranch
-	path         cleanInTlfPath
+	// path is the clean in-TLF path, or the clean in-TLF glob pattern for
+	// glob subscriptions.
+	path cleanInTlfPath
+}
+
+// pathSubscriptionKind describes how a path subscription matches changed
+// paths: exactly, anywhere under a subtree, or against a glob pattern.
+type pathSubscriptionKind int
+
+const (
+	pathSubscriptionExact pathSubscriptionKind = iota
+	pathSubscriptionRecursive
+	pathSubscriptionGlob
+)
+
+// isGlobPath reports whether p contains glob metacharacters and should be
+// treated as a pattern rather than a literal path.
+func isGlobPath(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// matchGlobPath reports whether the clean in-TLF path p matches the clean
+// in-TLF glob pattern. Each segment uses path.Match syntax, and a "**"
+// segment matches any number of path segments, including none.
+func matchGlobPath(pattern string, p cleanInTlfPath) bool {
+	return matchGlobSegments(
+		strings.Split(strings.TrimPrefix(pattern, "/"), "/"),
+		strings.Split(strings.TrimPrefix(string(p), "/"), "/"))
+}
+
+func matchGlobSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchGlobSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// PathSubscriptionDump describes one grouped path subscription in a
+// SubscriptionManagerDump.
+type PathSubscriptionDump struct {
+	Kind             string           `json:"kind"`
+	TlfID            string           `json:"tlf_id"`
+	Path             string           `json:"path"`
+	SubscriptionIDs  []SubscriptionID `json:"subscription_ids"`
+	DebounceInterval time.Duration    `json:"debounce_interval"`
+	Coalesce         bool             `json:"coalesce,omitempty"`
+	NotifyCount      int64            `json:"notify_count"`
+}
+
+// NonPathSubscriptionDump describes one grouped non-path subscription in
+// a SubscriptionManagerDump.
+type NonPathSubscriptionDump struct {
+	Topic            keybase1.SubscriptionTopic `json:"topic"`
+	SubscriptionIDs  []SubscriptionID           `json:"subscription_ids"`
+	DebounceInterval time.Duration              `json:"debounce_interval"`
+	NotifyCount      int64                      `json:"notify_count"`
+}
+
+// SubscriptionManagerDump is a point-in-time snapshot of everything one
+// client has registered with a subscription manager. It exists to debug
+// subscription leaks from clients that never unsubscribe.
+type SubscriptionManagerDump struct {
+	ClientID                   SubscriptionManagerClientID `json:"client_id"`
+	PathSubscriptions          []PathSubscriptionDump      `json:"path_subscriptions"`
+	NonPathSubscriptions       []NonPathSubscriptionDump   `json:"non_path_subscriptions"`
+	FolderBranchRegistrations  map[string]int              `json:"folder_branch_registrations"`
+	PendingFolderBranchPollers int                         `json:"pending_folder_branch_pollers"`
 }
 
 type pathSubscription struct {
@@ -107,13 +315,39 @@ type pathSubscription struct {
 	// to use the original path that came in with the SubscribePath calls.
 	pathsToNotify   map[string]struct{}
 	limit           rate.Limit
+	// coalesce makes debouncing trailing-edge; see debounceTrailing.
+	// It's sticky: once any subscriber on this ref asks for it, it
+	// stays on for the shared debouncer.
+	coalesce        bool
 	debouncedNotify *debouncedNotify
+	// pendingDetails accumulates what changed between debounced
+	// notifications, for notifiers that implement
+	// SubscriptionNotifierDetailed.
+	pendingDetails PathChangeDetails
+	// notifyCount counts delivered (post-debounce) notifications, for
+	// DumpSubscriptions.
+	notifyCount int64
 }
 
 type nonPathSubscription struct {
 	subscriptionIDs map[SubscriptionID]bool
 	limit           rate.Limit
 	debouncedNotify *debouncedNotify
+	notifyCount     int64
+}
+
+// persistedSubscription is the JSON record stored in the settings DB for
+// one live subscription, so that a frontend that outlives a KBFS restart
+// gets its subscriptions re-armed instead of silently losing updates.
+type persistedSubscription struct {
+	SubscriptionID      SubscriptionID                 `json:"sid"`
+	NonPath             bool                           `json:"non_path,omitempty"`
+	Path                string                         `json:"path,omitempty"`
+	PathTopic           keybase1.PathSubscriptionTopic `json:"path_topic,omitempty"`
+	Topic               keybase1.SubscriptionTopic     `json:"topic,omitempty"`
+	DeduplicateInterval *time.Duration                 `json:"dedup_interval,omitempty"`
+	Recursive           bool                           `json:"recursive,omitempty"`
+	Coalesce            bool                           `json:"coalesce,omitempty"`
 }
 
 // subscriptionManager manages subscriptions. There are two types of
@@ -132,18 +366,53 @@ type subscriptionManager struct {
 	config   Config
 	log      logger.Logger
 	notifier SubscriptionNotifier
+	// notificationLimiter caps how many notifications this client gets
+	// per second, across all of its subscriptions. nil means no cap.
+	notificationLimiter *rate.Limiter
+	// workerPool runs all deliveries to notifier, so a slow notifier
+	// blocks its own queue rather than spawning goroutines.
+	workerPool *notifyWorkerPool
 
 	onlineStatusTracker *onlineStatusTracker
 	lock                sync.RWMutex
 	// TODO HOTPOT-416: add another layer here to reference by topics, and
 	// actually check topics in LocalChange and BatchChanges.
-	pathSubscriptions               map[pathSubscriptionRef]*pathSubscription
-	pathSubscriptionIDToRef         map[SubscriptionID]pathSubscriptionRef
+	pathSubscriptions       map[pathSubscriptionRef]*pathSubscription
+	pathSubscriptionIDToRef map[SubscriptionID]pathSubscriptionRef
+	// Recursive subscriptions are kept separately from the exact-path ones
+	// so that a notification on a deep descendant doesn't fire exact-path
+	// subscriptions on its ancestors.
+	recursivePathSubscriptions       map[pathSubscriptionRef]*pathSubscription
+	recursivePathSubscriptionIDToRef map[SubscriptionID]pathSubscriptionRef
+	// Glob subscriptions are keyed by their pattern, so multiple
+	// subscriptions on the same pattern share a debouncer.
+	globSubscriptions               map[pathSubscriptionRef]*pathSubscription
+	globSubscriptionIDToRef         map[SubscriptionID]pathSubscriptionRef
 	nonPathSubscriptions            map[keybase1.SubscriptionTopic]*nonPathSubscription
 	nonPathSubscriptionIDToTopic    map[SubscriptionID]keybase1.SubscriptionTopic
 	subscriptionIDs                 map[SubscriptionID]bool
 	subscriptionCountByFolderBranch map[data.FolderBranch]int
 	folderBranchPollerCancelers     map[SubscriptionID]context.CancelFunc
+	// emptySince is when the last live subscription went away (or when
+	// the manager was created); it's only meaningful while
+	// subscriptionIDs is empty.
+	emptySince time.Time
+	// persistedSubscriptions mirrors the live registrations into the
+	// settings DB so they survive a KBFS restart. Shutdown leaves the
+	// persisted records alone; see shuttingDown.
+	persistedSubscriptions map[SubscriptionID]persistedSubscription
+	shuttingDown           bool
+	// journalStatus holds the counters from the most recent
+	// PublishJournalStatus, for delivery with JOURNAL_STATUS
+	// notifications.
+	journalStatus JournalStatusDetails
+	// favoritesDelta accumulates the changes from
+	// PublishFavoritesChange calls between deliveries, for delivery
+	// with FAVORITES notifications.
+	favoritesDelta FavoritesDelta
+	// overflowPending is true while a refresh-everything sweep is
+	// scheduled because the notification budget ran out.
+	overflowPending bool
 }
 
 func (sm *subscriptionManager) notifyOnlineStatus() {
@@ -159,17 +428,32 @@ func (sm *subscriptionManager) notifyOnlineStatus() {
 
 func newSubscriptionManager(clientID SubscriptionManagerClientID, config Config, notifier SubscriptionNotifier) *subscriptionManager {
 	sm := &subscriptionManager{
-		pathSubscriptions:               make(map[pathSubscriptionRef]*pathSubscription),
-		pathSubscriptionIDToRef:         make(map[SubscriptionID]pathSubscriptionRef),
-		nonPathSubscriptions:            make(map[keybase1.SubscriptionTopic]*nonPathSubscription),
-		nonPathSubscriptionIDToTopic:    make(map[SubscriptionID]keybase1.SubscriptionTopic),
-		clientID:                        clientID,
-		config:                          config,
-		log:                             config.MakeLogger("SubMan"),
-		notifier:                        notifier,
-		subscriptionIDs:                 make(map[SubscriptionID]bool),
-		subscriptionCountByFolderBranch: make(map[data.FolderBranch]int),
-		folderBranchPollerCancelers:     make(map[SubscriptionID]context.CancelFunc),
+		pathSubscriptions:                make(map[pathSubscriptionRef]*pathSubscription),
+		pathSubscriptionIDToRef:          make(map[SubscriptionID]pathSubscriptionRef),
+		recursivePathSubscriptions:       make(map[pathSubscriptionRef]*pathSubscription),
+		recursivePathSubscriptionIDToRef: make(map[SubscriptionID]pathSubscriptionRef),
+		globSubscriptions:                make(map[pathSubscriptionRef]*pathSubscription),
+		globSubscriptionIDToRef:          make(map[SubscriptionID]pathSubscriptionRef),
+		nonPathSubscriptions:             make(map[keybase1.SubscriptionTopic]*nonPathSubscription),
+		nonPathSubscriptionIDToTopic:     make(map[SubscriptionID]keybase1.SubscriptionTopic),
+		clientID:                         clientID,
+		config:                           config,
+		log:                              config.MakeLogger("SubMan"),
+		notifier:                         notifier,
+		subscriptionIDs:                  make(map[SubscriptionID]bool),
+		subscriptionCountByFolderBranch:  make(map[data.FolderBranch]int),
+		folderBranchPollerCancelers:      make(map[SubscriptionID]context.CancelFunc),
+		emptySince:                       config.Clock().Now(),
+		persistedSubscriptions:           make(map[SubscriptionID]persistedSubscription),
+		workerPool: newNotifyWorkerPool(
+			notifyWorkerPoolWorkers, notifyWorkerPoolQueueSize),
+	}
+	if qps := config.SubscriptionNotificationQPS(); qps > 0 {
+		burst := int(qps)
+		if burst < 1 {
+			burst = 1
+		}
+		sm.notificationLimiter = rate.NewLimiter(rate.Limit(qps), burst)
 	}
 	sm.onlineStatusTracker = newOnlineStatusTracker(config, sm.notifyOnlineStatus)
 	return sm
@@ -177,13 +461,25 @@ func newSubscriptionManager(clientID SubscriptionManagerClientID, config Config,
 
 func (sm *subscriptionManager) Shutdown(ctx context.Context) {
 	sm.onlineStatusTracker.shutdown()
+	sm.workerPool.shutdown()
 	sm.lock.Lock()
 	defer sm.lock.Unlock()
-	pathSids := make([]SubscriptionID, 0, len(sm.pathSubscriptionIDToRef))
+	// Don't erase the persisted registrations below; if this process
+	// comes back the frontend's subscriptions should get re-armed.
+	sm.shuttingDown = true
+	pathSids := make([]SubscriptionID, 0,
+		len(sm.pathSubscriptionIDToRef)+len(sm.recursivePathSubscriptionIDToRef)+
+			len(sm.globSubscriptionIDToRef))
 	nonPathSids := make([]SubscriptionID, 0, len(sm.nonPathSubscriptionIDToTopic))
 	for sid := range sm.pathSubscriptionIDToRef {
 		pathSids = append(pathSids, sid)
 	}
+	for sid := range sm.recursivePathSubscriptionIDToRef {
+		pathSids = append(pathSids, sid)
+	}
+	for sid := range sm.globSubscriptionIDToRef {
+		pathSids = append(pathSids, sid)
+	}
 	for sid := range sm.nonPathSubscriptionIDToTopic {
 		nonPathSids = append(nonPathSids, sid)
 	}
@@ -205,9 +501,143 @@ func (sm *subscriptionManager) checkSubscriptionIDLocked(sid SubscriptionID) (se
 	}
 	return func() {
 		sm.subscriptionIDs[sid] = true
+		sm.emptySince = time.Time{}
 	}, nil
 }
 
+// idleSince returns the time this manager last went down to zero live
+// subscriptions, and true, if it currently has none.
+func (sm *subscriptionManager) idleSince() (time.Time, bool) {
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	if len(sm.subscriptionIDs) != 0 {
+		return time.Time{}, false
+	}
+	return sm.emptySince, true
+}
+
+func (sm *subscriptionManager) subscriptionsSettingsDbKey(
+	uid keybase1.UID) []byte {
+	return getSettingsDbKey(
+		uid, subscriptionsSettingsDbKeyPrefix+string(sm.clientID))
+}
+
+// persistSubscriptionsLocked writes the current set of registrations to
+// the settings DB. It's best effort: if there's no settings DB or no
+// session, subscriptions simply don't survive a restart.
+func (sm *subscriptionManager) persistSubscriptionsLocked(
+	ctx context.Context) {
+	db := sm.config.GetSettingsDB()
+	if db == nil {
+		return
+	}
+	uid := db.getUID(ctx)
+	if uid == keybase1.UID("") {
+		return
+	}
+	subs := make([]persistedSubscription, 0, len(sm.persistedSubscriptions))
+	for _, sub := range sm.persistedSubscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].SubscriptionID < subs[j].SubscriptionID
+	})
+	buf, err := json.Marshal(subs)
+	if err != nil {
+		sm.log.CWarningf(ctx, "marshaling persisted subscriptions: %+v", err)
+		return
+	}
+	err = db.Put(sm.subscriptionsSettingsDbKey(uid), buf, nil)
+	if err != nil {
+		sm.log.CWarningf(ctx, "writing persisted subscriptions: %+v", err)
+	}
+}
+
+func (sm *subscriptionManager) recordSubscriptionLocked(
+	ctx context.Context, sub persistedSubscription) {
+	sm.persistedSubscriptions[sub.SubscriptionID] = sub
+	sm.persistSubscriptionsLocked(ctx)
+}
+
+func (sm *subscriptionManager) forgetSubscriptionLocked(
+	ctx context.Context, sid SubscriptionID) {
+	if sm.shuttingDown {
+		return
+	}
+	if _, ok := sm.persistedSubscriptions[sid]; !ok {
+		return
+	}
+	delete(sm.persistedSubscriptions, sid)
+	sm.persistSubscriptionsLocked(ctx)
+}
+
+// resumePersistedSubscriptions re-arms any subscriptions persisted by a
+// previous incarnation of this process for this client ID. The protocol
+// has no dedicated RESUBSCRIBED notification, so after re-arming each
+// subscription we fire a change notification on it; a frontend that
+// survived the KBFS restart sees its existing subscriptions fire and
+// refetches whatever state it was tracking.
+func (sm *subscriptionManager) resumePersistedSubscriptions(
+	ctx context.Context) {
+	db := sm.config.GetSettingsDB()
+	if db == nil {
+		return
+	}
+	uid := db.getUID(ctx)
+	if uid == keybase1.UID("") {
+		return
+	}
+	buf, err := db.Get(sm.subscriptionsSettingsDbKey(uid), nil)
+	switch errors.Cause(err) {
+	case nil:
+	case leveldb.ErrNotFound:
+		return
+	default:
+		sm.log.CWarningf(ctx, "reading persisted subscriptions: %+v", err)
+		return
+	}
+	var subs []persistedSubscription
+	if err := json.Unmarshal(buf, &subs); err != nil {
+		sm.log.CWarningf(ctx, "unmarshaling persisted subscriptions: %+v", err)
+		return
+	}
+	for _, sub := range subs {
+		if sub.NonPath {
+			err = sm.SubscribeNonPath(
+				ctx, sub.SubscriptionID, sub.Topic, sub.DeduplicateInterval)
+			if err == nil {
+				sm.notifier.OnNonPathChange(sm.clientID,
+					[]SubscriptionID{sub.SubscriptionID}, sub.Topic)
+			}
+		} else {
+			kind := pathSubscriptionExact
+			switch {
+			case sub.Recursive:
+				kind = pathSubscriptionRecursive
+			case isGlobPath(sub.Path):
+				kind = pathSubscriptionGlob
+			}
+			err = sm.subscribePath(ctx, subscribePathRequest{
+				sid:                 sub.SubscriptionID,
+				path:                sub.Path,
+				topic:               sub.PathTopic,
+				deduplicateInterval: sub.DeduplicateInterval,
+				kind:                kind,
+				coalesce:            sub.Coalesce,
+			})
+			if err == nil {
+				sm.notifier.OnPathChange(sm.clientID,
+					[]SubscriptionID{sub.SubscriptionID}, sub.Path,
+					[]keybase1.PathSubscriptionTopic{sub.PathTopic})
+			}
+		}
+		if err != nil {
+			sm.log.CWarningf(ctx, "re-arming persisted subscription %s: %+v",
+				sub.SubscriptionID, err)
+		}
+	}
+}
+
 func (sm *subscriptionManager) registerForChangesLocked(fb data.FolderBranch) {
 	if sm.subscriptionCountByFolderBranch[fb] == 0 {
 		_ = sm.config.Notifier().RegisterForChanges(
@@ -226,16 +656,36 @@ func (sm *subscriptionManager) unregisterForChangesLocked(fb data.FolderBranch)
 	sm.subscriptionCountByFolderBranch[fb]--
 }
 
+func (sm *subscriptionManager) pathSubscriptionMapsLocked(
+	kind pathSubscriptionKind) (
+	subscriptions map[pathSubscriptionRef]*pathSubscription,
+	idToRef map[SubscriptionID]pathSubscriptionRef) {
+	switch kind {
+	case pathSubscriptionRecursive:
+		return sm.recursivePathSubscriptions, sm.recursivePathSubscriptionIDToRef
+	case pathSubscriptionGlob:
+		return sm.globSubscriptions, sm.globSubscriptionIDToRef
+	default:
+		return sm.pathSubscriptions, sm.pathSubscriptionIDToRef
+	}
+}
+
 func (sm *subscriptionManager) preparePathNotification(
-	ref pathSubscriptionRef) (sids []SubscriptionID,
-	paths []string, topics []keybase1.PathSubscriptionTopic) {
-	sm.lock.RLock()
-	defer sm.lock.RUnlock()
+	ref pathSubscriptionRef, kind pathSubscriptionKind) (sids []SubscriptionID,
+	paths []string, topics []keybase1.PathSubscriptionTopic,
+	details PathChangeDetails) {
+	// Write lock since we reset the accumulated details below.
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
 
-	ps, ok := sm.pathSubscriptions[ref]
+	subscriptions, _ := sm.pathSubscriptionMapsLocked(kind)
+	ps, ok := subscriptions[ref]
 	if !ok {
 		return
 	}
+	details = ps.pendingDetails
+	ps.pendingDetails = PathChangeDetails{}
+	ps.notifyCount++
 	sids = make([]SubscriptionID, 0, len(ps.subscriptionIDs))
 	topicsMap := make(map[keybase1.PathSubscriptionTopic]struct{})
 	for sid, topic := range ps.subscriptionIDs {
@@ -250,28 +700,111 @@ func (sm *subscriptionManager) preparePathNotification(
 	for path := range ps.pathsToNotify {
 		paths = append(paths, path)
 	}
-	return sids, paths, topics
+	return sids, paths, topics, details
 }
 
 func (sm *subscriptionManager) makePathSubscriptionDebouncedNotify(
-	ref pathSubscriptionRef, limit rate.Limit) *debouncedNotify {
-	return debounce(func() {
-		sids, paths, topics := sm.preparePathNotification(ref)
+	ref pathSubscriptionRef, limit rate.Limit,
+	kind pathSubscriptionKind, coalesce bool) *debouncedNotify {
+	debouncer := debounce
+	if coalesce {
+		debouncer = debounceTrailing
+	}
+	return debouncer(sm.workerPool, func() {
+		sm.deliverWithinBudget(func() {
+			sids, paths, topics, details := sm.preparePathNotification(ref, kind)
+
+			detailedNotifier, _ := sm.notifier.(SubscriptionNotifierDetailed)
+			for _, path := range paths {
+				sm.notifier.OnPathChange(sm.clientID, sids, path, topics)
+				if detailedNotifier != nil {
+					detailedNotifier.OnPathChangeDetailed(
+						sm.clientID, sids, path, topics, details)
+				}
+			}
+		})
+	}, limit)
+}
+
+// deliverWithinBudget runs deliver if the per-client notification
+// budget allows it. Otherwise everything dropped until the budget
+// recovers collapses into a single refresh-everything sweep, so a
+// client that subscribed with a rate.Inf dedup interval can't keep the
+// notifier goroutines saturated.
+func (sm *subscriptionManager) deliverWithinBudget(deliver func()) {
+	if sm.notificationLimiter == nil || sm.notificationLimiter.Allow() {
+		deliver()
+		return
+	}
+	sm.lock.Lock()
+	if sm.overflowPending {
+		sm.lock.Unlock()
+		return
+	}
+	sm.overflowPending = true
+	sm.lock.Unlock()
+	go func() {
+		reservation := sm.notificationLimiter.Reserve()
+		time.Sleep(reservation.Delay())
+		sm.lock.Lock()
+		sm.overflowPending = false
+		sm.lock.Unlock()
+		sm.notifyRefreshAll()
+	}()
+}
 
+// notifyRefreshAll notifies every live subscription once. It's used
+// after the notification budget ran out and notifications were dropped
+// on the floor, at which point the client can't know which of its
+// subscriptions it missed events for.
+func (sm *subscriptionManager) notifyRefreshAll() {
+	type pathRef struct {
+		ref  pathSubscriptionRef
+		kind pathSubscriptionKind
+	}
+	var refs []pathRef
+	var nonPathTopics []keybase1.SubscriptionTopic
+	sm.lock.RLock()
+	for ref := range sm.pathSubscriptions {
+		refs = append(refs, pathRef{ref, pathSubscriptionExact})
+	}
+	for ref := range sm.recursivePathSubscriptions {
+		refs = append(refs, pathRef{ref, pathSubscriptionRecursive})
+	}
+	for ref := range sm.globSubscriptions {
+		refs = append(refs, pathRef{ref, pathSubscriptionGlob})
+	}
+	for topic := range sm.nonPathSubscriptions {
+		nonPathTopics = append(nonPathTopics, topic)
+	}
+	sm.lock.RUnlock()
+
+	detailedNotifier, _ := sm.notifier.(SubscriptionNotifierDetailed)
+	for _, r := range refs {
+		sids, paths, topics, details := sm.preparePathNotification(r.ref, r.kind)
 		for _, path := range paths {
 			sm.notifier.OnPathChange(sm.clientID, sids, path, topics)
+			if detailedNotifier != nil {
+				detailedNotifier.OnPathChangeDetailed(
+					sm.clientID, sids, path, topics, details)
+			}
 		}
-	}, limit)
+	}
+	for _, topic := range nonPathTopics {
+		sm.notifyNonPathSubscription(topic)
+	}
 }
 
 func (sm *subscriptionManager) prepareNonPathNotification(
 	topic keybase1.SubscriptionTopic) (sids []SubscriptionID) {
-	sm.lock.RLock()
-	defer sm.lock.RUnlock()
+	// Write lock since we count delivered notifications below.
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
 	nps, ok := sm.nonPathSubscriptions[topic]
 	if !ok {
 		return
 	}
+	nps.notifyCount++
 	sids = make([]SubscriptionID, 0, len(nps.subscriptionIDs))
 	for sid := range nps.subscriptionIDs {
 		sids = append(sids, sid)
@@ -281,21 +814,67 @@ func (sm *subscriptionManager) prepareNonPathNotification(
 
 func (sm *subscriptionManager) makeNonPathSubscriptionDebouncedNotify(
 	topic keybase1.SubscriptionTopic, limit rate.Limit) *debouncedNotify {
-	return debounce(func() {
-		sids := sm.prepareNonPathNotification(topic)
-		sm.notifier.OnNonPathChange(sm.clientID, sids, topic)
+	return debounce(sm.workerPool, func() {
+		sm.deliverWithinBudget(func() {
+			sm.notifyNonPathSubscription(topic)
+		})
 	}, limit)
 }
 
+func (sm *subscriptionManager) notifyNonPathSubscription(
+	topic keybase1.SubscriptionTopic) {
+	sids := sm.prepareNonPathNotification(topic)
+	sm.notifier.OnNonPathChange(sm.clientID, sids, topic)
+	switch topic {
+	case keybase1.SubscriptionTopic_JOURNAL_STATUS:
+		if jsNotifier, ok := sm.notifier.(SubscriptionNotifierJournalStatus); ok {
+			sm.lock.RLock()
+			details := sm.journalStatus
+			sm.lock.RUnlock()
+			jsNotifier.OnJournalStatusChange(sm.clientID, sids, details)
+		}
+	case keybase1.SubscriptionTopic_FAVORITES:
+		if favNotifier, ok := sm.notifier.(SubscriptionNotifierFavorites); ok {
+			sm.lock.Lock()
+			delta := sm.favoritesDelta
+			sm.favoritesDelta = FavoritesDelta{}
+			sm.lock.Unlock()
+			favNotifier.OnFavoritesChange(sm.clientID, sids, delta)
+		}
+	case keybase1.SubscriptionTopic_ONLINE_STATUS:
+		if cqNotifier, ok := sm.notifier.(SubscriptionNotifierConnectionQuality); ok {
+			cqNotifier.OnConnectionQualityChange(sm.clientID, sids,
+				sm.onlineStatusTracker.GetConnectionQuality())
+		}
+	}
+}
+
 type subscribePathRequest struct {
 	sid                 SubscriptionID
 	path                string // original, uncleaned path from GUI
 	topic               keybase1.PathSubscriptionTopic
 	deduplicateInterval *time.Duration
+	// kind controls whether the subscription matches path exactly, covers
+	// the entire subtree rooted at path, or treats path as a glob pattern.
+	kind pathSubscriptionKind
+	// coalesce requests trailing-edge debouncing; see debounceTrailing.
+	coalesce bool
+}
+
+func persistedSubscriptionForPathRequest(
+	req subscribePathRequest) persistedSubscription {
+	return persistedSubscription{
+		SubscriptionID:      req.sid,
+		Path:                req.path,
+		PathTopic:           req.topic,
+		DeduplicateInterval: req.deduplicateInterval,
+		Recursive:           req.kind == pathSubscriptionRecursive,
+		Coalesce:            req.coalesce,
+	}
 }
 
 func (sm *subscriptionManager) subscribePathWithFolderBranchLocked(
-	req subscribePathRequest,
+	ctx context.Context, req subscribePathRequest,
 	parsedPath *parsedPath, fb data.FolderBranch) error {
 	nitp := getCleanInTlfPath(parsedPath)
 	ref := pathSubscriptionRef{
@@ -313,26 +892,36 @@ func (sm *subscriptionManager) subscribePathWithFolderBranchLocked(
 	if req.deduplicateInterval != nil {
 		limit = rate.Every(*req.deduplicateInterval)
 	}
-	ps, ok := sm.pathSubscriptions[ref]
+	subscriptions, idToRef := sm.pathSubscriptionMapsLocked(req.kind)
+	ps, ok := subscriptions[ref]
 	if !ok {
 		ps = &pathSubscription{
 			subscriptionIDs: make(map[SubscriptionID]keybase1.PathSubscriptionTopic),
 			limit:           limit,
-			debouncedNotify: sm.makePathSubscriptionDebouncedNotify(ref, limit),
-			pathsToNotify:   make(map[string]struct{}),
+			coalesce:        req.coalesce,
+			debouncedNotify: sm.makePathSubscriptionDebouncedNotify(
+				ref, limit, req.kind, req.coalesce),
+			pathsToNotify: make(map[string]struct{}),
 		}
-		sm.pathSubscriptions[ref] = ps
-	} else if ps.limit < limit {
-		// New limit is higher than what we have. Update it to match.
-		ps.limit = limit
+		subscriptions[ref] = ps
+	} else if ps.limit < limit || (req.coalesce && !ps.coalesce) {
+		// New limit is higher than what we have, or the new subscriber
+		// wants trailing-edge coalescing. Update the shared debouncer
+		// to match.
+		if ps.limit < limit {
+			ps.limit = limit
+		}
+		ps.coalesce = ps.coalesce || req.coalesce
 		ps.debouncedNotify.shutdown()
-		ps.debouncedNotify = sm.makePathSubscriptionDebouncedNotify(ref, limit)
+		ps.debouncedNotify = sm.makePathSubscriptionDebouncedNotify(
+			ref, ps.limit, req.kind, ps.coalesce)
 	}
 	ps.subscriptionIDs[req.sid] = req.topic
 	ps.pathsToNotify[req.path] = struct{}{}
 
-	sm.pathSubscriptionIDToRef[req.sid] = ref
+	idToRef[req.sid] = ref
 	subscriptionIDSetter()
+	sm.recordSubscriptionLocked(ctx, persistedSubscriptionForPathRequest(req))
 	return nil
 }
 
@@ -385,7 +974,8 @@ func (sm *subscriptionManager) pollOnFolderBranchForSubscribePathRequest(
 			default:
 			}
 
-			err = sm.subscribePathWithFolderBranchLocked(req, parsedPath, fb)
+			err = sm.subscribePathWithFolderBranchLocked(
+				ctx, req, parsedPath, fb)
 			if err != nil {
 				sm.log.CErrorf(ctx,
 					"subscribePathWithFolderBranchLocked sid=%s err=%v", req.sid, err)
@@ -405,17 +995,18 @@ func (sm *subscriptionManager) pollOnFolderBranchForSubscribePathRequest(
 }
 
 func (sm *subscriptionManager) subscribePathWithoutFolderBranchLocked(
-	req subscribePathRequest, parsedPath *parsedPath) {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx context.Context, req subscribePathRequest, parsedPath *parsedPath) {
+	pollCtx, cancel := context.WithCancel(context.Background())
 	sm.folderBranchPollerCancelers[req.sid] = cancel
-	go sm.pollOnFolderBranchForSubscribePathRequest(ctx, req, parsedPath)
+	// Record the registration even before the poller resolves a folder
+	// branch; it's a live subscription from the frontend's perspective.
+	sm.recordSubscriptionLocked(ctx, persistedSubscriptionForPathRequest(req))
+	go sm.pollOnFolderBranchForSubscribePathRequest(pollCtx, req, parsedPath)
 }
 
-// SubscribePath implements the SubscriptionManager interface.
-func (sm *subscriptionManager) SubscribePath(ctx context.Context,
-	sid SubscriptionID, path string, topic keybase1.PathSubscriptionTopic,
-	deduplicateInterval *time.Duration) error {
-	parsedPath, err := parsePath(userPath(path))
+func (sm *subscriptionManager) subscribePath(
+	ctx context.Context, req subscribePathRequest) error {
+	parsedPath, err := parsePath(userPath(req.path))
 	if err != nil {
 		return err
 	}
@@ -440,19 +1031,112 @@ func (sm *subscriptionManager) SubscribePath(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	req := subscribePathRequest{
+	if fb != (data.FolderBranch{}) {
+		return sm.subscribePathWithFolderBranchLocked(ctx, req, parsedPath, fb)
+	}
+	sm.subscribePathWithoutFolderBranchLocked(ctx, req, parsedPath)
+	return nil
+}
+
+// SubscribePath implements the SubscriptionManager interface. If path
+// contains glob metacharacters (like "/keybase/team/acme/**/*.md"), the
+// subscription matches any path in the TLF against the pattern instead of
+// a literal path, so new files that match start firing notifications
+// without being subscribed individually.
+func (sm *subscriptionManager) SubscribePath(ctx context.Context,
+	sid SubscriptionID, path string, topic keybase1.PathSubscriptionTopic,
+	deduplicateInterval *time.Duration, coalesce bool) error {
+	kind := pathSubscriptionExact
+	if isGlobPath(path) {
+		kind = pathSubscriptionGlob
+	}
+	return sm.subscribePath(ctx, subscribePathRequest{
 		sid:                 sid,
 		path:                path,
 		topic:               topic,
 		deduplicateInterval: deduplicateInterval,
+		kind:                kind,
+		coalesce:            coalesce,
+	})
+}
+
+// pathSubscriptionKindForRequest returns the kind a
+// PathSubscriptionRequest maps to, mirroring the logic of
+// SubscribePath and SubscribePathRecursive.
+func pathSubscriptionKindForRequest(
+	req PathSubscriptionRequest) pathSubscriptionKind {
+	switch {
+	case req.Recursive:
+		return pathSubscriptionRecursive
+	case isGlobPath(req.Path):
+		return pathSubscriptionGlob
+	default:
+		return pathSubscriptionExact
 	}
-	if fb != (data.FolderBranch{}) {
-		return sm.subscribePathWithFolderBranchLocked(req, parsedPath, fb)
+}
+
+// SubscribePaths implements the SubscriptionManager interface. All the
+// requests are handled under a single lock acquisition, and the
+// refcounting in registerForChangesLocked means each folder branch is
+// registered with the notifier at most once, no matter how many
+// requests land in it.
+func (sm *subscriptionManager) SubscribePaths(
+	ctx context.Context, requests []PathSubscriptionRequest) error {
+	reqs := make([]subscribePathRequest, 0, len(requests))
+	parsedPaths := make([]*parsedPath, 0, len(requests))
+	for _, request := range requests {
+		parsedPath, err := parsePath(userPath(request.Path))
+		if err != nil {
+			return err
+		}
+		parsedPaths = append(parsedPaths, parsedPath)
+		reqs = append(reqs, subscribePathRequest{
+			sid:                 request.SubscriptionID,
+			path:                request.Path,
+			topic:               request.Topic,
+			deduplicateInterval: request.DeduplicateInterval,
+			kind:                pathSubscriptionKindForRequest(request),
+			coalesce:            request.Coalesce,
+		})
+	}
+
+	// See subscribePath for why we hold the lock before resolving the
+	// folder branches.
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	for i, req := range reqs {
+		fb, err := parsedPaths[i].getFolderBranch(ctx, sm.config)
+		if err != nil {
+			return err
+		}
+		if fb != (data.FolderBranch{}) {
+			err = sm.subscribePathWithFolderBranchLocked(
+				ctx, req, parsedPaths[i], fb)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		sm.subscribePathWithoutFolderBranchLocked(ctx, req, parsedPaths[i])
 	}
-	sm.subscribePathWithoutFolderBranchLocked(req, parsedPath)
 	return nil
 }
 
+// SubscribePathRecursive implements the SubscriptionManager interface.
+func (sm *subscriptionManager) SubscribePathRecursive(ctx context.Context,
+	sid SubscriptionID, path string, topic keybase1.PathSubscriptionTopic,
+	deduplicateInterval *time.Duration, coalesce bool) error {
+	return sm.subscribePath(ctx, subscribePathRequest{
+		sid:                 sid,
+		path:                path,
+		topic:               topic,
+		deduplicateInterval: deduplicateInterval,
+		kind:                pathSubscriptionRecursive,
+		coalesce:            coalesce,
+	})
+}
+
 // SubscribeNonPath implements the SubscriptionManager interface.
 func (sm *subscriptionManager) SubscribeNonPath(
 	ctx context.Context, sid SubscriptionID, topic keybase1.SubscriptionTopic,
@@ -486,6 +1170,12 @@ func (sm *subscriptionManager) SubscribeNonPath(
 
 	sm.nonPathSubscriptionIDToTopic[sid] = topic
 	subscriptionIDSetter()
+	sm.recordSubscriptionLocked(ctx, persistedSubscription{
+		SubscriptionID:      sid,
+		NonPath:             true,
+		Topic:               topic,
+		DeduplicateInterval: deduplicateInterval,
+	})
 	return nil
 }
 
@@ -494,27 +1184,37 @@ func (sm *subscriptionManager) unsubscribePathLocked(
 	// First check if this is a subscription we don't yet have a folderBranch
 	// for.
 	if sm.cancelAndDeleteFolderBranchPollerLocked(subscriptionID) {
+		sm.forgetSubscriptionLocked(ctx, subscriptionID)
 		return
 	}
 
-	ref, ok := sm.pathSubscriptionIDToRef[subscriptionID]
-	if !ok {
-		return
-	}
-	delete(sm.pathSubscriptionIDToRef, subscriptionID)
+	for _, kind := range []pathSubscriptionKind{
+		pathSubscriptionExact, pathSubscriptionRecursive, pathSubscriptionGlob,
+	} {
+		subscriptions, idToRef := sm.pathSubscriptionMapsLocked(kind)
+		ref, ok := idToRef[subscriptionID]
+		if !ok {
+			continue
+		}
+		delete(idToRef, subscriptionID)
 
-	ps, ok := sm.pathSubscriptions[ref]
-	if !ok {
-		return
-	}
-	delete(ps.subscriptionIDs, subscriptionID)
-	if len(ps.subscriptionIDs) == 0 {
-		ps.debouncedNotify.shutdown()
-		sm.unregisterForChangesLocked(ref.folderBranch)
-		delete(sm.pathSubscriptions, ref)
-	}
+		ps, ok := subscriptions[ref]
+		if !ok {
+			continue
+		}
+		delete(ps.subscriptionIDs, subscriptionID)
+		if len(ps.subscriptionIDs) == 0 {
+			ps.debouncedNotify.shutdown()
+			sm.unregisterForChangesLocked(ref.folderBranch)
+			delete(subscriptions, ref)
+		}
 
-	delete(sm.subscriptionIDs, subscriptionID)
+		delete(sm.subscriptionIDs, subscriptionID)
+		if len(sm.subscriptionIDs) == 0 {
+			sm.emptySince = sm.config.Clock().Now()
+		}
+		sm.forgetSubscriptionLocked(ctx, subscriptionID)
+	}
 }
 
 func (sm *subscriptionManager) unsubscribeNonPathLocked(
@@ -536,6 +1236,10 @@ func (sm *subscriptionManager) unsubscribeNonPathLocked(
 	}
 
 	delete(sm.subscriptionIDs, subscriptionID)
+	if len(sm.subscriptionIDs) == 0 {
+		sm.emptySince = sm.config.Clock().Now()
+	}
+	sm.forgetSubscriptionLocked(ctx, subscriptionID)
 }
 
 // Unsubscribe implements the SubscriptionManager interface.
@@ -546,11 +1250,24 @@ func (sm *subscriptionManager) Unsubscribe(ctx context.Context, sid Subscription
 	sm.unsubscribeNonPathLocked(ctx, sid)
 }
 
-func (sm *subscriptionManager) notifyRefLocked(ref pathSubscriptionRef) {
+// UnsubscribeBatch implements the SubscriptionManager interface.
+func (sm *subscriptionManager) UnsubscribeBatch(
+	ctx context.Context, sids []SubscriptionID) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	for _, sid := range sids {
+		sm.unsubscribePathLocked(ctx, sid)
+		sm.unsubscribeNonPathLocked(ctx, sid)
+	}
+}
+
+func (sm *subscriptionManager) notifyRefLocked(
+	ref pathSubscriptionRef, details PathChangeDetails) {
 	ps, ok := sm.pathSubscriptions[ref]
 	if !ok {
 		return
 	}
+	ps.pendingDetails.merge(details)
 	// We are notify()-ing while holding a lock, but it's fine since the
 	// other side of the channel consumes it pretty fast, either by
 	// dropping deduplicated ones, or by doing the actual send in a
@@ -560,7 +1277,18 @@ func (sm *subscriptionManager) notifyRefLocked(ref pathSubscriptionRef) {
 	ps.debouncedNotify.notify()
 }
 
-func (sm *subscriptionManager) nodeChangeLocked(node Node) {
+func (sm *subscriptionManager) notifyRecursiveRefLocked(
+	ref pathSubscriptionRef, details PathChangeDetails) {
+	ps, ok := sm.recursivePathSubscriptions[ref]
+	if !ok {
+		return
+	}
+	ps.pendingDetails.merge(details)
+	ps.debouncedNotify.notify()
+}
+
+func (sm *subscriptionManager) nodeChangeLocked(
+	node Node, details PathChangeDetails) {
 	path, ok := node.GetPathPlaintextSansTlf()
 	if !ok {
 		return
@@ -570,7 +1298,7 @@ func (sm *subscriptionManager) nodeChangeLocked(node Node) {
 	sm.notifyRefLocked(pathSubscriptionRef{
 		folderBranch: node.GetFolderBranch(),
 		path:         cleanPath,
-	})
+	}, details)
 
 	// Do this for parent as well, so if "children" is subscribed on parent
 	// path, we'd trigger a notification too.
@@ -578,11 +1306,188 @@ func (sm *subscriptionManager) nodeChangeLocked(node Node) {
 		sm.notifyRefLocked(pathSubscriptionRef{
 			folderBranch: node.GetFolderBranch(),
 			path:         parent,
+		}, details)
+	}
+
+	// Recursive subscriptions cover the whole subtree, so walk all the way up
+	// to the TLF root notifying any that exist along the way.
+	for p := cleanPath; ; {
+		sm.notifyRecursiveRefLocked(pathSubscriptionRef{
+			folderBranch: node.GetFolderBranch(),
+			path:         p,
+		}, details)
+		parent, ok := getParentPath(p)
+		if !ok {
+			break
+		}
+		p = parent
+	}
+	if cleanPath != "/" {
+		sm.notifyRecursiveRefLocked(pathSubscriptionRef{
+			folderBranch: node.GetFolderBranch(),
+			path:         "/",
+		}, details)
+	}
+
+	// Glob subscriptions match the changed path against their pattern.
+	// There are few enough of them that a linear scan is fine.
+	for ref, ps := range sm.globSubscriptions {
+		if ref.folderBranch != node.GetFolderBranch() {
+			continue
+		}
+		if !matchGlobPath(string(ref.path), cleanPath) {
+			continue
+		}
+		ps.pendingDetails.merge(details)
+		ps.debouncedNotify.notify()
+	}
+}
+
+// debounceIntervalFromLimit converts a rate limit back into the
+// deduplicate interval it was made from, for DumpSubscriptions.
+func debounceIntervalFromLimit(limit rate.Limit) time.Duration {
+	if limit == rate.Inf || limit <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / float64(limit))
+}
+
+// DumpSubscriptions returns a snapshot of everything registered with this
+// subscription manager.
+func (sm *subscriptionManager) DumpSubscriptions() SubscriptionManagerDump {
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	dump := SubscriptionManagerDump{
+		ClientID: sm.clientID,
+		FolderBranchRegistrations: make(
+			map[string]int, len(sm.subscriptionCountByFolderBranch)),
+		PendingFolderBranchPollers: len(sm.folderBranchPollerCancelers),
+	}
+	appendPathSubscriptions := func(
+		kind string, subscriptions map[pathSubscriptionRef]*pathSubscription) {
+		for ref, ps := range subscriptions {
+			d := PathSubscriptionDump{
+				Kind:             kind,
+				TlfID:            ref.folderBranch.Tlf.String(),
+				Path:             string(ref.path),
+				DebounceInterval: debounceIntervalFromLimit(ps.limit),
+				Coalesce:         ps.coalesce,
+				NotifyCount:      ps.notifyCount,
+			}
+			for sid := range ps.subscriptionIDs {
+				d.SubscriptionIDs = append(d.SubscriptionIDs, sid)
+			}
+			sort.Slice(d.SubscriptionIDs, func(i, j int) bool {
+				return d.SubscriptionIDs[i] < d.SubscriptionIDs[j]
+			})
+			dump.PathSubscriptions = append(dump.PathSubscriptions, d)
+		}
+	}
+	appendPathSubscriptions("exact", sm.pathSubscriptions)
+	appendPathSubscriptions("recursive", sm.recursivePathSubscriptions)
+	appendPathSubscriptions("glob", sm.globSubscriptions)
+	sort.Slice(dump.PathSubscriptions, func(i, j int) bool {
+		if dump.PathSubscriptions[i].Kind != dump.PathSubscriptions[j].Kind {
+			return dump.PathSubscriptions[i].Kind < dump.PathSubscriptions[j].Kind
+		}
+		return dump.PathSubscriptions[i].Path < dump.PathSubscriptions[j].Path
+	})
+
+	for topic, nps := range sm.nonPathSubscriptions {
+		d := NonPathSubscriptionDump{
+			Topic:            topic,
+			DebounceInterval: debounceIntervalFromLimit(nps.limit),
+			NotifyCount:      nps.notifyCount,
+		}
+		for sid := range nps.subscriptionIDs {
+			d.SubscriptionIDs = append(d.SubscriptionIDs, sid)
+		}
+		sort.Slice(d.SubscriptionIDs, func(i, j int) bool {
+			return d.SubscriptionIDs[i] < d.SubscriptionIDs[j]
 		})
+		dump.NonPathSubscriptions = append(dump.NonPathSubscriptions, d)
+	}
+	sort.Slice(dump.NonPathSubscriptions, func(i, j int) bool {
+		return dump.NonPathSubscriptions[i].Topic <
+			dump.NonPathSubscriptions[j].Topic
+	})
+
+	for fb, count := range sm.subscriptionCountByFolderBranch {
+		dump.FolderBranchRegistrations[fb.Tlf.String()] = count
 	}
+	return dump
+}
+
+// journalStatusPublisher is an optional interface a
+// SubscriptionManagerPublisher may implement to accept the journal
+// counters along with a JOURNAL_STATUS publish, for delivery to
+// notifiers implementing SubscriptionNotifierJournalStatus.
+type journalStatusPublisher interface {
+	PublishJournalStatus(details JournalStatusDetails)
 }
 
 var _ SubscriptionManagerPublisher = (*subscriptionManager)(nil)
+var _ journalStatusPublisher = (*subscriptionManager)(nil)
+
+// PublishJournalStatus implements the journalStatusPublisher interface.
+func (sm *subscriptionManager) PublishJournalStatus(
+	details JournalStatusDetails) {
+	sm.lock.Lock()
+	sm.journalStatus = details
+	sm.lock.Unlock()
+	sm.PublishChange(keybase1.SubscriptionTopic_JOURNAL_STATUS)
+}
+
+// favoritesPublisher is an optional interface a
+// SubscriptionManagerPublisher may implement to accept the delta along
+// with a FAVORITES publish, for delivery to notifiers implementing
+// SubscriptionNotifierFavorites.
+type favoritesPublisher interface {
+	PublishFavoritesChange(delta FavoritesDelta)
+}
+
+var _ favoritesPublisher = (*subscriptionManager)(nil)
+
+// PublishFavoritesChange implements the favoritesPublisher interface.
+func (sm *subscriptionManager) PublishFavoritesChange(delta FavoritesDelta) {
+	sm.lock.Lock()
+	sm.favoritesDelta.merge(delta)
+	sm.lock.Unlock()
+	sm.PublishChange(keybase1.SubscriptionTopic_FAVORITES)
+}
+
+// connectionQualitySample is a single quality observation from one of
+// the server connections: either a successful ping with its round-trip
+// time, or a failure, possibly one that put the connection into a
+// reconnect backoff.
+type connectionQualitySample struct {
+	service   string // MDServiceName or BServiceName
+	rtt       time.Duration
+	failed    bool
+	inBackoff bool
+}
+
+// connectionQualityPublisher is an optional interface a
+// SubscriptionManagerPublisher may implement to accept connection
+// quality samples from the server connections. There's no dedicated
+// subscription topic for quality, so the merged details ride along
+// with ONLINE_STATUS notifications, for notifiers implementing
+// SubscriptionNotifierConnectionQuality.
+type connectionQualityPublisher interface {
+	PublishConnectionQuality(sample connectionQualitySample)
+}
+
+var _ connectionQualityPublisher = (*subscriptionManager)(nil)
+
+// PublishConnectionQuality implements the connectionQualityPublisher
+// interface.
+func (sm *subscriptionManager) PublishConnectionQuality(
+	sample connectionQualitySample) {
+	if !sm.onlineStatusTracker.absorbConnectionQualitySample(sample) {
+		return
+	}
+	sm.PublishChange(keybase1.SubscriptionTopic_ONLINE_STATUS)
+}
 
 // PublishChange implements the SubscriptionManagerPublisher interface.
 func (sm *subscriptionManager) PublishChange(topic keybase1.SubscriptionTopic) {
@@ -600,6 +1505,12 @@ func (sm *subscriptionManager) PublishChange(topic keybase1.SubscriptionTopic) {
 		for _, ps := range sm.pathSubscriptions {
 			ps.debouncedNotify.notify()
 		}
+		for _, ps := range sm.recursivePathSubscriptions {
+			ps.debouncedNotify.notify()
+		}
+		for _, ps := range sm.globSubscriptions {
+			ps.debouncedNotify.notify()
+		}
 	}
 
 	if nps, ok := sm.nonPathSubscriptions[topic]; ok {
@@ -612,40 +1523,92 @@ var _ Observer = (*subscriptionManager)(nil)
 // LocalChange implements the Observer interface.
 func (sm *subscriptionManager) LocalChange(ctx context.Context,
 	node Node, write WriteRange) {
-	sm.lock.RLock()
-	defer sm.lock.RUnlock()
+	// Write lock since nodeChangeLocked accumulates change details.
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
 	// TODO HOTPOT-416: check topics
-	sm.nodeChangeLocked(node)
+	sm.nodeChangeLocked(node, PathChangeDetails{
+		ChangeType:  PathChangeTypeWrite,
+		WriteRanges: []WriteRange{write},
+	})
 }
 
 // BatchChanges implements the Observer interface.
 func (sm *subscriptionManager) BatchChanges(ctx context.Context,
 	changes []NodeChange, allAffectedNodeIDs []NodeID) {
-	sm.lock.RLock()
-	defer sm.lock.RUnlock()
+	// Write lock since nodeChangeLocked accumulates change details.
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
 	// TODO HOTPOT-416: check topics
 	for _, change := range changes {
-		sm.nodeChangeLocked(change.Node)
+		var details PathChangeDetails
+		if len(change.DirUpdated) > 0 {
+			details.ChangeType |= PathChangeTypeChildren
+			for _, name := range change.DirUpdated {
+				details.EntryNames = append(
+					details.EntryNames, name.Plaintext())
+			}
+		}
+		if len(change.FileUpdated) > 0 {
+			details.ChangeType |= PathChangeTypeWrite
+			details.WriteRanges = append(
+				details.WriteRanges, change.FileUpdated...)
+		}
+		sm.nodeChangeLocked(change.Node, details)
 	}
 }
 
-// TlfHandleChange implements the Observer interface.
+// TlfHandleChange implements the Observer interface. A handle change
+// means every path in the TLF is now reachable under a new canonical
+// name (e.g. an SBS assertion like friend@twitter resolving to the
+// user friend), so every subscription in the folder gets notified with
+// the new canonical path. The rename also shows up in the favorites
+// list, so FAVORITES subscribers hear about it too.
 func (sm *subscriptionManager) TlfHandleChange(ctx context.Context,
 	newHandle *tlfhandle.Handle) {
+	if newHandle == nil {
+		return
+	}
+	tlfID := newHandle.TlfID()
+	details := PathChangeDetails{
+		ChangeType: PathChangeTypeHandleChange,
+		NewTlfPath: newHandle.GetCanonicalPath(),
+	}
+	func() {
+		// Write lock since we accumulate change details below.
+		sm.lock.Lock()
+		defer sm.lock.Unlock()
+		for _, subscriptions := range []map[pathSubscriptionRef]*pathSubscription{
+			sm.pathSubscriptions, sm.recursivePathSubscriptions,
+			sm.globSubscriptions,
+		} {
+			for ref, ps := range subscriptions {
+				if ref.folderBranch.Tlf != tlfID {
+					continue
+				}
+				ps.pendingDetails.merge(details)
+				ps.debouncedNotify.notify()
+			}
+		}
+	}()
+	sm.PublishChange(keybase1.SubscriptionTopic_FAVORITES)
 }
 
 type subscriptionManagerManager struct {
-	lock                   sync.RWMutex
-	config                 Config
-	subscriptionManagers   map[SubscriptionManagerClientID]*subscriptionManager
-	purgeableClientIDsFIFO []SubscriptionManagerClientID
+	lock                 sync.RWMutex
+	config               Config
+	subscriptionManagers map[SubscriptionManagerClientID]*subscriptionManager
+	// purgeableClientIDs is ordered from oldest to newest creation,
+	// which is only used as a tie-breaker when no purgeable manager is
+	// idle.
+	purgeableClientIDs []SubscriptionManagerClientID
 }
 
 func newSubscriptionManagerManager(config Config) *subscriptionManagerManager {
 	return &subscriptionManagerManager{
-		config:                 config,
-		subscriptionManagers:   make(map[SubscriptionManagerClientID]*subscriptionManager),
-		purgeableClientIDsFIFO: nil,
+		config:               config,
+		subscriptionManagers: make(map[SubscriptionManagerClientID]*subscriptionManager),
+		purgeableClientIDs:   nil,
 	}
 }
 
@@ -657,7 +1620,50 @@ func (smm *subscriptionManagerManager) Shutdown(ctx context.Context) {
 		sm.Shutdown(ctx)
 	}
 	smm.subscriptionManagers = make(map[SubscriptionManagerClientID]*subscriptionManager)
-	smm.purgeableClientIDsFIFO = nil
+	smm.purgeableClientIDs = nil
+}
+
+func (smm *subscriptionManagerManager) purgeLocked(i int) {
+	toPurge := smm.purgeableClientIDs[i]
+	smm.subscriptionManagers[toPurge].Shutdown(context.Background())
+	delete(smm.subscriptionManagers, toPurge)
+	smm.purgeableClientIDs = append(
+		smm.purgeableClientIDs[:i], smm.purgeableClientIDs[i+1:]...)
+}
+
+// purgeIdleClientsLocked purges every purgeable manager that has had
+// zero live subscriptions for at least the policy's idle timeout.
+func (smm *subscriptionManagerManager) purgeIdleClientsLocked(
+	policy SubscriptionManagerPurgePolicy) {
+	now := smm.config.Clock().Now()
+	for i := 0; i < len(smm.purgeableClientIDs); {
+		emptySince, idle :=
+			smm.subscriptionManagers[smm.purgeableClientIDs[i]].idleSince()
+		if idle && now.Sub(emptySince) >= policy.IdleTimeout {
+			smm.purgeLocked(i)
+		} else {
+			i++
+		}
+	}
+}
+
+// pickEvictionVictimLocked returns the index of the purgeable manager
+// to evict when we're at the policy's client limit: the one that's
+// been idle the longest, or the oldest-created one if none are idle.
+func (smm *subscriptionManagerManager) pickEvictionVictimLocked() int {
+	victim := 0
+	var victimEmptySince time.Time
+	victimIdle := false
+	for i, clientID := range smm.purgeableClientIDs {
+		emptySince, idle := smm.subscriptionManagers[clientID].idleSince()
+		if !idle {
+			continue
+		}
+		if !victimIdle || emptySince.Before(victimEmptySince) {
+			victim, victimEmptySince, victimIdle = i, emptySince, true
+		}
+	}
+	return victim
 }
 
 func (smm *subscriptionManagerManager) get(
@@ -676,7 +1682,7 @@ func (smm *subscriptionManagerManager) get(
 
 	// Check again under the lock in case we've already created one. This is
 	// important since if we create it twice we'd end up with having the same
-	// clientID appearing twice in purgeableClientIDsFIFO and when we purge the
+	// clientID appearing twice in purgeableClientIDs and when we purge the
 	// second one we'd have a panic.
 	sm, ok = smm.subscriptionManagers[clientID]
 	if ok {
@@ -684,21 +1690,42 @@ func (smm *subscriptionManagerManager) get(
 	}
 
 	if purgeable {
-		if len(smm.purgeableClientIDsFIFO) == maxPurgeableSubscriptionManagerClient {
-			toPurge := smm.purgeableClientIDsFIFO[0]
-			smm.subscriptionManagers[toPurge].Shutdown(context.Background())
-			delete(smm.subscriptionManagers, toPurge)
-			smm.purgeableClientIDsFIFO = smm.purgeableClientIDsFIFO[1:]
+		policy := smm.config.SubscriptionManagerPurgePolicy()
+		smm.purgeIdleClientsLocked(policy)
+		for len(smm.purgeableClientIDs) > 0 &&
+			len(smm.purgeableClientIDs) >= policy.MaxClients {
+			smm.purgeLocked(smm.pickEvictionVictimLocked())
 		}
-		smm.purgeableClientIDsFIFO = append(smm.purgeableClientIDsFIFO, clientID)
+		smm.purgeableClientIDs = append(smm.purgeableClientIDs, clientID)
 	}
 
 	sm = newSubscriptionManager(clientID, smm.config, notifier)
 	smm.subscriptionManagers[clientID] = sm
 
+	// If a previous incarnation of this process persisted registrations
+	// for this client ID, re-arm them now so a frontend that survived
+	// the restart keeps getting updates.
+	sm.resumePersistedSubscriptions(context.Background())
+
 	return sm
 }
 
+// DumpSubscriptions returns a snapshot of every live subscription
+// manager, one entry per client.
+func (smm *subscriptionManagerManager) DumpSubscriptions() (
+	dumps []SubscriptionManagerDump) {
+	smm.lock.RLock()
+	defer smm.lock.RUnlock()
+	dumps = make([]SubscriptionManagerDump, 0, len(smm.subscriptionManagers))
+	for _, sm := range smm.subscriptionManagers {
+		dumps = append(dumps, sm.DumpSubscriptions())
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].ClientID < dumps[j].ClientID
+	})
+	return dumps
+}
+
 // PublishChange implements the SubscriptionManagerPublisher interface.
 func (smm *subscriptionManagerManager) PublishChange(topic keybase1.SubscriptionTopic) {
 	smm.lock.RLock()
@@ -707,3 +1734,40 @@ func (smm *subscriptionManagerManager) PublishChange(topic keybase1.Subscription
 		sm.PublishChange(topic)
 	}
 }
+
+var _ journalStatusPublisher = (*subscriptionManagerManager)(nil)
+
+// PublishJournalStatus implements the journalStatusPublisher interface.
+func (smm *subscriptionManagerManager) PublishJournalStatus(
+	details JournalStatusDetails) {
+	smm.lock.RLock()
+	defer smm.lock.RUnlock()
+	for _, sm := range smm.subscriptionManagers {
+		sm.PublishJournalStatus(details)
+	}
+}
+
+var _ favoritesPublisher = (*subscriptionManagerManager)(nil)
+
+// PublishFavoritesChange implements the favoritesPublisher interface.
+func (smm *subscriptionManagerManager) PublishFavoritesChange(
+	delta FavoritesDelta) {
+	smm.lock.RLock()
+	defer smm.lock.RUnlock()
+	for _, sm := range smm.subscriptionManagers {
+		sm.PublishFavoritesChange(delta)
+	}
+}
+
+var _ connectionQualityPublisher = (*subscriptionManagerManager)(nil)
+
+// PublishConnectionQuality implements the connectionQualityPublisher
+// interface.
+func (smm *subscriptionManagerManager) PublishConnectionQuality(
+	sample connectionQualitySample) {
+	smm.lock.RLock()
+	defer smm.lock.RUnlock()
+	for _, sm := range smm.subscriptionManagers {
+		sm.PublishConnectionQuality(sample)
+	}
+}
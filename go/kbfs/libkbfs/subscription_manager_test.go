@@ -7,6 +7,7 @@ package libkbfs
 import (
 	"fmt"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 )
 
 func waitForCall(t *testing.T, timeout time.Duration) (
@@ -111,7 +113,7 @@ func TestSubscriptionManagerSubscribePath(t *testing.T) {
 
 	t.Logf("Subscribe to CHILDREN at TLF root using sid1, and create a file. We should get a notification.")
 	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe",
-		keybase1.PathSubscriptionTopic_CHILDREN, nil)
+		keybase1.PathSubscriptionTopic_CHILDREN, nil, false)
 	require.NoError(t, err)
 	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
 		[]SubscriptionID{sid1}, "/keybase/private/jdoe",
@@ -123,12 +125,12 @@ func TestSubscriptionManagerSubscribePath(t *testing.T) {
 
 	t.Logf("Try to subscribe using sid1 again, and it should fail")
 	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe",
-		keybase1.PathSubscriptionTopic_STAT, nil)
+		keybase1.PathSubscriptionTopic_STAT, nil, false)
 	require.Error(t, err)
 
 	t.Logf("Subscribe to STAT at TLF root using sid2, and create a dir. We should get a notification for STAT, and a notificiation for CHILDREN.")
 	err = sm.SubscribePath(ctx, sid2, "/keybase/private/jdoe",
-		keybase1.PathSubscriptionTopic_STAT, nil)
+		keybase1.PathSubscriptionTopic_STAT, nil, false)
 	require.NoError(t, err)
 	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
 		sliceMatcherNoOrder{[]SubscriptionID{sid1, sid2}},
@@ -164,7 +166,7 @@ func TestSubscriptionManagerSubscribePath(t *testing.T) {
 
 	t.Logf("Unsubscribe sid2 as well. Then subscribe to STAT on the file using sid1 (which we unsubscribed earlier), and write to it. We should get STAT notification.")
 	sm.Unsubscribe(ctx, sid2)
-	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe/dir1/../file", keybase1.PathSubscriptionTopic_STAT, nil)
+	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe/dir1/../file", keybase1.PathSubscriptionTopic_STAT, nil, false)
 	require.NoError(t, err)
 	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
 		[]SubscriptionID{sid1}, "/keybase/private/jdoe/dir1/../file",
@@ -178,6 +180,198 @@ func TestSubscriptionManagerSubscribePath(t *testing.T) {
 	waiter3()
 }
 
+func TestSubscriptionManagerSubscribePathRecursive(t *testing.T) {
+	config, sm, notifier, finish := initSubscriptionManagerTest(t)
+	defer finish()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	ctx, err := libcontext.NewContextWithCancellationDelayer(
+		libcontext.NewContextReplayable(
+			ctx, func(c context.Context) context.Context {
+				return ctx
+			}))
+	require.NoError(t, err)
+
+	waiter0, done0 := waitForCall(t, 4*time.Second)
+
+	tlfHandle, err := GetHandleFromFolderNameAndType(
+		ctx, config.KBPKI(), config.MDOps(), config, "jdoe", tlf.Private)
+	require.NoError(t, err)
+	rootNode, _, err := config.KBFSOps().GetOrCreateRootNode(
+		ctx, tlfHandle, data.MasterBranch)
+	require.NoError(t, err)
+	dirNode, _, err := config.KBFSOps().CreateDir(
+		ctx, rootNode, rootNode.ChildName("dir1"))
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	sid1 := SubscriptionID("sid1")
+
+	t.Logf("Subscribe recursively to CHILDREN at TLF root using sid1, and create a file inside dir1. We should get a notification even though the change is not a direct child of the root.")
+	err = sm.SubscribePathRecursive(ctx, sid1, "/keybase/private/jdoe",
+		keybase1.PathSubscriptionTopic_CHILDREN, nil, false)
+	require.NoError(t, err)
+	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
+		[]SubscriptionID{sid1}, "/keybase/private/jdoe",
+		[]keybase1.PathSubscriptionTopic{keybase1.PathSubscriptionTopic_CHILDREN}).AnyTimes().Do(done0)
+	_, _, err = config.KBFSOps().CreateFile(
+		ctx, dirNode, dirNode.ChildName("file"), false, NoExcl)
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	t.Logf("Waiting for notification (done0) before finishing the test.")
+	waiter0()
+
+	t.Logf("Unsubscribe sid1. Trying to subscribe with sid1 again should now succeed.")
+	sm.Unsubscribe(ctx, sid1)
+	err = sm.SubscribePathRecursive(ctx, sid1, "/keybase/private/jdoe/dir1",
+		keybase1.PathSubscriptionTopic_CHILDREN, nil, false)
+	require.NoError(t, err)
+}
+
+func TestDebounceTrailing(t *testing.T) {
+	var calls int32
+	pool := newNotifyWorkerPool(1, 4)
+	defer pool.shutdown()
+	dn := debounceTrailing(pool, func() {
+		atomic.AddInt32(&calls, 1)
+	}, rate.Every(50*time.Millisecond))
+	defer dn.shutdown()
+
+	waitForCalls := func(desc string, want int32) {
+		deadline := time.Now().Add(4 * time.Second)
+		for atomic.LoadInt32(&calls) != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s (calls=%d, want %d)",
+					desc, atomic.LoadInt32(&calls), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Logf("A burst of notifies within one window should coalesce into a single call.")
+	for i := 0; i < 10; i++ {
+		dn.notify()
+	}
+	waitForCalls("first coalesced call", 1)
+
+	t.Logf("A notify after the window closes should fire again.")
+	dn.notify()
+	waitForCalls("second call", 2)
+}
+
+func TestSubscriptionManagerSubscribePathCoalesce(t *testing.T) {
+	config, sm, notifier, finish := initSubscriptionManagerTest(t)
+	defer finish()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	ctx, err := libcontext.NewContextWithCancellationDelayer(
+		libcontext.NewContextReplayable(
+			ctx, func(c context.Context) context.Context {
+				return ctx
+			}))
+	require.NoError(t, err)
+
+	waiter0, done0 := waitForCall(t, 4*time.Second)
+
+	tlfHandle, err := GetHandleFromFolderNameAndType(
+		ctx, config.KBPKI(), config.MDOps(), config, "jdoe", tlf.Private)
+	require.NoError(t, err)
+	rootNode, _, err := config.KBFSOps().GetOrCreateRootNode(
+		ctx, tlfHandle, data.MasterBranch)
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	sid1 := SubscriptionID("sid1")
+
+	t.Logf("Subscribe to CHILDREN at TLF root with a deduplicate interval and coalescing, and create a file. The notification should arrive on the trailing edge of the window.")
+	interval := 50 * time.Millisecond
+	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe",
+		keybase1.PathSubscriptionTopic_CHILDREN, &interval, true)
+	require.NoError(t, err)
+	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
+		[]SubscriptionID{sid1}, "/keybase/private/jdoe",
+		[]keybase1.PathSubscriptionTopic{keybase1.PathSubscriptionTopic_CHILDREN}).AnyTimes().Do(done0)
+	_, _, err = config.KBFSOps().CreateFile(
+		ctx, rootNode, rootNode.ChildName("file"), false, NoExcl)
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	t.Logf("Waiting for notification (done0) before finishing the test.")
+	waiter0()
+}
+
+func TestSubscriptionManagerSubscribePathsBatch(t *testing.T) {
+	config, sm, notifier, finish := initSubscriptionManagerTest(t)
+	defer finish()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	ctx, err := libcontext.NewContextWithCancellationDelayer(
+		libcontext.NewContextReplayable(
+			ctx, func(c context.Context) context.Context {
+				return ctx
+			}))
+	require.NoError(t, err)
+
+	waiter0, done0 := waitForCall(t, 4*time.Second)
+
+	tlfHandle, err := GetHandleFromFolderNameAndType(
+		ctx, config.KBPKI(), config.MDOps(), config, "jdoe", tlf.Private)
+	require.NoError(t, err)
+	rootNode, _, err := config.KBFSOps().GetOrCreateRootNode(
+		ctx, tlfHandle, data.MasterBranch)
+	require.NoError(t, err)
+	dirNode, _, err := config.KBFSOps().CreateDir(
+		ctx, rootNode, rootNode.ChildName("dir1"))
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	sid1, sid2 := SubscriptionID("sid1"), SubscriptionID("sid2")
+
+	t.Logf("Subscribe to CHILDREN at TLF root (recursively with sid1, exactly with sid2) in one batch, and create a file inside dir1. We should get a notification for the recursive subscription.")
+	err = sm.SubscribePaths(ctx, []PathSubscriptionRequest{{
+		SubscriptionID: sid1,
+		Path:           "/keybase/private/jdoe",
+		Topic:          keybase1.PathSubscriptionTopic_CHILDREN,
+		Recursive:      true,
+	}, {
+		SubscriptionID: sid2,
+		Path:           "/keybase/private/jdoe/dir1",
+		Topic:          keybase1.PathSubscriptionTopic_CHILDREN,
+	}})
+	require.NoError(t, err)
+	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
+		gomock.Any(), gomock.Any(),
+		[]keybase1.PathSubscriptionTopic{
+			keybase1.PathSubscriptionTopic_CHILDREN,
+		}).AnyTimes().Do(done0)
+	_, _, err = config.KBFSOps().CreateFile(
+		ctx, dirNode, dirNode.ChildName("file"), false, NoExcl)
+	require.NoError(t, err)
+	err = config.KBFSOps().SyncAll(ctx, rootNode.GetFolderBranch())
+	require.NoError(t, err)
+
+	t.Logf("Waiting for notification (done0) before unsubscribing.")
+	waiter0()
+
+	t.Logf("Reusing a subscription ID from the batch should fail until the batch is unsubscribed.")
+	err = sm.SubscribePath(ctx, sid2, "/keybase/private/jdoe",
+		keybase1.PathSubscriptionTopic_STAT, nil, false)
+	require.Error(t, err)
+	sm.UnsubscribeBatch(ctx, []SubscriptionID{sid1, sid2})
+	err = sm.SubscribePath(ctx, sid2, "/keybase/private/jdoe",
+		keybase1.PathSubscriptionTopic_STAT, nil, false)
+	require.NoError(t, err)
+}
+
 func TestSubscriptionManagerFavoritesChange(t *testing.T) {
 	config, sm, notifier, finish := initSubscriptionManagerTest(t)
 	defer finish()
@@ -223,7 +417,7 @@ func TestSubscriptionManagerSubscribePathNoFolderBranch(t *testing.T) {
 	sid1 := SubscriptionID("sid1")
 
 	err = sm.SubscribePath(ctx, sid1, "/keybase/private/jdoe",
-		keybase1.PathSubscriptionTopic_CHILDREN, nil)
+		keybase1.PathSubscriptionTopic_CHILDREN, nil, false)
 	require.NoError(t, err)
 	notifier.EXPECT().OnPathChange(testSubscriptionManagerClientID,
 		[]SubscriptionID{sid1}, "/keybase/private/jdoe",
@@ -34,6 +34,32 @@ import (
 
 // tlfJournalConfig is the subset of the Config interface needed by
 // tlfJournal (for ease of testing).
+// JournalStatusThresholds controls how often JOURNAL_STATUS
+// subscription notifications are published as a TLF journal grows and
+// shrinks. A notification is published whenever the unflushed byte or
+// block count crosses into a different multiple of the respective
+// threshold, and whenever a count empties out.
+type JournalStatusThresholds struct {
+	// Bytes is the unflushed-bytes granularity. A non-positive value
+	// publishes on every change.
+	Bytes int64
+	// Blocks is the unflushed-block-count granularity. A non-positive
+	// value publishes on every change.
+	Blocks int64
+}
+
+const (
+	defaultJournalStatusBytesThreshold  = 1 << 20 // 1 MB
+	defaultJournalStatusBlocksThreshold = 100
+)
+
+func defaultJournalStatusThresholds() JournalStatusThresholds {
+	return JournalStatusThresholds{
+		Bytes:  defaultJournalStatusBytesThreshold,
+		Blocks: defaultJournalStatusBlocksThreshold,
+	}
+}
+
 type tlfJournalConfig interface {
 	BlockSplitter() data.BlockSplitter
 	Clock() Clock
@@ -55,6 +81,7 @@ type tlfJournalConfig interface {
 	BGFlushDirOpBatchSize() int
 	syncedTlfGetterSetter
 	SubscriptionManagerPublisher() SubscriptionManagerPublisher
+	JournalStatusThresholds() JournalStatusThresholds
 }
 
 // tlfJournalConfigWrapper is an adapter for Config objects to the
@@ -329,6 +356,10 @@ type tlfJournal struct {
 	currBytesFlushing   int64
 	currFlushStarted    time.Time
 	needInfoFile        bool
+	// lastNotifiedJournalStatus holds the counters from the most
+	// recent JOURNAL_STATUS publish, for detecting threshold
+	// crossings.
+	lastNotifiedJournalStatus JournalStatusDetails
 
 	bwDelegate tlfJournalBWDelegate
 }
@@ -1088,6 +1119,45 @@ func (j *tlfJournal) getNextBlockEntriesToFlush(
 		maxJournalBlockFlushBatchSize, mdJournalID)
 }
 
+// publishJournalStatusLocked publishes a JOURNAL_STATUS notification
+// if the journal's unflushed byte or block count has crossed a
+// threshold (or emptied out) since the last publish. The counters are
+// handed to the publisher so subscribers implementing
+// SubscriptionNotifierJournalStatus can display them directly.
+func (j *tlfJournal) publishJournalStatusLocked() {
+	details := JournalStatusDetails{
+		UnflushedBytes:  j.blockJournal.getUnflushedBytes(),
+		UnflushedBlocks: int64(j.blockJournal.length()),
+	}
+	thresholds := j.config.JournalStatusThresholds()
+	last := j.lastNotifiedJournalStatus
+	crossed := func(now, before, threshold int64) bool {
+		if now == before {
+			return false
+		}
+		if threshold <= 0 {
+			return true
+		}
+		// Also fire when the counter empties out, even if it never
+		// crossed a threshold on the way up.
+		return now/threshold != before/threshold ||
+			(now == 0) != (before == 0)
+	}
+	if !crossed(details.UnflushedBytes, last.UnflushedBytes,
+		thresholds.Bytes) &&
+		!crossed(details.UnflushedBlocks, last.UnflushedBlocks,
+			thresholds.Blocks) {
+		return
+	}
+	j.lastNotifiedJournalStatus = details
+	publisher := j.config.SubscriptionManagerPublisher()
+	if jsp, ok := publisher.(journalStatusPublisher); ok {
+		jsp.PublishJournalStatus(details)
+		return
+	}
+	publisher.PublishChange(keybase1.SubscriptionTopic_JOURNAL_STATUS)
+}
+
 func (j *tlfJournal) removeFlushedBlockEntries(ctx context.Context,
 	entries blockEntriesToFlush, flushEnded time.Time) error {
 	j.journalLock.Lock()
@@ -1100,8 +1170,6 @@ func (j *tlfJournal) removeFlushedBlockEntries(ctx context.Context,
 
 	// TODO: Check storedFiles also.
 
-	j.config.SubscriptionManagerPublisher().PublishChange(
-		keybase1.SubscriptionTopic_JOURNAL_STATUS)
 	j.config.SubscriptionManagerPublisher().PublishChange(
 		keybase1.SubscriptionTopic_FILES_TAB_BADGE)
 	flushedBytes, err := j.blockJournal.removeFlushedEntries(
@@ -1109,6 +1177,7 @@ func (j *tlfJournal) removeFlushedBlockEntries(ctx context.Context,
 	if err != nil {
 		return err
 	}
+	j.publishJournalStatusLocked()
 	storedBytesAfter := j.blockJournal.getStoredBytes()
 
 	// storedBytes shouldn't change since removedBytes is 0.
@@ -2207,8 +2276,7 @@ func (j *tlfJournal) putBlockData(
 		j.unsquashedBytes += uint64(bufLen)
 	}
 
-	j.config.SubscriptionManagerPublisher().PublishChange(
-		keybase1.SubscriptionTopic_JOURNAL_STATUS)
+	j.publishJournalStatusLocked()
 	j.config.SubscriptionManagerPublisher().PublishChange(
 		keybase1.SubscriptionTopic_FILES_TAB_BADGE)
 	j.config.Reporter().NotifySyncStatus(ctx, &keybase1.FSPathSyncStatus{
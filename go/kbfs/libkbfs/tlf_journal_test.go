@@ -106,6 +106,11 @@ func (c testTLFJournalConfig) SubscriptionManagerPublisher() SubscriptionManager
 	return c.subscriptionManagerPublisher
 }
 
+func (c testTLFJournalConfig) JournalStatusThresholds() JournalStatusThresholds {
+	// Publish on every change, so tests see deterministic counts.
+	return JournalStatusThresholds{}
+}
+
 func (c testTLFJournalConfig) BlockSplitter() data.BlockSplitter {
 	return c.splitter
 }
@@ -89,6 +89,16 @@ type Config interface {
 	// GetAccessControlAllowOrigin returns a string that, if non-empty, should
 	// be set as Access-Control-Allow-Origin header.
 	GetAccessControlAllowOrigin(path string) (setting string, err error)
+	// GetIndexFileName returns a filename that, if non-empty, should be
+	// served in place of index.html when path (a directory) is requested.
+	GetIndexFileName(path string) (name string, err error)
+	// GetDirectoryListingDisabled returns true if automatic directory
+	// listings should be suppressed under path regardless of the list
+	// permission.
+	GetDirectoryListingDisabled(path string) (disabled bool, err error)
+	// GetRedirect returns the redirect location configured for exactly
+	// path, if any.
+	GetRedirect(path string) (location string, ok bool, err error)
 
 	Encode(w io.Writer, prettify bool) error
 }
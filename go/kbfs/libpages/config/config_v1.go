@@ -42,6 +42,13 @@ type V1 struct {
 	// for individual paths. Configured paths apply to their sub paths too.
 	PerPathConfigs map[string]PerPathConfigV1 `json:"per_path_configs"`
 
+	// Redirects is a path -> location map. Requests for an exact path are
+	// answered with a 301 to the configured location, which can be a
+	// site-relative path or a full URL.
+	Redirects map[string]string `json:"redirects,omitempty"`
+
+	redirects map[string]string
+
 	initOnce                    sync.Once
 	perPathConfigsReader        *perPathConfigsReaderV1
 	perPathConfigsReaderInitErr error
@@ -99,6 +106,16 @@ func (c *V1) init() {
 			return
 		}
 	}
+
+	c.redirects = make(map[string]string)
+	for p, location := range c.Redirects {
+		if len(strings.TrimSpace(location)) == 0 {
+			c.perPathConfigsReaderInitErr = ErrInvalidConfig{
+				msg: "empty redirect location for " + p}
+			return
+		}
+		c.redirects["/"+cleanPath(p)] = location
+	}
 }
 
 // EnsureInit initializes c, and returns any error encountered during the
@@ -152,6 +169,31 @@ func (c *V1) GetAccessControlAllowOrigin(path string) (setting string, err error
 	return c.perPathConfigsReader.getSetAccessControlAllowOrigin(path), nil
 }
 
+// GetIndexFileName implements the Config interface.
+func (c *V1) GetIndexFileName(path string) (name string, err error) {
+	if err = c.EnsureInit(); err != nil {
+		return "", err
+	}
+	return c.perPathConfigsReader.getIndexFile(path), nil
+}
+
+// GetDirectoryListingDisabled implements the Config interface.
+func (c *V1) GetDirectoryListingDisabled(path string) (disabled bool, err error) {
+	if err = c.EnsureInit(); err != nil {
+		return false, err
+	}
+	return c.perPathConfigsReader.getDirectoryListingDisabled(path), nil
+}
+
+// GetRedirect implements the Config interface.
+func (c *V1) GetRedirect(path string) (location string, ok bool, err error) {
+	if err = c.EnsureInit(); err != nil {
+		return "", false, err
+	}
+	location, ok = c.redirects["/"+cleanPath(path)]
+	return location, ok, nil
+}
+
 // Encode implements the Config interface.
 func (c *V1) Encode(w io.Writer, prettify bool) error {
 	encoder := json.NewEncoder(w)
@@ -176,8 +218,15 @@ func (c *V1) Validate() (err error) {
 	if err := c.checkAndRenameACLsIfNeeded(); err != nil {
 		return err
 	}
-	_, err = makePerPathConfigsReaderV1(c.PerPathConfigs, c.Users)
-	return err
+	if _, err = makePerPathConfigsReaderV1(c.PerPathConfigs, c.Users); err != nil {
+		return err
+	}
+	for p, location := range c.Redirects {
+		if len(strings.TrimSpace(location)) == 0 {
+			return ErrInvalidConfig{msg: "empty redirect location for " + p}
+		}
+	}
+	return nil
 }
 
 // HasBcryptPasswords checks if any password hash in the config is a bcrypt
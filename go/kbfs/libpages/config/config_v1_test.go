@@ -388,3 +388,62 @@ func TestV1DeprecatingACLsField(t *testing.T) {
 	}).EnsureInit()
 	require.IsType(t, ErrACLsPerPathConfigsBothPresent{}, err)
 }
+
+func TestConfigV1SiteSettings(t *testing.T) {
+	config := &V1{
+		Common: Common{
+			Version: Version1Str,
+		},
+		PerPathConfigs: map[string]PerPathConfigV1{
+			"/": {
+				AnonymousPermissions:    PermRead,
+				IndexFile:               "home.html",
+				DisableDirectoryListing: true,
+			},
+		},
+		Redirects: map[string]string{
+			"/old": "/new",
+		},
+	}
+	require.NoError(t, config.EnsureInit())
+
+	name, err := config.GetIndexFileName("/sub/dir")
+	require.NoError(t, err)
+	require.Equal(t, "home.html", name)
+
+	disabled, err := config.GetDirectoryListingDisabled("/sub")
+	require.NoError(t, err)
+	require.True(t, disabled)
+
+	location, ok, err := config.GetRedirect("/old")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "/new", location)
+
+	_, ok, err = config.GetRedirect("/old/sub")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	err = (&V1{
+		Common: Common{
+			Version: Version1Str,
+		},
+		Redirects: map[string]string{
+			"/old": " ",
+		},
+	}).EnsureInit()
+	require.Error(t, err)
+	require.IsType(t, ErrInvalidConfig{}, err)
+
+	err = (&V1{
+		Common: Common{
+			Version: Version1Str,
+		},
+		PerPathConfigs: map[string]PerPathConfigV1{
+			"/": {
+				IndexFile: "../escape.html",
+			},
+		},
+	}).EnsureInit()
+	require.Error(t, err)
+}
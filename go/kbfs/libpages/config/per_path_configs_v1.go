@@ -40,6 +40,14 @@ type PerPathConfigV1 struct {
 	// Custom404NotFound specifies a path (relative to site root) to a html
 	// file to be served when 404 errors happen.
 	Custom404NotFound string `json:"custom_404_not_found,omitempty"`
+
+	// IndexFile, if set, specifies a filename (relative to the requested
+	// directory) to be served in place of the default index.html when a
+	// directory is requested.
+	IndexFile string `json:"index_file,omitempty"`
+	// DisableDirectoryListing, if set, suppresses automatic directory
+	// listings under this path even when the requester has list permission.
+	DisableDirectoryListing bool `json:"disable_directory_listing,omitempty"`
 }
 
 // permissionsV1 is the parsed version of a permission string.
@@ -91,6 +99,8 @@ type perPathConfigV1 struct {
 	accessControlAllowOrigin string
 	custom403Forbidden       string
 	custom404NotFound        string
+	indexFile                string
+	disableDirectoryListing  bool
 }
 
 func checkCors(acao string) (cleaned string, err error) {
@@ -158,6 +168,10 @@ func makePerPathConfigV1Internal(
 		a.Custom404NotFound); err != nil {
 		return nil, err
 	}
+	if ac.indexFile, err = checkCustomPagePath(a.IndexFile); err != nil {
+		return nil, err
+	}
+	ac.disableDirectoryListing = a.DisableDirectoryListing
 
 	return ac, nil
 }
@@ -278,6 +292,16 @@ func (c *perPathConfigsReaderV1) getSetAccessControlAllowOrigin(p string) (setti
 	return ac.accessControlAllowOrigin
 }
 
+func (c *perPathConfigsReaderV1) getIndexFile(p string) string {
+	ac := c.getPerPathConfig(nil, p)
+	return ac.indexFile
+}
+
+func (c *perPathConfigsReaderV1) getDirectoryListingDisabled(p string) bool {
+	ac := c.getPerPathConfig(nil, p)
+	return ac.disableDirectoryListing
+}
+
 // makePerPathConfigsReaderV1 makes an *perPathConfigsReaderV1 out of
 // user-defined per-path configs. It recursively constructs nested
 // *perPathConfigsReaderV1 so that each defined path has a corresponding
@@ -0,0 +1,242 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libsftp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net"
+	"path"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/keybase/client/go/kbfs/data"
+	"github.com/keybase/client/go/kbfs/libfs"
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	"github.com/keybase/client/go/kbfs/tlf"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/logger"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+	billy "gopkg.in/src-d/go-billy.v4"
+)
+
+const fsCacheSize = 64
+const tokenByteSize = 32
+
+// DefaultAddr is the loopback address the gateway listens on when no
+// explicit address is configured; port 0 picks a free port.
+const DefaultAddr = "127.0.0.1:0"
+
+// Server is a loopback SFTP gateway into KBFS, for environments where
+// a FUSE mount is unavailable (locked-down macOS, some containers). It
+// reuses the same libfs plumbing as the local HTTP server, and
+// authenticates each connection with a per-process random token used
+// as the SSH password. The host key is ephemeral and regenerated on
+// every start.
+type Server struct {
+	config    libkbfs.Config
+	logger    logger.Logger
+	vlog      *libkb.VDebugLog
+	sshConfig *ssh.ServerConfig
+	token     string
+	listener  net.Listener
+	cancel    func()
+
+	fsLock sync.Mutex
+	fs     *lru.Cache
+}
+
+// New creates a gateway listening on addr, which must be a loopback
+// address. Pass an empty addr for DefaultAddr.
+func New(config libkbfs.Config, addr string) (s *Server, err error) {
+	if len(addr) == 0 {
+		addr = DefaultAddr
+	}
+	logger := config.MakeLogger("SFTP")
+	s = &Server{
+		config: config,
+		logger: logger,
+		vlog:   config.MakeVLogger(logger),
+	}
+	if s.fs, err = lru.New(fsCacheSize); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, tokenByteSize)
+	if _, err = rand.Read(buf); err != nil {
+		return nil, err
+	}
+	s.token = base64.URLEncoding.EncodeToString(buf)
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	s.sshConfig = &ssh.ServerConfig{
+		PasswordCallback: s.checkPassword,
+	}
+	s.sshConfig.AddHostKey(signer)
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return nil, errors.New("SFTP gateway only listens on loopback addresses")
+	}
+	if s.listener, err = net.Listen("tcp", addr); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.acceptLoop(ctx)
+	return s, nil
+}
+
+// Token returns the password a client must present to connect. The
+// username is ignored.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Address returns the address that the gateway is listening on.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops accepting connections.
+func (s *Server) Shutdown() {
+	s.cancel()
+	_ = s.listener.Close()
+}
+
+func (s *Server) checkPassword(_ ssh.ConnMetadata, password []byte) (
+	*ssh.Permissions, error) {
+	if subtle.ConstantTimeCompare(password, []byte(s.token)) != 1 {
+		return nil, errors.New("invalid gateway token")
+	}
+	return nil, nil
+}
+
+func (s *Server) acceptLoop(ctx context.Context) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Warning("acceptLoop: %v", err)
+			return
+		}
+		go s.handleConn(ctx, conn)
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, s.sshConfig)
+	if err != nil {
+		s.vlog.Log(libkb.VLog1, "SSH handshake failed: %v", err)
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, requests, err := newCh.Accept()
+		if err != nil {
+			s.vlog.Log(libkb.VLog1, "channel accept failed: %v", err)
+			continue
+		}
+		go s.handleSession(ctx, ch, requests)
+	}
+}
+
+// handleSession waits for the client to request the sftp subsystem and
+// then hands the channel to the protocol loop. Everything else (shell,
+// exec) is refused.
+func (s *Server) handleSession(
+	ctx context.Context, ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range requests {
+		if req.Type == "subsystem" && parseSubsystem(req.Payload) == "sftp" {
+			_ = req.Reply(true, nil)
+			c := newSFTPConn(s, ch)
+			c.serve(ctx)
+			return
+		}
+		_ = req.Reply(false, nil)
+	}
+}
+
+func parseSubsystem(payload []byte) string {
+	if len(payload) < 4 {
+		return ""
+	}
+	n := int(uint32(payload[0])<<24 | uint32(payload[1])<<16 |
+		uint32(payload[2])<<8 | uint32(payload[3]))
+	if len(payload) < 4+n {
+		return ""
+	}
+	return string(payload[4 : 4+n])
+}
+
+// getFS resolves an absolute SFTP path like /private/user/dir/file to
+// a billy filesystem and the path relative to it. Paths above a TLF
+// resolve to the read-only root filesystem.
+func (s *Server) getFS(ctx context.Context, sftpPath string) (
+	fs billy.Filesystem, rel string, err error) {
+	cleaned := strings.TrimPrefix(path.Clean(sftpPath), "/")
+	fields := strings.Split(cleaned, "/")
+	if len(fields) < 2 || len(fields[1]) == 0 {
+		return libfs.NewRootFS(s.config), cleaned, nil
+	}
+
+	tlfType, err := tlf.ParseTlfTypeFromPath(fields[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	cacheKey := path.Join(fields[0], fields[1])
+	rel = strings.TrimPrefix(cleaned, cacheKey)
+	rel = strings.TrimPrefix(rel, "/")
+	if len(rel) == 0 {
+		rel = "."
+	}
+
+	s.fsLock.Lock()
+	defer s.fsLock.Unlock()
+	if fsCached, ok := s.fs.Get(cacheKey); ok {
+		return fsCached.(*libfs.FS), rel, nil
+	}
+
+	tlfHandle, err := libkbfs.GetHandleFromFolderNameAndType(ctx,
+		s.config.KBPKI(), s.config.MDOps(), s.config, fields[1], tlfType)
+	if err != nil {
+		return nil, "", err
+	}
+	tlfFS, err := libfs.NewFS(ctx,
+		s.config, tlfHandle, data.MasterBranch, "", "",
+		keybase1.MDPriorityNormal)
+	if err != nil {
+		return nil, "", err
+	}
+	s.fs.Add(cacheKey, tlfFS)
+	return tlfFS, rel, nil
+}
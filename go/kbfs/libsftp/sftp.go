@@ -0,0 +1,552 @@
+// Copyright 2026 Keybase Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package libsftp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/crypto/ssh"
+	billy "gopkg.in/src-d/go-billy.v4"
+)
+
+// This file implements the server side of SFTP version 3
+// (draft-ietf-secsh-filexfer-02), the dialect every mainstream client
+// speaks, on top of billy filesystems from libfs. Only the operations
+// a file browser or sftp(1) actually issues are supported; everything
+// else gets SSH_FX_OP_UNSUPPORTED.
+
+// Packet types.
+const (
+	fxpInit     = 1
+	fxpVersion  = 2
+	fxpOpen     = 3
+	fxpClose    = 4
+	fxpRead     = 5
+	fxpWrite    = 6
+	fxpLstat    = 7
+	fxpFstat    = 8
+	fxpSetstat  = 9
+	fxpFsetstat = 10
+	fxpOpendir  = 11
+	fxpReaddir  = 12
+	fxpRemove   = 13
+	fxpMkdir    = 14
+	fxpRmdir    = 15
+	fxpRealpath = 16
+	fxpStat     = 17
+	fxpRename   = 18
+	fxpReadlink = 19
+	fxpSymlink  = 20
+	fxpStatus   = 101
+	fxpHandle   = 102
+	fxpData     = 103
+	fxpName     = 104
+	fxpAttrs    = 105
+)
+
+// Status codes.
+const (
+	fxOK            = 0
+	fxEOF           = 1
+	fxNoSuchFile    = 2
+	fxPermDenied    = 3
+	fxFailure       = 4
+	fxBadMessage    = 5
+	fxOpUnsupported = 8
+)
+
+// Open pflags.
+const (
+	pflagRead   = 0x1
+	pflagWrite  = 0x2
+	pflagAppend = 0x4
+	pflagCreat  = 0x8
+	pflagTrunc  = 0x10
+	pflagExcl   = 0x20
+)
+
+// Attr flags.
+const (
+	attrSize      = 0x1
+	attrPerms     = 0x4
+	attrAcModTime = 0x8
+)
+
+const sftpVersion = 3
+const maxPacketSize = 1 << 20
+const readdirBatch = 100
+
+type sftpHandle struct {
+	// mu serializes seek+write pairs on the same handle.
+	mu   sync.Mutex
+	file billy.File
+	fs   billy.Filesystem
+	rel  string
+	// dir holds the remaining unread entries for directory handles.
+	dir   []os.FileInfo
+	isDir bool
+}
+
+type sftpConn struct {
+	server      *Server
+	ch          ssh.Channel
+	handles     map[string]*sftpHandle
+	nextHandle  uint64
+	initialized bool
+}
+
+func newSFTPConn(s *Server, ch ssh.Channel) *sftpConn {
+	return &sftpConn{
+		server:  s,
+		ch:      ch,
+		handles: make(map[string]*sftpHandle),
+	}
+}
+
+func (c *sftpConn) serve(ctx context.Context) {
+	defer func() {
+		for _, h := range c.handles {
+			if h.file != nil {
+				_ = h.file.Close()
+			}
+		}
+	}()
+	for {
+		pkt, err := c.readPacket()
+		if err != nil {
+			if err != io.EOF {
+				c.server.vlog.Log(libkb.VLog1, "sftp read error: %v", err)
+			}
+			return
+		}
+		if err = c.dispatch(ctx, pkt); err != nil {
+			c.server.vlog.Log(libkb.VLog1, "sftp write error: %v", err)
+			return
+		}
+	}
+}
+
+func (c *sftpConn) readPacket() ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(c.ch, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length == 0 || length > maxPacketSize {
+		return nil, fmt.Errorf("bad sftp packet length %d", length)
+	}
+	pkt := make([]byte, length)
+	if _, err := io.ReadFull(c.ch, pkt); err != nil {
+		return nil, err
+	}
+	return pkt, nil
+}
+
+// decoder walks an incoming packet payload.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uint32() uint32 {
+	if d.err != nil || len(d.buf) < 4 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := binary.BigEndian.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return v
+}
+
+func (d *decoder) uint64() uint64 {
+	if d.err != nil || len(d.buf) < 8 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) str() string {
+	return string(d.bytes())
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uint32()
+	if d.err != nil || uint32(len(d.buf)) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	v := d.buf[:n]
+	d.buf = d.buf[n:]
+	return v
+}
+
+// encoder builds an outgoing packet payload.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) byte(v byte)     { e.buf = append(e.buf, v) }
+func (e *encoder) uint32(v uint32) { e.buf = append(e.buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v)) }
+func (e *encoder) uint64(v uint64) {
+	e.uint32(uint32(v >> 32))
+	e.uint32(uint32(v))
+}
+func (e *encoder) str(v string) {
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+func (e *encoder) bytes(v []byte) {
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (c *sftpConn) writePacket(e *encoder) error {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(e.buf)))
+	if _, err := c.ch.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := c.ch.Write(e.buf)
+	return err
+}
+
+func (c *sftpConn) sendStatus(id uint32, code uint32, msg string) error {
+	e := &encoder{}
+	e.byte(fxpStatus)
+	e.uint32(id)
+	e.uint32(code)
+	e.str(msg)
+	e.str("") // language tag
+	return c.writePacket(e)
+}
+
+func (c *sftpConn) sendErr(id uint32, err error) error {
+	switch {
+	case err == nil:
+		return c.sendStatus(id, fxOK, "")
+	case os.IsNotExist(err):
+		return c.sendStatus(id, fxNoSuchFile, err.Error())
+	case os.IsPermission(err) || err == billy.ErrReadOnly:
+		return c.sendStatus(id, fxPermDenied, err.Error())
+	case err == io.EOF:
+		return c.sendStatus(id, fxEOF, "")
+	default:
+		return c.sendStatus(id, fxFailure, err.Error())
+	}
+}
+
+func encodeAttrs(e *encoder, fi os.FileInfo) {
+	e.uint32(attrSize | attrPerms | attrAcModTime)
+	e.uint64(uint64(fi.Size()))
+	mode := uint32(fi.Mode().Perm())
+	if fi.IsDir() {
+		mode |= 0040000
+	} else if fi.Mode()&os.ModeSymlink != 0 {
+		mode |= 0120000
+	} else {
+		mode |= 0100000
+	}
+	e.uint32(mode)
+	mtime := uint32(fi.ModTime().Unix())
+	e.uint32(mtime) // atime; we don't track it separately
+	e.uint32(mtime)
+}
+
+// longname is the ls -l style line clients show in directory listings.
+func longname(fi os.FileInfo) string {
+	return fmt.Sprintf("%s 1 keybase keybase %12d %s %s",
+		fi.Mode().String(), fi.Size(),
+		fi.ModTime().Format("Jan _2 15:04"), fi.Name())
+}
+
+func (c *sftpConn) dispatch(ctx context.Context, pkt []byte) error {
+	typ := pkt[0]
+	d := &decoder{buf: pkt[1:]}
+
+	if typ == fxpInit {
+		c.initialized = true
+		e := &encoder{}
+		e.byte(fxpVersion)
+		e.uint32(sftpVersion)
+		return c.writePacket(e)
+	}
+	if !c.initialized {
+		return fmt.Errorf("sftp request before init")
+	}
+
+	id := d.uint32()
+	if d.err != nil {
+		return c.sendStatus(id, fxBadMessage, "short packet")
+	}
+
+	switch typ {
+	case fxpRealpath:
+		p := d.str()
+		cleaned := path.Clean("/" + p)
+		e := &encoder{}
+		e.byte(fxpName)
+		e.uint32(id)
+		e.uint32(1)
+		e.str(cleaned)
+		e.str(cleaned)
+		e.uint32(0) // no attrs
+		return c.writePacket(e)
+	case fxpStat, fxpLstat:
+		p := d.str()
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		var fi os.FileInfo
+		if typ == fxpLstat {
+			fi, err = fs.Lstat(rel)
+		} else {
+			fi, err = fs.Stat(rel)
+		}
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		e := &encoder{}
+		e.byte(fxpAttrs)
+		e.uint32(id)
+		encodeAttrs(e, fi)
+		return c.writePacket(e)
+	case fxpOpendir:
+		p := d.str()
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		entries, err := fs.ReadDir(rel)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		return c.sendHandle(id, &sftpHandle{fs: fs, rel: rel, dir: entries, isDir: true})
+	case fxpReaddir:
+		h, ok := c.handles[d.str()]
+		if !ok || !h.isDir {
+			return c.sendStatus(id, fxBadMessage, "bad handle")
+		}
+		if len(h.dir) == 0 {
+			return c.sendStatus(id, fxEOF, "")
+		}
+		batch := h.dir
+		if len(batch) > readdirBatch {
+			batch = batch[:readdirBatch]
+		}
+		h.dir = h.dir[len(batch):]
+		e := &encoder{}
+		e.byte(fxpName)
+		e.uint32(id)
+		e.uint32(uint32(len(batch)))
+		for _, fi := range batch {
+			e.str(fi.Name())
+			e.str(longname(fi))
+			encodeAttrs(e, fi)
+		}
+		return c.writePacket(e)
+	case fxpOpen:
+		p := d.str()
+		pflags := d.uint32()
+		_ = d.uint32() // attr flags on create; ignored
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		flags, err := openFlags(pflags)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		f, err := fs.OpenFile(rel, flags, 0644)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		return c.sendHandle(id, &sftpHandle{file: f, fs: fs, rel: rel})
+	case fxpRead:
+		h, ok := c.handles[d.str()]
+		offset := d.uint64()
+		length := d.uint32()
+		if !ok || h.file == nil {
+			return c.sendStatus(id, fxBadMessage, "bad handle")
+		}
+		if length > maxPacketSize-64 {
+			length = maxPacketSize - 64
+		}
+		buf := make([]byte, length)
+		n, err := h.file.ReadAt(buf, int64(offset))
+		if n > 0 {
+			e := &encoder{}
+			e.byte(fxpData)
+			e.uint32(id)
+			e.bytes(buf[:n])
+			return c.writePacket(e)
+		}
+		return c.sendErr(id, err)
+	case fxpWrite:
+		h, ok := c.handles[d.str()]
+		offset := d.uint64()
+		data := d.bytes()
+		if !ok || h.file == nil {
+			return c.sendStatus(id, fxBadMessage, "bad handle")
+		}
+		h.mu.Lock()
+		_, err := h.file.Seek(int64(offset), io.SeekStart)
+		if err == nil {
+			_, err = h.file.Write(data)
+		}
+		h.mu.Unlock()
+		return c.sendErr(id, err)
+	case fxpClose:
+		key := d.str()
+		h, ok := c.handles[key]
+		if !ok {
+			return c.sendStatus(id, fxBadMessage, "bad handle")
+		}
+		delete(c.handles, key)
+		if h.file != nil {
+			return c.sendErr(id, h.file.Close())
+		}
+		return c.sendStatus(id, fxOK, "")
+	case fxpFstat:
+		h, ok := c.handles[d.str()]
+		if !ok {
+			return c.sendStatus(id, fxBadMessage, "bad handle")
+		}
+		fi, err := h.fs.Stat(h.rel)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		e := &encoder{}
+		e.byte(fxpAttrs)
+		e.uint32(id)
+		encodeAttrs(e, fi)
+		return c.writePacket(e)
+	case fxpRemove, fxpRmdir:
+		p := d.str()
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		return c.sendErr(id, fs.Remove(rel))
+	case fxpMkdir:
+		p := d.str()
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		return c.sendErr(id, fs.MkdirAll(rel, 0755))
+	case fxpRename:
+		oldPath := d.str()
+		newPath := d.str()
+		oldFS, oldRel, err := c.server.getFS(ctx, oldPath)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		newFS, newRel, err := c.server.getFS(ctx, newPath)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		if oldFS != newFS {
+			return c.sendStatus(id, fxOpUnsupported,
+				"cannot rename across folders")
+		}
+		return c.sendErr(id, oldFS.Rename(oldRel, newRel))
+	case fxpReadlink:
+		p := d.str()
+		fs, rel, err := c.server.getFS(ctx, p)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		target, err := fs.Readlink(rel)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		e := &encoder{}
+		e.byte(fxpName)
+		e.uint32(id)
+		e.uint32(1)
+		e.str(target)
+		e.str(target)
+		e.uint32(0)
+		return c.writePacket(e)
+	case fxpSymlink:
+		// Note the draft's backwards argument order: target first.
+		target := d.str()
+		linkPath := d.str()
+		fs, rel, err := c.server.getFS(ctx, linkPath)
+		if err != nil {
+			return c.sendErr(id, err)
+		}
+		return c.sendErr(id, fs.Symlink(target, rel))
+	case fxpSetstat, fxpFsetstat:
+		// KBFS doesn't track permissions or times the way these
+		// requests want to set them; honor only truncation on open
+		// handles and report success for the rest so copies don't
+		// fail at the final chmod.
+		if typ == fxpFsetstat {
+			h, ok := c.handles[d.str()]
+			if !ok || h.file == nil {
+				return c.sendStatus(id, fxBadMessage, "bad handle")
+			}
+			flags := d.uint32()
+			if flags&attrSize != 0 {
+				size := d.uint64()
+				return c.sendErr(id, h.file.Truncate(int64(size)))
+			}
+		}
+		return c.sendStatus(id, fxOK, "")
+	default:
+		return c.sendStatus(id, fxOpUnsupported,
+			"unsupported sftp request type "+strconv.Itoa(int(typ)))
+	}
+}
+
+func (c *sftpConn) sendHandle(id uint32, h *sftpHandle) error {
+	c.nextHandle++
+	key := strconv.FormatUint(c.nextHandle, 10)
+	c.handles[key] = h
+	e := &encoder{}
+	e.byte(fxpHandle)
+	e.uint32(id)
+	e.str(key)
+	return c.writePacket(e)
+}
+
+func openFlags(pflags uint32) (flags int, err error) {
+	switch pflags & (pflagRead | pflagWrite) {
+	case pflagRead:
+		flags = os.O_RDONLY
+	case pflagWrite:
+		flags = os.O_WRONLY
+	case pflagRead | pflagWrite:
+		flags = os.O_RDWR
+	default:
+		return 0, fmt.Errorf("open without read or write access")
+	}
+	if pflags&pflagAppend != 0 {
+		flags |= os.O_APPEND
+	}
+	if pflags&pflagCreat != 0 {
+		flags |= os.O_CREATE
+	}
+	if pflags&pflagTrunc != 0 {
+		flags |= os.O_TRUNC
+	}
+	if pflags&pflagExcl != 0 {
+		flags |= os.O_EXCL
+	}
+	return flags, nil
+}
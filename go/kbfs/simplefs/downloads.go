@@ -5,6 +5,8 @@
 package simplefs
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -39,6 +41,10 @@ type download struct {
 	safeFilename string
 	state        keybase1.DownloadState
 	opid         keybase1.OpID
+	// queued is true while the download waits for a free slot; paused
+	// downloads stay queued until resumed.
+	queued bool
+	paused bool
 }
 
 // downloadManager manages "downloads" initiated from outside KBFS. To KBFS,
@@ -59,16 +65,100 @@ type downloadManager struct {
 	cacheDir    string
 	downloadDir string
 	downloads   map[string]download // download ID -> download
+	active      int                 // number of downloads currently copying
 }
 
 func newDownloadManager(simpleFS *SimpleFS) *downloadManager {
-	return &downloadManager{
+	m := &downloadManager{
 		k:           simpleFS,
 		publisher:   simpleFS.config.SubscriptionManagerPublisher(),
 		cacheDir:    simpleFS.config.KbEnv().GetCacheDir(),
 		downloadDir: simpleFS.config.KbEnv().GetDownloadsDir(),
 		downloads:   make(map[string]download),
 	}
+	m.loadPersistedDownloads()
+	return m
+}
+
+// maxConcurrentDownloads caps how many downloads copy at once; the rest
+// wait in the queue in StartTime order.
+const maxConcurrentDownloads = 4
+
+const downloadQueueFilename = "download_queue.json"
+
+// persistedDownload is what we remember about an unfinished download
+// across service restarts.
+type persistedDownload struct {
+	DownloadID        string            `json:"download_id"`
+	Path              keybase1.KBFSPath `json:"path"`
+	IsRegularDownload bool              `json:"is_regular_download"`
+	StartTime         keybase1.Time     `json:"start_time"`
+}
+
+func (m *downloadManager) queueFilePathLocked() string {
+	return filepath.Join(m.cacheDir, "simplefsdownload", downloadQueueFilename)
+}
+
+// persistQueueLocked saves all unfinished downloads so they can be
+// requeued after a service restart. Callers must hold m.lock.
+func (m *downloadManager) persistQueueLocked() {
+	var persisted []persistedDownload
+	for _, d := range m.downloads {
+		if d.state.Done || d.state.Canceled || len(d.state.Error) > 0 {
+			continue
+		}
+		persisted = append(persisted, persistedDownload{
+			DownloadID:        d.info.DownloadID,
+			Path:              d.info.Path,
+			IsRegularDownload: d.info.IsRegularDownload,
+			StartTime:         d.info.StartTime,
+		})
+	}
+	queueFilePath := m.queueFilePathLocked()
+	if len(persisted) == 0 {
+		_ = os.Remove(queueFilePath)
+		return
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(queueFilePath), 0700); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(queueFilePath, data, 0600)
+}
+
+// loadPersistedDownloads requeues downloads that didn't finish before the
+// last shutdown. They start copying on the next queue dispatch.
+func (m *downloadManager) loadPersistedDownloads() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	data, err := ioutil.ReadFile(m.queueFilePathLocked())
+	if err != nil {
+		return
+	}
+	var persisted []persistedDownload
+	if json.Unmarshal(data, &persisted) != nil {
+		return
+	}
+	for _, p := range persisted {
+		filename, safeFilename := m.getFilenames(p.Path)
+		m.downloads[p.DownloadID] = download{
+			info: keybase1.DownloadInfo{
+				DownloadID:        p.DownloadID,
+				Path:              p.Path,
+				Filename:          filename,
+				StartTime:         p.StartTime,
+				IsRegularDownload: p.IsRegularDownload,
+			},
+			safeFilename: safeFilename,
+			state: keybase1.DownloadState{
+				DownloadID: p.DownloadID,
+			},
+			queued: true,
+		}
+	}
 }
 
 func (m *downloadManager) makeContext() (ctx context.Context, cancel func()) {
@@ -244,25 +334,8 @@ func (m *downloadManager) waitForDownload(ctx context.Context,
 func (m *downloadManager) startDownload(
 	ctx context.Context, arg keybase1.SimpleFSStartDownloadArg) (
 	downloadID string, err error) {
-	opid, err := m.k.SimpleFSMakeOpid(ctx)
-	if err != nil {
-		return "", err
-	}
 	downloadID = strconv.FormatInt(time.Now().UnixNano(), 16)
 	filename, safeFilename := m.getFilenames(arg.Path)
-	downloadPath, err := m.getDownloadPath(ctx, filename, downloadID)
-	if err != nil {
-		return "", err
-	}
-	// TODO for dirs maybe we want zip instead?
-	err = m.k.SimpleFSCopyRecursive(ctx, keybase1.SimpleFSCopyRecursiveArg{
-		OpID: opid,
-		Src:  keybase1.NewPathWithKbfs(arg.Path),
-		Dest: keybase1.NewPathWithLocal(downloadPath),
-	})
-	if err != nil {
-		return "", err
-	}
 
 	func() {
 		defer m.publisher.PublishChange(keybase1.SubscriptionTopic_DOWNLOAD_STATUS)
@@ -276,16 +349,125 @@ func (m *downloadManager) startDownload(
 				StartTime:         keybase1.ToTime(time.Now()),
 				IsRegularDownload: arg.IsRegularDownload,
 			},
-			opid:         opid,
 			safeFilename: safeFilename,
 			state: keybase1.DownloadState{
 				DownloadID: downloadID,
 			},
+			queued: true,
 		}
+		m.persistQueueLocked()
 	}()
 
-	bgCtx, cancelBtCtx := m.makeContext()
+	m.dispatchDownloads(ctx)
+	return downloadID, nil
+}
+
+// nextQueuedLocked returns the oldest queued, unpaused download. Callers
+// must hold m.lock.
+func (m *downloadManager) nextQueuedLocked() (downloadID string, ok bool) {
+	var earliest keybase1.Time
+	for id, d := range m.downloads {
+		if !d.queued || d.paused {
+			continue
+		}
+		if !ok || earliest.After(d.info.StartTime) {
+			earliest = d.info.StartTime
+			downloadID = id
+			ok = true
+		}
+	}
+	return downloadID, ok
+}
+
+// dispatchDownloads starts queued downloads until the concurrency limit
+// is reached or the queue runs dry.
+func (m *downloadManager) dispatchDownloads(ctx context.Context) {
+	for {
+		m.lock.Lock()
+		if m.active >= maxConcurrentDownloads {
+			m.lock.Unlock()
+			return
+		}
+		downloadID, ok := m.nextQueuedLocked()
+		if !ok {
+			m.lock.Unlock()
+			return
+		}
+		d := m.downloads[downloadID]
+		d.queued = false
+		m.downloads[downloadID] = d
+		m.active++
+		m.lock.Unlock()
+
+		if err := m.startQueuedDownload(ctx, downloadID); err != nil {
+			m.downloadDone(ctx, downloadID, nil, err)
+		}
+	}
+}
+
+// startQueuedDownload kicks off the actual copy for a download that was
+// just taken off the queue.
+func (m *downloadManager) startQueuedDownload(
+	ctx context.Context, downloadID string) (err error) {
+	d, err := m.getDownload(downloadID)
+	if err != nil {
+		return err
+	}
+	opid, err := m.k.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	downloadPath, err := m.getDownloadPath(ctx, d.info.Filename, downloadID)
+	if err != nil {
+		return err
+	}
+	// TODO for dirs maybe we want zip instead?
+	err = m.k.SimpleFSCopyRecursive(ctx, keybase1.SimpleFSCopyRecursiveArg{
+		OpID: opid,
+		Src:  keybase1.NewPathWithKbfs(d.info.Path),
+		Dest: keybase1.NewPathWithLocal(downloadPath),
+	})
+	if err != nil {
+		return err
+	}
+	if err = m.updateDownload(downloadID, func(d download) download {
+		d.opid = opid
+		return d
+	}); err != nil {
+		return err
+	}
+
+	bgCtx, cancelBgCtx := m.makeContext()
+	// monitorDownload and waitForDownload can both report completion;
+	// only the first one counts.
+	var doneOnce sync.Once
 	done := func(err error) {
+		doneOnce.Do(func() {
+			m.downloadDone(ctx, downloadID, cancelBgCtx, err)
+		})
+	}
+	go m.monitorDownload(bgCtx, opid, downloadID, done)
+	go m.waitForDownload(bgCtx, downloadID, downloadPath, done)
+	return nil
+}
+
+// downloadDone records a download's final state (unless it was merely
+// paused), frees its concurrency slot, and starts the next queued one.
+func (m *downloadManager) downloadDone(
+	ctx context.Context, downloadID string, cancelBgCtx func(), err error) {
+	paused := false
+	if d, getErr := m.getDownload(downloadID); getErr == nil {
+		paused = d.paused
+	}
+	if paused {
+		// A paused download stays queued. Reset its progress, since the
+		// copy restarts from scratch on resume.
+		_ = m.updateDownload(downloadID, func(d download) download {
+			d.state.Progress = 0
+			d.state.EndEstimate = 0
+			return d
+		})
+	} else {
 		_ = m.updateDownload(downloadID, func(d download) download {
 			if d.state.Done || d.state.Canceled || len(d.state.Error) > 0 {
 				return d
@@ -301,16 +483,71 @@ func (m *downloadManager) startDownload(
 			}
 			return d
 		})
-		cancelBtCtx()
 	}
-	go m.monitorDownload(bgCtx, opid, downloadID, done)
-	go m.waitForDownload(bgCtx, downloadID, downloadPath, done)
+	if cancelBgCtx != nil {
+		cancelBgCtx()
+	}
+	m.lock.Lock()
+	m.active--
+	m.persistQueueLocked()
+	m.lock.Unlock()
+	m.dispatchDownloads(ctx)
+}
 
-	return downloadID, nil
+// pauseDownload stops a download's copying but keeps it in the queue so
+// resumeDownload can restart it.
+func (m *downloadManager) pauseDownload(
+	ctx context.Context, downloadID string) error {
+	d, err := m.getDownload(downloadID)
+	if err != nil {
+		return err
+	}
+	if d.state.Done || d.state.Canceled || len(d.state.Error) > 0 {
+		return errors.New("download has already finished")
+	}
+	if d.paused {
+		return nil
+	}
+	if err = m.updateDownload(downloadID, func(d download) download {
+		d.paused = true
+		d.queued = true
+		return d
+	}); err != nil {
+		return err
+	}
+	if !d.queued {
+		// Cancel the in-flight copy; downloadDone sees the paused flag
+		// and leaves the download restartable.
+		return m.k.SimpleFSCancel(ctx, d.opid)
+	}
+	return nil
+}
+
+// resumeDownload puts a paused download back into contention for a
+// download slot.
+func (m *downloadManager) resumeDownload(
+	ctx context.Context, downloadID string) error {
+	d, err := m.getDownload(downloadID)
+	if err != nil {
+		return err
+	}
+	if !d.paused {
+		return nil
+	}
+	if err = m.updateDownload(downloadID, func(d download) download {
+		d.paused = false
+		return d
+	}); err != nil {
+		return err
+	}
+	m.dispatchDownloads(ctx)
+	return nil
 }
 
 func (m *downloadManager) getDownloadStatus(ctx context.Context) (
 	status keybase1.DownloadStatus) {
+	// Downloads requeued from a previous run start on the first poll.
+	m.dispatchDownloads(ctx)
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 	for _, download := range m.downloads {
@@ -340,6 +577,15 @@ func (m *downloadManager) cancelDownload(
 	if err != nil {
 		return err
 	}
+	if d.queued {
+		// Nothing is copying yet; just take it out of the queue.
+		return m.updateDownload(downloadID, func(d download) download {
+			d.queued = false
+			d.paused = false
+			d.state.Canceled = true
+			return d
+		})
+	}
 	return m.k.SimpleFSCancel(ctx, d.opid)
 }
 
@@ -351,6 +597,7 @@ func (m *downloadManager) dismissDownload(
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	delete(m.downloads, downloadID)
+	m.persistQueueLocked()
 }
 
 func (m *downloadManager) getDownloadInfo(downloadID string) (keybase1.DownloadInfo, error) {
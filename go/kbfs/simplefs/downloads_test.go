@@ -91,3 +91,54 @@ func TestDownloadManager(t *testing.T) {
 	testDownload(true, 1)
 	testDownload(false, 0)
 }
+
+func TestDownloadQueuePersistence(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "simplefs-downloadtest-queue")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	m := &downloadManager{
+		cacheDir:  cacheDir,
+		downloads: make(map[string]download),
+	}
+	m.downloads["a"] = download{
+		info: keybase1.DownloadInfo{
+			DownloadID:        "a",
+			Path:              keybase1.KBFSPath{Path: "/private/jdoe/test.txt"},
+			Filename:          "test.txt",
+			StartTime:         keybase1.ToTime(time.Now()),
+			IsRegularDownload: true,
+		},
+		queued: true,
+	}
+	// Finished downloads shouldn't be persisted.
+	m.downloads["b"] = download{
+		info:  keybase1.DownloadInfo{DownloadID: "b"},
+		state: keybase1.DownloadState{DownloadID: "b", Done: true},
+	}
+	m.lock.Lock()
+	m.persistQueueLocked()
+	m.lock.Unlock()
+
+	m2 := &downloadManager{
+		cacheDir:  cacheDir,
+		downloads: make(map[string]download),
+	}
+	m2.loadPersistedDownloads()
+	require.Len(t, m2.downloads, 1)
+	d, ok := m2.downloads["a"]
+	require.True(t, ok)
+	require.True(t, d.queued)
+	require.Equal(t, "test.txt", d.info.Filename)
+	require.True(t, d.info.IsRegularDownload)
+
+	downloadID, ok := m2.nextQueuedLocked()
+	require.True(t, ok)
+	require.Equal(t, "a", downloadID)
+
+	// Paused downloads are skipped by the dispatcher.
+	d.paused = true
+	m2.downloads["a"] = d
+	_, ok = m2.nextQueuedLocked()
+	require.False(t, ok)
+}
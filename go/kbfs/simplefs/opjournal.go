@@ -0,0 +1,167 @@
+// Copyright 2026 Keybase, Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package simplefs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/logger"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// opJournal persists the description of long-running async operations
+// (copies, moves, removals) so that a service restart mid-operation doesn't
+// silently drop them. On startup the journal's surviving entries are
+// replayed through the normal RPC entry points under their original op IDs,
+// which makes SimpleFSCheck and SimpleFSWait work on an op started before
+// the restart. A replayed copy re-walks the tree from the start; with
+// OverwriteExistingFiles unset that skips data already copied, so resumes
+// are incremental in the common case.
+const opJournalFilename = "simplefs_ops.json"
+
+type opJournalEntry struct {
+	OpID      string                 `json:"opID"`
+	OpType    keybase1.AsyncOps      `json:"opType"`
+	Desc      keybase1.OpDescription `json:"desc"`
+	StartedAt time.Time              `json:"startedAt"`
+}
+
+type opJournal struct {
+	lock sync.Mutex
+	// path of the journal file; empty disables journaling (e.g. in-memory
+	// test configs without a storage root).
+	path string
+	log  logger.Logger
+}
+
+func newOpJournal(storageRoot string, log logger.Logger) *opJournal {
+	j := &opJournal{log: log}
+	if storageRoot != "" {
+		j.path = filepath.Join(storageRoot, opJournalFilename)
+	}
+	return j
+}
+
+// journalable says whether an op type survives restarts. Only ops that are
+// safe to re-run from the top are journaled.
+func journalable(opType keybase1.AsyncOps) bool {
+	switch opType {
+	case keybase1.AsyncOps_COPY, keybase1.AsyncOps_MOVE, keybase1.AsyncOps_REMOVE:
+		return true
+	default:
+		return false
+	}
+}
+
+func (j *opJournal) load() map[string]opJournalEntry {
+	entries := make(map[string]opJournalEntry)
+	dat, err := os.ReadFile(j.path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(dat, &entries); err != nil {
+		j.log.Warning("opJournal: corrupt journal %s: %v", j.path, err)
+	}
+	return entries
+}
+
+func (j *opJournal) save(entries map[string]opJournalEntry) {
+	dat, err := json.Marshal(entries)
+	if err != nil {
+		j.log.Warning("opJournal: marshal: %v", err)
+		return
+	}
+	if err := os.WriteFile(j.path, dat, 0600); err != nil {
+		j.log.Warning("opJournal: write %s: %v", j.path, err)
+	}
+}
+
+func (j *opJournal) record(opid keybase1.OpID, opType keybase1.AsyncOps,
+	desc keybase1.OpDescription) {
+	if j.path == "" || !journalable(opType) {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	entries := j.load()
+	key := fmt.Sprintf("%X", opid)
+	entries[key] = opJournalEntry{
+		OpID:      key,
+		OpType:    opType,
+		Desc:      desc,
+		StartedAt: time.Now(),
+	}
+	j.save(entries)
+}
+
+func (j *opJournal) remove(opid keybase1.OpID) {
+	if j.path == "" {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	entries := j.load()
+	key := fmt.Sprintf("%X", opid)
+	if _, ok := entries[key]; !ok {
+		return
+	}
+	delete(entries, key)
+	j.save(entries)
+}
+
+// take returns all journaled entries and clears the journal; replaying an
+// entry re-records it, so anything that fails to replay isn't retried on
+// the next restart forever.
+func (j *opJournal) take() []opJournalEntry {
+	if j.path == "" {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	entries := j.load()
+	if len(entries) == 0 {
+		return nil
+	}
+	j.save(map[string]opJournalEntry{})
+	res := make([]opJournalEntry, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, entry)
+	}
+	return res
+}
+
+// resumeJournaledOps replays operations that were in flight when the
+// service went down, through the regular RPC entry points.
+func (k *SimpleFS) resumeJournaledOps(ctx context.Context) {
+	entries := k.opJournal.take()
+	for _, entry := range entries {
+		entry := entry
+		k.log.CDebugf(ctx, "Resuming journaled op %s (%v) from %s",
+			entry.OpID, entry.OpType, entry.StartedAt)
+		var err error
+		switch entry.OpType {
+		case keybase1.AsyncOps_COPY:
+			err = k.SimpleFSCopyRecursive(ctx,
+				keybase1.SimpleFSCopyRecursiveArg(entry.Desc.Copy()))
+		case keybase1.AsyncOps_MOVE:
+			err = k.SimpleFSMove(ctx,
+				keybase1.SimpleFSMoveArg(entry.Desc.Move()))
+		case keybase1.AsyncOps_REMOVE:
+			err = k.SimpleFSRemove(ctx,
+				keybase1.SimpleFSRemoveArg(entry.Desc.Remove()))
+		default:
+			continue
+		}
+		if err != nil {
+			k.log.CDebugf(ctx, "Couldn't resume op %s: %v", entry.OpID, err)
+		}
+	}
+}
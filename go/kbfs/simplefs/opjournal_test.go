@@ -0,0 +1,51 @@
+// Copyright 2026 Keybase, Inc. All rights reserved.
+// Use of this source code is governed by a BSD
+// license that can be found in the LICENSE file.
+
+package simplefs
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/logger"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpJournalRoundTrip(t *testing.T) {
+	j := newOpJournal(t.TempDir(), logger.NewTestLogger(t))
+
+	copyOpid := keybase1.OpID{1}
+	copyArgs := keybase1.CopyArgs{
+		OpID: copyOpid,
+		Src:  keybase1.NewPathWithKbfsPath("/private/alice/a"),
+		Dest: keybase1.NewPathWithKbfsPath("/private/alice/b"),
+	}
+	j.record(copyOpid, keybase1.AsyncOps_COPY,
+		keybase1.NewOpDescriptionWithCopy(copyArgs))
+
+	// non-restartable ops are not journaled
+	j.record(keybase1.OpID{2}, keybase1.AsyncOps_LIST,
+		keybase1.NewOpDescriptionWithList(keybase1.ListArgs{OpID: keybase1.OpID{2}}))
+
+	entries := j.take()
+	require.Len(t, entries, 1)
+	require.Equal(t, keybase1.AsyncOps_COPY, entries[0].OpType)
+	require.Equal(t, copyArgs, entries[0].Desc.Copy())
+
+	// take clears the journal
+	require.Len(t, j.take(), 0)
+
+	// done ops disappear
+	j.record(copyOpid, keybase1.AsyncOps_COPY,
+		keybase1.NewOpDescriptionWithCopy(copyArgs))
+	j.remove(copyOpid)
+	require.Len(t, j.take(), 0)
+}
+
+func TestOpJournalDisabled(t *testing.T) {
+	j := newOpJournal("", logger.NewTestLogger(t))
+	j.record(keybase1.OpID{1}, keybase1.AsyncOps_COPY,
+		keybase1.NewOpDescriptionWithCopy(keybase1.CopyArgs{}))
+	require.Nil(t, j.take())
+}
@@ -23,12 +23,14 @@ import (
 	"github.com/keybase/client/go/kbfs/data"
 	"github.com/keybase/client/go/kbfs/env"
 	"github.com/keybase/client/go/kbfs/idutil"
+	"github.com/keybase/client/go/kbfs/ioutil"
 	"github.com/keybase/client/go/kbfs/kbfscrypto"
 	"github.com/keybase/client/go/kbfs/kbfsmd"
 	"github.com/keybase/client/go/kbfs/libcontext"
 	"github.com/keybase/client/go/kbfs/libfs"
 	"github.com/keybase/client/go/kbfs/libhttpserver"
 	"github.com/keybase/client/go/kbfs/libkbfs"
+	"github.com/keybase/client/go/kbfs/libsftp"
 	"github.com/keybase/client/go/kbfs/search"
 	"github.com/keybase/client/go/kbfs/tlf"
 	"github.com/keybase/client/go/kbfs/tlfhandle"
@@ -158,12 +160,16 @@ type SimpleFS struct {
 	subscribeToEmptyTlf         string
 
 	localHTTPServer *libhttpserver.Server
+	sftpGateway     *libsftp.Server
 
 	subscriptionNotifier libkbfs.SubscriptionNotifier
 
 	downloadManager *downloadManager
 	uploadManager   *uploadManager
 
+	// opJournal persists restartable async ops across service restarts.
+	opJournal *opJournal
+
 	httpClient *http.Client
 }
 
@@ -228,6 +234,27 @@ func newSimpleFS(appStateUpdater env.AppStateUpdater, config libkbfs.Config) *Si
 		}
 	}
 
+	// The SFTP gateway is opt-in, for environments where FUSE is
+	// unavailable. Set KEYBASE_SFTP_GATEWAY=1 for a random loopback
+	// port, or to an explicit loopback host:port. The address and
+	// per-process token land in sftp_gateway.json under the storage
+	// root.
+	var sftpGateway *libsftp.Server
+	if gatewayAddr := os.Getenv("KEYBASE_SFTP_GATEWAY"); len(gatewayAddr) > 0 {
+		if gatewayAddr == "1" {
+			gatewayAddr = libsftp.DefaultAddr
+		}
+		sftpGateway, err = libsftp.New(config, gatewayAddr)
+		if err != nil {
+			log.Warning("Couldn't start SFTP gateway: %+v", err)
+		} else {
+			log.Info("SFTP gateway listening on %s", sftpGateway.Address())
+			if err := writeSftpGatewayInfo(config, sftpGateway); err != nil {
+				log.Warning("Couldn't write SFTP gateway info file: %+v", err)
+			}
+		}
+	}
+
 	var indexer *search.Indexer
 	if config.Mode().IndexingEnabled() {
 		newIndexer, err := search.NewIndexer(config)
@@ -249,7 +276,9 @@ func newSimpleFS(appStateUpdater env.AppStateUpdater, config libkbfs.Config) *Si
 		idd:                  libkbfs.NewImpatientDebugDumperForForcedDumps(config),
 		indexer:              indexer,
 		localHTTPServer:      localHTTPServer,
+		sftpGateway:          sftpGateway,
 		subscriptionNotifier: subscriptionNotifier{config},
+		opJournal:            newOpJournal(config.StorageRoot(), log),
 		httpClient:           &http.Client{},
 	}
 	k.downloadManager = newDownloadManager(k)
@@ -263,6 +292,8 @@ func NewSimpleFS(
 	iface keybase1.SimpleFSInterface,
 	shutdownFn func(context.Context) error) {
 	simpleFS := newSimpleFS(appStateUpdater, config)
+	// Pick up any long-running ops a previous incarnation left behind.
+	go simpleFS.resumeJournaledOps(context.Background())
 	return simpleFS, simpleFS.Shutdown
 }
 
@@ -351,6 +382,28 @@ func splitPathFromKbfsPath(path keybase1.Path) ([]string, error) {
 	return strings.Split(raw, `/`), nil
 }
 
+// rebuildKbfsPath replaces the path portion of a remote path, keeping the
+// variant and any identify behavior or archived params.
+func rebuildKbfsPath(path keybase1.Path, elems []string) (keybase1.Path, error) {
+	pt, err := path.PathType()
+	if err != nil {
+		return keybase1.Path{}, err
+	}
+	raw := "/" + strings.Join(elems, "/")
+	switch pt {
+	case keybase1.PathType_KBFS:
+		p := path.Kbfs()
+		p.Path = raw
+		return keybase1.NewPathWithKbfs(p), nil
+	case keybase1.PathType_KBFS_ARCHIVED:
+		p := path.KbfsArchived()
+		p.Path = raw
+		return keybase1.NewPathWithKbfsArchived(p), nil
+	default:
+		return keybase1.Path{}, errOnlyRemotePathSupported
+	}
+}
+
 // remoteTlfAndPath decodes a remote path for us.
 func remoteTlfAndPath(path keybase1.Path) (
 	t tlf.Type, tlfName, middlePath, finalElem string, err error) {
@@ -465,6 +518,15 @@ func (k *SimpleFS) getFSWithMaybeCreate(
 		if err != nil {
 			return nil, "", err
 		}
+		// Resolve mount namespace aliases before any folderlist parsing.
+		if resolved := libkbfs.ResolveMountAliasElems(
+			k.config, ps); len(resolved) != len(ps) {
+			ps = resolved
+			path, err = rebuildKbfsPath(path, ps)
+			if err != nil {
+				return nil, "", err
+			}
+		}
 		if len(ps) < 2 || len(ps) < 3 && strings.HasPrefix(ps[0], ".kbfs_") {
 			fs = libfs.NewRootFS(k.config)
 			if len(ps) == 1 {
@@ -606,8 +668,8 @@ func (k *SimpleFS) setStat(
 	switch {
 	case fi.IsDir():
 		t = data.Dir
-	case fi.Mode()&0100 != 0:
-		t = data.Exec
+	// Symlinks must be checked before the exec bit, since on most
+	// platforms a symlink's mode includes 0777 permission bits.
 	case fi.Mode()&os.ModeSymlink != 0:
 		t = data.Sym
 
@@ -616,6 +678,8 @@ func (k *SimpleFS) setStat(
 			return err
 		}
 		de.SymlinkTarget = link
+	case fi.Mode()&0100 != 0:
+		t = data.Exec
 	}
 	de.DirentType = deTy2Ty(t)
 	de.Writable = (fi.Mode()&0222 != 0)
@@ -657,6 +721,7 @@ func (k *SimpleFS) startOp(ctx context.Context, opid keybase1.OpID,
 	// ignore error, this is just for logging.
 	descBS, _ := json.Marshal(desc)
 	k.vlog.CLogf(ctx, libkb.VLog1, "start %X %s", opid, descBS)
+	k.opJournal.record(opid, opType, desc)
 	newCtx, err := k.startOpWrapContext(ctx)
 	return newCtx, w, err
 }
@@ -670,6 +735,7 @@ func (k *SimpleFS) doneOp(ctx context.Context, opid keybase1.OpID, w *inprogress
 
 	w.done <- err
 	close(w.done)
+	k.opJournal.remove(opid)
 	k.log.CDebugf(ctx, "done op %X, status=%+v", opid, err)
 	if ctx != nil {
 		err := libcontext.CleanupCancellationDelayer(ctx)
@@ -1200,7 +1266,39 @@ func (k *SimpleFS) SimpleFSListFavorites(ctx context.Context) (
 	}
 
 	k.config.GetPerfLog().CDebugf(ctx, "GetFavorites simplefs.SimpleFSListFavorites")
-	return k.config.KBFSOps().GetFavoritesAll(ctx)
+	res, err := k.config.KBFSOps().GetFavoritesAll(ctx)
+	if err != nil {
+		return keybase1.FavoritesResult{}, err
+	}
+	if db := k.config.GetSettingsDB(); db != nil {
+		if prefs, err := db.FavoritePrefs(ctx); err == nil && len(prefs) > 0 {
+			res.FavoriteFolders = libkbfs.ApplyFavoritePrefs(
+				prefs, res.FavoriteFolders, false)
+		}
+	}
+	return res, nil
+}
+
+// SetFavoritePrefs stores tags, custom ordering, and hidden flags for a
+// favorite folder and notifies favorites subscribers.
+func (k *SimpleFS) SetFavoritePrefs(
+	ctx context.Context, folder keybase1.Folder,
+	prefs libkbfs.FavoritePrefs) (err error) {
+	defer func() {
+		k.log.CDebugf(ctx, "SetFavoritePrefs folder=%s prefs=%+v err=%+v",
+			folder.Name, prefs, err)
+	}()
+	db := k.config.GetSettingsDB()
+	if db == nil {
+		return libkbfs.ErrNoSettingsDB
+	}
+	err = db.SetFavoritePrefs(ctx, libkbfs.FavoritePrefsKey(folder), prefs)
+	if err != nil {
+		return err
+	}
+	k.config.SubscriptionManagerPublisher().PublishChange(
+		keybase1.SubscriptionTopic_FAVORITES)
+	return nil
 }
 
 func recursiveByteAndFileCount(fs billy.Filesystem) (
@@ -1300,6 +1398,20 @@ func (k *SimpleFS) doCopyFromSource(
 		return dstFS.MkdirAll(finalDstElem, 0755)
 	}
 
+	if srcFI.Mode()&os.ModeSymlink != 0 {
+		// Recreate the symlink instead of copying through it, so links
+		// survive a round trip between KBFS and a local directory.
+		target, err := srcFS.Readlink(srcFI.Name())
+		if err != nil {
+			return err
+		}
+		if overwriteExistingFiles {
+			// Symlink fails on an existing link; match O_TRUNC semantics.
+			_ = dstFS.Remove(finalDstElem)
+		}
+		return dstFS.Symlink(target, finalDstElem)
+	}
+
 	src, err := srcFS.Open(srcFI.Name())
 	if err != nil {
 		return err
@@ -1310,7 +1422,11 @@ func (k *SimpleFS) doCopyFromSource(
 	if overwriteExistingFiles {
 		mode = os.O_RDWR | os.O_CREATE | os.O_TRUNC
 	}
-	dst, err := dstFS.OpenFile(finalDstElem, mode, 0600)
+	perm := os.FileMode(0600)
+	if srcFI.Mode()&0100 != 0 {
+		perm = 0700
+	}
+	dst, err := dstFS.OpenFile(finalDstElem, mode, perm)
 	if err != nil {
 		return err
 	}
@@ -1330,7 +1446,67 @@ func (k *SimpleFS) doCopyFromSource(
 		&progressWriter{k, opID, dst},
 		&progressReader{k, opID, src},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return finalizeCopiedFile(dstPath, dstFS, finalDstElem, srcFI)
+}
+
+// finalizeCopiedFile carries the source's exec bit and mtime over to a
+// newly copied file, so code trees round-trip through `keybase fs`
+// without losing permissions or timestamps.
+func finalizeCopiedFile(
+	dstPath keybase1.Path, dstFS billy.Filesystem, finalDstElem string,
+	srcFI os.FileInfo) error {
+	perm := os.FileMode(0644)
+	if srcFI.Mode()&0100 != 0 {
+		perm = 0755
+	}
+	if pathType, _ := dstPath.PathType(); pathType == keybase1.PathType_LOCAL {
+		// The local billy FS doesn't support Chmod/Chtimes; go straight
+		// to the OS.
+		local := dstPath.Local()
+		if err := os.Chmod(local, perm); err != nil {
+			return err
+		}
+		return os.Chtimes(local, srcFI.ModTime(), srcFI.ModTime())
+	}
+	changeFS, ok := dstFS.(billy.Change)
+	if !ok {
+		return nil
+	}
+	if err := changeFS.Chmod(finalDstElem, perm); err != nil {
+		return err
+	}
+	return changeFS.Chtimes(finalDstElem, srcFI.ModTime(), srcFI.ModTime())
+}
+
+// scanContentForUpload runs the locally configured content scanner (the
+// same hook the chat attachment uploader uses) when copying from a local
+// path into KBFS. Copies in other directions are not scanned.
+func (k *SimpleFS) scanContentForUpload(
+	ctx context.Context, srcPath, destPath keybase1.Path) error {
+	srcType, err := srcPath.PathType()
+	if err != nil || srcType != keybase1.PathType_LOCAL {
+		return nil
+	}
+	destType, err := destPath.PathType()
+	if err != nil || destType != keybase1.PathType_KBFS {
+		return nil
+	}
+	env := k.config.KbContext().GetEnv()
+	if env == nil {
+		return nil
+	}
+	bypassed, err := libkb.ScanOutgoingContent(env, srcPath.Local())
+	if err != nil {
+		k.log.CDebugf(ctx, "content scan blocked %s: %s", srcPath.Local(), err)
+		return err
+	}
+	if bypassed {
+		k.log.CWarningf(ctx, "content scan bypassed for %s", srcPath.Local())
+	}
+	return nil
 }
 
 func (k *SimpleFS) doCopy(
@@ -1338,11 +1514,15 @@ func (k *SimpleFS) doCopy(
 	srcPath, destPath keybase1.Path, overwriteExistingFiles bool) (err error) {
 	// Note this is also used by move, so if this changes update SimpleFSMove
 	// code also.
+	if err := k.scanContentForUpload(ctx, srcPath, destPath); err != nil {
+		return err
+	}
 	srcFS, finalSrcElem, err := k.getFS(ctx, srcPath)
 	if err != nil {
 		return err
 	}
-	srcFI, err := srcFS.Stat(finalSrcElem)
+	// Lstat, not Stat, so symlinks are copied as symlinks.
+	srcFI, err := srcFS.Lstat(finalSrcElem)
 	if err != nil {
 		return err
 	}
@@ -1417,12 +1597,17 @@ func pathAppend(p keybase1.Path, leaf string) keybase1.Path {
 
 func (k *SimpleFS) doCopyRecursive(ctx context.Context,
 	opID keybase1.OpID, src, dest keybase1.Path, overwriteExistingFiles bool) error {
+	// The scanner gets the directory root and is expected to recurse
+	// itself if it cares about the contents.
+	if err := k.scanContentForUpload(ctx, src, dest); err != nil {
+		return err
+	}
 	// Get the full byte/file count.
 	srcFS, finalSrcElem, err := k.getFSIfExists(ctx, src)
 	if err != nil {
 		return err
 	}
-	srcFI, err := srcFS.Stat(finalSrcElem)
+	srcFI, err := srcFS.Lstat(finalSrcElem)
 	if err != nil {
 		return err
 	}
@@ -1464,7 +1649,7 @@ func (k *SimpleFS) doCopyRecursive(ctx context.Context,
 		node := nodes[len(nodes)-1]
 		nodes = nodes[:len(nodes)-1]
 
-		srcFI, err := node.srcFS.Stat(node.srcFinalElem)
+		srcFI, err := node.srcFS.Lstat(node.srcFinalElem)
 		if err != nil {
 			return err
 		}
@@ -1476,7 +1661,6 @@ func (k *SimpleFS) doCopyRecursive(ctx context.Context,
 			return err
 		}
 
-		// TODO symlinks
 		if srcFI.IsDir() {
 			fis, err := node.srcFS.ReadDir(srcFI.Name())
 			if err != nil {
@@ -2300,6 +2484,8 @@ func (k *SimpleFS) SimpleFSClose(ctx context.Context, opid keybase1.OpID) (err e
 // Returns before cancellation is guaranteeded to be done - that
 // may take some time. Currently always returns nil.
 func (k *SimpleFS) SimpleFSCancel(_ context.Context, opid keybase1.OpID) error {
+	// a cancelled op shouldn't come back after a restart
+	k.opJournal.remove(opid)
 	k.lock.Lock()
 	defer k.lock.Unlock()
 	delete(k.handles, opid)
@@ -2393,6 +2579,16 @@ func (k *SimpleFS) SimpleFSWait(ctx context.Context, opid keybase1.OpID) error {
 func (k *SimpleFS) SimpleFSDumpDebuggingInfo(ctx context.Context) error {
 	ctx = k.makeContext(ctx)
 	k.idd.ForceDump(ctx)
+	// Include the subscription registrations, since leaked subscriptions
+	// from GUI clients are otherwise invisible.
+	for _, dump := range k.config.DumpSubscriptionManagers() {
+		out, err := json.Marshal(dump)
+		if err != nil {
+			k.log.CDebugf(ctx, "Couldn't marshal subscription dump: %+v", err)
+			continue
+		}
+		k.log.CDebugf(ctx, "Subscriptions for client %s: %s", dump.ClientID, out)
+	}
 	return nil
 }
 
@@ -3191,9 +3387,11 @@ func (k *SimpleFS) SimpleFSSubscribePath(
 		return err
 	}
 	interval := time.Second * time.Duration(arg.DeduplicateIntervalSecond)
+	// The RPC has no way to ask for trailing-edge coalescing, so keep
+	// the historical leading-edge behavior here.
 	return k.subscriptionManager(arg.ClientID).SubscribePath(
 		ctx, libkbfs.SubscriptionID(arg.SubscriptionID),
-		arg.KbfsPath, arg.Topic, &interval)
+		arg.KbfsPath, arg.Topic, &interval, false)
 }
 
 // SimpleFSSubscribeNonPath implements the SimpleFSInterface.
@@ -3239,6 +3437,21 @@ func (k *SimpleFS) SimpleFSCancelDownload(
 	return k.downloadManager.cancelDownload(ctx, downloadID)
 }
 
+// PauseDownload stops a download's copying without removing it from the
+// download queue. There's no RPC for this yet; it's exported for
+// in-process callers.
+func (k *SimpleFS) PauseDownload(
+	ctx context.Context, downloadID string) (err error) {
+	return k.downloadManager.pauseDownload(ctx, downloadID)
+}
+
+// ResumeDownload restarts a download previously stopped by
+// PauseDownload.
+func (k *SimpleFS) ResumeDownload(
+	ctx context.Context, downloadID string) (err error) {
+	return k.downloadManager.resumeDownload(ctx, downloadID)
+}
+
 // SimpleFSDismissDownload implements the SimpleFSInterface.
 func (k *SimpleFS) SimpleFSDismissDownload(
 	ctx context.Context, downloadID string) (err error) {
@@ -3563,8 +3776,29 @@ func (k *SimpleFS) SimpleFSCancelJournalUploads(
 
 // Shutdown shuts down SimpleFS.
 func (k *SimpleFS) Shutdown(ctx context.Context) error {
+	if k.sftpGateway != nil {
+		k.sftpGateway.Shutdown()
+	}
 	if k.indexer == nil {
 		return nil
 	}
 	return k.indexer.Shutdown(ctx)
 }
+
+// writeSftpGatewayInfo drops the gateway's address and connection
+// token where a local client can find them, readable only by the
+// current user.
+func writeSftpGatewayInfo(config libkbfs.Config, gateway *libsftp.Server) error {
+	info, err := json.Marshal(struct {
+		Address string `json:"address"`
+		Token   string `json:"token"`
+	}{
+		Address: gateway.Address(),
+		Token:   gateway.Token(),
+	})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(
+		filepath.Join(config.StorageRoot(), "sftp_gateway.json"), info, 0600)
+}
@@ -679,6 +679,56 @@ func TestCopyToRemote(t *testing.T) {
 		string(readRemoteFile(ctx, t, sfs, pathAppend(path2, "test1.txt"))))
 }
 
+func simpleFSCopyAndWait(
+	ctx context.Context, t *testing.T, sfs *SimpleFS,
+	src, dest keybase1.Path) {
+	opid, err := sfs.SimpleFSMakeOpid(ctx)
+	require.NoError(t, err)
+	err = sfs.SimpleFSCopy(ctx, keybase1.SimpleFSCopyArg{
+		OpID: opid,
+		Src:  src,
+		Dest: dest,
+	})
+	require.NoError(t, err)
+	err = sfs.SimpleFSWait(ctx, opid)
+	require.NoError(t, err)
+}
+
+func TestCopyExecutableBitRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	sfs := newSimpleFS(env.EmptyAppStateUpdater{}, libkbfs.MakeTestConfigOrBust(t, "jdoe"))
+	defer closeSimpleFS(ctx, t, sfs)
+
+	pathRemote := keybase1.NewPathWithKbfsPath(`/private/jdoe`)
+
+	tempdir, err := ioutil.TempDir("", "simpleFstest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+	localScript := filepath.Join(tempdir, "run.sh")
+	err = ioutil.WriteFile(localScript, []byte("#!/bin/sh\n"), 0755)
+	require.NoError(t, err)
+
+	simpleFSCopyAndWait(ctx, t, sfs,
+		keybase1.NewPathWithLocal(filepath.ToSlash(localScript)),
+		pathAppend(pathRemote, "run.sh"))
+
+	// The exec bit should survive the upload.
+	de, err := sfs.SimpleFSStat(ctx, keybase1.SimpleFSStatArg{
+		Path: pathAppend(pathRemote, "run.sh"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, keybase1.DirentType_EXEC, de.DirentType)
+
+	// And the download.
+	localCopy := filepath.Join(tempdir, "run-copy.sh")
+	simpleFSCopyAndWait(ctx, t, sfs,
+		pathAppend(pathRemote, "run.sh"),
+		keybase1.NewPathWithLocal(filepath.ToSlash(localCopy)))
+	fi, err := os.Stat(localCopy)
+	require.NoError(t, err)
+	require.NotZero(t, fi.Mode()&0100)
+}
+
 func writeRemoteFile(ctx context.Context, t *testing.T, sfs *SimpleFS, path keybase1.Path, data []byte) {
 	opid, err := sfs.SimpleFSMakeOpid(ctx)
 	require.NoError(t, err)
@@ -1517,6 +1517,52 @@ func (m *MockChatHelper) JourneycardDebugState(ctx context.Context, uid gregor1.
 	return "", fmt.Errorf("JourneycardDebugState not implemented on mock")
 }
 
+func (m *MockChatHelper) ListMessageRequests(ctx context.Context, uid gregor1.UID) ([]chat1.ConversationLocal, error) {
+	return nil, fmt.Errorf("ListMessageRequests not implemented on mock")
+}
+
+func (m *MockChatHelper) MarkAllAsRead(ctx context.Context, uid gregor1.UID) (int, error) {
+	return 0, fmt.Errorf("MarkAllAsRead not implemented on mock")
+}
+
+func (m *MockChatHelper) PutChatSavedSearch(ctx context.Context, name, query string,
+	unreadOnly, teamsOnly, hasAttachment bool) error {
+	return fmt.Errorf("PutChatSavedSearch not implemented on mock")
+}
+
+func (m *MockChatHelper) RemoveChatSavedSearch(ctx context.Context, name string) error {
+	return fmt.Errorf("RemoveChatSavedSearch not implemented on mock")
+}
+
+func (m *MockChatHelper) ListChatSavedSearches(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("ListChatSavedSearches not implemented on mock")
+}
+
+func (m *MockChatHelper) RunChatSavedSearch(ctx context.Context, uid gregor1.UID,
+	name string) ([]chat1.ConversationLocal, error) {
+	return nil, fmt.Errorf("RunChatSavedSearch not implemented on mock")
+}
+
+func (m *MockChatHelper) StartAttachmentRekey(ctx context.Context, uid gregor1.UID,
+	age time.Duration, bwKBps int) error {
+	return fmt.Errorf("StartAttachmentRekey not implemented on mock")
+}
+
+func (m *MockChatHelper) StopAttachmentRekey(ctx context.Context) {}
+
+func (m *MockChatHelper) AttachmentRekeyStatus(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("AttachmentRekeyStatus not implemented on mock")
+}
+
+func (m *MockChatHelper) RecentChatPartners(ctx context.Context, uid gregor1.UID) (
+	[]string, []string, error) {
+	return nil, nil, fmt.Errorf("RecentChatPartners not implemented on mock")
+}
+
+func (m *MockChatHelper) AcceptMessageRequest(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) error {
+	return fmt.Errorf("AcceptMessageRequest not implemented on mock")
+}
+
 func (m *MockChatHelper) NewConversation(ctx context.Context, uid gregor1.UID, tlfName string,
 	topicName *string, topicType chat1.TopicType, membersType chat1.ConversationMembersType,
 	vis keybase1.TLFVisibility) (chat1.ConversationLocal, bool, error) {
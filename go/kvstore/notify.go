@@ -0,0 +1,40 @@
+package kvstore
+
+import (
+	"sync"
+
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// ChangeListener is told whenever this client writes or deletes a
+// kvstore entry, so in-process consumers (bots, background tasks) can
+// react to changes without polling. A revision of 0 would never be
+// written; deletes report the revision that tombstoned the entry.
+// Callbacks run on the storing goroutine and should return quickly.
+type ChangeListener interface {
+	KVStoreChanged(entryID keybase1.KVEntryID, revision int)
+}
+
+var changeListenersMu sync.Mutex
+var changeListeners []ChangeListener
+
+// RegisterChangeListener adds a listener for local kvstore writes and
+// deletes. There is no way to unregister; listeners live as long as
+// the process.
+func RegisterChangeListener(l ChangeListener) {
+	changeListenersMu.Lock()
+	defer changeListenersMu.Unlock()
+	changeListeners = append(changeListeners, l)
+}
+
+// NotifyChange fans a successful write or delete out to all registered
+// listeners.
+func NotifyChange(entryID keybase1.KVEntryID, revision int) {
+	changeListenersMu.Lock()
+	listeners := make([]ChangeListener, len(changeListeners))
+	copy(listeners, changeListeners)
+	changeListenersMu.Unlock()
+	for _, l := range listeners {
+		l.KVStoreChanged(entryID, revision)
+	}
+}
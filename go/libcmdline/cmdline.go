@@ -266,6 +266,9 @@ func (p CommandLine) GetAPITimeout() (time.Duration, bool) {
 	}
 	return ret, true
 }
+func (p CommandLine) GetClockSkewTolerance() (time.Duration, bool) {
+	return 0, false
+}
 func (p CommandLine) GetGpgOptions() []string {
 	var ret []string
 	s := p.GetGString("gpg-options")
@@ -291,6 +294,14 @@ func (p CommandLine) GetCodeSigningKIDs() []string {
 	return p.getKIDs("code-signing-kids")
 }
 
+func (p CommandLine) GetAPIResponseSigningKIDs() []string {
+	return nil
+}
+
+func (p CommandLine) GetSignedAPIResponses() (bool, bool) {
+	return false, false
+}
+
 func (p CommandLine) GetUserCacheMaxAge() (time.Duration, bool) {
 	ret, err := p.GetGDuration("user-cache-maxage")
 	if err != nil {
@@ -0,0 +1,73 @@
+package libkb
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// AccountManager parks the in-memory state of logged-in accounts across
+// user switches, keyed by UID. When the active user changes, the
+// outgoing account's ActiveDevice snapshot — device keys plus the warm
+// caches (passphrase stream, secret syncer) — is parked here, and any
+// state previously parked for the incoming account is restored. A round
+// trip between accounts therefore doesn't cost a full logout/login or
+// a re-derivation of the passphrase stream. Secrets on disk were
+// already namespaced per username in the secret store, so only the
+// in-memory side needs managing.
+type AccountManager struct {
+	sync.Mutex
+	parked map[keybase1.UID]*ActiveDevice
+}
+
+func NewAccountManager() *AccountManager {
+	return &AccountManager{
+		parked: make(map[keybase1.UID]*ActiveDevice),
+	}
+}
+
+// Park snapshots ad for later restoration, if it holds a valid account.
+func (am *AccountManager) Park(m MetaContext, ad *ActiveDevice) {
+	if ad == nil || !ad.Valid() {
+		return
+	}
+	snap, err := ad.snapshot(m)
+	if err != nil {
+		m.Debug("AccountManager#Park: cannot snapshot active device: %s", err)
+		return
+	}
+	am.Lock()
+	defer am.Unlock()
+	am.parked[snap.UID()] = snap
+}
+
+// Restore hands back the state parked for uid, removing it from the
+// manager, or nil if there is none.
+func (am *AccountManager) Restore(uid keybase1.UID) *ActiveDevice {
+	am.Lock()
+	defer am.Unlock()
+	ad := am.parked[uid]
+	delete(am.parked, uid)
+	return ad
+}
+
+// Forget drops any state parked for uid. Called on logout, so an
+// explicitly logged-out account can't be warm-switched back in.
+func (am *AccountManager) Forget(uid keybase1.UID) {
+	am.Lock()
+	defer am.Unlock()
+	delete(am.parked, uid)
+}
+
+// List returns the UIDs with parked state, sorted, for diagnostics.
+func (am *AccountManager) List() []keybase1.UID {
+	am.Lock()
+	defer am.Unlock()
+	uids := make([]keybase1.UID, 0, len(am.parked))
+	for uid := range am.parked {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids
+}
@@ -111,6 +111,53 @@ func (a *ActiveDevice) Copy(m MetaContext, src *ActiveDevice) error {
 	return a.Set(m, uv, deviceID, sigKey, encKey, name, ctime, keychainMode)
 }
 
+// snapshot returns a new ActiveDevice carrying everything Copy does
+// plus the warm caches (passphrase stream, secret syncer, provisioning
+// key) that Copy deliberately resets. The AccountManager uses it to
+// park an account's state across a user switch.
+func (a *ActiveDevice) snapshot(m MetaContext) (*ActiveDevice, error) {
+	ret := NewActiveDevice()
+	if err := ret.Copy(m, a); err != nil {
+		return nil, err
+	}
+	a.RLock()
+	passphrase := a.passphrase
+	secretSyncer := a.secretSyncer
+	provisioningKey := a.provisioningKey
+	a.RUnlock()
+	ret.Lock()
+	ret.passphrase = passphrase
+	ret.secretSyncer = secretSyncer
+	ret.provisioningKey = provisioningKey
+	ret.Unlock()
+	return ret, nil
+}
+
+// adoptCachesFrom moves the warm caches from a parked ActiveDevice into
+// a, which must already be set to the same user; the inverse of
+// snapshot, for restoring a parked account.
+func (a *ActiveDevice) adoptCachesFrom(parked *ActiveDevice) {
+	if parked == nil || !a.UID().Equal(parked.UID()) {
+		return
+	}
+	parked.RLock()
+	passphrase := parked.passphrase
+	secretSyncer := parked.secretSyncer
+	provisioningKey := parked.provisioningKey
+	parked.RUnlock()
+	a.Lock()
+	defer a.Unlock()
+	if passphrase != nil {
+		a.passphrase = passphrase
+	}
+	if secretSyncer != nil {
+		a.secretSyncer = secretSyncer
+	}
+	if provisioningKey != nil {
+		a.provisioningKey = provisioningKey
+	}
+}
+
 func (a *ActiveDevice) SetOrClear(m MetaContext, a2 *ActiveDevice) error {
 	// Always clear, if we are also setting we set all new values.
 	err := a.Clear()
@@ -678,20 +725,33 @@ func (a *ActiveDevice) ClearPassphraseStreamCache() {
 	a.passphrase = nil
 }
 
+// ClearPassphraseStreamCacheIfStale drops the cached passphrase stream
+// only if its generation predates ppgen, and reports whether it did.
+// Unlike ClearPassphraseStreamCache, this is safe to call concurrently
+// with an unlock that may have just refreshed the cache with the new
+// passphrase; the fresh entry survives.
+func (a *ActiveDevice) ClearPassphraseStreamCacheIfStale(ppgen PassphraseGeneration) bool {
+	a.RLock()
+	c := a.passphrase
+	a.RUnlock()
+	return c.ClearIfStale(ppgen)
+}
+
 func (a *ActiveDevice) ClearPassphraseStreamCacheIfOutdated(mctx MetaContext) error {
 	pps := a.PassphraseStream()
 	if pps == nil {
 		return nil
 	}
 
-	outdated, err := pps.SyncAndCheckIfOutdated(mctx)
+	serverGen, err := pps.SyncServerGeneration(mctx)
 	if err != nil {
 		return err
 	}
 
-	if outdated {
-		a.ClearPassphraseStreamCache()
-	}
+	// Scoped invalidation: only an entry older than the server's
+	// generation is dropped, so a concurrent unlock that already cached
+	// the new stream isn't wiped.
+	a.ClearPassphraseStreamCacheIfStale(serverGen)
 	return nil
 }
 
@@ -55,6 +55,7 @@ type Requester interface {
 	fixHeaders(m MetaContext, arg APIArg, req *http.Request, nist *NIST) error
 	getCli(needSession bool) (*Client, error)
 	consumeHeaders(m MetaContext, resp *http.Response, nist *NIST) error
+	verifyResponse(m MetaContext, arg APIArg, resp *http.Response, body []byte) error
 	isExternal() bool
 }
 
@@ -348,6 +349,10 @@ func doRequestShared(m MetaContext, api Requester, arg APIArg, req *http.Request
 			return nil, finisher, nil, err
 		}
 
+		// Snapshot the raw body before the decoder consumes it, for
+		// signed-response verification below.
+		rawBody := buf.Bytes()
+
 		reader := newCountingReader(&buf)
 		decoder := json.NewDecoder(reader)
 		var obj interface{}
@@ -364,6 +369,10 @@ func doRequestShared(m MetaContext, api Requester, arg APIArg, req *http.Request
 			b, _ := json.MarshalIndent(obj, "", "  ")
 			m.Debug("| full reply: %s", b)
 		}
+
+		if err = api.verifyResponse(m, arg, internalResp, rawBody); err != nil {
+			return nil, finisher, nil, err
+		}
 	}
 
 	return internalResp, finisher, jw, nil
@@ -526,39 +535,46 @@ func (a *InternalAPIEngine) sessionArgs(m MetaContext, arg APIArg) (tok, csrf st
 
 func (a *InternalAPIEngine) isExternal() bool { return false }
 
-func computeCriticalClockSkew(g *GlobalContext, s string) time.Duration {
-	var ret time.Duration
+// computeClockSkew parses the server's Date header and returns the raw
+// offset from our clock, plus a critical value that is only nonzero when
+// the offset exceeds the configured tolerance.
+func computeClockSkew(g *GlobalContext, s string) (measured time.Duration, critical time.Duration) {
 	if s == "" {
-		return ret
+		return measured, critical
 	}
 	serverNow, err := time.Parse(time.RFC1123, s)
 
 	if err != nil {
 		g.Log.Warning("Failed to parse server time: %s", err)
-		return ret
+		return measured, critical
 	}
 	ourNow := g.Clock().Now()
-	diff := serverNow.Sub(ourNow)
-	if diff > CriticalClockSkewLimit || diff < -1*CriticalClockSkewLimit {
-		ret = diff
+	measured = serverNow.Sub(ourNow)
+	tolerance := g.Env.GetClockSkewTolerance()
+	if measured > tolerance || measured < -1*tolerance {
+		critical = measured
 	}
-	return ret
+	return measured, critical
 }
 
-// If the local clock is within a reasonable offset of the server's
-// clock, we'll get 0.  Otherwise, we set the skew accordingly. Safe
-// to set this every time.
+// If the local clock is within the configured tolerance of the server's
+// clock, the critical skew will be 0. Otherwise, we set the skew
+// accordingly. The raw measurement is always recorded. Safe to set this
+// every time.
 func (a *InternalAPIEngine) updateCriticalClockSkewWarning(resp *http.Response) {
 
 	g := a.G()
+	measuredClockSkew, criticalClockSkewDur := computeClockSkew(a.G(), resp.Header.Get("Date"))
+	criticalClockSkew := int64(criticalClockSkewDur)
 	g.oodiMu.RLock()
-	criticalClockSkew := int64(computeCriticalClockSkew(a.G(), resp.Header.Get("Date")))
-	needUpdate := (criticalClockSkew != a.G().outOfDateInfo.CriticalClockSkew)
+	needUpdate := (criticalClockSkew != a.G().outOfDateInfo.CriticalClockSkew ||
+		measuredClockSkew != g.measuredClockSkew)
 	g.oodiMu.RUnlock()
 
 	if needUpdate {
 		g.oodiMu.Lock()
 		g.outOfDateInfo.CriticalClockSkew = criticalClockSkew
+		g.measuredClockSkew = measuredClockSkew
 		g.oodiMu.Unlock()
 	}
 }
@@ -592,6 +608,7 @@ func (a *InternalAPIEngine) consumeHeaders(m MetaContext, resp *http.Response, n
 	}
 
 	a.updateCriticalClockSkewWarning(resp)
+	observeRateLimitHeaders(m, resp.Header.Get)
 
 	if len(upgradeTo) > 0 || len(customMessage) > 0 {
 		now := time.Now()
@@ -621,6 +638,46 @@ func (a *InternalAPIEngine) consumeHeaders(m MetaContext, resp *http.Response, n
 	return
 }
 
+// criticalAPIEndpoints are the endpoints that must carry a valid
+// signature from a pinned key when signed-response verification is on.
+// They cover the data the client otherwise takes on server trust:
+// merkle roots, team server-trust data, and user lookups.
+var criticalAPIEndpoints = map[string]bool{
+	"merkle/root": true,
+	"merkle/path": true,
+	"user/lookup": true,
+	"user/load":   true,
+	"team/get":    true,
+}
+
+const serverSigHeader = "X-Keybase-Server-Sig"
+
+func (a *InternalAPIEngine) verifyResponse(m MetaContext, arg APIArg, resp *http.Response, body []byte) error {
+	if !m.G().Env.GetSignedAPIResponses() || !criticalAPIEndpoints[arg.Endpoint] {
+		return nil
+	}
+	sig := resp.Header.Get(serverSigHeader)
+	if sig == "" {
+		return NewAPIResponseSignatureError(arg.Endpoint, "response carried no %s header", serverSigHeader)
+	}
+	kids := m.G().Env.GetAPIResponseSigningKIDs()
+	if len(kids) == 0 {
+		return NewAPIResponseSignatureError(arg.Endpoint, "no pinned response signing keys configured")
+	}
+	for _, kid := range kids {
+		key, err := ImportKeypairFromKID(kid)
+		if err != nil {
+			m.Debug("verifyResponse: skipping unusable pinned KID %s: %s", kid, err)
+			continue
+		}
+		if _, err := key.VerifyString(m.G().Log, sig, body); err == nil {
+			m.Debug("verifyResponse: %s verified with pinned key %s", arg.Endpoint, kid)
+			return nil
+		}
+	}
+	return NewAPIResponseSignatureError(arg.Endpoint, "signature did not verify with any pinned key")
+}
+
 func (a *InternalAPIEngine) fixHeaders(m MetaContext, arg APIArg, req *http.Request, nist *NIST) error {
 
 	if nist != nil {
@@ -956,6 +1013,10 @@ func (api *ExternalAPIEngine) consumeHeaders(m MetaContext, resp *http.Response,
 	return nil
 }
 
+func (api *ExternalAPIEngine) verifyResponse(m MetaContext, arg APIArg, resp *http.Response, body []byte) error {
+	return nil
+}
+
 func (api *ExternalAPIEngine) isExternal() bool { return true }
 
 func (api *ExternalAPIEngine) DoRequest(m MetaContext,
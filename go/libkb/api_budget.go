@@ -0,0 +1,113 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// APIBudget is a snapshot of the server-side rate limit state for one
+// endpoint category, as reported in API response headers.
+type APIBudget struct {
+	Category  string    `json:"category"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Exhausted returns true if the budget is running low enough that a
+// caller driving the API programmatically should start throttling.
+func (b APIBudget) Exhausted() bool {
+	return b.Limit > 0 && b.Remaining*10 < b.Limit
+}
+
+// APIBudgetTracker records the most recent rate limit state the server
+// has reported per endpoint category. It is fed by the internal API
+// engine as responses come back and can be queried at any time.
+type APIBudgetTracker struct {
+	sync.Mutex
+	categories map[string]APIBudget
+	lastWarn   map[string]time.Time
+}
+
+func NewAPIBudgetTracker() *APIBudgetTracker {
+	return &APIBudgetTracker{
+		categories: make(map[string]APIBudget),
+		lastWarn:   make(map[string]time.Time),
+	}
+}
+
+// Observe records rate limit state for a category. It returns true if
+// the budget is low enough that a warning is due, rate-limited to one
+// warning per category per minute.
+func (t *APIBudgetTracker) Observe(category string, limit, remaining int, reset time.Time) (warn bool) {
+	t.Lock()
+	defer t.Unlock()
+	budget := APIBudget{
+		Category:  category,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+		UpdatedAt: time.Now(),
+	}
+	t.categories[category] = budget
+	if !budget.Exhausted() {
+		return false
+	}
+	now := time.Now()
+	if last, found := t.lastWarn[category]; found && now.Sub(last) < time.Minute {
+		return false
+	}
+	t.lastWarn[category] = now
+	return true
+}
+
+// All returns the current budgets, sorted by category for stable output.
+func (t *APIBudgetTracker) All() []APIBudget {
+	t.Lock()
+	defer t.Unlock()
+	res := make([]APIBudget, 0, len(t.categories))
+	for _, budget := range t.categories {
+		res = append(res, budget)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Category < res[j].Category })
+	return res
+}
+
+// observeRateLimitHeaders parses the X-RateLimit-* headers from an API
+// response into the global budget tracker, warning when a category is
+// nearly exhausted.
+func observeRateLimitHeaders(m MetaContext, getHeader func(string) string) {
+	limitStr := getHeader("X-RateLimit-Limit")
+	remainingStr := getHeader("X-RateLimit-Remaining")
+	if limitStr == "" || remainingStr == "" {
+		return
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(remainingStr)
+	if err != nil {
+		return
+	}
+	var reset time.Time
+	if resetStr := getHeader("X-RateLimit-Reset"); resetStr != "" {
+		if unix, err := strconv.ParseInt(resetStr, 10, 64); err == nil {
+			reset = time.Unix(unix, 0)
+		}
+	}
+	category := getHeader("X-RateLimit-Category")
+	if category == "" {
+		category = "default"
+	}
+	if m.G().APIBudgets.Observe(category, limit, remaining, reset) {
+		m.Warning("API budget for %q nearly exhausted: %d of %d requests remaining (resets %s)",
+			category, remaining, limit, reset.Format(time.RFC3339))
+	}
+}
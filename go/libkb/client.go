@@ -91,6 +91,12 @@ func genClientConfigForInternalAPI(g *GlobalContext) (*ClientConfig, error) {
 		return nil, err
 	}
 
+	// A pinned or measured endpoint takes precedence over the
+	// configured server URI; see EndpointSelector.
+	if selected := g.Endpoints.Selected(); selected != "" {
+		serverURI = selected
+	}
+
 	if e.GetTorMode().Enabled() {
 		serverURI = e.GetTorHiddenAddress()
 	}
@@ -204,7 +210,7 @@ func NewClient(g *GlobalContext, config *ClientConfig, needCookie bool) (*Client
 		xprt.TLSClientConfig = &tls.Config{RootCAs: config.RootCAs}
 	}
 
-	xprt.Proxy = MakeProxy(env)
+	xprt.Proxy = MakeProxyForCircuit(env, TorCircuitAPI)
 
 	if !env.GetTorMode().Enabled() && env.GetRunMode() == DevelRunMode {
 		xprt.Proxy = func(req *http.Request) (*url.URL, error) {
@@ -629,6 +629,10 @@ func (f *JSONConfigFile) GetGregorSaveInterval() (time.Duration, bool) {
 	return f.GetDurationAtPath("push.save_interval")
 }
 
+func (f *JSONConfigFile) GetClockSkewTolerance() (time.Duration, bool) {
+	return f.GetDurationAtPath("clock_skew_tolerance")
+}
+
 func (f *JSONConfigFile) GetGregorPingInterval() (time.Duration, bool) {
 	return f.GetDurationAtPath("push.ping_interval")
 }
@@ -748,6 +752,22 @@ func (f *JSONConfigFile) GetCodeSigningKIDs() []string {
 	return f.getStringArray(v)
 }
 
+func (f *JSONConfigFile) GetAPIResponseSigningKIDs() []string {
+	if f.jw == nil {
+		return nil
+	}
+
+	v, err := f.jw.AtKey("keys").AtKey("api_response_signing").ToArray()
+	if err != nil || v == nil {
+		return nil
+	}
+	return f.getStringArray(v)
+}
+
+func (f *JSONConfigFile) GetSignedAPIResponses() (bool, bool) {
+	return f.GetBoolAtPath("signed_api_responses")
+}
+
 func (f *JSONConfigFile) GetGpgHome() (ret string) {
 	ret, _ = f.GetStringAtPath("gpg.home")
 	return ret
@@ -0,0 +1,63 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// contentScanTimeout bounds how long an external scanner may hold up an
+// outgoing send or upload.
+const contentScanTimeout = 2 * time.Minute
+
+// ContentScanError is returned when a configured content scanner rejects
+// an outgoing file.
+type ContentScanError struct {
+	Filename string
+	Output   string
+}
+
+func (e ContentScanError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("content scan rejected %s", filepath.Base(e.Filename))
+	}
+	return fmt.Sprintf("content scan rejected %s: %s",
+		filepath.Base(e.Filename), e.Output)
+}
+
+// ScanOutgoingContent runs the locally configured content-scanner command
+// (see Env.GetContentScanCommand) against filename before it is sent or
+// uploaded. The command is invoked with the file path as its only
+// argument; a non-zero exit blocks the operation and the command's output
+// is surfaced in the error. Managed deployments can point the command at
+// a DLP or malware scanner via the config file.
+//
+// When the bypass knob is set, the scan is skipped and bypassed is
+// returned true so callers can record an audit entry; a scan is never
+// silently skipped.
+func ScanOutgoingContent(env *Env, filename string) (bypassed bool, err error) {
+	command := env.GetContentScanCommand()
+	if command == "" {
+		return false, nil
+	}
+	if env.GetContentScanBypass() {
+		return true, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), contentScanTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, command, filename).CombinedOutput()
+	if err != nil {
+		return false, ContentScanError{
+			Filename: filename,
+			Output:   strings.TrimSpace(string(out)),
+		}
+	}
+	return false, nil
+}
@@ -440,10 +440,22 @@ func (m MetaContext) SwitchUserToActiveDevice(n NormalizedUsername, ad *ActiveDe
 	if err != nil {
 		return err
 	}
+	// Park the outgoing account's in-memory state, and restore anything
+	// previously parked for the incoming one, so a round trip between
+	// accounts doesn't need a fresh login.
+	g.Accounts.Park(m, g.ActiveDevice)
+	var parked *ActiveDevice
+	if ad == nil {
+		if cr := g.Env.GetConfig(); cr != nil {
+			parked = g.Accounts.Restore(cr.GetUIDForUsername(n))
+			ad = parked
+		}
+	}
 	err = g.ActiveDevice.SetOrClear(m, ad)
 	if err != nil {
 		return err
 	}
+	g.ActiveDevice.adoptCachesFrom(parked)
 	m.CommitProvisionalLogin()
 
 	return nil
@@ -50,6 +50,14 @@ const (
 	DBOfflineRPC                     = 0xbe
 	DBChatCollapses                  = 0xbf
 	DBSupportsHiddenFlagStorage      = 0xc0
+	DBMerklePin                      = 0xc1
+	DBChatEmojiUsage                 = 0xc2
+	DBChatBotSendIntents             = 0xc3
+	DBActivityTimeline               = 0xc4
+	DBNotificationDigest             = 0xc5
+	DBChatMessageRequests            = 0xc6
+	DBStellarAddressBook             = 0xc7
+	DBChatReadVectors                = 0xc8
 	DBMerkleAudit                    = 0xca
 	DBUnfurler                       = 0xcb
 	DBStellarDisclaimer              = 0xcc
@@ -132,6 +140,7 @@ func IsPermDbKey(typ ObjType) bool {
 		DBLegacyHasRandomPW,
 		DBChatReacji,
 		DBStellarDisclaimer,
+		DBStellarAddressBook,
 		DBChatIndex,
 		DBBoxAuditorPermanent,
 		DBSavedContacts,
@@ -207,6 +207,7 @@ func (d *Delegator) SignAndPost(m MetaContext, proof *ProofMetadataRes) (err err
 		m.Debug("| Failure in SignJson()")
 		return err
 	}
+	m.G().KeyUsageLog.Record("sign", d.GetSigningKey().GetKID(), string(d.DelegationType))
 	d.sigID = sigIDBase.ToSigIDLegacy()
 	if err = d.post(m); err != nil {
 		m.Debug("| Failure in post()")
@@ -0,0 +1,188 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointMeasurement is the result of one latency probe against a
+// candidate API server endpoint.
+type EndpointMeasurement struct {
+	URI        string        `json:"uri"`
+	Latency    time.Duration `json:"latency"`
+	Err        string        `json:"err,omitempty"`
+	MeasuredAt time.Time     `json:"measuredAt"`
+}
+
+// Healthy returns true if the last probe of this endpoint succeeded.
+func (m EndpointMeasurement) Healthy() bool {
+	return m.Err == ""
+}
+
+const endpointProbeTimeout = 10 * time.Second
+
+// probeEndpoint issues a HEAD request against the endpoint's ping route
+// purely to measure round-trip time and availability; the response body
+// is irrelevant.
+func probeEndpoint(uri string, timeout time.Duration) error {
+	cli := &http.Client{Timeout: timeout}
+	resp, err := cli.Head(uri + "/_/api/1.0/ping.json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EndpointSelector measures latency and availability across candidate
+// API server endpoints and recommends the closest healthy one, for
+// users far from the default endpoints. The configured server URI is
+// always a candidate; regional endpoints are registered on top, and a
+// manual pin overrides the measurements entirely. A selection only
+// takes effect for API clients configured after it's made.
+type EndpointSelector struct {
+	sync.Mutex
+	candidates   []string
+	measurements map[string]EndpointMeasurement
+	pinned       string
+	// probe is swappable for tests.
+	probe func(uri string, timeout time.Duration) error
+}
+
+func NewEndpointSelector() *EndpointSelector {
+	return &EndpointSelector{
+		measurements: make(map[string]EndpointMeasurement),
+		probe:        probeEndpoint,
+	}
+}
+
+// AddCandidate registers an additional endpoint to measure, on top of
+// the configured server URI.
+func (s *EndpointSelector) AddCandidate(uri string) {
+	s.Lock()
+	defer s.Unlock()
+	for _, candidate := range s.candidates {
+		if candidate == uri {
+			return
+		}
+	}
+	s.candidates = append(s.candidates, uri)
+}
+
+// Measure probes the given endpoints plus any registered candidates,
+// records a measurement for each, and returns all measurements.
+func (s *EndpointSelector) Measure(defaults ...string) []EndpointMeasurement {
+	s.Lock()
+	uris := make([]string, 0, len(defaults)+len(s.candidates))
+	seen := make(map[string]bool)
+	for _, uri := range append(defaults, s.candidates...) {
+		if uri == "" || seen[uri] {
+			continue
+		}
+		seen[uri] = true
+		uris = append(uris, uri)
+	}
+	probe := s.probe
+	s.Unlock()
+
+	for _, uri := range uris {
+		start := time.Now()
+		err := probe(uri, endpointProbeTimeout)
+		measurement := EndpointMeasurement{
+			URI:        uri,
+			Latency:    time.Since(start),
+			MeasuredAt: time.Now(),
+		}
+		if err != nil {
+			measurement.Err = err.Error()
+		}
+		s.Lock()
+		s.measurements[uri] = measurement
+		s.Unlock()
+	}
+	return s.All()
+}
+
+// Pin forces Selected to return uri regardless of measurements, until
+// Unpin is called.
+func (s *EndpointSelector) Pin(uri string) {
+	s.Lock()
+	defer s.Unlock()
+	s.pinned = uri
+}
+
+// Unpin clears a manual pin, going back to measurement-based selection.
+func (s *EndpointSelector) Unpin() {
+	s.Lock()
+	defer s.Unlock()
+	s.pinned = ""
+}
+
+// Selected returns the pinned endpoint if one is set, otherwise the
+// healthy endpoint with the lowest measured latency. It returns ""
+// when nothing has been measured yet, meaning the caller should stick
+// with the configured server URI.
+func (s *EndpointSelector) Selected() string {
+	s.Lock()
+	defer s.Unlock()
+	if s.pinned != "" {
+		return s.pinned
+	}
+	var best string
+	var bestLatency time.Duration
+	for uri, measurement := range s.measurements {
+		if !measurement.Healthy() {
+			continue
+		}
+		if best == "" || measurement.Latency < bestLatency {
+			best = uri
+			bestLatency = measurement.Latency
+		}
+	}
+	return best
+}
+
+// All returns the current measurements, sorted by URI for stable output.
+func (s *EndpointSelector) All() []EndpointMeasurement {
+	s.Lock()
+	defer s.Unlock()
+	res := make([]EndpointMeasurement, 0, len(s.measurements))
+	for _, measurement := range s.measurements {
+		res = append(res, measurement)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].URI < res[j].URI })
+	return res
+}
+
+// EndpointSelectorStatus is the JSON dump returned by the
+// endpoint-status debug script.
+type EndpointSelectorStatus struct {
+	Pinned       string                `json:"pinned,omitempty"`
+	Selected     string                `json:"selected,omitempty"`
+	Measurements []EndpointMeasurement `json:"measurements"`
+}
+
+// Status returns a snapshot of the pin, the current selection, and all
+// measurements.
+func (s *EndpointSelector) Status() EndpointSelectorStatus {
+	return EndpointSelectorStatus{
+		Pinned:       s.pin(),
+		Selected:     s.Selected(),
+		Measurements: s.All(),
+	}
+}
+
+func (s *EndpointSelector) pin() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.pinned
+}
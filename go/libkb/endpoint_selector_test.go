@@ -0,0 +1,55 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointSelector(t *testing.T) {
+	latencies := map[string]time.Duration{
+		"https://api-us.example.com": 80 * time.Millisecond,
+		"https://api-eu.example.com": 20 * time.Millisecond,
+		"https://api-ap.example.com": 50 * time.Millisecond,
+	}
+	s := NewEndpointSelector()
+	s.probe = func(uri string, _ time.Duration) error {
+		latency, ok := latencies[uri]
+		if !ok {
+			return errors.New("unreachable")
+		}
+		time.Sleep(latency)
+		return nil
+	}
+
+	require.Equal(t, "", s.Selected(), "nothing measured yet")
+
+	s.AddCandidate("https://api-eu.example.com")
+	s.AddCandidate("https://api-ap.example.com")
+	s.AddCandidate("https://api-down.example.com")
+	measurements := s.Measure("https://api-us.example.com")
+	require.Len(t, measurements, 4)
+	require.Equal(t, "https://api-eu.example.com", s.Selected())
+
+	status := s.Status()
+	require.Equal(t, "https://api-eu.example.com", status.Selected)
+	require.Empty(t, status.Pinned)
+	for _, measurement := range status.Measurements {
+		if measurement.URI == "https://api-down.example.com" {
+			require.False(t, measurement.Healthy())
+		} else {
+			require.True(t, measurement.Healthy())
+		}
+	}
+
+	t.Logf("A manual pin overrides the measurements, even for an endpoint that was never measured.")
+	s.Pin("https://api-pinned.example.com")
+	require.Equal(t, "https://api-pinned.example.com", s.Selected())
+	s.Unpin()
+	require.Equal(t, "https://api-eu.example.com", s.Selected())
+}
@@ -55,6 +55,8 @@ func (n NullConfiguration) GetUIDMapFullNameCacheSize() (int, bool)
 func (n NullConfiguration) GetPayloadCacheSize() (int, bool)                               { return 0, false }
 func (n NullConfiguration) GetMerkleKIDs() []string                                        { return nil }
 func (n NullConfiguration) GetCodeSigningKIDs() []string                                   { return nil }
+func (n NullConfiguration) GetAPIResponseSigningKIDs() []string                            { return nil }
+func (n NullConfiguration) GetSignedAPIResponses() (bool, bool)                            { return false, false }
 func (n NullConfiguration) GetPinentry() string                                            { return "" }
 func (n NullConfiguration) GetUID() (ret keybase1.UID)                                     { return }
 func (n NullConfiguration) GetGpg() string                                                 { return "" }
@@ -106,6 +108,7 @@ func (n NullConfiguration) GetGregorSaveInterval() (time.Duration, bool)    { re
 func (n NullConfiguration) GetGregorPingInterval() (time.Duration, bool)    { return 0, false }
 func (n NullConfiguration) GetGregorPingTimeout() (time.Duration, bool)     { return 0, false }
 func (n NullConfiguration) GetChatDelivererInterval() (time.Duration, bool) { return 0, false }
+func (n NullConfiguration) GetClockSkewTolerance() (time.Duration, bool)    { return 0, false }
 func (n NullConfiguration) GetGregorDisabled() (bool, bool)                 { return false, false }
 func (n NullConfiguration) GetSecretStorePrimingDisabled() (bool, bool)     { return false, false }
 func (n NullConfiguration) GetMountDir() string                             { return "" }
@@ -1005,6 +1008,13 @@ func (e *Env) GetGregorPingInterval() time.Duration {
 	)
 }
 
+func (e *Env) GetClockSkewTolerance() time.Duration {
+	return e.GetDuration(CriticalClockSkewLimit,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_CLOCK_SKEW_TOLERANCE") },
+		func() (time.Duration, bool) { return e.GetConfig().GetClockSkewTolerance() },
+	)
+}
+
 func (e *Env) GetGregorPingTimeout() time.Duration {
 	return e.GetDuration(5*time.Second,
 		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_PUSH_PING_TIMEOUT") },
@@ -1441,6 +1451,14 @@ func (e *Env) GetReadDeletedSigChain() bool {
 	)
 }
 
+// GetForceFullSigchainVerification makes sigchain loading ignore
+// server-provided checkpoints, so every link gets verified.
+func (e *Env) GetForceFullSigchainVerification() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_FORCE_FULL_SIGCHAIN_VERIFICATION") },
+	)
+}
+
 func (e *Env) GetFeatureFlags() FeatureFlags {
 	var ret FeatureFlags
 	pick := func(f FeatureFlags, err error) {
@@ -1520,6 +1538,36 @@ func (e *Env) GetCodeSigningKIDs() []keybase1.KID {
 	return ret
 }
 
+// GetSignedAPIResponses is the strict mode for users pointing at
+// self-hosted or mirrored infrastructure: critical API responses must
+// carry a valid signature from one of the pinned response-signing keys.
+func (e *Env) GetSignedAPIResponses() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_SIGNED_API_RESPONSES") },
+		func() (bool, bool) { return e.GetConfig().GetSignedAPIResponses() },
+	)
+}
+
+// GetAPIResponseSigningKIDs returns the pinned keys allowed to sign
+// critical API responses in signed-response mode. There is no built-in
+// default: the set comes from the local config or environment.
+func (e *Env) GetAPIResponseSigningKIDs() []keybase1.KID {
+	slist := e.GetStringList(
+		func() []string { return e.getEnvPath("KEYBASE_API_RESPONSE_SIGNING_KIDS") },
+		func() []string { return e.GetConfig().GetAPIResponseSigningKIDs() },
+	)
+
+	if slist == nil {
+		return nil
+	}
+	var ret []keybase1.KID
+	for _, s := range slist {
+		ret = append(ret, keybase1.KIDFromString(s))
+	}
+
+	return ret
+}
+
 func (e *Env) GetGpg() string {
 	return e.GetString(
 		func() string { return e.Test.GPG },
@@ -1580,6 +1628,44 @@ func (e *Env) GetInboxSourceType() string {
 	)
 }
 
+// GetChatMessageRequestMode controls the chat message-request layer:
+// "off" (the default), "strangers" to quarantine first messages from
+// senders the user doesn't follow, or "all" to quarantine every new DM
+// until explicitly accepted.
+func (e *Env) GetChatMessageRequestMode() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_CHAT_MESSAGE_REQUESTS") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("chat.message_requests")
+			return s
+		},
+		func() string { return "off" },
+	)
+}
+
+// GetContentScanCommand returns the path of an optional external content
+// scanner that's run against outgoing chat attachments and KBFS uploads.
+// The command gets the file path as its only argument; a non-zero exit
+// blocks the send. Empty (the default) disables scanning.
+func (e *Env) GetContentScanCommand() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_CONTENT_SCAN_COMMAND") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("scan.command")
+			return s
+		},
+	)
+}
+
+// GetContentScanBypass skips a configured content scan. Bypasses are
+// always logged so managed deployments have an audit trail.
+func (e *Env) GetContentScanBypass() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_CONTENT_SCAN_BYPASS") },
+		func() (bool, bool) { return e.GetConfig().GetBoolAtPath("scan.bypass") },
+	)
+}
+
 func (e *Env) GetChatInboxSourceLocalizeThreads() int {
 	return e.GetInt(
 		10,
@@ -2036,6 +2122,31 @@ func (e *Env) ForceSecretStoreFile() bool {
 	)
 }
 
+func (e *Env) GetKeyUsageLogFile() string {
+	// If set, the key usage audit trail is also appended to this file
+	// as JSON lines, so it survives service restarts.
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_KEY_USAGE_LOG_FILE") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("key_usage_log_file")
+			return s
+		},
+	)
+}
+
+func (e *Env) GetSecretKeyringBackend() string {
+	// Which backend holds SKB keyrings: "file" (the default on-disk
+	// keyring), "memory" (ephemeral, for CI/bot usage), or "external"
+	// (a registered OS keychain-backed store).
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_SECRET_KEYRING_BACKEND") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("secret_keyring_backend")
+			return s
+		},
+	)
+}
+
 func (e *Env) GetRuntimeStatsEnabled() bool {
 	return e.GetBool(false,
 		func() (bool, bool) { return e.getEnvBool("KEYBASE_RUNTIME_STATS_ENABLED") },
@@ -1143,6 +1143,28 @@ func (r KeyExpiredError) Error() string {
 
 //=============================================================================
 
+// ClockSkewError names a skewed local clock as the cause of a failure
+// that would otherwise be misreported, like a signing key that only
+// looks expired. Inner, when set, is the error the skew explains.
+type ClockSkewError struct {
+	Skew  time.Duration
+	Inner error
+}
+
+func NewClockSkewError(skew time.Duration, inner error) ClockSkewError {
+	return ClockSkewError{Skew: skew, Inner: inner}
+}
+
+func (e ClockSkewError) Error() string {
+	msg := fmt.Sprintf("local clock is off by %s from the Keybase servers; enable NTP time synchronization", e.Skew.Round(time.Second))
+	if e.Inner != nil {
+		msg += fmt.Sprintf(" (underlying error: %s)", e.Inner)
+	}
+	return msg
+}
+
+//=============================================================================
+
 type UnknownKeyTypeError struct {
 	typ kbcrypto.AlgoType
 }
@@ -1464,6 +1486,24 @@ func (e APINetError) Error() string {
 
 //=============================================================================
 
+type APIResponseSignatureError struct {
+	Endpoint string
+	Msg      string
+}
+
+func NewAPIResponseSignatureError(endpoint string, format string, args ...interface{}) APIResponseSignatureError {
+	return APIResponseSignatureError{
+		Endpoint: endpoint,
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+func (e APIResponseSignatureError) Error() string {
+	return fmt.Sprintf("signed response verification failed for %s: %s", e.Endpoint, e.Msg)
+}
+
+//=============================================================================
+
 type NoDecryptionKeyError struct {
 	Msg string
 }
@@ -1517,6 +1557,16 @@ func (e ChainLinkPrevHashMismatchError) Error() string {
 
 //=============================================================================
 
+type SigchainCheckpointMismatchError struct {
+	Msg string
+}
+
+func (e SigchainCheckpointMismatchError) Error() string {
+	return fmt.Sprintf("Sigchain checkpoint mismatch error: %s", e.Msg)
+}
+
+//=============================================================================
+
 type ChainLinkWrongSeqnoError struct {
 	Msg string
 }
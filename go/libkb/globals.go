@@ -110,6 +110,10 @@ type GlobalContext struct {
 	kvRevisionCache        KVRevisionCacher   // cache of revisions for verifying key-value store results
 	Pegboard               *Pegboard
 
+	loginTwoFactorProvider LoginTwoFactorProvider // solves second-factor login challenges (enterprise servers)
+
+	passphraseStretchProgressor PassphraseStretchProgressor // told when triplesec stretches start/finish (for UI spinners)
+
 	GpgClient        *GpgCLI        // A standard GPG-client (optional)
 	ShutdownHooks    []ShutdownHook // on shutdown, fire these...
 	SocketInfo       Socket         // which socket to bind/connect to
@@ -145,7 +149,12 @@ type GlobalContext struct {
 	GregorListener     GregorListener            // for alerting about clients connecting and registering UI protocols
 	oodiMu             *sync.RWMutex             // For manipulating the OutOfDateInfo
 	outOfDateInfo      *keybase1.OutOfDateInfo   // Stores out of date messages we got from API server headers.
+	measuredClockSkew  time.Duration             // Last server-vs-local clock offset seen on an API response (oodiMu)
 	lastUpgradeWarning *time.Time                // When the last upgrade was warned for (to reate-limit nagging)
+	APIBudgets         *APIBudgetTracker         // Per-category rate limit state reported by API response headers
+	Endpoints          *EndpointSelector         // Latency measurements and manual pinning across API server endpoints
+	Maintenance        *MaintenanceScheduler     // Gates heavy background jobs behind time windows or AC-power-and-idle
+	Accounts           *AccountManager           // Parks in-memory account state across user switches
 
 	uchMu               *sync.Mutex          // protects the UserChangedHandler array
 	UserChangedHandlers []UserChangedHandler // a list of handlers that deal generically with userchanged events
@@ -184,6 +193,8 @@ type GlobalContext struct {
 	avatarLoader AvatarLoaderSource
 
 	TeamMemberCountCache *TeamMemberCountCache
+
+	KeyUsageLog *KeyUsageLog // audit trail of local secret key usage
 }
 
 type GlobalTestOptions struct {
@@ -237,6 +248,10 @@ func NewGlobalContext() *GlobalContext {
 		oodiMu:             new(sync.RWMutex),
 		outOfDateInfo:      &keybase1.OutOfDateInfo{},
 		lastUpgradeWarning: new(time.Time),
+		APIBudgets:         NewAPIBudgetTracker(),
+		Endpoints:          NewEndpointSelector(),
+		Maintenance:        NewMaintenanceScheduler(),
+		Accounts:           NewAccountManager(),
 		uchMu:              new(sync.Mutex),
 		secretStoreMu:      new(sync.Mutex),
 		NewTriplesec:       NewSecureTriplesec,
@@ -249,6 +264,7 @@ func NewGlobalContext() *GlobalContext {
 		RuntimeStats:       NewDummyRuntimeStats(),
 	}
 	ret.TeamMemberCountCache = newTeamMemberCountCache(ret)
+	ret.KeyUsageLog = NewKeyUsageLog(ret)
 	return ret
 }
 
@@ -1415,6 +1431,28 @@ func (g *GlobalContext) GetOutOfDateInfo() keybase1.OutOfDateInfo {
 	return ret
 }
 
+// MeasuredClockSkew returns the last server-vs-local clock offset observed
+// on an API response, whether or not it exceeded the tolerance. Zero if no
+// measurement has been made yet.
+func (g *GlobalContext) MeasuredClockSkew() time.Duration {
+	g.oodiMu.RLock()
+	ret := g.measuredClockSkew
+	g.oodiMu.RUnlock()
+	return ret
+}
+
+// CheckClockSkew returns a ClockSkewError if the last measured clock skew
+// exceeds the configured tolerance (clock_skew_tolerance, default
+// CriticalClockSkewLimit), and nil otherwise.
+func (g *GlobalContext) CheckClockSkew() error {
+	skew := g.MeasuredClockSkew()
+	tolerance := g.Env.GetClockSkewTolerance()
+	if skew > tolerance || skew < -tolerance {
+		return NewClockSkewError(skew, nil)
+	}
+	return nil
+}
+
 func (g *GlobalContext) KeyfamilyChanged(ctx context.Context, u keybase1.UID) {
 	g.Log.CDebugf(ctx, "+ KeyfamilyChanged(%s)", u)
 	defer g.Log.CDebugf(ctx, "- KeyfamilyChanged(%s)", u)
@@ -38,3 +38,7 @@ func (n nullGregorState) LocalDismissItem(ctx context.Context, id gregor.MsgID)
 func (n nullGregorState) DismissCategory(ctx context.Context, cat gregor1.Category) error {
 	return nil
 }
+
+func (n nullGregorState) ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (int, error) {
+	return 0, nil
+}
@@ -54,6 +54,7 @@ type configGetter interface {
 	GetGregorDisabled() (bool, bool)
 	GetSecretStorePrimingDisabled() (bool, bool)
 	GetBGIdentifierDisabled() (bool, bool)
+	GetClockSkewTolerance() (time.Duration, bool)
 	GetGregorPingInterval() (time.Duration, bool)
 	GetGregorPingTimeout() (time.Duration, bool)
 	GetGregorSaveInterval() (time.Duration, bool)
@@ -72,6 +73,8 @@ type configGetter interface {
 	GetLogPrefix() string
 	GetLogFormat() string
 	GetMerkleKIDs() []string
+	GetAPIResponseSigningKIDs() []string
+	GetSignedAPIResponses() (bool, bool)
 	GetMountDir() string
 	GetMountDirDefault() string
 	GetPidFile() string
@@ -556,6 +559,7 @@ type GregorState interface {
 	DismissItem(ctx context.Context, cli gregor1.IncomingInterface, id gregor.MsgID) error
 	DismissCategory(ctx context.Context, cat gregor1.Category) error
 	LocalDismissItem(ctx context.Context, id gregor.MsgID) error
+	ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (int, error)
 }
 
 type GregorInBandMessageHandler interface {
@@ -1104,6 +1108,32 @@ type ChatHelper interface {
 	JourneycardTimeTravel(context.Context, gregor1.UID, time.Duration) (int, int, error)
 	JourneycardResetAllConvs(context.Context, gregor1.UID) error
 	JourneycardDebugState(context.Context, gregor1.UID, keybase1.TeamID) (string, error)
+	ListMessageRequests(context.Context, gregor1.UID) ([]chat1.ConversationLocal, error)
+	AcceptMessageRequest(context.Context, gregor1.UID, chat1.ConversationID) error
+	// MarkAllAsRead marks every unread conversation as read, pushing the
+	// read pointers to the server so all devices converge.
+	MarkAllAsRead(context.Context, gregor1.UID) (int, error)
+	// Saved searches are user-defined inbox filters synced across
+	// devices via gregor; see chat/utils.SavedSearches.
+	PutChatSavedSearch(ctx context.Context, name, query string,
+		unreadOnly, teamsOnly, hasAttachment bool) error
+	RemoveChatSavedSearch(ctx context.Context, name string) error
+	// ListChatSavedSearches returns a JSON dump of the saved searches.
+	ListChatSavedSearches(ctx context.Context) (string, error)
+	RunChatSavedSearch(context.Context, gregor1.UID, string) ([]chat1.ConversationLocal, error)
+	// Attachment rekey is an optional background job for team owners
+	// that re-posts recent attachments under the team's current key
+	// after a rotation; see chat.AttachmentRekeyer.
+	StartAttachmentRekey(ctx context.Context, uid gregor1.UID,
+		age time.Duration, bwKBps int) error
+	StopAttachmentRekey(ctx context.Context)
+	// AttachmentRekeyStatus returns a JSON dump of the job status.
+	AttachmentRekeyStatus(ctx context.Context) (string, error)
+	// RecentChatPartners returns usernames from the user's most recent
+	// conversations: non-team chat partners and teammates seen in team
+	// channels, both ordered by conversation recency.
+	RecentChatPartners(ctx context.Context, uid gregor1.UID) (
+		direct []string, teammates []string, err error)
 	// InTeam gives a best effort to answer team membership based on the current state of the inbox cache
 	InTeam(context.Context, gregor1.UID, keybase1.TeamID) (bool, error)
 	BulkAddToConv(context.Context, gregor1.UID, chat1.ConversationID, []string) error
@@ -808,6 +808,9 @@ func MakeSig(
 	default:
 		err = errors.New("Invalid Signature Version")
 	}
+	if err == nil {
+		m.G().KeyUsageLog.Record("sign", signingKey.GetKID(), string(v1LinkType))
+	}
 	return sig, sigID, linkID, err
 }
 
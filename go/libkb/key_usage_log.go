@@ -0,0 +1,87 @@
+package libkb
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// KeyUsageEntry records one use of a local secret key: a locked keyring
+// lookup, an SKB unlock, or a signing operation.
+type KeyUsageEntry struct {
+	Time      time.Time    `json:"time"`
+	Operation string       `json:"operation"`
+	KID       keybase1.KID `json:"kid"`
+	Detail    string       `json:"detail,omitempty"`
+}
+
+// keyUsageLogMaxEntries bounds the in-memory trail; older entries fall
+// off the front.
+const keyUsageLogMaxEntries = 512
+
+// KeyUsageLog keeps a bounded in-memory audit trail of recent secret
+// key usage, surfaced through `keybase log key-usage` for users
+// investigating suspicious activity. If key_usage_log_file is
+// configured, each entry is also appended there as a JSON line so the
+// trail survives service restarts.
+type KeyUsageLog struct {
+	Contextified
+	sync.Mutex
+	entries []KeyUsageEntry
+}
+
+func NewKeyUsageLog(g *GlobalContext) *KeyUsageLog {
+	return &KeyUsageLog{Contextified: NewContextified(g)}
+}
+
+// Record appends an entry to the trail. It never fails; persistence
+// problems are logged and otherwise ignored, so auditing can't disrupt
+// key usage itself.
+func (l *KeyUsageLog) Record(operation string, kid keybase1.KID, detail string) {
+	e := KeyUsageEntry{
+		Time:      l.G().Clock().Now(),
+		Operation: operation,
+		KID:       kid,
+		Detail:    detail,
+	}
+	l.Lock()
+	l.entries = append(l.entries, e)
+	if len(l.entries) > keyUsageLogMaxEntries {
+		l.entries = l.entries[len(l.entries)-keyUsageLogMaxEntries:]
+	}
+	l.Unlock()
+	l.persist(e)
+}
+
+func (l *KeyUsageLog) persist(e KeyUsageEntry) {
+	path := l.G().Env.GetKeyUsageLogFile()
+	if path == "" {
+		return
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		l.G().Log.Debug("KeyUsageLog: cannot marshal entry: %s", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, PermFile)
+	if err != nil {
+		l.G().Log.Debug("KeyUsageLog: cannot open %s: %s", path, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		l.G().Log.Debug("KeyUsageLog: cannot append to %s: %s", path, err)
+	}
+}
+
+// Entries returns a copy of the in-memory trail, oldest first.
+func (l *KeyUsageLog) Entries() []KeyUsageEntry {
+	l.Lock()
+	defer l.Unlock()
+	ret := make([]KeyUsageEntry, len(l.entries))
+	copy(ret, l.entries)
+	return ret
+}
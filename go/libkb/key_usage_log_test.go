@@ -0,0 +1,61 @@
+package libkb
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyUsageLog(t *testing.T) {
+	g := NewGlobalContext()
+	g.Env = NewEnv(nil, nil, makeLogGetter(t))
+	l := NewKeyUsageLog(g)
+
+	require.Len(t, l.Entries(), 0)
+
+	for i := 0; i < keyUsageLogMaxEntries+10; i++ {
+		l.Record("sign", "", fmt.Sprintf("op-%d", i))
+	}
+	entries := l.Entries()
+	require.Len(t, entries, keyUsageLogMaxEntries)
+	// Oldest entries fell off the front.
+	require.Equal(t, "op-10", entries[0].Detail)
+	require.Equal(t, fmt.Sprintf("op-%d", keyUsageLogMaxEntries+9),
+		entries[len(entries)-1].Detail)
+}
+
+func TestKeyUsageLogPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kul")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "key_usage.json")
+	os.Setenv("KEYBASE_KEY_USAGE_LOG_FILE", path)
+	defer os.Unsetenv("KEYBASE_KEY_USAGE_LOG_FILE")
+
+	g := NewGlobalContext()
+	g.Env = NewEnv(nil, nil, makeLogGetter(t))
+	l := NewKeyUsageLog(g)
+	l.Record("skb-unlock", "", "passphrase")
+	l.Record("sign", "", "eldest")
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines []KeyUsageEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e KeyUsageEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		lines = append(lines, e)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 2)
+	require.Equal(t, "skb-unlock", lines[0].Operation)
+	require.Equal(t, "sign", lines[1].Operation)
+}
@@ -519,6 +519,11 @@ func (ckf ComputedKeyFamily) getCkiIfActiveAtTime(kid keybase1.KID, t time.Time)
 		ckf.G().Log.Warning("Checking status of key %s\n    with respect to time [%s],\n    found it had expired at [%s].",
 			kid, t.Format(formatStr), time.Unix(ki.ETime, 0).Format(formatStr))
 		err = KeyExpiredError{fmt.Sprintf("The key '%s' expired at %s", kid, time.Unix(ki.ETime, 0))}
+		// A skewed local clock can make a live key look expired; name the
+		// real cause if that's what's going on.
+		if skewErr := ckf.G().CheckClockSkew(); skewErr != nil {
+			err = NewClockSkewError(ckf.G().MeasuredClockSkew(), err)
+		}
 	} else {
 		ret = ki
 	}
@@ -256,6 +256,9 @@ func LockedLocalSecretKey(m MetaContext, ska SecretKeyArg) (*SKB, error) {
 
 	if ret != nil {
 		ret.SetUID(me.GetUID())
+		if key, keyErr := ret.GetPubKey(); keyErr == nil {
+			m.G().KeyUsageLog.Record("locked-key-lookup", key.GetKID(), ska.KeyType.String())
+		}
 	}
 
 	return ret, nil
@@ -0,0 +1,93 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Second-factor support for the login flow. Self-hosted and enterprise
+// servers can demand an additional factor after the passphrase proof by
+// answering the login post with SCLoginSecondFactorNeeded and a challenge
+// description; the client solves it through a LoginTwoFactorProvider and
+// retries the post with the response attached. keybase.io never asks for
+// one, so stock deployments don't see any of this.
+
+// SCLoginSecondFactorNeeded is the app status an enterprise server returns
+// when passphrase auth succeeded but a second factor is still required. It
+// is not part of the shared status code space keybase.io uses.
+const SCLoginSecondFactorNeeded = 2500
+
+// TwoFactorType says what kind of challenge the server issued.
+type TwoFactorType string
+
+const (
+	TwoFactorTypeTOTP     TwoFactorType = "totp"
+	TwoFactorTypeHardware TwoFactorType = "hardware"
+)
+
+// TwoFactorChallenge is the server's description of the factor it wants.
+type TwoFactorChallenge struct {
+	Type   TwoFactorType `json:"type"`
+	Prompt string        `json:"prompt"`
+}
+
+// LoginTwoFactorProvider solves a second-factor challenge during login.
+// There is at most one provider per GlobalContext; when none is set, the
+// default provider prompts through SecretUI, which is already delegated to
+// the frontend, so custom GUIs can render the challenge without any new
+// protocol.
+type LoginTwoFactorProvider interface {
+	Solve(m MetaContext, username string, challenge TwoFactorChallenge) (response string, err error)
+}
+
+// secretUITwoFactorProvider is the default provider: it collects the
+// factor through the delegated SecretUI prompt.
+type secretUITwoFactorProvider struct{}
+
+func (p secretUITwoFactorProvider) Solve(m MetaContext, username string,
+	challenge TwoFactorChallenge) (string, error) {
+	if m.UIs().SecretUI == nil {
+		return "", NoUIError{"secret"}
+	}
+	arg := DefaultPassphraseArg(m)
+	arg.Username = username
+	arg.WindowTitle = "Two-factor authentication"
+	arg.Type = keybase1.PassphraseType_NONE
+	arg.Prompt = challenge.Prompt
+	if len(arg.Prompt) == 0 {
+		switch challenge.Type {
+		case TwoFactorTypeHardware:
+			arg.Prompt = "Touch your hardware token, then press enter"
+		default:
+			arg.Prompt = fmt.Sprintf("Enter the verification code for %s", username)
+		}
+	}
+	res, err := GetKeybasePassphrase(m, m.UIs().SecretUI, arg)
+	if err != nil {
+		return "", err
+	}
+	return res.Passphrase, nil
+}
+
+// GetLoginTwoFactorProvider returns the registered provider, falling back
+// to the SecretUI prompt.
+func (g *GlobalContext) GetLoginTwoFactorProvider() LoginTwoFactorProvider {
+	g.cacheMu.RLock()
+	defer g.cacheMu.RUnlock()
+	if g.loginTwoFactorProvider != nil {
+		return g.loginTwoFactorProvider
+	}
+	return secretUITwoFactorProvider{}
+}
+
+// SetLoginTwoFactorProvider installs a custom provider, for deployments
+// whose second factor can't be typed into a passphrase prompt.
+func (g *GlobalContext) SetLoginTwoFactorProvider(p LoginTwoFactorProvider) {
+	g.cacheMu.Lock()
+	defer g.cacheMu.Unlock()
+	g.loginTwoFactorProvider = p
+}
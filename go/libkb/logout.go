@@ -48,6 +48,12 @@ func (mctx MetaContext) LogoutUsernameWithOptions(username NormalizedUsername, o
 			options.Force, options.KeepSecrets)
 	}
 
+	// An explicitly logged-out account shouldn't be warm-switchable back
+	// in; drop any state parked for it.
+	if cr := g.Env.GetConfig(); cr != nil {
+		g.Accounts.Forget(cr.GetUIDForUsername(username))
+	}
+
 	var keychainMode KeychainMode
 	keychainMode, err = g.ActiveDevice.ClearGetKeychainMode()
 	if err != nil {
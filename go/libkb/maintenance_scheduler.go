@@ -0,0 +1,180 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// MaintenanceWindow is a daily wall-clock interval during which heavy
+// background work may run. Start and End are minutes since local
+// midnight; a window whose end is at or before its start wraps past
+// midnight, so "22:00-06:00" covers the night.
+type MaintenanceWindow struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ParseMaintenanceWindow parses a window in "HH:MM-HH:MM" form.
+func ParseMaintenanceWindow(s string) (w MaintenanceWindow, err error) {
+	var startHour, startMin, endHour, endMin int
+	if _, err = fmt.Sscanf(s, "%d:%d-%d:%d",
+		&startHour, &startMin, &endHour, &endMin); err != nil {
+		return w, fmt.Errorf("bad window %q (want HH:MM-HH:MM)", s)
+	}
+	if startHour < 0 || startHour > 23 || endHour < 0 || endHour > 23 ||
+		startMin < 0 || startMin > 59 || endMin < 0 || endMin > 59 {
+		return w, fmt.Errorf("bad window %q (want HH:MM-HH:MM)", s)
+	}
+	return MaintenanceWindow{
+		Start: startHour*60 + startMin,
+		End:   endHour*60 + endMin,
+	}, nil
+}
+
+func (w MaintenanceWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d",
+		w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// contains reports whether the given minute-since-midnight falls inside
+// the window, accounting for midnight wraparound.
+func (w MaintenanceWindow) contains(minute int) bool {
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// MaintenanceScheduler gates heavy background work — prefetch sweeps,
+// index rebuilds, scrubs, GC — behind user-configured time windows.
+// With no windows configured, work is allowed while the machine is on
+// AC power and the user is idle, as reported by the frontend. Jobs
+// register a run function and ask ShouldRun when they come due; a "no"
+// is recorded as a deferral so the pending work can be inspected and
+// force-run through the maintenance-* debugging scripts.
+type MaintenanceScheduler struct {
+	sync.Mutex
+	windows   []MaintenanceWindow
+	onACPower bool
+	idle      bool
+	jobs      map[string]func(ctx context.Context) error
+	deferred  map[string]time.Time
+	// clock is swappable for tests.
+	clock func() time.Time
+}
+
+func NewMaintenanceScheduler() *MaintenanceScheduler {
+	return &MaintenanceScheduler{
+		jobs:     make(map[string]func(ctx context.Context) error),
+		deferred: make(map[string]time.Time),
+		clock:    time.Now,
+	}
+}
+
+// RegisterJob makes a job visible to the scheduler, replacing any
+// previous registration under the same name.
+func (s *MaintenanceScheduler) RegisterJob(
+	name string, run func(ctx context.Context) error) {
+	s.Lock()
+	defer s.Unlock()
+	s.jobs[name] = run
+}
+
+// SetWindows replaces the configured windows; an empty slice goes back
+// to the AC-power-and-idle policy.
+func (s *MaintenanceScheduler) SetWindows(windows []MaintenanceWindow) {
+	s.Lock()
+	defer s.Unlock()
+	s.windows = windows
+}
+
+// SetPowerState records the frontend's report of whether the machine
+// is on AC power and whether the user is idle.
+func (s *MaintenanceScheduler) SetPowerState(onACPower, idle bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.onACPower = onACPower
+	s.idle = idle
+}
+
+func (s *MaintenanceScheduler) allowedNowLocked() bool {
+	if len(s.windows) == 0 {
+		return s.onACPower && s.idle
+	}
+	now := s.clock()
+	minute := now.Hour()*60 + now.Minute()
+	for _, w := range s.windows {
+		if w.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldRun reports whether the named job may run now. A refusal is
+// recorded as a deferral, cleared the next time the job is allowed.
+func (s *MaintenanceScheduler) ShouldRun(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.allowedNowLocked() {
+		delete(s.deferred, name)
+		return true
+	}
+	s.deferred[name] = s.clock()
+	return false
+}
+
+// ForceRun runs a registered job immediately, regardless of policy.
+func (s *MaintenanceScheduler) ForceRun(ctx context.Context, name string) error {
+	s.Lock()
+	run, ok := s.jobs[name]
+	delete(s.deferred, name)
+	s.Unlock()
+	if !ok {
+		return fmt.Errorf("no maintenance job named %q", name)
+	}
+	return run(ctx)
+}
+
+// MaintenanceSchedulerStatus is the JSON dump returned by the
+// maintenance-status debug script.
+type MaintenanceSchedulerStatus struct {
+	Windows    []string             `json:"windows"`
+	OnACPower  bool                 `json:"onAcPower"`
+	Idle       bool                 `json:"idle"`
+	AllowedNow bool                 `json:"allowedNow"`
+	Jobs       []string             `json:"jobs"`
+	Deferred   map[string]time.Time `json:"deferred"`
+}
+
+// Status returns a snapshot of the policy and what's waiting on it.
+func (s *MaintenanceScheduler) Status() MaintenanceSchedulerStatus {
+	s.Lock()
+	defer s.Unlock()
+	status := MaintenanceSchedulerStatus{
+		Windows:    make([]string, 0, len(s.windows)),
+		OnACPower:  s.onACPower,
+		Idle:       s.idle,
+		AllowedNow: s.allowedNowLocked(),
+		Jobs:       make([]string, 0, len(s.jobs)),
+		Deferred:   make(map[string]time.Time, len(s.deferred)),
+	}
+	for _, w := range s.windows {
+		status.Windows = append(status.Windows, w.String())
+	}
+	for name := range s.jobs {
+		status.Jobs = append(status.Jobs, name)
+	}
+	sort.Strings(status.Jobs)
+	for name, when := range s.deferred {
+		status.Deferred[name] = when
+	}
+	return status
+}
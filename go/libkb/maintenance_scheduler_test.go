@@ -0,0 +1,53 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestMaintenanceScheduler(t *testing.T) {
+	now := time.Date(2026, 8, 30, 23, 30, 0, 0, time.Local)
+	s := NewMaintenanceScheduler()
+	s.clock = func() time.Time { return now }
+
+	t.Logf("With no windows configured, only AC power plus idle allows work.")
+	require.False(t, s.ShouldRun("scrub"))
+	s.SetPowerState(true, false)
+	require.False(t, s.ShouldRun("scrub"))
+	s.SetPowerState(true, true)
+	require.True(t, s.ShouldRun("scrub"))
+
+	t.Logf("A wrapping window covers the night but not the afternoon.")
+	window, err := ParseMaintenanceWindow("22:00-06:00")
+	require.NoError(t, err)
+	require.Equal(t, "22:00-06:00", window.String())
+	s.SetWindows([]MaintenanceWindow{window})
+	s.SetPowerState(false, false)
+	require.True(t, s.ShouldRun("scrub"))
+	now = time.Date(2026, 8, 31, 14, 0, 0, 0, time.Local)
+	require.False(t, s.ShouldRun("scrub"))
+
+	t.Logf("A refusal shows up as a deferral, and ForceRun runs anyway.")
+	status := s.Status()
+	require.False(t, status.AllowedNow)
+	require.Contains(t, status.Deferred, "scrub")
+	var ran bool
+	s.RegisterJob("scrub", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, s.ForceRun(context.Background(), "scrub"))
+	require.True(t, ran)
+	require.NotContains(t, s.Status().Deferred, "scrub")
+
+	require.Error(t, s.ForceRun(context.Background(), "no-such-job"))
+
+	_, err = ParseMaintenanceWindow("25:00-06:00")
+	require.Error(t, err)
+}
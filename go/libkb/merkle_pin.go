@@ -0,0 +1,101 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// The merkle prefetcher keeps a rolling window of recently fetched roots
+// pinned here, so that freshness checks made shortly after going offline
+// have something trustworthy to compare against. The full root payloads
+// (and skips) stay in the merkle client's own cache; the pin window just
+// tracks which roots we vouch for and when we fetched them.
+
+const (
+	// merklePinWindowSize caps the rolling window of pinned roots.
+	merklePinWindowSize = 32
+
+	// MerklePinDefaultTolerance is how stale a pinned root may be and still
+	// be considered usable for offline freshness checks.
+	MerklePinDefaultTolerance = 1 * time.Hour
+)
+
+// MerklePinnedRoot is one entry of the pinned rolling window.
+type MerklePinnedRoot struct {
+	Seqno   keybase1.Seqno `json:"seqno"`
+	Ctime   int64          `json:"ctime"`
+	Fetched time.Time      `json:"fetched"`
+}
+
+type merklePinState struct {
+	Roots []MerklePinnedRoot `json:"roots"`
+}
+
+func merklePinDbKey() DbKey {
+	return DbKey{Typ: DBMerklePin, Key: "pinned-window"}
+}
+
+// PinMerkleRoot adds the given root to the local rolling window, trimming
+// the window to size. The merkle client has already verified the root and
+// its skips by the time it gets here.
+func PinMerkleRoot(mctx MetaContext, root *MerkleRoot) error {
+	if root == nil || root.Seqno() == nil {
+		return nil
+	}
+	return pinMerkleRootEntry(mctx, MerklePinnedRoot{
+		Seqno:   *root.Seqno(),
+		Ctime:   root.Ctime(),
+		Fetched: root.Fetched(),
+	})
+}
+
+func pinMerkleRootEntry(mctx MetaContext, entry MerklePinnedRoot) error {
+	var state merklePinState
+	if _, err := mctx.G().LocalDb.GetInto(&state, merklePinDbKey()); err != nil {
+		return err
+	}
+	// Replace rather than duplicate if we refetched the same seqno.
+	if n := len(state.Roots); n > 0 && state.Roots[n-1].Seqno == entry.Seqno {
+		state.Roots = state.Roots[:n-1]
+	}
+	state.Roots = append(state.Roots, entry)
+	if len(state.Roots) > merklePinWindowSize {
+		state.Roots = state.Roots[len(state.Roots)-merklePinWindowSize:]
+	}
+	return mctx.G().LocalDb.PutObj(merklePinDbKey(), nil, state)
+}
+
+// PinnedMerkleRoot returns the most recent pinned root along with its
+// staleness (time since fetch). Callers doing offline verification should
+// check staleness against their tolerance and surface it explicitly in
+// results rather than silently accepting old roots.
+func PinnedMerkleRoot(mctx MetaContext) (root MerklePinnedRoot, staleness time.Duration, err error) {
+	var state merklePinState
+	found, err := mctx.G().LocalDb.GetInto(&state, merklePinDbKey())
+	if err != nil {
+		return root, 0, err
+	}
+	if !found || len(state.Roots) == 0 {
+		return root, 0, MerkleClientError{m: "no pinned merkle root", t: merkleErrorNotFound}
+	}
+	root = state.Roots[len(state.Roots)-1]
+	return root, mctx.G().Clock().Now().Sub(root.Fetched), nil
+}
+
+// PinnedMerkleRootFreshEnough reports whether the pinned window can vouch
+// for freshness within the given tolerance (or the default if tolerance is
+// zero).
+func PinnedMerkleRootFreshEnough(mctx MetaContext, tolerance time.Duration) bool {
+	if tolerance == 0 {
+		tolerance = MerklePinDefaultTolerance
+	}
+	_, staleness, err := PinnedMerkleRoot(mctx)
+	if err != nil {
+		return false
+	}
+	return staleness <= tolerance
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerklePinWindow(t *testing.T) {
+	tc := SetupTest(t, "merkle pin window", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	_, _, err := PinnedMerkleRoot(m)
+	require.Error(t, err)
+
+	now := tc.G.Clock().Now()
+	for i := 1; i <= merklePinWindowSize+5; i++ {
+		err := pinMerkleRootEntry(m, MerklePinnedRoot{
+			Seqno:   keybase1.Seqno(i),
+			Ctime:   now.Unix(),
+			Fetched: now,
+		})
+		require.NoError(t, err)
+	}
+
+	root, staleness, err := PinnedMerkleRoot(m)
+	require.NoError(t, err)
+	require.Equal(t, keybase1.Seqno(merklePinWindowSize+5), root.Seqno)
+	require.True(t, staleness < time.Minute)
+
+	// Re-pinning the same seqno replaces rather than duplicates.
+	err = pinMerkleRootEntry(m, MerklePinnedRoot{
+		Seqno:   keybase1.Seqno(merklePinWindowSize + 5),
+		Ctime:   now.Unix(),
+		Fetched: now,
+	})
+	require.NoError(t, err)
+
+	var state merklePinState
+	found, err := tc.G.LocalDb.GetInto(&state, merklePinDbKey())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, state.Roots, merklePinWindowSize)
+
+	require.True(t, PinnedMerkleRootFreshEnough(m, 0))
+	require.False(t, PinnedMerkleRootFreshEnough(m, -time.Second))
+}
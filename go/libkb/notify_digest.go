@@ -0,0 +1,230 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keybase/clockwork"
+)
+
+// Notification digest mode. When enabled, the NotifyRouter stops pushing
+// non-urgent notifications (follows, KBFS edits, team membership churn) to
+// connected frontends one by one and instead parks a short summary of each
+// in a persistent store, delivering them as a periodic batch. Urgent
+// traffic — chat messages, @mentions, anything driving internal caches —
+// is never digested; the router keeps firing its in-process listeners even
+// for captured notifications so caches stay fresh.
+
+// NotifyDigestMode is the user's batching preference.
+type NotifyDigestMode string
+
+const (
+	NotifyDigestOff    NotifyDigestMode = "off"
+	NotifyDigestHourly NotifyDigestMode = "hourly"
+	NotifyDigestDaily  NotifyDigestMode = "daily"
+)
+
+func (m NotifyDigestMode) interval() time.Duration {
+	switch m {
+	case NotifyDigestHourly:
+		return time.Hour
+	case NotifyDigestDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// NotifyDigestEntry is one summarized notification awaiting delivery.
+type NotifyDigestEntry struct {
+	Category string    `json:"category"`
+	Summary  string    `json:"summary"`
+	Ctime    time.Time `json:"ctime"`
+}
+
+// NotifyDigestDeliverFunc receives a flushed batch. The default just logs;
+// the service installs a real deliverer at startup.
+type NotifyDigestDeliverFunc func(mctx MetaContext, entries []NotifyDigestEntry)
+
+type notifyDigestState struct {
+	Mode      NotifyDigestMode    `json:"mode"`
+	Pending   []NotifyDigestEntry `json:"pending"`
+	LastFlush time.Time           `json:"lastFlush"`
+}
+
+// NotifyDigester holds the pending-digest store. State persists in the
+// local DB per user, so batched notifications survive a service restart.
+type NotifyDigester struct {
+	Contextified
+	sync.Mutex
+	deliver NotifyDigestDeliverFunc
+	clock   clockwork.Clock
+	stopCh  chan struct{}
+}
+
+func NewNotifyDigester(g *GlobalContext) *NotifyDigester {
+	return &NotifyDigester{
+		Contextified: NewContextified(g),
+		clock:        clockwork.NewRealClock(),
+	}
+}
+
+// SetClock is for testing.
+func (d *NotifyDigester) SetClock(clock clockwork.Clock) {
+	d.clock = clock
+}
+
+// SetDeliverFunc installs the batch deliverer.
+func (d *NotifyDigester) SetDeliverFunc(deliver NotifyDigestDeliverFunc) {
+	d.Lock()
+	defer d.Unlock()
+	d.deliver = deliver
+}
+
+func (d *NotifyDigester) dbKey() DbKey {
+	return DbKey{
+		Typ: DBNotificationDigest,
+		Key: d.G().Env.GetUID().String(),
+	}
+}
+
+func (d *NotifyDigester) getState() (res notifyDigestState) {
+	found, err := d.G().GetKVStore().GetInto(&res, d.dbKey())
+	if err != nil || !found {
+		return notifyDigestState{Mode: NotifyDigestOff}
+	}
+	if res.Mode == "" {
+		res.Mode = NotifyDigestOff
+	}
+	return res
+}
+
+func (d *NotifyDigester) putState(state notifyDigestState) {
+	if err := d.G().GetKVStore().PutObj(d.dbKey(), nil, state); err != nil {
+		d.G().Log.Debug("NotifyDigester: putState: %s", err)
+	}
+}
+
+// Mode returns the user's digest preference.
+func (d *NotifyDigester) Mode() NotifyDigestMode {
+	d.Lock()
+	defer d.Unlock()
+	return d.getState().Mode
+}
+
+// SetMode changes the preference; turning digests off flushes anything
+// pending so nothing is silently dropped.
+func (d *NotifyDigester) SetMode(mctx MetaContext, mode NotifyDigestMode) error {
+	switch mode {
+	case NotifyDigestOff, NotifyDigestHourly, NotifyDigestDaily:
+	default:
+		return fmt.Errorf("unknown digest mode %q", mode)
+	}
+	d.Lock()
+	state := d.getState()
+	if mode != NotifyDigestOff && state.Mode != mode {
+		// The interval counts from when batching was (re)enabled.
+		state.LastFlush = d.clock.Now()
+	}
+	state.Mode = mode
+	d.putState(state)
+	d.Unlock()
+	if mode == NotifyDigestOff {
+		d.FlushNow(mctx)
+	}
+	return nil
+}
+
+// Capture offers a notification to the digester. It returns true if the
+// notification was parked for the next digest, in which case the caller
+// should skip the immediate frontend broadcast.
+func (d *NotifyDigester) Capture(category, summary string) bool {
+	d.Lock()
+	defer d.Unlock()
+	state := d.getState()
+	if state.Mode == NotifyDigestOff {
+		return false
+	}
+	state.Pending = append(state.Pending, NotifyDigestEntry{
+		Category: category,
+		Summary:  summary,
+		Ctime:    d.clock.Now(),
+	})
+	d.putState(state)
+	return true
+}
+
+// FlushNow delivers and clears the pending batch.
+func (d *NotifyDigester) FlushNow(mctx MetaContext) {
+	d.Lock()
+	state := d.getState()
+	pending := state.Pending
+	state.Pending = nil
+	state.LastFlush = d.clock.Now()
+	d.putState(state)
+	deliver := d.deliver
+	d.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	if deliver == nil {
+		mctx.Debug("NotifyDigester: no deliverer; dropping %d entries to the log", len(pending))
+		for _, entry := range pending {
+			mctx.G().Log.Notice("notification digest: [%s] %s", entry.Category, entry.Summary)
+		}
+		return
+	}
+	deliver(mctx, pending)
+}
+
+// flushIfDue flushes when the mode's interval has elapsed since LastFlush.
+func (d *NotifyDigester) flushIfDue(mctx MetaContext) {
+	d.Lock()
+	state := d.getState()
+	interval := state.Mode.interval()
+	due := interval > 0 && len(state.Pending) > 0 &&
+		d.clock.Now().Sub(state.LastFlush) >= interval
+	d.Unlock()
+	if due {
+		d.FlushNow(mctx)
+	}
+}
+
+// Start runs the periodic flusher until Stop.
+func (d *NotifyDigester) Start(mctx MetaContext) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	if d.stopCh != nil {
+		d.Unlock()
+		return
+	}
+	stopCh := make(chan struct{})
+	d.stopCh = stopCh
+	d.Unlock()
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-d.clock.After(time.Minute):
+				d.flushIfDue(mctx)
+			}
+		}
+	}()
+}
+
+// Stop halts the periodic flusher.
+func (d *NotifyDigester) Stop() {
+	d.Lock()
+	defer d.Unlock()
+	if d.stopCh != nil {
+		close(d.stopCh)
+		d.stopCh = nil
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyDigestCapture(t *testing.T) {
+	tc := SetupTest(t, "notify_digest", 1)
+	defer tc.Cleanup()
+	mctx := NewMetaContextForTest(tc)
+
+	d := NewNotifyDigester(tc.G)
+
+	// Off by default: nothing is captured.
+	require.Equal(t, NotifyDigestOff, d.Mode())
+	require.False(t, d.Capture("follow", "You followed t_alice"))
+
+	require.Error(t, d.SetMode(mctx, NotifyDigestMode("weekly")))
+	require.NoError(t, d.SetMode(mctx, NotifyDigestHourly))
+	require.True(t, d.Capture("follow", "You followed t_alice"))
+	require.True(t, d.Capture("kbfs-edit", "FILE_MODIFIED: notes.txt"))
+
+	var got []NotifyDigestEntry
+	d.SetDeliverFunc(func(_ MetaContext, entries []NotifyDigestEntry) {
+		got = entries
+	})
+	d.FlushNow(mctx)
+	require.Len(t, got, 2)
+	require.Equal(t, "follow", got[0].Category)
+
+	// Flushed entries are cleared.
+	got = nil
+	d.FlushNow(mctx)
+	require.Len(t, got, 0)
+}
+
+func TestNotifyDigestFlushIfDue(t *testing.T) {
+	tc := SetupTest(t, "notify_digest", 1)
+	defer tc.Cleanup()
+	mctx := NewMetaContextForTest(tc)
+
+	d := NewNotifyDigester(tc.G)
+	clock := clockwork.NewFakeClock()
+	d.SetClock(clock)
+
+	var flushes int
+	d.SetDeliverFunc(func(_ MetaContext, entries []NotifyDigestEntry) {
+		flushes++
+	})
+
+	require.NoError(t, d.SetMode(mctx, NotifyDigestHourly))
+	require.True(t, d.Capture("follow", "You followed t_bob"))
+
+	clock.Advance(30 * time.Minute)
+	d.flushIfDue(mctx)
+	require.Equal(t, 0, flushes)
+
+	clock.Advance(31 * time.Minute)
+	d.flushIfDue(mctx)
+	require.Equal(t, 1, flushes)
+
+	// Nothing pending: no further flushes even after the interval.
+	clock.Advance(2 * time.Hour)
+	d.flushIfDue(mctx)
+	require.Equal(t, 1, flushes)
+}
+
+func TestNotifyDigestOffFlushes(t *testing.T) {
+	tc := SetupTest(t, "notify_digest", 1)
+	defer tc.Cleanup()
+	mctx := NewMetaContextForTest(tc)
+
+	d := NewNotifyDigester(tc.G)
+	require.NoError(t, d.SetMode(mctx, NotifyDigestDaily))
+	require.True(t, d.Capture("team-membership", "Membership changed in acme"))
+
+	var got []NotifyDigestEntry
+	d.SetDeliverFunc(func(_ MetaContext, entries []NotifyDigestEntry) {
+		got = entries
+	})
+	// Turning digests off drains pending entries rather than dropping them.
+	require.NoError(t, d.SetMode(mctx, NotifyDigestOff))
+	require.Len(t, got, 1)
+	require.False(t, d.Capture("follow", "You followed t_carol"))
+}
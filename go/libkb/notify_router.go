@@ -116,6 +116,8 @@ type NotifyListener interface {
 	TeamTreeMembershipsPartial(keybase1.TeamTreeMembership)
 	TeamTreeMembershipsDone(keybase1.TeamTreeMembershipsDoneResult)
 	WebOfTrustChanged(username string)
+	ConfigChanged(applied []string, restartRequired []string)
+	TlfShareInvite(invite TlfInvite)
 }
 
 type NoopNotifyListener struct{}
@@ -269,6 +271,8 @@ func (n *NoopNotifyListener) TeamTreeMembershipsPartial(keybase1.TeamTreeMembers
 func (n *NoopNotifyListener) TeamTreeMembershipsDone(keybase1.TeamTreeMembershipsDoneResult) {}
 func (n *NoopNotifyListener) WebOfTrustChanged(username string) {
 }
+func (n *NoopNotifyListener) ConfigChanged(applied []string, restartRequired []string) {}
+func (n *NoopNotifyListener) TlfShareInvite(invite TlfInvite)                          {}
 
 type NotifyListenerID string
 
@@ -280,6 +284,7 @@ type NotifyRouter struct {
 	cm        *ConnectionManager
 	state     map[ConnectionID]keybase1.NotificationChannels
 	listeners map[NotifyListenerID]NotifyListener
+	digester  *NotifyDigester
 }
 
 // NewNotifyRouter makes a new notification router; we should only
@@ -290,9 +295,29 @@ func NewNotifyRouter(g *GlobalContext) *NotifyRouter {
 		cm:           g.ConnectionManager,
 		state:        make(map[ConnectionID]keybase1.NotificationChannels),
 		listeners:    make(map[NotifyListenerID]NotifyListener),
+		digester:     NewNotifyDigester(g),
 	}
 }
 
+// Digester returns the router's notification digester, which batches
+// non-urgent notifications into periodic summaries when enabled.
+func (n *NotifyRouter) Digester() *NotifyDigester {
+	if n == nil {
+		return nil
+	}
+	return n.digester
+}
+
+// digestCapture offers a non-urgent notification to the digester. A true
+// return means the caller should skip the frontend broadcast; in-process
+// listeners must still run, since they drive internal caches.
+func (n *NotifyRouter) digestCapture(category, summary string) bool {
+	if n.digester == nil {
+		return false
+	}
+	return n.digester.Capture(category, summary)
+}
+
 func (n *NotifyRouter) AddListener(listener NotifyListener) NotifyListenerID {
 	n.Lock()
 	defer n.Unlock()
@@ -520,10 +545,18 @@ func (n *NotifyRouter) HandleTrackingChanged(uid keybase1.UID, username Normaliz
 		Username:   username.String(),
 		IsTracking: isTracking,
 	}
+	// Follow changes are non-urgent; in digest mode we summarize them
+	// instead of pushing each one, but still run the listeners below so
+	// tracking caches stay current.
+	verb := "unfollowed"
+	if isTracking {
+		verb = "followed"
+	}
+	digested := n.digestCapture("follow", fmt.Sprintf("You %s %s", verb, username))
 	// For all connections we currently have open...
 	n.cm.ApplyAll(func(id ConnectionID, xp rpc.Transporter) bool {
 		// If the connection wants the `Tracking` notification type
-		if n.getNotificationChannels(id).Tracking {
+		if !digested && n.getNotificationChannels(id).Tracking {
 			// In the background do...
 			go func() {
 				// A send of a `TrackingChanged` RPC with the user's UID
@@ -562,6 +595,33 @@ func (n *NotifyRouter) HandleWebOfTrustChanged(username string) {
 	})
 }
 
+// HandleTlfShareInvite is called when someone explicitly shares a TLF with
+// the current user.
+func (n *NotifyRouter) HandleTlfShareInvite(ctx context.Context, invite TlfInvite) {
+	if n == nil {
+		return
+	}
+	n.G().Log.CDebugf(ctx, "NotifyRouter#HandleTlfShareInvite: tlf=%s from=%s",
+		invite.Tlf, invite.Inviter)
+	n.runListeners(func(listener NotifyListener) {
+		listener.TlfShareInvite(invite)
+	})
+}
+
+// HandleConfigChanged is called when the service reloads its config file,
+// reporting which changed keys were applied live and which require a
+// restart to take effect.
+func (n *NotifyRouter) HandleConfigChanged(ctx context.Context, applied []string, restartRequired []string) {
+	if n == nil {
+		return
+	}
+	n.G().Log.CDebugf(ctx, "NotifyRouter#HandleConfigChanged: applied=%v restartRequired=%v",
+		applied, restartRequired)
+	n.runListeners(func(listener NotifyListener) {
+		listener.ConfigChanged(applied, restartRequired)
+	})
+}
+
 func (n *NotifyRouter) HandleTrackingInfo(arg keybase1.TrackingInfoArg) {
 	if n == nil {
 		return
@@ -695,10 +755,22 @@ func (n *NotifyRouter) HandleFSActivity(activity keybase1.FSNotification) {
 	if n == nil {
 		return
 	}
+	// File edit activity is non-urgent and noisy; in digest mode we batch
+	// it rather than pinging the desktop per file. Sync/error status stays
+	// immediate, and listeners below always run.
+	digested := false
+	switch activity.NotificationType {
+	case keybase1.FSNotificationType_FILE_CREATED,
+		keybase1.FSNotificationType_FILE_MODIFIED,
+		keybase1.FSNotificationType_FILE_DELETED,
+		keybase1.FSNotificationType_FILE_RENAMED:
+		digested = n.digestCapture("kbfs-edit", fmt.Sprintf("%s: %s",
+			activity.NotificationType, activity.Filename))
+	}
 	// For all connections we currently have open...
 	n.cm.ApplyAll(func(id ConnectionID, xp rpc.Transporter) bool {
 		// If the connection wants the `Kbfsdesktop` notification type
-		if n.getNotificationChannels(id).Kbfsdesktop {
+		if !digested && n.getNotificationChannels(id).Kbfsdesktop {
 			// In the background do...
 			go func() {
 				// A send of a `FSActivity` RPC with the notification
@@ -2082,6 +2154,14 @@ func (n *NotifyRouter) HandleTeamChangedByName(ctx context.Context,
 		Source:              source,
 	}
 
+	// Membership churn is digest-worthy, but TeamChanged notifications also
+	// drive team cache invalidation in the GUI, so we record a summary
+	// without suppressing the broadcast.
+	if changes.MembershipChanged && !implicitTeam && n.digester != nil {
+		n.digester.Capture("team-membership",
+			fmt.Sprintf("Membership changed in %s", teamName))
+	}
+
 	var wg sync.WaitGroup
 	n.G().Log.CDebugf(ctx, "+ Sending TeamChangedByName notification (team:%v, seqno:%v, implicit:%v)",
 		teamName, latestSeqno, implicitTeam)
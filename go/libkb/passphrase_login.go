@@ -19,7 +19,17 @@ func loginWithPassphraseStream(mctx MetaContext, usernameOrEmail string, tsec Tr
 	if err != nil {
 		return err
 	}
-	res, err := pplPost(mctx, usernameOrEmail, pdpka)
+	res, err := pplPost(mctx, usernameOrEmail, pdpka, "")
+	if sfErr, ok := err.(loginSecondFactorRequiredError); ok {
+		// enterprise server wants another factor; solve it and retry the
+		// post within the same login session
+		var response string
+		response, err = mctx.G().GetLoginTwoFactorProvider().Solve(mctx, usernameOrEmail, sfErr.Challenge)
+		if err != nil {
+			return err
+		}
+		res, err = pplPost(mctx, usernameOrEmail, pdpka, response)
+	}
 	if err != nil {
 		return err
 	}
@@ -99,7 +109,7 @@ func pplPromptOnce(m MetaContext, ls *LoginSession, arg keybase1.GUIEntryArg) (e
 func pplGotPassphrase(m MetaContext, usernameOrEmail string, passphrase string, ls *LoginSession) (err error) {
 	defer m.Trace("pplGotPassphrase", &err)()
 
-	tsec, pps, err := StretchPassphrase(m.G(), passphrase, ls.salt)
+	tsec, pps, err := StretchPassphraseContext(m.Ctx(), m.G(), passphrase, ls.salt)
 	if err != nil {
 		return err
 	}
@@ -120,12 +130,23 @@ func pplPromptLoop(m MetaContext, maxAttempts int, ls *LoginSession, arg keybase
 	return err
 }
 
+// loginSecondFactorRequiredError surfaces an enterprise server's demand
+// for a second factor out of pplPost so the caller can solve and retry.
+type loginSecondFactorRequiredError struct {
+	Challenge TwoFactorChallenge
+}
+
+func (e loginSecondFactorRequiredError) Error() string {
+	return fmt.Sprintf("server requires a second factor (%s)", e.Challenge.Type)
+}
+
 type loginReply struct {
-	Status    AppStatus    `json:"status"`
-	Session   string       `json:"session"`
-	CsrfToken string       `json:"csrf_token"`
-	UID       keybase1.UID `json:"uid"`
-	Me        struct {
+	Status       AppStatus           `json:"status"`
+	Session      string              `json:"session"`
+	CsrfToken    string              `json:"csrf_token"`
+	UID          keybase1.UID        `json:"uid"`
+	SecondFactor *TwoFactorChallenge `json:"second_factor,omitempty"`
+	Me           struct {
 		Basics struct {
 			Username             string               `json:"username"`
 			PassphraseGeneration PassphraseGeneration `json:"passphrase_generation"`
@@ -138,7 +159,7 @@ func (l *loginReply) GetAppStatus() *AppStatus {
 	return &l.Status
 }
 
-func pplPost(m MetaContext, eOu string, lp PDPKALoginPackage) (*loginAPIResult, error) {
+func pplPost(m MetaContext, eOu string, lp PDPKALoginPackage, otp string) (*loginAPIResult, error) {
 
 	arg := APIArg{
 		Endpoint:    "login",
@@ -146,7 +167,10 @@ func pplPost(m MetaContext, eOu string, lp PDPKALoginPackage) (*loginAPIResult,
 		Args: HTTPArgs{
 			"email_or_username": S{eOu},
 		},
-		AppStatusCodes: []int{SCOk, SCBadLoginPassword, SCBadLoginUserNotFound},
+		AppStatusCodes: []int{SCOk, SCBadLoginPassword, SCBadLoginUserNotFound, SCLoginSecondFactorNeeded},
+	}
+	if len(otp) > 0 {
+		arg.Args["otp"] = S{otp}
 	}
 	lp.PopulateArgs(&arg.Args)
 	var res loginReply
@@ -162,6 +186,13 @@ func pplPost(m MetaContext, eOu string, lp PDPKALoginPackage) (*loginAPIResult,
 	if res.Status.Code == SCBadLoginUserNotFound {
 		return nil, NotFoundError{}
 	}
+	if res.Status.Code == SCLoginSecondFactorNeeded {
+		var challenge TwoFactorChallenge
+		if res.SecondFactor != nil {
+			challenge = *res.SecondFactor
+		}
+		return nil, loginSecondFactorRequiredError{Challenge: challenge}
+	}
 	return &loginAPIResult{
 		sessionID: res.Session,
 		csrfToken: res.CsrfToken,
@@ -501,7 +532,7 @@ func UnverifiedPassphraseStream(m MetaContext, uid keybase1.UID, passphrase stri
 			return nil, nil, err
 		}
 	}
-	return StretchPassphrase(m.G(), passphrase, salt)
+	return StretchPassphraseContext(m.Ctx(), m.G(), passphrase, salt)
 }
 
 func LoginFromPassphraseStream(mctx MetaContext, username string, pps *PassphraseStream) (err error) {
@@ -512,3 +543,35 @@ func LoginFromPassphraseStream(mctx MetaContext, username string, pps *Passphras
 	}
 	return loginWithPassphraseStream(mctx, username, nil, pps, ls)
 }
+
+// RetryOnStalePassphraseGeneration runs fn, which is expected to use the
+// cached passphrase stream. If fn fails and a secrets sync shows the
+// cached generation went stale mid-flight — the passphrase was changed
+// on another device — the stale cache entry is dropped and fn is retried
+// once, so it re-derives a stream at the new generation.
+func RetryOnStalePassphraseGeneration(m MetaContext, fn func(m MetaContext) error) (err error) {
+	defer m.Trace("RetryOnStalePassphraseGeneration", &err)()
+
+	startGen := m.ActiveDevice().PassphraseStreamCache().Generation()
+	err = fn(m)
+	if err == nil || startGen.IsNil() {
+		return err
+	}
+
+	pps := m.PassphraseStream()
+	if pps == nil {
+		return err
+	}
+	serverGen, syncErr := pps.SyncServerGeneration(m)
+	if syncErr != nil {
+		m.Debug("| cannot check for stale passphrase generation: %s", syncErr)
+		return err
+	}
+	if serverGen <= startGen {
+		return err
+	}
+
+	m.Debug("| operation used stale passphrase generation %d (server has %d); retrying", startGen, serverGen)
+	m.ActiveDevice().ClearPassphraseStreamCacheIfStale(serverGen)
+	return fn(m)
+}
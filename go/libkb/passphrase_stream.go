@@ -9,18 +9,54 @@ import (
 
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	triplesec "github.com/keybase/go-triplesec"
+	context "golang.org/x/net/context"
 )
 
 func NewSecureTriplesec(passphrase []byte, salt []byte) (Triplesec, error) {
 	return triplesec.NewCipher(passphrase, salt, ClientTriplesecVersion)
 }
 
+// PassphraseStretchProgressor is told when an expensive passphrase
+// stretch starts and finishes, so the code driving SecretUI can show a
+// "deriving keys…" indicator while scrypt runs. Callbacks fire on the
+// stretching goroutine and should return quickly.
+type PassphraseStretchProgressor interface {
+	PassphraseStretchStarted()
+	PassphraseStretchFinished()
+}
+
+// GetPassphraseStretchProgressor returns the registered progressor, or
+// nil if none is installed.
+func (g *GlobalContext) GetPassphraseStretchProgressor() PassphraseStretchProgressor {
+	g.cacheMu.RLock()
+	defer g.cacheMu.RUnlock()
+	return g.passphraseStretchProgressor
+}
+
+// SetPassphraseStretchProgressor installs a hook to be notified around
+// passphrase stretches; passing nil clears it.
+func (g *GlobalContext) SetPassphraseStretchProgressor(p PassphraseStretchProgressor) {
+	g.cacheMu.Lock()
+	defer g.cacheMu.Unlock()
+	g.passphraseStretchProgressor = p
+}
+
 func StretchPassphrase(g *GlobalContext, passphrase string, salt []byte) (tsec Triplesec, pps *PassphraseStream, err error) {
+	return StretchPassphraseContext(context.Background(), g, passphrase, salt)
+}
+
+// StretchPassphraseContext is StretchPassphrase with cancellation. The
+// scrypt work itself can't be interrupted once started, so on
+// cancellation the call returns promptly with the context's error while
+// the stretch finishes in the background and its output is discarded.
+func StretchPassphraseContext(ctx context.Context, g *GlobalContext, passphrase string, salt []byte) (tsec Triplesec, pps *PassphraseStream, err error) {
 	if salt == nil {
 		err = fmt.Errorf("no salt provided to StretchPassphrase")
 		return nil, nil, err
 	}
-	var tmp []byte
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	var fn func(pw []byte, salt []byte) (Triplesec, error)
 
 	// free memory on mobile before we do this to reduce chance that we get killed because of the
@@ -34,16 +70,38 @@ func StretchPassphrase(g *GlobalContext, passphrase string, salt []byte) (tsec T
 		fn = g.NewTriplesec
 	}
 
-	tsec, err = fn([]byte(passphrase), salt)
-	if err != nil {
-		return nil, nil, err
+	type stretchRes struct {
+		tsec Triplesec
+		pps  *PassphraseStream
+		err  error
 	}
-	_, tmp, err = tsec.DeriveKey(extraLen)
-	if err != nil {
-		return nil, nil, err
+	resCh := make(chan stretchRes, 1)
+	go func() {
+		if g != nil {
+			if p := g.GetPassphraseStretchProgressor(); p != nil {
+				p.PassphraseStretchStarted()
+				defer p.PassphraseStretchFinished()
+			}
+		}
+		tsec, err := fn([]byte(passphrase), salt)
+		if err != nil {
+			resCh <- stretchRes{err: err}
+			return
+		}
+		_, tmp, err := tsec.DeriveKey(extraLen)
+		if err != nil {
+			resCh <- stretchRes{err: err}
+			return
+		}
+		resCh <- stretchRes{tsec: tsec, pps: NewPassphraseStream(tmp)}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.tsec, res.pps, res.err
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
 	}
-	pps = NewPassphraseStream(tmp)
-	return tsec, pps, nil
 }
 
 const (
@@ -172,16 +230,27 @@ func (ps PassphraseStream) Export() keybase1.PassphraseStream {
 	}
 }
 
-func (ps PassphraseStream) SyncAndCheckIfOutdated(mctx MetaContext) (bool, error) {
+// SyncServerGeneration syncs secrets with the server and returns the
+// passphrase generation it has on file for this device.
+func (ps PassphraseStream) SyncServerGeneration(mctx MetaContext) (PassphraseGeneration, error) {
+	var zed PassphraseGeneration
 	ss, err := mctx.SyncSecrets()
 	if err != nil {
-		return false, err
+		return zed, err
 	}
 
 	key, err := ss.FindDevice(mctx.G().Env.GetDeviceID())
 	if err != nil {
-		return false, err
+		return zed, err
 	}
 
-	return key.PPGen > ps.Generation(), nil
+	return key.PPGen, nil
+}
+
+func (ps PassphraseStream) SyncAndCheckIfOutdated(mctx MetaContext) (bool, error) {
+	serverGen, err := ps.SyncServerGeneration(mctx)
+	if err != nil {
+		return false, err
+	}
+	return serverGen > ps.Generation(), nil
 }
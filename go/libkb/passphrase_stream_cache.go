@@ -158,6 +158,47 @@ func (s *PassphraseStreamCache) ValidTsec() bool {
 	return s.tsec != nil
 }
 
+// Generation returns the passphrase generation of the cached stream, or
+// the nil generation if nothing is cached.
+func (s *PassphraseStreamCache) Generation() PassphraseGeneration {
+	var zed PassphraseGeneration
+	if s == nil {
+		return zed
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.passphraseStream == nil {
+		return zed
+	}
+	return s.passphraseStream.Generation()
+}
+
+// ClearIfStale scrubs the cache only if the cached stream's generation
+// predates ppgen, and reports whether it did. A passphrase update on
+// another device invalidates stale entries this way without racing a
+// concurrent unlock that already refreshed the cache to the new
+// generation.
+func (s *PassphraseStreamCache) ClearIfStale(ppgen PassphraseGeneration) bool {
+	if s == nil {
+		return false
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.passphraseStream == nil {
+		return false
+	}
+	gen := s.passphraseStream.Generation()
+	if gen.IsNil() || gen >= ppgen {
+		return false
+	}
+	if s.tsec != nil {
+		s.tsec.Scrub()
+		s.tsec = nil
+	}
+	s.passphraseStream = nil
+	return true
+}
+
 func (s *PassphraseStreamCache) Clear() {
 	if s == nil {
 		return
@@ -0,0 +1,41 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import "testing"
+
+func TestPassphraseStreamCacheClearIfStale(t *testing.T) {
+	pps := NewPassphraseStream(make([]byte, extraLen))
+	pps.SetGeneration(PassphraseGeneration(2))
+	c := NewPassphraseStreamCache(nil, pps)
+
+	if cleared := c.ClearIfStale(PassphraseGeneration(2)); cleared {
+		t.Errorf("cache at generation 2 cleared for server generation 2")
+	}
+	if cleared := c.ClearIfStale(PassphraseGeneration(1)); cleared {
+		t.Errorf("cache at generation 2 cleared for server generation 1")
+	}
+	if !c.ValidPassphraseStream() {
+		t.Errorf("cache invalidated by no-op ClearIfStale calls")
+	}
+	if gen := c.Generation(); gen != PassphraseGeneration(2) {
+		t.Errorf("generation = %d, expected 2", gen)
+	}
+
+	if cleared := c.ClearIfStale(PassphraseGeneration(3)); !cleared {
+		t.Errorf("cache at generation 2 survived server generation 3")
+	}
+	if c.ValidPassphraseStream() {
+		t.Errorf("cache still valid after ClearIfStale at a newer generation")
+	}
+	if gen := c.Generation(); !gen.IsNil() {
+		t.Errorf("generation = %d after clear, expected nil generation", gen)
+	}
+
+	// A nil cache is a no-op, matching the other methods.
+	var nilCache *PassphraseStreamCache
+	if cleared := nilCache.ClearIfStale(PassphraseGeneration(3)); cleared {
+		t.Errorf("nil cache reported a clear")
+	}
+}
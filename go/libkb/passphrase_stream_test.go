@@ -6,6 +6,8 @@ package libkb
 import (
 	"encoding/hex"
 	"testing"
+
+	context "golang.org/x/net/context"
 )
 
 type dktest struct {
@@ -69,3 +71,44 @@ func TestTSPassKey(t *testing.T) {
 		}
 	}
 }
+
+type testStretchProgressor struct {
+	started  int
+	finished int
+	doneCh   chan struct{}
+}
+
+func (p *testStretchProgressor) PassphraseStretchStarted() { p.started++ }
+func (p *testStretchProgressor) PassphraseStretchFinished() {
+	p.finished++
+	close(p.doneCh)
+}
+
+func TestStretchPassphraseContext(t *testing.T) {
+	test := dktests[0]
+
+	// A context cancelled up front short-circuits before any scrypt work.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := StretchPassphraseContext(ctx, nil, test.passphrase, []byte(test.salt))
+	if err != context.Canceled {
+		t.Fatalf("got err %v, expected context.Canceled", err)
+	}
+
+	// The installed progressor hears about the stretch.
+	g := NewGlobalContext()
+	g.Env = NewEnv(nil, nil, makeLogGetter(t))
+	prog := &testStretchProgressor{doneCh: make(chan struct{})}
+	g.SetPassphraseStretchProgressor(prog)
+	_, dk, err := StretchPassphraseContext(context.Background(), g, test.passphrase, []byte(test.salt))
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if hex.EncodeToString(dk.PWHash()) != test.pwh {
+		t.Errorf("pwh = %x, expected %q", dk.PWHash(), test.pwh)
+	}
+	<-prog.doneCh
+	if prog.started != 1 || prog.finished != 1 {
+		t.Errorf("progressor saw started=%d finished=%d, expected 1/1", prog.started, prog.finished)
+	}
+}
@@ -101,6 +101,24 @@ func MakeProxy(e *Env) func(r *http.Request) (*url.URL, error) {
 	}
 }
 
+// MakeProxyForCircuit is like MakeProxy, but when Tor mode is enabled it
+// attaches the circuit's SOCKS isolation credentials, so each subsystem's
+// streams land on a distinct Tor circuit.
+func MakeProxyForCircuit(e *Env, circuit TorIsolationCircuit) func(r *http.Request) (*url.URL, error) {
+	base := MakeProxy(e)
+	return func(r *http.Request) (*url.URL, error) {
+		u, err := base(r)
+		if err != nil || u == nil {
+			return u, err
+		}
+		if e.GetTorMode().Enabled() {
+			username, password := TorIsolationCredentials(circuit)
+			u.User = url.UserPassword(username, password)
+		}
+		return u, nil
+	}
+}
+
 // Get a string that represents a proxy including the protocol needed for the proxy
 func BuildProxyAddressWithProtocol(proxyType ProxyType, proxyAddress string) string {
 	realProxyAddress := proxyAddress
@@ -238,7 +238,7 @@ func NewSecretStoreLocked(m MetaContext) *SecretStoreLocked {
 	// to remember their passphrase, we don't store it on-disk.
 	return &SecretStoreLocked{
 		mem:  NewSecretStoreMem(),
-		disk: NewSecretStoreAll(m),
+		disk: maybeHardwareBackedSecretStore(m, NewSecretStoreAll(m)),
 	}
 }
 
@@ -0,0 +1,206 @@
+// Copyright 2020 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// HardwareKeyWrapper wraps and unwraps secrets with a key held in
+// platform secure hardware (macOS Secure Enclave, Windows Hello/TPM,
+// TPM2 on Linux). The wrapping key never leaves the hardware; only
+// wrapped ciphertexts are handed back to us for storage on disk.
+// Platform-specific code registers an implementation via
+// SetGlobalHardwareKeyWrapper, much like Android registers its
+// KeyStore with SetGlobalExternalKeyStore.
+type HardwareKeyWrapper interface {
+	// Available reports whether the hardware-backed key can be used
+	// on this device right now.
+	Available(m MetaContext) bool
+	// Description names the backing mechanism for logs and UI, e.g.
+	// "Secure Enclave" or "TPM 2.0".
+	Description() string
+	// Wrap encrypts plaintext under the hardware-resident key.
+	Wrap(m MetaContext, plaintext []byte) ([]byte, error)
+	// Unwrap decrypts a ciphertext previously returned by Wrap.
+	Unwrap(m MetaContext, ciphertext []byte) ([]byte, error)
+}
+
+var hardwareKeyWrapper HardwareKeyWrapper
+var hardwareKeyWrapperMu sync.Mutex
+
+// SetGlobalHardwareKeyWrapper registers the platform's hardware key
+// wrapper. It should be called at most once, early in startup, before
+// the secret store is constructed.
+func SetGlobalHardwareKeyWrapper(w HardwareKeyWrapper) {
+	hardwareKeyWrapperMu.Lock()
+	defer hardwareKeyWrapperMu.Unlock()
+	hardwareKeyWrapper = w
+}
+
+func getGlobalHardwareKeyWrapper() HardwareKeyWrapper {
+	hardwareKeyWrapperMu.Lock()
+	defer hardwareKeyWrapperMu.Unlock()
+	return hardwareKeyWrapper
+}
+
+// HasHardwareSecretStore reports whether this device can wrap secrets
+// with a hardware-resident key, for capability surfacing in the UI.
+func HasHardwareSecretStore(m MetaContext) bool {
+	w := getGlobalHardwareKeyWrapper()
+	return w != nil && w.Available(m)
+}
+
+// SecretStoreHardware is a SecretStoreAll that keeps only
+// hardware-wrapped ciphertexts on disk; the device secret itself
+// never exists unencrypted outside process memory. It is normally
+// layered over the file-based store with SecretStoreUpgradeable so
+// existing secrets migrate over on first retrieval.
+type SecretStoreHardware struct {
+	dir     string
+	wrapper func() HardwareKeyWrapper
+}
+
+var _ SecretStoreAll = (*SecretStoreHardware)(nil)
+
+func NewSecretStoreHardware(dir string) *SecretStoreHardware {
+	return &SecretStoreHardware{
+		dir:     dir,
+		wrapper: getGlobalHardwareKeyWrapper,
+	}
+}
+
+func (s *SecretStoreHardware) getWrapper(m MetaContext) (HardwareKeyWrapper, error) {
+	w := s.wrapper()
+	if w == nil {
+		return nil, SecretStoreError{Msg: "No hardware key wrapper registered"}
+	}
+	if !w.Available(m) {
+		return nil, SecretStoreError{Msg: fmt.Sprintf("Hardware key store (%s) not available", w.Description())}
+	}
+	return w, nil
+}
+
+func (s *SecretStoreHardware) userpath(username NormalizedUsername) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.hss", username))
+}
+
+func (s *SecretStoreHardware) StoreSecret(m MetaContext, username NormalizedUsername, secret LKSecFullSecret) (err error) {
+	defer m.Trace(fmt.Sprintf("SecretStoreHardware.StoreSecret(%s)", username), &err)()
+	w, err := s.getWrapper(m)
+	if err != nil {
+		return err
+	}
+	wrapped, err := w.Wrap(m, secret.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, PermDir); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.dir, username.String())
+	if err != nil {
+		return err
+	}
+	// remove the temp file if it still exists at the end of this function
+	defer func() { _ = ShredFile(tmp.Name()) }()
+	if runtime.GOOS != "windows" {
+		// os.Fchmod not supported on windows
+		if err := tmp.Chmod(PermFile); err != nil {
+			return err
+		}
+	}
+	if _, err := tmp.Write(wrapped); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.userpath(username)); err != nil {
+		return err
+	}
+	return os.Chmod(s.userpath(username), PermFile)
+}
+
+func (s *SecretStoreHardware) RetrieveSecret(m MetaContext, username NormalizedUsername) (secret LKSecFullSecret, err error) {
+	defer m.Trace(fmt.Sprintf("SecretStoreHardware.RetrieveSecret(%s)", username), &err)()
+	w, err := s.getWrapper(m)
+	if err != nil {
+		return LKSecFullSecret{}, err
+	}
+	wrapped, err := ioutil.ReadFile(s.userpath(username))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return LKSecFullSecret{}, NewErrSecretForUserNotFound(username)
+		}
+		return LKSecFullSecret{}, err
+	}
+	plaintext, err := w.Unwrap(m, wrapped)
+	if err != nil {
+		return LKSecFullSecret{}, err
+	}
+	return newLKSecFullSecretFromBytes(plaintext)
+}
+
+func (s *SecretStoreHardware) ClearSecret(m MetaContext, username NormalizedUsername) (err error) {
+	defer m.Trace(fmt.Sprintf("SecretStoreHardware.ClearSecret(%s)", username), &err)()
+	if username.IsNil() {
+		m.Debug("NOOPing SecretStoreHardware#ClearSecret for empty username")
+		return nil
+	}
+	if err := ShredFile(s.userpath(username)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SecretStoreHardware) GetUsersWithStoredSecrets(m MetaContext) (users []string, err error) {
+	defer m.Trace("SecretStoreHardware.GetUsersWithStoredSecrets", &err)()
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.hss"))
+	if err != nil {
+		return nil, err
+	}
+	users = make([]string, 0, len(files))
+	for _, f := range files {
+		uname := stripExt(filepath.Base(f))
+		if !isPPSSecretStore(uname) {
+			users = append(users, uname)
+		}
+	}
+	return users, nil
+}
+
+func (s *SecretStoreHardware) GetOptions(MetaContext) *SecretStoreOptions  { return nil }
+func (s *SecretStoreHardware) SetOptions(MetaContext, *SecretStoreOptions) {}
+
+// maybeHardwareBackedSecretStore layers a hardware-backed store over
+// the platform's disk store when the device supports one. Retrievals
+// that fall through to the old store opportunistically migrate the
+// secret into hardware wrapping; storing falls back to the old store
+// if the hardware errors out.
+func maybeHardwareBackedSecretStore(m MetaContext, disk SecretStoreAll) SecretStoreAll {
+	if disk == nil || !HasHardwareSecretStore(m) {
+		return disk
+	}
+	w := getGlobalHardwareKeyWrapper()
+	hw := NewSecretStoreHardware(m.G().Env.GetDataDir())
+	shouldUpgradeOpportunistically := func() bool {
+		return true
+	}
+	shouldStoreInFallback := func(options *SecretStoreOptions) SecretStoreFallbackBehavior {
+		return SecretStoreFallbackBehaviorOnError
+	}
+	return NewSecretStoreUpgradeable(hw, disk,
+		fmt.Sprintf("hardware-backed secret store (%s)", w.Description()),
+		"platform secret store", shouldUpgradeOpportunistically,
+		shouldStoreInFallback)
+}
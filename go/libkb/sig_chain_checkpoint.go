@@ -0,0 +1,42 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// SigchainCheckpoint is a server-provided claim that a user's sigchain
+// contained the given link at the given seqno. For very long chains it
+// lets VerifyChain skip per-link payload verification below the
+// checkpoint: the client still checks prev-pointer and seqno
+// continuity all the way down from the merkle-anchored tail, confirms
+// that the link at the checkpoint seqno carries the checkpointed link
+// ID, and fully verifies a random spot-check sample of the skipped
+// history. Set KEYBASE_FORCE_FULL_SIGCHAIN_VERIFICATION to ignore
+// checkpoints and verify every link.
+type SigchainCheckpoint struct {
+	Seqno  keybase1.Seqno
+	LinkID LinkID
+}
+
+// sigchainCheckpointSpotChecks is how many links below a checkpoint
+// get full verification anyway, picked at random on every load.
+const sigchainCheckpointSpotChecks = 16
+
+// pickSigchainSpotChecks picks a random sample of seqnos in
+// [1, checkpointSeqno] for full verification. The sample is drawn with
+// replacement, so it may come out smaller than count.
+func pickSigchainSpotChecks(
+	checkpointSeqno keybase1.Seqno, count int) map[keybase1.Seqno]bool {
+	picks := make(map[keybase1.Seqno]bool, count)
+	n := int(checkpointSeqno)
+	if n <= 0 {
+		return picks
+	}
+	for i := 0; i < count; i++ {
+		picks[keybase1.Seqno(RandIntn(n)+1)] = true
+	}
+	return picks
+}
@@ -227,6 +227,9 @@ func (s *SKB) unlockSecretKeyFromSecretRetriever(m MetaContext, secretRetriever
 	if err == nil {
 		key, err = s.parseUnlocked(unlocked)
 	}
+	if err == nil {
+		m.G().KeyUsageLog.Record("skb-unlock", key.GetKID(), "secret-store")
+	}
 	return
 }
 
@@ -280,6 +283,9 @@ func (s *SKB) UnlockSecretKey(m MetaContext, passphrase string, tsec Triplesec,
 		return nil, err
 	}
 	key, err = s.parseUnlocked(unlocked)
+	if err == nil {
+		m.G().KeyUsageLog.Record("skb-unlock", key.GetKID(), "passphrase")
+	}
 	return key, err
 }
 
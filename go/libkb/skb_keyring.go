@@ -1,6 +1,7 @@
 package libkb
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -19,6 +20,7 @@ type SKBKeyringFile struct {
 	sync.Mutex
 	username NormalizedUsername
 	filename string
+	backend  SKBKeyringBackend
 	Blocks   []*SKB
 	fpIndex  map[PGPFingerprint]*SKB
 	kidIndex map[keybase1.KID]*SKB
@@ -30,6 +32,7 @@ func NewSKBKeyringFile(g *GlobalContext, un NormalizedUsername) *SKBKeyringFile
 		Contextified: NewContextified(g),
 		username:     un,
 		filename:     g.SKBFilenameForUser(un),
+		backend:      skbKeyringBackend(g),
 		fpIndex:      make(map[PGPFingerprint]*SKB),
 		kidIndex:     make(map[keybase1.KID]*SKB),
 		dirty:        false,
@@ -53,12 +56,7 @@ func (k *SKBKeyringFile) IsForUsername(un NormalizedUsername) bool {
 func (k *SKBKeyringFile) MTime() (mtime time.Time, err error) {
 	k.Lock()
 	defer k.Unlock()
-	var fi os.FileInfo
-	fi, err = os.Stat(k.filename)
-	if err != nil {
-		return mtime, err
-	}
-	return fi.ModTime(), err
+	return k.backend.MTime(k.G(), k.username)
 }
 
 func (k *SKBKeyringFile) MarkDirty() {
@@ -121,9 +119,9 @@ func decodeSKBPacketList(r io.Reader, g *GlobalContext) ([]*SKB, error) {
 }
 
 func (k *SKBKeyringFile) loadLocked(ctx context.Context) (err error) {
-	k.G().Log.CDebugf(ctx, "+ Loading SKB keyring: %s", k.filename)
+	k.G().Log.CDebugf(ctx, "+ Loading SKB keyring (%s): %s", k.backend.Description(), k.filename)
 
-	file, err := os.OpenFile(k.filename, os.O_RDONLY, 0)
+	raw, err := k.backend.Read(ctx, k.G(), k.username)
 	if err != nil {
 		if os.IsNotExist(err) {
 			k.G().Log.CDebugf(ctx, "| Keybase secret keyring doesn't exist: %s", k.filename)
@@ -134,14 +132,8 @@ func (k *SKBKeyringFile) loadLocked(ctx context.Context) (err error) {
 		}
 		return err
 	}
-	defer func() {
-		closeErr := file.Close()
-		if err == nil {
-			err = closeErr
-		}
-	}()
 
-	stream := base64.NewDecoder(base64.StdEncoding, file)
+	stream := base64.NewDecoder(base64.StdEncoding, bytes.NewReader(raw))
 	skbs, err := decodeSKBPacketList(stream, k.G())
 	if err != nil {
 		return err
@@ -307,13 +299,14 @@ func (k *SKBKeyringFile) saveLocked() error {
 		k.G().Log.Debug("SKBKeyringFile: saveLocked %s: not dirty, so skipping save", k.filename)
 		return nil
 	}
-	if err := MakeParentDirs(k.G().Log, k.filename); err != nil {
-		k.G().Log.Debug("SKBKeyringFile: saveLocked %s: failed to make parent dirs: %s", k.filename, err)
+	k.G().Log.Debug("SKBKeyringFile: saveLocked %s: dirty, saving via %s backend", k.filename, k.backend.Description())
+	var buf bytes.Buffer
+	if _, err := k.WriteTo(&buf); err != nil {
+		k.G().Log.Debug("SKBKeyringFile: saveLocked %s: encoding error: %s", k.filename, err)
 		return err
 	}
-	k.G().Log.Debug("SKBKeyringFile: saveLocked %s: dirty, safe saving", k.filename)
-	if err := SafeWriteToFile(k.G().Log, k, 0); err != nil {
-		k.G().Log.Debug("SKBKeyringFile: saveLocked %s: SafeWriteToFile error: %s", k.filename, err)
+	if err := k.backend.Write(context.Background(), k.G(), k.username, buf.Bytes()); err != nil {
+		k.G().Log.Debug("SKBKeyringFile: saveLocked %s: backend write error: %s", k.filename, err)
 		return err
 	}
 	k.dirty = false
@@ -0,0 +1,175 @@
+package libkb
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	context "golang.org/x/net/context"
+)
+
+// Values for the "secret_keyring_backend" config key (or the
+// KEYBASE_SECRET_KEYRING_BACKEND environment variable), selecting
+// where SKB keyrings are persisted.
+const (
+	SecretKeyringBackendFile     = "file"
+	SecretKeyringBackendMemory   = "memory"
+	SecretKeyringBackendExternal = "external"
+)
+
+// SKBKeyringBackend abstracts where a user's SKB keyring is
+// persisted. The default backend is the historical on-disk file
+// under the home directory; the in-memory backend keeps keys out of
+// the filesystem entirely (for CI and bot deployments), and an
+// externally registered backend can put the keyring in an OS
+// keychain.
+type SKBKeyringBackend interface {
+	// Read returns the raw base64-encoded keyring for the given
+	// user, or an error satisfying os.IsNotExist if there is none.
+	Read(ctx context.Context, g *GlobalContext, un NormalizedUsername) ([]byte, error)
+	// Write persists the raw base64-encoded keyring for the given user.
+	Write(ctx context.Context, g *GlobalContext, un NormalizedUsername, data []byte) error
+	// MTime returns the last time the keyring for the given user was
+	// written, or an error satisfying os.IsNotExist if there is none.
+	MTime(g *GlobalContext, un NormalizedUsername) (time.Time, error)
+	// Description names the backend for logs.
+	Description() string
+}
+
+// skbKeyringFileBackend is the historical backend: one base64 file
+// per user under the secret keyring template path.
+type skbKeyringFileBackend struct{}
+
+var _ SKBKeyringBackend = skbKeyringFileBackend{}
+
+func (b skbKeyringFileBackend) Read(ctx context.Context, g *GlobalContext, un NormalizedUsername) ([]byte, error) {
+	return ioutil.ReadFile(g.SKBFilenameForUser(un))
+}
+
+// skbKeyringFileData adapts a raw keyring blob to the interface
+// required by SafeWriteToFile.
+type skbKeyringFileData struct {
+	filename string
+	data     []byte
+}
+
+func (d skbKeyringFileData) GetFilename() string { return d.filename }
+
+func (d skbKeyringFileData) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(d.data)
+	return int64(n), err
+}
+
+func (b skbKeyringFileBackend) Write(ctx context.Context, g *GlobalContext, un NormalizedUsername, data []byte) error {
+	filename := g.SKBFilenameForUser(un)
+	if err := MakeParentDirs(g.Log, filename); err != nil {
+		return err
+	}
+	return SafeWriteToFile(g.Log, skbKeyringFileData{filename: filename, data: data}, 0)
+}
+
+func (b skbKeyringFileBackend) MTime(g *GlobalContext, un NormalizedUsername) (time.Time, error) {
+	fi, err := os.Stat(g.SKBFilenameForUser(un))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fi.ModTime(), nil
+}
+
+func (b skbKeyringFileBackend) Description() string { return SecretKeyringBackendFile }
+
+// SKBKeyringMem is an ephemeral keyring backend; keys live only in
+// process memory and are gone on restart. Headless deployments that
+// provision a key per run (CI, bots) can select it to avoid writing
+// secret keys to the home directory.
+type SKBKeyringMem struct {
+	sync.Mutex
+	rings  map[NormalizedUsername][]byte
+	mtimes map[NormalizedUsername]time.Time
+}
+
+var _ SKBKeyringBackend = (*SKBKeyringMem)(nil)
+
+func NewSKBKeyringMem() *SKBKeyringMem {
+	return &SKBKeyringMem{
+		rings:  make(map[NormalizedUsername][]byte),
+		mtimes: make(map[NormalizedUsername]time.Time),
+	}
+}
+
+func (b *SKBKeyringMem) Read(ctx context.Context, g *GlobalContext, un NormalizedUsername) ([]byte, error) {
+	b.Lock()
+	defer b.Unlock()
+	data, ok := b.rings[un]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	ret := make([]byte, len(data))
+	copy(ret, data)
+	return ret, nil
+}
+
+func (b *SKBKeyringMem) Write(ctx context.Context, g *GlobalContext, un NormalizedUsername, data []byte) error {
+	b.Lock()
+	defer b.Unlock()
+	cpy := make([]byte, len(data))
+	copy(cpy, data)
+	b.rings[un] = cpy
+	b.mtimes[un] = time.Now()
+	return nil
+}
+
+func (b *SKBKeyringMem) MTime(g *GlobalContext, un NormalizedUsername) (time.Time, error) {
+	b.Lock()
+	defer b.Unlock()
+	mtime, ok := b.mtimes[un]
+	if !ok {
+		return time.Time{}, os.ErrNotExist
+	}
+	return mtime, nil
+}
+
+func (b *SKBKeyringMem) Description() string { return SecretKeyringBackendMemory }
+
+var globalSKBKeyringMem = NewSKBKeyringMem()
+
+// globalSKBKeyringExternal is an optional keychain-backed backend
+// registered by platform-specific code, along the same lines as
+// SetGlobalExternalKeyStore on Android.
+var globalSKBKeyringExternal SKBKeyringBackend
+var globalSKBKeyringExternalMu sync.Mutex
+
+// SetGlobalSKBKeyringBackend registers an externally implemented
+// keyring backend (e.g. one storing the keyring in the OS keychain),
+// selectable with the "external" secret keyring backend setting.
+func SetGlobalSKBKeyringBackend(b SKBKeyringBackend) {
+	globalSKBKeyringExternalMu.Lock()
+	defer globalSKBKeyringExternalMu.Unlock()
+	globalSKBKeyringExternal = b
+}
+
+func getGlobalSKBKeyringExternal() SKBKeyringBackend {
+	globalSKBKeyringExternalMu.Lock()
+	defer globalSKBKeyringExternalMu.Unlock()
+	return globalSKBKeyringExternal
+}
+
+// skbKeyringBackend picks the keyring backend selected by the
+// environment, falling back to the on-disk file when an external
+// backend is requested but none is registered.
+func skbKeyringBackend(g *GlobalContext) SKBKeyringBackend {
+	switch g.Env.GetSecretKeyringBackend() {
+	case SecretKeyringBackendMemory:
+		return globalSKBKeyringMem
+	case SecretKeyringBackendExternal:
+		if b := getGlobalSKBKeyringExternal(); b != nil {
+			return b
+		}
+		g.Log.Warning("external secret keyring backend selected but none registered; using file")
+		return skbKeyringFileBackend{}
+	default:
+		return skbKeyringFileBackend{}
+	}
+}
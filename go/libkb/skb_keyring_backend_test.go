@@ -0,0 +1,39 @@
+package libkb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	context "golang.org/x/net/context"
+)
+
+func TestSKBKeyringMemBackend(t *testing.T) {
+	b := NewSKBKeyringMem()
+	ctx := context.Background()
+	un := NormalizedUsername("alice")
+
+	_, err := b.Read(ctx, nil, un)
+	require.True(t, os.IsNotExist(err))
+	_, err = b.MTime(nil, un)
+	require.True(t, os.IsNotExist(err))
+
+	data := []byte("keyring-bytes")
+	err = b.Write(ctx, nil, un, data)
+	require.NoError(t, err)
+
+	out, err := b.Read(ctx, nil, un)
+	require.NoError(t, err)
+	require.Equal(t, data, out)
+
+	mtime, err := b.MTime(nil, un)
+	require.NoError(t, err)
+	require.False(t, mtime.IsZero())
+
+	// The backend hands back a copy, so callers can't mutate the
+	// stored keyring.
+	out[0] = 'x'
+	out2, err := b.Read(ctx, nil, un)
+	require.NoError(t, err)
+	require.Equal(t, data, out2)
+}
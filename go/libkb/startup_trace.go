@@ -0,0 +1,87 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StartupTraceFileName is the file in the runtime dir where the service
+// records a trace of its most recent startup. The client reads this file
+// directly (rather than over RPC) so the trace is available even when the
+// service is wedged mid-startup.
+const StartupTraceFileName = "startup_trace.json"
+
+// StartupTraceStage is one timed stage of service startup.
+type StartupTraceStage struct {
+	Name       string        `json:"name"`
+	StartedAt  time.Time     `json:"started_at"`
+	Duration   time.Duration `json:"duration_ns"`
+	OffsetFrom time.Duration `json:"offset_ns"` // offset from process start
+}
+
+// StartupTrace records timing for each service init stage so that slow
+// startups ("keybase takes 30s to start") can produce actionable data.
+type StartupTrace struct {
+	sync.Mutex
+	ProcessStart time.Time           `json:"process_start"`
+	FinishedAt   time.Time           `json:"finished_at,omitempty"`
+	Stages       []StartupTraceStage `json:"stages"`
+}
+
+// NewStartupTrace makes a StartupTrace anchored at the current time.
+func NewStartupTrace() *StartupTrace {
+	return &StartupTrace{ProcessStart: time.Now()}
+}
+
+// Stage starts timing a named startup stage and returns a closure that
+// records its completion. Usage: defer trace.Stage("gregor connect")()
+func (t *StartupTrace) Stage(name string) func() {
+	start := time.Now()
+	return func() {
+		t.Lock()
+		defer t.Unlock()
+		t.Stages = append(t.Stages, StartupTraceStage{
+			Name:       name,
+			StartedAt:  start,
+			Duration:   time.Since(start),
+			OffsetFrom: start.Sub(t.ProcessStart),
+		})
+	}
+}
+
+// Finish marks the trace complete and writes it to the runtime dir. Errors
+// are returned for logging but a failed write is not fatal to startup.
+func (t *StartupTrace) Finish(g *GlobalContext) error {
+	t.Lock()
+	t.FinishedAt = time.Now()
+	data, err := json.MarshalIndent(t, "", "  ")
+	t.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(startupTracePath(g), data, PermFile)
+}
+
+// LoadStartupTrace reads the most recent startup trace written by the
+// service, if any.
+func LoadStartupTrace(g *GlobalContext) (*StartupTrace, error) {
+	data, err := os.ReadFile(startupTracePath(g))
+	if err != nil {
+		return nil, err
+	}
+	var t StartupTrace
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func startupTracePath(g *GlobalContext) string {
+	return filepath.Join(g.Env.GetRuntimeDir(), StartupTraceFileName)
+}
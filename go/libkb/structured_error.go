@@ -0,0 +1,124 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"strconv"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// ErrorSubsystem identifies which part of the service produced an error
+// crossing the RPC boundary.
+type ErrorSubsystem string
+
+const (
+	ErrorSubsystemLogin    ErrorSubsystem = "login"
+	ErrorSubsystemIdentify ErrorSubsystem = "identify"
+	ErrorSubsystemChat     ErrorSubsystem = "chat"
+	ErrorSubsystemSimpleFS ErrorSubsystem = "simplefs"
+	ErrorSubsystemTeams    ErrorSubsystem = "teams"
+	ErrorSubsystemKVStore  ErrorSubsystem = "kvstore"
+)
+
+// Keys used in keybase1.Status.Fields for the structured error envelope.
+// Frontends should switch on these instead of string-matching Desc.
+const (
+	ErrorEnvelopeSubsystemKey = "subsystem"
+	ErrorEnvelopeRetryableKey = "retryable"
+	ErrorEnvelopeHintKey      = "remediation"
+)
+
+// StructuredError wraps an error with a stable envelope — subsystem,
+// retryability, and a user-remediation hint — which rides across the RPC
+// boundary in Status.Fields. The wrapped error's own status code, name and
+// description are preserved, so existing code switching on status codes is
+// unaffected.
+type StructuredError struct {
+	Subsystem ErrorSubsystem
+	Retryable bool
+	Hint      string
+	inner     error
+}
+
+var _ ExportableError = StructuredError{}
+
+// NewStructuredError annotates err with an envelope. A nil err returns nil
+// so call sites can wrap unconditionally.
+func NewStructuredError(subsystem ErrorSubsystem, retryable bool, hint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	// Don't double-wrap; inner envelopes are closer to the source.
+	if _, ok := err.(StructuredError); ok {
+		return err
+	}
+	return StructuredError{
+		Subsystem: subsystem,
+		Retryable: retryable,
+		Hint:      hint,
+		inner:     err,
+	}
+}
+
+func (e StructuredError) Error() string {
+	return e.inner.Error()
+}
+
+func (e StructuredError) Unwrap() error {
+	return e.inner
+}
+
+// ToStatus exports the inner error's status and appends the envelope to its
+// Fields.
+func (e StructuredError) ToStatus() keybase1.Status {
+	status := ExportErrorAsStatus(nil, e.inner)
+	if status == nil {
+		return keybase1.Status{}
+	}
+	ret := *status
+	ret.Fields = append(ret.Fields,
+		keybase1.StringKVPair{Key: ErrorEnvelopeSubsystemKey, Value: string(e.Subsystem)},
+		keybase1.StringKVPair{Key: ErrorEnvelopeRetryableKey, Value: strconv.FormatBool(e.Retryable)},
+	)
+	if e.Hint != "" {
+		ret.Fields = append(ret.Fields,
+			keybase1.StringKVPair{Key: ErrorEnvelopeHintKey, Value: e.Hint})
+	}
+	return ret
+}
+
+// ErrorEnvelope is the decoded form of the structured envelope, for clients
+// importing a Status.
+type ErrorEnvelope struct {
+	Subsystem ErrorSubsystem
+	Retryable bool
+	Hint      string
+}
+
+// ImportErrorEnvelope pulls the envelope out of a status, if present.
+func ImportErrorEnvelope(status keybase1.Status) (env ErrorEnvelope, found bool) {
+	for _, field := range status.Fields {
+		switch field.Key {
+		case ErrorEnvelopeSubsystemKey:
+			env.Subsystem = ErrorSubsystem(field.Value)
+			found = true
+		case ErrorEnvelopeRetryableKey:
+			env.Retryable, _ = strconv.ParseBool(field.Value)
+		case ErrorEnvelopeHintKey:
+			env.Hint = field.Value
+		}
+	}
+	return env, found
+}
+
+// ErrorIsRetryable classifies common transient failures, for handlers that
+// don't have better information at the wrap site.
+func ErrorIsRetryable(err error) bool {
+	switch err.(type) {
+	case APINetError, TimeoutError, OfflineError:
+		return true
+	}
+	return false
+}
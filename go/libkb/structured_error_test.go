@@ -0,0 +1,44 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredErrorEnvelope(t *testing.T) {
+	require.NoError(t, NewStructuredError(ErrorSubsystemChat, true, "retry", nil))
+
+	inner := errors.New("boom")
+	err := NewStructuredError(ErrorSubsystemChat, true, "it is safe to retry", inner)
+	serr, ok := err.(StructuredError)
+	require.True(t, ok)
+	require.Equal(t, inner.Error(), serr.Error())
+	require.Equal(t, inner, serr.Unwrap())
+
+	// Wrapping a wrapped error keeps the inner envelope.
+	rewrapped := NewStructuredError(ErrorSubsystemLogin, false, "other", err)
+	require.Equal(t, err, rewrapped)
+
+	status := serr.ToStatus()
+	require.Equal(t, SCGeneric, status.Code)
+	require.Equal(t, "boom", status.Desc)
+
+	env, found := ImportErrorEnvelope(status)
+	require.True(t, found)
+	require.Equal(t, ErrorSubsystemChat, env.Subsystem)
+	require.True(t, env.Retryable)
+	require.Equal(t, "it is safe to retry", env.Hint)
+
+	_, found = ImportErrorEnvelope(ExportErrorAsStatus(nil, inner).DeepCopy())
+	require.False(t, found)
+}
+
+func TestErrorIsRetryable(t *testing.T) {
+	require.True(t, ErrorIsRetryable(TimeoutError{}))
+	require.False(t, ErrorIsRetryable(errors.New("nope")))
+}
@@ -573,6 +573,10 @@ func (f *FakeGregorState) LocalDismissItem(ctx context.Context, id gregor.MsgID)
 	return nil
 }
 
+func (f *FakeGregorState) ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (int, error) {
+	return 0, nil
+}
+
 func (f *FakeGregorState) PeekDismissedIDs() []gregor.MsgID {
 	return f.dismissedIDs
 }
@@ -0,0 +1,78 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// TlfInviteState is the lifecycle state of a TLF share invitation.
+type TlfInviteState string
+
+const (
+	TlfInviteStatePending  TlfInviteState = "pending"
+	TlfInviteStateAccepted TlfInviteState = "accepted"
+	TlfInviteStateDeclined TlfInviteState = "declined"
+)
+
+// TlfInvite is an explicit record that someone shared a TLF with you (e.g.
+// created /keybase/private/me,you), with an optional message. It makes the
+// first contact visible instead of relying on the folder showing up
+// implicitly in favorites.
+type TlfInvite struct {
+	ID      string         `json:"invite_id"`
+	Tlf     string         `json:"tlf"`
+	Inviter string         `json:"inviter"`
+	Invitee string         `json:"invitee"`
+	Message string         `json:"message"`
+	State   TlfInviteState `json:"state"`
+	Ctime   keybase1.Time  `json:"ctime"`
+}
+
+type tlfInviteListRes struct {
+	AppStatusEmbed
+	Invites []TlfInvite `json:"invites"`
+}
+
+// SendTlfInvite records a share invitation for the given TLF, addressed to
+// assertion, with an optional message shown to the invitee.
+func SendTlfInvite(mctx MetaContext, tlf, assertion, message string) error {
+	_, err := mctx.G().API.Post(mctx, APIArg{
+		Endpoint:    "kbfs/share_invite/send",
+		SessionType: APISessionTypeREQUIRED,
+		Args: HTTPArgs{
+			"tlf":       S{Val: tlf},
+			"assertion": S{Val: assertion},
+			"message":   S{Val: message},
+		},
+	})
+	return err
+}
+
+// ListTlfInvites returns the current user's share invitations, both sent
+// and received.
+func ListTlfInvites(mctx MetaContext) ([]TlfInvite, error) {
+	var res tlfInviteListRes
+	err := mctx.G().API.GetDecode(mctx, APIArg{
+		Endpoint:    "kbfs/share_invite/list",
+		SessionType: APISessionTypeREQUIRED,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Invites, nil
+}
+
+// RespondTlfInvite accepts or declines a received share invitation.
+func RespondTlfInvite(mctx MetaContext, inviteID string, accept bool) error {
+	_, err := mctx.G().API.Post(mctx, APIArg{
+		Endpoint:    "kbfs/share_invite/respond",
+		SessionType: APISessionTypeREQUIRED,
+		Args: HTTPArgs{
+			"invite_id": S{Val: inviteID},
+			"accept":    B{Val: accept},
+		},
+	})
+	return err
+}
@@ -6,6 +6,8 @@ package libkb
 import (
 	"fmt"
 	"net/url"
+	"strings"
+	"sync"
 )
 
 type TorMode int
@@ -56,3 +58,102 @@ func TorParseProxy(s string) (*url.URL, error) {
 	}
 	return url.Parse(s)
 }
+
+// TorIsolationCircuit names a logical subsystem whose Tor traffic rides its
+// own SOCKS circuit. Isolation works through Tor's IsolateSOCKSAuth
+// behavior: streams carrying different SOCKS credentials are never put on
+// the same circuit, so the API server, gregor, KBFS servers and chat
+// attachment fetches cannot be correlated at an exit.
+type TorIsolationCircuit string
+
+const (
+	TorCircuitAPI             TorIsolationCircuit = "api"
+	TorCircuitGregor          TorIsolationCircuit = "gregor"
+	TorCircuitMDServer        TorIsolationCircuit = "mdserver"
+	TorCircuitBlockServer     TorIsolationCircuit = "bserver"
+	TorCircuitChatAttachments TorIsolationCircuit = "chat-attachments"
+	TorCircuitAvatars         TorIsolationCircuit = "avatars"
+)
+
+// TorIsolationCircuits lists every circuit, for status reporting.
+var TorIsolationCircuits = []TorIsolationCircuit{
+	TorCircuitAPI,
+	TorCircuitGregor,
+	TorCircuitMDServer,
+	TorCircuitBlockServer,
+	TorCircuitChatAttachments,
+	TorCircuitAvatars,
+}
+
+var torIsolationNonceOnce sync.Once
+var torIsolationNonce string
+
+// torSessionNonce returns a random per-process value mixed into the SOCKS
+// password, so circuits are also fresh across restarts.
+func torSessionNonce() string {
+	torIsolationNonceOnce.Do(func() {
+		torIsolationNonce, _ = RandString("", 8)
+	})
+	return torIsolationNonce
+}
+
+// TorIsolationCredentials returns the SOCKS auth pair for a circuit.
+func TorIsolationCredentials(circuit TorIsolationCircuit) (username, password string) {
+	return "keybase-" + string(circuit), torSessionNonce()
+}
+
+// torProxyURLForAddress builds a socks5:// URL for the given Tor SOCKS
+// address carrying the circuit's isolation credentials. The socks5 scheme
+// matters: it makes the http library hand hostnames to the proxy unresolved,
+// so DNS never leaks outside Tor.
+func torProxyURLForAddress(addr string, circuit TorIsolationCircuit) (*url.URL, error) {
+	u, err := url.Parse("socks5://" + addr)
+	if err != nil {
+		return nil, err
+	}
+	username, password := TorIsolationCredentials(circuit)
+	u.User = url.UserPassword(username, password)
+	return u, nil
+}
+
+// TorProxyURLForCircuit returns the proxy URL a subsystem should use for
+// its isolated circuit, or nil if Tor mode is off.
+func TorProxyURLForCircuit(e *Env, circuit TorIsolationCircuit) (*url.URL, error) {
+	if !e.GetTorMode().Enabled() {
+		return nil, nil
+	}
+	return torProxyURLForAddress(e.GetTorProxy(), circuit)
+}
+
+// TorIsolationStatus describes the current Tor configuration for
+// `keybase ctl tor-status`.
+type TorIsolationStatus struct {
+	Mode          TorMode
+	ProxyAddress  string
+	HiddenAddress string
+	OnionAPI      bool
+	DNSLeakSafe   bool
+	CircuitUsers  map[TorIsolationCircuit]string
+}
+
+// GetTorIsolationStatus reports how traffic is isolated under the current
+// configuration.
+func GetTorIsolationStatus(e *Env) TorIsolationStatus {
+	res := TorIsolationStatus{
+		Mode:         e.GetTorMode(),
+		CircuitUsers: make(map[TorIsolationCircuit]string),
+	}
+	if !res.Mode.Enabled() {
+		return res
+	}
+	res.ProxyAddress = e.GetTorProxy()
+	res.HiddenAddress = e.GetTorHiddenAddress()
+	res.OnionAPI = strings.Contains(res.HiddenAddress, ".onion")
+	// hostnames go to the SOCKS proxy unresolved under socks5://
+	res.DNSLeakSafe = true
+	for _, circuit := range TorIsolationCircuits {
+		username, _ := TorIsolationCredentials(circuit)
+		res.CircuitUsers[circuit] = username
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTorIsolationCredentials(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, circuit := range TorIsolationCircuits {
+		username, password := TorIsolationCredentials(circuit)
+		require.False(t, seen[username], "username reused across circuits")
+		seen[username] = true
+		require.NotEmpty(t, password)
+
+		// credentials are stable within a process, so a subsystem's
+		// streams share one circuit
+		username2, password2 := TorIsolationCredentials(circuit)
+		require.Equal(t, username, username2)
+		require.Equal(t, password, password2)
+	}
+}
+
+func TestTorProxyURLForAddress(t *testing.T) {
+	u, err := torProxyURLForAddress("localhost:9050", TorCircuitAPI)
+	require.NoError(t, err)
+	require.Equal(t, "socks5", u.Scheme)
+	require.Equal(t, "localhost:9050", u.Host)
+	require.Equal(t, "keybase-api", u.User.Username())
+	password, set := u.User.Password()
+	require.True(t, set)
+	require.NotEmpty(t, password)
+}
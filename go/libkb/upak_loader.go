@@ -3,6 +3,7 @@ package libkb
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"sync"
@@ -36,6 +37,7 @@ type UPAKLoader interface {
 	LoadV2WithKID(ctx context.Context, uid keybase1.UID, kid keybase1.KID) (*keybase1.UserPlusKeysV2AllIncarnations, error)
 	CheckDeviceForUIDAndUsername(ctx context.Context, uid keybase1.UID, did keybase1.DeviceID, n NormalizedUsername, suppressNetworkErrors bool) error
 	Batcher(ctx context.Context, getArg func(int) *LoadUserArg, processResult func(int, *keybase1.UserPlusKeysV2AllIncarnations) error, window int) (err error)
+	RefreshFrequentlyUsed(ctx context.Context, maxUsers int) (int, error)
 }
 
 // CachedUPAKLoader is a UPAKLoader implementation that can cache results both
@@ -49,8 +51,14 @@ type CachedUPAKLoader struct {
 	noCache        bool
 	TestDeadlocker func()
 	currentUID     keybase1.UID
+	usageMu        sync.Mutex
+	usage          map[keybase1.UID]int
 }
 
+// upakUsageTrackLimit caps how many distinct UIDs we count loads for;
+// beyond it, new UIDs just aren't tracked until the map resets.
+const upakUsageTrackLimit = 1024
+
 // NewCachedUPAKLoader constructs a new CachedUPAKLoader
 func NewCachedUPAKLoader(g *GlobalContext, f time.Duration) *CachedUPAKLoader {
 	c, err := lru.New(g.Env.GetUPAKCacheSize())
@@ -63,6 +71,7 @@ func NewCachedUPAKLoader(g *GlobalContext, f time.Duration) *CachedUPAKLoader {
 		cache:        c,
 		noCache:      false,
 		locktab:      NewLockTable(),
+		usage:        make(map[keybase1.UID]int),
 	}
 }
 
@@ -83,6 +92,58 @@ func (u *CachedUPAKLoader) OnLogout() (err error) {
 	return u.LoginAs(keybase1.UID(""))
 }
 
+// recordUsage counts a load of the given UID, feeding the
+// frequently-used set that the background refresher keeps fresh.
+func (u *CachedUPAKLoader) recordUsage(uid keybase1.UID) {
+	if u.noCache || uid.IsNil() {
+		return
+	}
+	u.usageMu.Lock()
+	defer u.usageMu.Unlock()
+	if u.usage == nil {
+		return
+	}
+	if _, found := u.usage[uid]; !found && len(u.usage) >= upakUsageTrackLimit {
+		return
+	}
+	u.usage[uid]++
+}
+
+// mostUsedUIDs returns up to maxUsers UIDs ordered by load count.
+func (u *CachedUPAKLoader) mostUsedUIDs(maxUsers int) []keybase1.UID {
+	u.usageMu.Lock()
+	defer u.usageMu.Unlock()
+	uids := make([]keybase1.UID, 0, len(u.usage))
+	for uid := range u.usage {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return u.usage[uids[i]] > u.usage[uids[j]] })
+	if len(uids) > maxUsers {
+		uids = uids[:maxUsers]
+	}
+	return uids
+}
+
+// RefreshFrequentlyUsed reloads the most frequently used UPAKs (team
+// members, recent chat partners, and anyone else we keep looking up) so
+// stale entries are refreshed ahead of need. Loads go through the usual
+// cache freshness logic, so entries still within the freshness window
+// cost nothing. Returns the number of users loaded without error.
+func (u *CachedUPAKLoader) RefreshFrequentlyUsed(ctx context.Context, maxUsers int) (n int, err error) {
+	if u.noCache {
+		return 0, nil
+	}
+	for _, uid := range u.mostUsedUIDs(maxUsers) {
+		arg := NewLoadUserArgWithContext(ctx, u.G()).WithUID(uid).WithPublicKeyOptional()
+		if _, _, err := u.loadWithInfo(arg, nil, nil, false); err != nil {
+			u.G().Log.CDebugf(ctx, "RefreshFrequentlyUsed: skipping %s: %s", uid, err)
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
 // NewUncachedUPAKLoader creates a UPAK loader that doesn't do any caching.
 // It uses the implementation of CachedUPAKLoader but disables all caching.
 func NewUncachedUPAKLoader(g *GlobalContext) UPAKLoader {
@@ -376,6 +437,8 @@ func (u *CachedUPAKLoader) loadWithInfo(arg LoadUserArg, info *CachedUserLoadInf
 		}
 	}
 
+	u.recordUsage(arg.uid)
+
 	var lock *NamedLock
 	if !arg.cachedOnly {
 		lock = u.locktab.AcquireOnName(ctx, g, arg.uid.String())
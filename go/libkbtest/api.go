@@ -0,0 +1,121 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkbtest
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	jsonw "github.com/keybase/go-jsonw"
+	"golang.org/x/net/context"
+)
+
+// FakeAPIServer is an in-process libkb.API implementation that serves
+// canned JSON responses per endpoint and records every call. Endpoints
+// without a canned response get an empty OK body, so most code paths run
+// without further setup.
+type FakeAPIServer struct {
+	sync.Mutex
+	responses map[string]*libkb.APIRes
+	calls     []libkb.APIArg
+}
+
+var _ libkb.API = (*FakeAPIServer)(nil)
+
+// NewFakeAPIServer makes an empty FakeAPIServer. Install it on a
+// GlobalContext with g.API = server.
+func NewFakeAPIServer() *FakeAPIServer {
+	return &FakeAPIServer{responses: make(map[string]*libkb.APIRes)}
+}
+
+// HandleJSON installs a canned JSON body for an endpoint (e.g.
+// "user/lookup"). The body should include a "status" object if the caller
+// under test checks app status.
+func (s *FakeAPIServer) HandleJSON(endpoint string, body string) error {
+	jw, err := jsonw.Unmarshal([]byte(body))
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.responses[endpoint] = &libkb.APIRes{
+		Body:       jw,
+		Status:     jw.AtKey("status"),
+		HTTPStatus: http.StatusOK,
+		AppStatus:  &libkb.AppStatus{Code: libkb.SCOk, Name: "OK"},
+	}
+	return nil
+}
+
+// Calls returns the args of every request served so far.
+func (s *FakeAPIServer) Calls() []libkb.APIArg {
+	s.Lock()
+	defer s.Unlock()
+	ret := make([]libkb.APIArg, len(s.calls))
+	copy(ret, s.calls)
+	return ret
+}
+
+func (s *FakeAPIServer) serve(arg libkb.APIArg) (*libkb.APIRes, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.calls = append(s.calls, arg)
+	if res, ok := s.responses[arg.Endpoint]; ok {
+		return res, nil
+	}
+	body := jsonw.NewDictionary()
+	return &libkb.APIRes{
+		Body:       body,
+		Status:     body.AtKey("status"),
+		HTTPStatus: http.StatusOK,
+		AppStatus:  &libkb.AppStatus{Code: libkb.SCOk, Name: "OK"},
+	}, nil
+}
+
+func (s *FakeAPIServer) Get(_ libkb.MetaContext, arg libkb.APIArg) (*libkb.APIRes, error) {
+	return s.serve(arg)
+}
+
+func (s *FakeAPIServer) GetDecode(_ libkb.MetaContext, arg libkb.APIArg, _ libkb.APIResponseWrapper) error {
+	_, err := s.serve(arg)
+	return err
+}
+
+func (s *FakeAPIServer) GetDecodeCtx(_ context.Context, arg libkb.APIArg, _ libkb.APIResponseWrapper) error {
+	_, err := s.serve(arg)
+	return err
+}
+
+func (s *FakeAPIServer) GetResp(_ libkb.MetaContext, arg libkb.APIArg) (*http.Response, func(), error) {
+	_, err := s.serve(arg)
+	return nil, func() {}, err
+}
+
+func (s *FakeAPIServer) Post(_ libkb.MetaContext, arg libkb.APIArg) (*libkb.APIRes, error) {
+	return s.serve(arg)
+}
+
+func (s *FakeAPIServer) PostJSON(_ libkb.MetaContext, arg libkb.APIArg) (*libkb.APIRes, error) {
+	return s.serve(arg)
+}
+
+func (s *FakeAPIServer) PostDecode(_ libkb.MetaContext, arg libkb.APIArg, _ libkb.APIResponseWrapper) error {
+	_, err := s.serve(arg)
+	return err
+}
+
+func (s *FakeAPIServer) PostDecodeCtx(_ context.Context, arg libkb.APIArg, _ libkb.APIResponseWrapper) error {
+	_, err := s.serve(arg)
+	return err
+}
+
+func (s *FakeAPIServer) PostRaw(_ libkb.MetaContext, arg libkb.APIArg, _ string, _ io.Reader) (*libkb.APIRes, error) {
+	return s.serve(arg)
+}
+
+func (s *FakeAPIServer) Delete(_ libkb.MetaContext, arg libkb.APIArg) (*libkb.APIRes, error) {
+	return s.serve(arg)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkbtest
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/keybase/client/go/gregor"
+	"github.com/keybase/client/go/gregor/storage"
+	"github.com/keybase/client/go/logger"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/clockwork"
+	"golang.org/x/net/context"
+)
+
+// FakeGregord is an in-memory gregor server backed by the same state
+// machine the real gregord uses. It implements the subset of
+// gregor1.IncomingInterface the client exercises during sync and consume.
+type FakeGregord struct {
+	sm  gregor.StateMachine
+	fc  clockwork.FakeClock
+	log logger.Logger
+}
+
+// NewFakeGregord makes a FakeGregord with a fake clock that advances one
+// minute per injected message.
+func NewFakeGregord(log logger.Logger) *FakeGregord {
+	fc := clockwork.NewFakeClock()
+	return &FakeGregord{
+		sm:  storage.NewMemEngine(gregor1.ObjFactory{}, fc, log),
+		fc:  fc,
+		log: log,
+	}
+}
+
+// NewMessage builds an in-band state-update message for uid in the given
+// category. Feed it to ConsumeMessage to simulate a server push.
+func (g *FakeGregord) NewMessage(uid gregor1.UID, category gregor1.Category, body gregor1.Body) gregor1.Message {
+	g.fc.Advance(time.Minute)
+	return gregor1.Message{
+		Ibm_: &gregor1.InBandMessage{
+			StateUpdate_: &gregor1.StateUpdateMessage{
+				Md_: gregor1.Metadata{
+					Uid_:   uid,
+					MsgID_: newFakeMsgID(),
+					Ctime_: gregor1.ToTime(g.fc.Now()),
+				},
+				Creation_: &gregor1.Item{
+					Category_: category,
+					Body_:     body,
+				},
+			},
+		},
+	}
+}
+
+// NewDismissal builds a dismissal of a previously consumed message.
+func (g *FakeGregord) NewDismissal(uid gregor1.UID, msg gregor.Message) gregor.Message {
+	g.fc.Advance(time.Minute)
+	dismissalID := msg.ToInBandMessage().Metadata().MsgID().(gregor1.MsgID)
+	return gregor1.Message{
+		Ibm_: &gregor1.InBandMessage{
+			StateUpdate_: &gregor1.StateUpdateMessage{
+				Md_: gregor1.Metadata{
+					Uid_:   uid,
+					MsgID_: newFakeMsgID(),
+					Ctime_: gregor1.ToTime(g.fc.Now()),
+				},
+				Dismissal_: &gregor1.Dismissal{
+					MsgIDs_: []gregor1.MsgID{dismissalID},
+				},
+			},
+		},
+	}
+}
+
+func (g *FakeGregord) SyncAll(ctx context.Context, arg chat1.SyncAllArg) (res chat1.SyncAllResult, err error) {
+	sres, err := g.Sync(ctx, gregor1.SyncArg{
+		Uid:      arg.Uid,
+		Deviceid: arg.DeviceID,
+		Ctime:    arg.Ctime,
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Notification = chat1.NewSyncAllNotificationResWithIncremental(sres)
+	return res, nil
+}
+
+func (g *FakeGregord) Sync(ctx context.Context, arg gregor1.SyncArg) (gregor1.SyncResult, error) {
+	var res gregor1.SyncResult
+	msgs, err := g.sm.InBandMessagesSince(ctx, arg.UID(), arg.DeviceID(), arg.CTime())
+	if err != nil {
+		return res, err
+	}
+	state, err := g.sm.State(ctx, arg.UID(), arg.DeviceID(), nil)
+	if err != nil {
+		return res, err
+	}
+	hash, err := state.Hash()
+	if err != nil {
+		return res, err
+	}
+	for _, msg := range msgs {
+		if msg, ok := msg.(gregor1.InBandMessage); ok {
+			res.Msgs = append(res.Msgs, msg)
+		} else {
+			g.log.Warning("FakeGregord: bad cast in Sync (type=%T)", msg)
+		}
+	}
+	res.Hash = hash
+	return res, nil
+}
+
+func (g *FakeGregord) ConsumeMessage(ctx context.Context, msg gregor1.Message) error {
+	_, err := g.sm.ConsumeMessage(ctx, msg)
+	return err
+}
+
+func (g *FakeGregord) ConsumeMessageMulti(ctx context.Context, arg gregor1.ConsumeMessageMultiArg) error {
+	return errors.New("unimplemented")
+}
+
+func (g *FakeGregord) ConsumePublishMessage(_ context.Context, _ gregor1.Message) error {
+	return errors.New("unimplemented")
+}
+
+func (g *FakeGregord) Ping(_ context.Context) (string, error) {
+	return "pong", nil
+}
+
+func (g *FakeGregord) State(ctx context.Context, arg gregor1.StateArg) (gregor1.State, error) {
+	state, err := g.sm.State(ctx, arg.Uid, arg.Deviceid, arg.TimeOrOffset)
+	if err != nil {
+		return gregor1.State{}, err
+	}
+	return state.(gregor1.State), nil
+}
+
+func (g *FakeGregord) StateByCategoryPrefix(_ context.Context, _ gregor1.StateByCategoryPrefixArg) (gregor1.State, error) {
+	return gregor1.State{}, errors.New("unimplemented")
+}
+
+func (g *FakeGregord) Version(_ context.Context, _ gregor1.UID) (string, error) {
+	return "fake", nil
+}
+
+func (g *FakeGregord) DescribeConnectedUsers(_ context.Context, _ []gregor1.UID) ([]gregor1.ConnectedUser, error) {
+	return nil, nil
+}
+
+func (g *FakeGregord) DescribeConnectedUsersInternal(_ context.Context, _ []gregor1.UID) ([]gregor1.ConnectedUser, error) {
+	return nil, nil
+}
+
+func newFakeMsgID() gregor1.MsgID {
+	ret := make([]byte, 16)
+	_, _ = rand.Read(ret)
+	return ret
+}
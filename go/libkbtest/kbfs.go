@@ -0,0 +1,23 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkbtest
+
+import (
+	"github.com/keybase/client/go/kbfs/libkbfs"
+	kbname "github.com/keybase/client/go/kbun"
+	"github.com/keybase/client/go/logger"
+)
+
+// NewKBFSConfig returns a KBFS config wired to in-memory MD, block, and
+// key servers, with the listed users provisioned locally and the first one
+// logged in. The returned config is the same one KBFS's own unit tests run
+// against; shut it down with libkbfs.CheckConfigAndShutdown or
+// config.Shutdown when done.
+func NewKBFSConfig(t logger.TestLogBackend, users ...string) *libkbfs.ConfigLocal {
+	usernames := make([]kbname.NormalizedUsername, len(users))
+	for i, user := range users {
+		usernames[i] = kbname.NormalizedUsername(user)
+	}
+	return libkbfs.MakeTestConfigOrBust(t, usernames...)
+}
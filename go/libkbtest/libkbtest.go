@@ -0,0 +1,9 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package libkbtest exports an in-process test harness for third-party Go
+// programs that embed the client libraries and want hermetic integration
+// tests: a fake Keybase API server, in-memory KBFS MD/block servers, and an
+// in-memory gregor. It repackages fixtures that previously lived inside
+// _test.go files and so were unavailable to external consumers.
+package libkbtest
@@ -0,0 +1,152 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	logging "github.com/keybase/go-logging"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Module log levels form a hierarchy on dotted names: an override set
+// for "kbfs" covers "kbfs.prefetcher" too, unless the submodule has a
+// more specific override of its own. Overrides survive the global debug
+// flag: Configure() with debug on raises the default level for modules
+// without an override, but leaves overridden modules alone.
+
+// moduleOverrides maps a module name (or dotted prefix) to its
+// explicitly requested level. Guarded by initLoggingSetLevelMutex along
+// with the rest of the module level state.
+var moduleOverrides = map[string]logging.Level{}
+
+// defaultModuleLevel is the level applied to modules with no override.
+// It starts at INFO and moves to DEBUG when Configure() runs with the
+// debug flag on.
+var defaultModuleLevel = logging.INFO
+
+func convertLogLevel(level keybase1.LogLevel) (logging.Level, error) {
+	switch level {
+	case keybase1.LogLevel_DEBUG:
+		return logging.DEBUG, nil
+	case keybase1.LogLevel_INFO:
+		return logging.INFO, nil
+	case keybase1.LogLevel_NOTICE:
+		return logging.NOTICE, nil
+	case keybase1.LogLevel_WARN:
+		return logging.WARNING, nil
+	case keybase1.LogLevel_ERROR:
+		return logging.ERROR, nil
+	case keybase1.LogLevel_CRITICAL, keybase1.LogLevel_FATAL:
+		return logging.CRITICAL, nil
+	default:
+		return logging.ERROR, fmt.Errorf("log level %v cannot be applied to a module", level)
+	}
+}
+
+func exportLogLevel(level logging.Level) keybase1.LogLevel {
+	switch level {
+	case logging.DEBUG:
+		return keybase1.LogLevel_DEBUG
+	case logging.INFO:
+		return keybase1.LogLevel_INFO
+	case logging.NOTICE:
+		return keybase1.LogLevel_NOTICE
+	case logging.WARNING:
+		return keybase1.LogLevel_WARN
+	case logging.ERROR:
+		return keybase1.LogLevel_ERROR
+	default:
+		return keybase1.LogLevel_CRITICAL
+	}
+}
+
+// ParseLogLevel converts a level name like "debug" or "WARN" into a
+// keybase1.LogLevel.
+func ParseLogLevel(s string) (keybase1.LogLevel, error) {
+	if level, ok := keybase1.LogLevelMap[strings.ToUpper(s)]; ok {
+		return level, nil
+	}
+	return keybase1.LogLevel_NONE, fmt.Errorf("unknown log level %q", s)
+}
+
+// overrideForModule returns the override with the longest matching
+// dotted prefix, if any. Caller holds initLoggingSetLevelMutex.
+func overrideForModule(module string) (logging.Level, bool) {
+	for {
+		if level, found := moduleOverrides[module]; found {
+			return level, true
+		}
+		idx := strings.LastIndex(module, ".")
+		if idx < 0 {
+			return defaultModuleLevel, false
+		}
+		module = module[:idx]
+	}
+}
+
+// levelForModule returns the level a module should log at, given the
+// current overrides and default. Caller holds initLoggingSetLevelMutex.
+func levelForModule(module string) logging.Level {
+	level, _ := overrideForModule(module)
+	return level
+}
+
+// SetModuleLevel overrides the log level for module and any of its
+// dotted submodules without a more specific override.
+func SetModuleLevel(module string, level keybase1.LogLevel) error {
+	converted, err := convertLogLevel(level)
+	if err != nil {
+		return err
+	}
+	initLoggingSetLevelMutex.Lock()
+	defer initLoggingSetLevelMutex.Unlock()
+	moduleOverrides[module] = converted
+	reapplyModuleLevels()
+	return nil
+}
+
+// ClearModuleLevel removes the override for module, returning it (and
+// its submodules) to the default level.
+func ClearModuleLevel(module string) {
+	initLoggingSetLevelMutex.Lock()
+	defer initLoggingSetLevelMutex.Unlock()
+	delete(moduleOverrides, module)
+	reapplyModuleLevels()
+}
+
+// reapplyModuleLevels pushes the current overrides and default onto
+// every module seen so far. Caller holds initLoggingSetLevelMutex.
+func reapplyModuleLevels() {
+	for module := range initLoggingSetLevelCalled {
+		logging.SetLevel(levelForModule(module), module)
+	}
+}
+
+// ModuleLogLevel describes the current level of one registered module.
+type ModuleLogLevel struct {
+	Module     string
+	Level      keybase1.LogLevel
+	Overridden bool
+}
+
+// ListModuleLevels reports every module registered so far with its
+// current level, sorted by module name.
+func ListModuleLevels() (res []ModuleLogLevel) {
+	initLoggingSetLevelMutex.Lock()
+	defer initLoggingSetLevelMutex.Unlock()
+	for module := range initLoggingSetLevelCalled {
+		_, overridden := overrideForModule(module)
+		res = append(res, ModuleLogLevel{
+			Module:     module,
+			Level:      exportLogLevel(logging.GetLevel(module)),
+			Overridden: overridden,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Module < res[j].Module })
+	return res
+}
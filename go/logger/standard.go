@@ -136,7 +136,7 @@ func (log *Standard) setLogLevelInfo() {
 	defer initLoggingSetLevelMutex.Unlock()
 
 	if _, found := initLoggingSetLevelCalled[log.module]; !found {
-		logging.SetLevel(logging.INFO, log.module)
+		logging.SetLevel(levelForModule(log.module), log.module)
 		initLoggingSetLevelCalled[log.module] = struct{}{}
 	}
 }
@@ -303,7 +303,12 @@ func (log *Standard) Configure(style string, debug bool, filename string) {
 	}
 
 	if debug {
-		logging.SetLevel(logging.DEBUG, log.module)
+		initLoggingSetLevelMutex.Lock()
+		defaultModuleLevel = logging.DEBUG
+		if _, overridden := overrideForModule(log.module); !overridden {
+			logging.SetLevel(logging.DEBUG, log.module)
+		}
+		initLoggingSetLevelMutex.Unlock()
 	}
 
 	logging.SetFormatter(logging.MustStringFormatter(logfmt))
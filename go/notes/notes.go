@@ -0,0 +1,328 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package notes implements a simple collaborative notes store for teams.
+// Each note is a file in the team's KBFS folder, so notes are team-keyed and
+// end-to-end encrypted, and revision history comes for free from KBFS
+// archived revisions. Edits are conflict-safe: writers state the revision
+// they edited and the write is refused if someone else got there first.
+package notes
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// notesDir is the directory inside the team folder that holds notes.
+const notesDir = ".kbnotes"
+const noteExt = ".md"
+
+const readBufSize = 1024 * 1024
+
+// NoteInfo describes a note without its contents.
+type NoteInfo struct {
+	Name string
+	Size int64
+	Time keybase1.Time
+}
+
+// NoteRevision is one entry of a note's revision history.
+type NoteRevision struct {
+	Revision keybase1.KBFSRevision
+	Time     keybase1.Time
+}
+
+// NoteConflictError is returned by Edit when the note changed since the
+// revision the caller read.
+type NoteConflictError struct {
+	Name     string
+	Expected keybase1.KBFSRevision
+	Current  keybase1.KBFSRevision
+}
+
+func (e NoteConflictError) Error() string {
+	return fmt.Sprintf("note %s changed since revision %d (now %d); re-read and retry", e.Name,
+		e.Expected, e.Current)
+}
+
+// Store reads and writes a team's notes over SimpleFS.
+type Store struct {
+	libkb.Contextified
+	cli  keybase1.SimpleFSClient
+	team string
+}
+
+func NewStore(g *libkb.GlobalContext, cli keybase1.SimpleFSClient, team string) *Store {
+	return &Store{
+		Contextified: libkb.NewContextified(g),
+		cli:          cli,
+		team:         team,
+	}
+}
+
+func checkNoteName(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("note name required")
+	}
+	if strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return fmt.Errorf("invalid note name: %s", name)
+	}
+	return nil
+}
+
+func (s *Store) notePathStr(name string) string {
+	return fmt.Sprintf("/team/%s/%s/%s%s", s.team, notesDir, name, noteExt)
+}
+
+func (s *Store) notePath(name string) keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(s.notePathStr(name))
+}
+
+func (s *Store) dirPath() keybase1.Path {
+	return keybase1.NewPathWithKbfsPath(fmt.Sprintf("/team/%s/%s", s.team, notesDir))
+}
+
+// DeepLink returns a link to the note that chat renders as a clickable
+// KBFS path.
+func DeepLink(team, name string) string {
+	return fmt.Sprintf("keybase://team/%s/%s/%s%s", team, notesDir, name, noteExt)
+}
+
+func (s *Store) readFile(ctx context.Context, path keybase1.Path) (res []byte, err error) {
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	}); err != nil {
+		return nil, err
+	}
+	var offset int64
+	for {
+		content, err := s.cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   readBufSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(content.Data) == 0 {
+			break
+		}
+		res = append(res, content.Data...)
+		offset += int64(len(content.Data))
+	}
+	return res, nil
+}
+
+func (s *Store) writeFile(ctx context.Context, path keybase1.Path, dat []byte,
+	flags keybase1.OpenFlags) error {
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  s.dirPath(),
+		Flags: keybase1.OpenFlags_DIRECTORY,
+	}); err != nil {
+		return err
+	}
+	s.cli.SimpleFSClose(ctx, opid)
+	if opid, err = s.cli.SimpleFSMakeOpid(ctx); err != nil {
+		return err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  path,
+		Flags: flags,
+	}); err != nil {
+		return err
+	}
+	var offset int64
+	if flags&keybase1.OpenFlags_APPEND != 0 {
+		e, err := s.cli.SimpleFSStat(ctx, keybase1.SimpleFSStatArg{Path: path})
+		if err == nil {
+			offset = int64(e.Size)
+		}
+	}
+	return s.cli.SimpleFSWrite(ctx, keybase1.SimpleFSWriteArg{
+		OpID:    opid,
+		Offset:  offset,
+		Content: dat,
+	})
+}
+
+// List returns the team's notes.
+func (s *Store) List(ctx context.Context) (res []NoteInfo, err error) {
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSList(ctx, keybase1.SimpleFSListArg{
+		OpID: opid,
+		Path: s.dirPath(),
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.cli.SimpleFSWait(ctx, opid); err != nil {
+		// no notes yet
+		return nil, nil
+	}
+	for {
+		listResult, err := s.cli.SimpleFSReadList(ctx, opid)
+		if err != nil {
+			break
+		}
+		for _, e := range listResult.Entries {
+			if !strings.HasSuffix(e.Name, noteExt) {
+				continue
+			}
+			res = append(res, NoteInfo{
+				Name: strings.TrimSuffix(e.Name, noteExt),
+				Size: int64(e.Size),
+				Time: e.Time,
+			})
+		}
+		if len(listResult.Entries) == 0 {
+			break
+		}
+	}
+	return res, nil
+}
+
+// Read returns the note's contents along with the KBFS revision they came
+// from, for use as the expected revision of a later Edit.
+func (s *Store) Read(ctx context.Context, name string) (contents string,
+	revision keybase1.KBFSRevision, err error) {
+	if err := checkNoteName(name); err != nil {
+		return "", 0, err
+	}
+	dat, err := s.readFile(ctx, s.notePath(name))
+	if err != nil {
+		return "", 0, err
+	}
+	if revision, err = s.currentRevision(ctx, name); err != nil {
+		return "", 0, err
+	}
+	return string(dat), revision, nil
+}
+
+// ReadAtRevision returns the note's contents as of the given KBFS revision,
+// through an archived path.
+func (s *Store) ReadAtRevision(ctx context.Context, name string,
+	revision keybase1.KBFSRevision) (contents string, err error) {
+	if err := checkNoteName(name); err != nil {
+		return "", err
+	}
+	path := keybase1.NewPathWithKbfsArchived(keybase1.KBFSArchivedPath{
+		Path:          s.notePathStr(name),
+		ArchivedParam: keybase1.NewKBFSArchivedParamWithRevision(revision),
+	})
+	dat, err := s.readFile(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(dat), nil
+}
+
+// Append adds text to the end of a note, creating it if needed. Appends
+// don't need a revision check since they can't clobber other edits.
+func (s *Store) Append(ctx context.Context, name, text string) error {
+	if err := checkNoteName(name); err != nil {
+		return err
+	}
+	return s.writeFile(ctx, s.notePath(name), []byte(text),
+		keybase1.OpenFlags_WRITE|keybase1.OpenFlags_APPEND)
+}
+
+// Edit replaces a note's contents. expectedRevision is the revision the
+// caller read before editing; if the note has moved on since then the edit
+// fails with NoteConflictError. A zero expectedRevision skips the check and
+// is only appropriate when creating a new note.
+func (s *Store) Edit(ctx context.Context, name, contents string,
+	expectedRevision keybase1.KBFSRevision) error {
+	if err := checkNoteName(name); err != nil {
+		return err
+	}
+	if expectedRevision != 0 {
+		current, err := s.currentRevision(ctx, name)
+		if err != nil {
+			return err
+		}
+		if current != expectedRevision {
+			return NoteConflictError{
+				Name:     name,
+				Expected: expectedRevision,
+				Current:  current,
+			}
+		}
+	}
+	return s.writeFile(ctx, s.notePath(name), []byte(contents),
+		keybase1.OpenFlags_WRITE|keybase1.OpenFlags_REPLACE)
+}
+
+// Revisions returns the note's recent revision history.
+func (s *Store) Revisions(ctx context.Context, name string) (res []NoteRevision, err error) {
+	if err := checkNoteName(name); err != nil {
+		return nil, err
+	}
+	revs, err := s.getRevisions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, rev := range revs {
+		res = append(res, NoteRevision{
+			Revision: rev.Revision,
+			Time:     rev.Entry.Time,
+		})
+	}
+	return res, nil
+}
+
+func (s *Store) currentRevision(ctx context.Context, name string) (keybase1.KBFSRevision, error) {
+	revs, err := s.getRevisions(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(revs) == 0 {
+		return 0, nil
+	}
+	return revs[0].Revision, nil
+}
+
+func (s *Store) getRevisions(ctx context.Context, name string) (
+	res []keybase1.DirentWithRevision, err error) {
+	opid, err := s.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cli.SimpleFSClose(ctx, opid)
+	if err := s.cli.SimpleFSGetRevisions(ctx, keybase1.SimpleFSGetRevisionsArg{
+		OpID:     opid,
+		Path:     s.notePath(name),
+		SpanType: keybase1.RevisionSpanType_LAST_FIVE,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.cli.SimpleFSWait(ctx, opid); err != nil {
+		return nil, err
+	}
+	result, err := s.cli.SimpleFSReadRevisions(ctx, opid)
+	if err != nil {
+		return nil, err
+	}
+	return result.Revisions, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteNames(t *testing.T) {
+	require.NoError(t, checkNoteName("roadmap"))
+	require.NoError(t, checkNoteName("meeting notes 2026"))
+	require.Error(t, checkNoteName(""))
+	require.Error(t, checkNoteName("../escape"))
+	require.Error(t, checkNoteName("a/b"))
+	require.Error(t, checkNoteName(".."))
+}
+
+func TestNoteDeepLink(t *testing.T) {
+	require.Equal(t, "keybase://team/acme.eng/.kbnotes/roadmap.md", DeepLink("acme.eng", "roadmap"))
+}
+
+func TestNoteConflictError(t *testing.T) {
+	err := NoteConflictError{Name: "roadmap", Expected: 4, Current: 6}
+	require.Contains(t, err.Error(), "roadmap")
+	require.Contains(t, err.Error(), "re-read")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/install"
 	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/logger"
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	"github.com/keybase/client/go/status"
 	"github.com/keybase/go-framed-msgpack-rpc/rpc"
@@ -125,9 +126,25 @@ func (h ConfigHandler) setValue(_ context.Context, arg keybase1.SetValueArg, w l
 			h.G().Log.Debug("setValue: error reloading: %+v", reloadErr)
 		}
 	}
+	if err == nil && strings.HasPrefix(arg.Path, logLevelsConfigPrefix) && arg.Value.S != nil {
+		err = applyModuleLogLevel(strings.TrimPrefix(arg.Path, logLevelsConfigPrefix), *arg.Value.S)
+	}
 	return err
 }
 
+// logLevelsConfigPrefix holds per-module log level overrides in the
+// config file, applied live by `keybase log set-level` and again at
+// service startup.
+const logLevelsConfigPrefix = "log_levels."
+
+func applyModuleLogLevel(module string, levelName string) error {
+	level, err := logger.ParseLogLevel(levelName)
+	if err != nil {
+		return err
+	}
+	return logger.SetModuleLevel(module, level)
+}
+
 func (h ConfigHandler) GuiClearValue(ctx context.Context, path string) error {
 	return h.clearValue(ctx, path, h.G().Env.GetGUIConfig())
 }
@@ -138,6 +155,9 @@ func (h ConfigHandler) ClearValue(ctx context.Context, path string) error {
 
 func (h ConfigHandler) clearValue(_ context.Context, path string, w libkb.JSONWriter) error {
 	w.DeleteAtPath(path)
+	if strings.HasPrefix(path, logLevelsConfigPrefix) {
+		logger.ClearModuleLevel(strings.TrimPrefix(path, logLevelsConfigPrefix))
+	}
 	return h.G().ConfigReload()
 }
 
@@ -0,0 +1,157 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+const configWatcherPollInterval = 5 * time.Second
+
+// configSafeKeys are top-level config keys the service can apply without a
+// restart. Everything else that changes is reported as restart-required.
+var configSafeKeys = map[string]bool{
+	"debug":            true,
+	"vdebug":           true,
+	"log_format":       true,
+	"proxy":            true,
+	"proxy-type":       true,
+	"api_timeout":      true,
+	"sync_bandwidth":   true,
+	"slow_gregor_conn": true,
+}
+
+// configWatcher polls the config file for edits (made by hand or via
+// `keybase config set`), reloads it, applies safe changes in place, and
+// notifies listeners which keys were applied and which need a restart.
+type configWatcher struct {
+	libkb.Contextified
+	stopCh chan struct{}
+
+	mtime time.Time
+	last  map[string]interface{}
+}
+
+func newConfigWatcher(g *libkb.GlobalContext) *configWatcher {
+	return &configWatcher{
+		Contextified: libkb.NewContextified(g),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+func (w *configWatcher) Start(ctx context.Context) {
+	w.mtime, w.last = w.snapshot()
+	go w.loop(ctx)
+}
+
+func (w *configWatcher) Stop() {
+	close(w.stopCh)
+}
+
+func (w *configWatcher) loop(ctx context.Context) {
+	ticker := time.NewTicker(configWatcherPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.check(ctx)
+		case <-w.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// snapshot reads the config file into a generic map for diffing. Errors are
+// tolerated; an unreadable config is treated as empty.
+func (w *configWatcher) snapshot() (time.Time, map[string]interface{}) {
+	filename := w.G().Env.GetConfigFilename()
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fi.ModTime(), nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fi.ModTime(), nil
+	}
+	return fi.ModTime(), m
+}
+
+func (w *configWatcher) check(ctx context.Context) {
+	mtime, current := w.snapshot()
+	if !mtime.After(w.mtime) {
+		return
+	}
+	prev := w.last
+	w.mtime, w.last = mtime, current
+
+	applied, restartRequired := diffConfigKeys(prev, current)
+	if len(applied) == 0 && len(restartRequired) == 0 {
+		return
+	}
+
+	w.G().Log.CDebugf(ctx, "configWatcher: config file changed; applied=%v restartRequired=%v",
+		applied, restartRequired)
+
+	if err := w.G().ConfigReload(); err != nil {
+		w.G().Log.CDebugf(ctx, "configWatcher: reload error: %s", err)
+		return
+	}
+	for _, key := range applied {
+		w.apply(ctx, key)
+	}
+	w.G().NotifyRouter.HandleConfigChanged(ctx, applied, restartRequired)
+}
+
+// apply re-runs the relevant configuration step for a safe key, now that
+// the reloaded config is in place.
+func (w *configWatcher) apply(ctx context.Context, key string) {
+	var err error
+	switch key {
+	case "debug", "vdebug", "log_format":
+		err = w.G().ConfigureLogging(nil)
+	case "proxy", "proxy-type", "api_timeout":
+		err = w.G().ConfigureAPI()
+	default:
+		// Keys like rate limits and sync bandwidth are read from Env on
+		// each use, so the reload alone is enough.
+	}
+	if err != nil {
+		w.G().Log.CDebugf(ctx, "configWatcher: error applying %q: %s", key, err)
+	}
+}
+
+// diffConfigKeys returns the changed top-level keys split into those we can
+// apply live and those requiring a restart.
+func diffConfigKeys(prev, current map[string]interface{}) (applied, restartRequired []string) {
+	seen := make(map[string]bool, len(prev)+len(current))
+	for key := range prev {
+		seen[key] = true
+	}
+	for key := range current {
+		seen[key] = true
+	}
+	for key := range seen {
+		if reflect.DeepEqual(prev[key], current[key]) {
+			continue
+		}
+		if configSafeKeys[key] {
+			applied = append(applied, key)
+		} else {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	return applied, restartRequired
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigKeys(t *testing.T) {
+	prev := map[string]interface{}{
+		"debug":    false,
+		"proxy":    "",
+		"mountdir": "/keybase",
+	}
+	current := map[string]interface{}{
+		"debug":    true,
+		"proxy":    "socks5://localhost:9050",
+		"mountdir": "/mnt/keybase",
+	}
+	applied, restartRequired := diffConfigKeys(prev, current)
+	require.ElementsMatch(t, []string{"debug", "proxy"}, applied)
+	require.ElementsMatch(t, []string{"mountdir"}, restartRequired)
+
+	applied, restartRequired = diffConfigKeys(current, current)
+	require.Empty(t, applied)
+	require.Empty(t, restartRequired)
+
+	// Removed keys count as changes too.
+	applied, restartRequired = diffConfigKeys(current, map[string]interface{}{})
+	require.ElementsMatch(t, []string{"debug", "proxy"}, applied)
+	require.ElementsMatch(t, []string{"mountdir"}, restartRequired)
+}
@@ -4,13 +4,20 @@
 package service
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/keybase/client/go/badges"
 	"github.com/keybase/client/go/libkb"
+	chat1 "github.com/keybase/client/go/protocol/chat1"
 	gregor1 "github.com/keybase/client/go/protocol/gregor1"
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	stellar1 "github.com/keybase/client/go/protocol/stellar1"
+	"github.com/keybase/client/go/stellar"
 	"github.com/keybase/go-framed-msgpack-rpc/rpc"
 	"golang.org/x/net/context"
 )
@@ -18,16 +25,20 @@ import (
 type DebuggingHandler struct {
 	libkb.Contextified
 	*BaseHandler
-	userHandler   *UserHandler
-	walletHandler *walletHandler
+	userHandler       *UserHandler
+	walletHandler     *walletHandler
+	userSearchHandler *UserSearchHandler
+	badger            *badges.Badger
 }
 
-func NewDebuggingHandler(xp rpc.Transporter, g *libkb.GlobalContext, userHandler *UserHandler, walletHandler *walletHandler) *DebuggingHandler {
+func NewDebuggingHandler(xp rpc.Transporter, g *libkb.GlobalContext, userHandler *UserHandler, walletHandler *walletHandler, userSearchHandler *UserSearchHandler, badger *badges.Badger) *DebuggingHandler {
 	return &DebuggingHandler{
-		Contextified:  libkb.NewContextified(g),
-		BaseHandler:   NewBaseHandler(g, xp),
-		userHandler:   userHandler,
-		walletHandler: walletHandler,
+		Contextified:      libkb.NewContextified(g),
+		BaseHandler:       NewBaseHandler(g, xp),
+		userHandler:       userHandler,
+		walletHandler:     walletHandler,
+		userSearchHandler: userSearchHandler,
+		badger:            badger,
 	}
 }
 
@@ -82,6 +93,380 @@ func (t *DebuggingHandler) Script(ctx context.Context, arg keybase1.ScriptArg) (
 		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
 		summary, err := t.G().ChatHelper.JourneycardDebugState(m.Ctx(), uidGregor, teamID)
 		return summary, err
+	case "gregor-replay":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: gregor-replay <category-prefix>")
+		}
+		n, err := t.G().GregorState.ReplayCategory(m.Ctx(), gregor1.Category(args[0]))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("replayed %d item(s) in category %q", n, args[0]), nil
+	case "chat-requests-list":
+		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+		convs, err := t.G().ChatHelper.ListMessageRequests(m.Ctx(), uidGregor)
+		if err != nil {
+			return "", err
+		}
+		if len(convs) == 0 {
+			return "no pending message requests", nil
+		}
+		var lines []string
+		for _, conv := range convs {
+			lines = append(lines, fmt.Sprintf("%s %s",
+				conv.GetConvID(), conv.Info.TlfName))
+		}
+		return strings.Join(lines, "\n"), nil
+	case "chat-requests-accept":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: chat-requests-accept <conv-id>")
+		}
+		convID, err := chat1.MakeConvID(args[0])
+		if err != nil {
+			return "", err
+		}
+		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+		if err := t.G().ChatHelper.AcceptMessageRequest(m.Ctx(), uidGregor, convID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("accepted message request %s", convID), nil
+	case "chat-mark-all-read":
+		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+		marked, err := t.G().ChatHelper.MarkAllAsRead(m.Ctx(), uidGregor)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("marked %d conversation(s) as read", marked), nil
+	case "chat-savedsearch-list":
+		out, err := t.G().ChatHelper.ListChatSavedSearches(m.Ctx())
+		if err != nil {
+			return "", err
+		}
+		if out == "null" {
+			return "no saved searches", nil
+		}
+		return out, nil
+	case "chat-savedsearch-put":
+		if len(args) < 1 {
+			return "", fmt.Errorf(
+				"usage: chat-savedsearch-put <name> [+unread] [+team] [+attachment] [query...]")
+		}
+		var unreadOnly, teamsOnly, hasAttachment bool
+		var queryWords []string
+		for _, tok := range args[1:] {
+			switch tok {
+			case "+unread":
+				unreadOnly = true
+			case "+team":
+				teamsOnly = true
+			case "+attachment":
+				hasAttachment = true
+			default:
+				queryWords = append(queryWords, tok)
+			}
+		}
+		if err := t.G().ChatHelper.PutChatSavedSearch(m.Ctx(), args[0],
+			strings.Join(queryWords, " "), unreadOnly, teamsOnly, hasAttachment); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("saved search %q", args[0]), nil
+	case "chat-savedsearch-remove":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: chat-savedsearch-remove <name>")
+		}
+		if err := t.G().ChatHelper.RemoveChatSavedSearch(m.Ctx(), args[0]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("removed saved search %q", args[0]), nil
+	case "chat-savedsearch-run":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: chat-savedsearch-run <name>")
+		}
+		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+		convs, err := t.G().ChatHelper.RunChatSavedSearch(m.Ctx(), uidGregor, args[0])
+		if err != nil {
+			return "", err
+		}
+		if len(convs) == 0 {
+			return "no conversations matched", nil
+		}
+		var lines []string
+		for _, conv := range convs {
+			lines = append(lines, fmt.Sprintf("%s %s",
+				conv.GetConvID(), conv.Info.TlfName))
+		}
+		return strings.Join(lines, "\n"), nil
+	case "attachment-rekey-start":
+		if len(args) < 1 || len(args) > 2 {
+			return "", fmt.Errorf("usage: attachment-rekey-start <days> [kbps]")
+		}
+		days, err := strconv.Atoi(args[0])
+		if err != nil || days <= 0 {
+			return "", fmt.Errorf("bad days: %v", args[0])
+		}
+		var bwKBps int
+		if len(args) == 2 {
+			if bwKBps, err = strconv.Atoi(args[1]); err != nil || bwKBps <= 0 {
+				return "", fmt.Errorf("bad kbps: %v", args[1])
+			}
+		}
+		uidGregor := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+		age := time.Duration(days) * 24 * time.Hour
+		if err := t.G().ChatHelper.StartAttachmentRekey(m.Ctx(), uidGregor, age, bwKBps); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("started attachment rekey covering the last %d day(s)", days), nil
+	case "attachment-rekey-stop":
+		t.G().ChatHelper.StopAttachmentRekey(m.Ctx())
+		return "stopped attachment rekey", nil
+	case "attachment-rekey-status":
+		return t.G().ChatHelper.AttachmentRekeyStatus(m.Ctx())
+	case "person-search":
+		if len(args) < 1 {
+			return "", fmt.Errorf("usage: person-search <query...>")
+		}
+		query := strings.Join(args, " ")
+		results, err := t.userSearchHandler.UnifiedPersonSearch(
+			m, query, 20, defaultPersonPickerWeights())
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "no results", nil
+		}
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "wallet-addressbook-list":
+		entries, err := stellar.AddressBookList(m)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) == 0 {
+			return "address book is empty", nil
+		}
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "wallet-addressbook-put":
+		if len(args) < 1 {
+			return "", fmt.Errorf("usage: wallet-addressbook-put <account-id> [label]")
+		}
+		label := strings.Join(args[1:], " ")
+		entry, err := stellar.AddressBookPut(m, stellar1.AccountID(args[0]), label)
+		if err != nil {
+			return "", err
+		}
+		if entry.KeybaseUser != "" {
+			return fmt.Sprintf("saved %v (%v)", entry.AccountID, entry.KeybaseUser), nil
+		}
+		return fmt.Sprintf("saved %v", entry.AccountID), nil
+	case "wallet-addressbook-remove":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: wallet-addressbook-remove <account-id>")
+		}
+		if err := stellar.AddressBookRemove(m, stellar1.AccountID(args[0])); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("removed %v", args[0]), nil
+	case "wallet-addressbook-import":
+		added, err := stellar.AddressBookImportFromHistory(
+			m, t.walletHandler.Remoter())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("imported %d address(es) from payment history", added), nil
+	case "maintenance-windows-set":
+		windows := make([]libkb.MaintenanceWindow, 0, len(args))
+		for _, spec := range args {
+			window, err := libkb.ParseMaintenanceWindow(spec)
+			if err != nil {
+				return "", err
+			}
+			windows = append(windows, window)
+		}
+		t.G().Maintenance.SetWindows(windows)
+		if len(windows) == 0 {
+			return "windows cleared; using AC-power-and-idle policy", nil
+		}
+		return fmt.Sprintf("set %d maintenance window(s)", len(windows)), nil
+	case "maintenance-power":
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: maintenance-power <ac|battery> <idle|active>")
+		}
+		onAC := args[0] == "ac"
+		if !onAC && args[0] != "battery" {
+			return "", fmt.Errorf("bad power state: %v", args[0])
+		}
+		idle := args[1] == "idle"
+		if !idle && args[1] != "active" {
+			return "", fmt.Errorf("bad idle state: %v", args[1])
+		}
+		t.G().Maintenance.SetPowerState(onAC, idle)
+		return "power state recorded", nil
+	case "maintenance-status":
+		status := t.G().Maintenance.Status()
+		out, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "maintenance-force":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: maintenance-force <job>")
+		}
+		if err := t.G().Maintenance.ForceRun(m.Ctx(), args[0]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ran %v", args[0]), nil
+	case "approval-request":
+		if len(args) < 1 {
+			return "", fmt.Errorf("usage: approval-request <operation...>")
+		}
+		id, err := RequestDeviceApproval(m, strings.Join(args, " "))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("request %s posted; wait with approval-wait %s", id, id), nil
+	case "approval-wait":
+		if len(args) < 1 || len(args) > 2 {
+			return "", fmt.Errorf("usage: approval-wait <request-id> [seconds]")
+		}
+		timeout := 120 * time.Second
+		if len(args) == 2 {
+			secs, err := strconv.Atoi(args[1])
+			if err != nil || secs <= 0 {
+				return "", fmt.Errorf("invalid timeout %q", args[1])
+			}
+			timeout = time.Duration(secs) * time.Second
+		}
+		approved, err := AwaitDeviceApproval(m, args[0], timeout)
+		if err != nil {
+			return "", err
+		}
+		if !approved {
+			return fmt.Sprintf("request %s denied", args[0]), nil
+		}
+		return fmt.Sprintf("request %s approved", args[0]), nil
+	case "approval-pending":
+		pending, err := PendingDeviceApproval(m)
+		if err != nil {
+			return "", err
+		}
+		if pending == nil {
+			return "no pending approval requests", nil
+		}
+		out, err := json.MarshalIndent(pending, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "approval-respond":
+		if len(args) != 2 || (args[1] != "approve" && args[1] != "deny") {
+			return "", fmt.Errorf("usage: approval-respond <request-id> <approve|deny>")
+		}
+		if err := RespondDeviceApproval(m, args[0], args[1] == "approve"); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("responded %s to request %s", args[1], args[0]), nil
+	case "endpoint-status":
+		status := t.G().Endpoints.Status()
+		out, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "endpoint-measure":
+		for _, uri := range args {
+			t.G().Endpoints.AddCandidate(uri)
+		}
+		serverURI, err := t.G().Env.GetServerURI()
+		if err != nil {
+			return "", err
+		}
+		measurements := t.G().Endpoints.Measure(serverURI)
+		out, err := json.MarshalIndent(measurements, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "endpoint-pin":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: endpoint-pin <server-uri>")
+		}
+		t.G().Endpoints.Pin(args[0])
+		return fmt.Sprintf("pinned %s (takes effect for new API clients)", args[0]), nil
+	case "endpoint-unpin":
+		t.G().Endpoints.Unpin()
+		return "unpinned; back to measurement-based selection", nil
+	case "badge-snooze":
+		if t.badger == nil {
+			return "", fmt.Errorf("no badger available")
+		}
+		if len(args) < 1 {
+			return "", fmt.Errorf("usage: badge-snooze <category> [duration]")
+		}
+		duration := time.Hour
+		if len(args) >= 2 {
+			duration, err = time.ParseDuration(args[1])
+			if err != nil {
+				return "", err
+			}
+		}
+		until := t.G().Clock().Now().Add(duration)
+		if err := t.badger.SnoozeCategory(ctx, badges.BadgeCategory(args[0]), until); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("snoozed %q until %s", args[0], until.Format(time.RFC3339)), nil
+	case "badge-unsnooze":
+		if t.badger == nil {
+			return "", fmt.Errorf("no badger available")
+		}
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: badge-unsnooze <category>")
+		}
+		if err := t.badger.UnsnoozeCategory(ctx, badges.BadgeCategory(args[0])); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("unsnoozed %q", args[0]), nil
+	case "badge-snoozes":
+		if t.badger == nil {
+			return "", fmt.Errorf("no badger available")
+		}
+		snoozes := t.badger.Snoozes()
+		if len(snoozes) == 0 {
+			return "no categories snoozed", nil
+		}
+		var lines []string
+		for category, until := range snoozes {
+			lines = append(lines, fmt.Sprintf("%s until %s", category, until.Format(time.RFC3339)))
+		}
+		sort.Strings(lines)
+		return strings.Join(lines, "\n"), nil
+	case "key-usage":
+		entries := t.G().KeyUsageLog.Entries()
+		if len(entries) == 0 {
+			return "no key usage recorded yet", nil
+		}
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "api-budget":
+		budgets := t.G().APIBudgets.All()
+		if len(budgets) == 0 {
+			return "no rate limit state observed yet", nil
+		}
+		out, err := json.MarshalIndent(budgets, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
 	case "":
 		return "", fmt.Errorf("empty script name")
 	default:
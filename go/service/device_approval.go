@@ -0,0 +1,189 @@
+// Copyright 2019 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+// Delegated approval for sensitive CLI operations. The requesting
+// device posts a request into a dedicated self-conversation (topic
+// type DEV, so the GUI doesn't render it as chat), another of the
+// user's devices reviews the pending request there and posts a
+// response, and the requester polls for that response until a
+// deadline. Messages ride the existing chat channel, so they're
+// end-to-end encrypted and reach all devices without new server
+// support.
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	chat1 "github.com/keybase/client/go/protocol/chat1"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+const (
+	deviceApprovalTopicName    = "device-approvals"
+	deviceApprovalKindRequest  = "request"
+	deviceApprovalKindResponse = "response"
+	// deviceApprovalLifetime bounds how long a request stays actionable
+	// on the responding side.
+	deviceApprovalLifetime     = 10 * time.Minute
+	deviceApprovalPollInterval = 2 * time.Second
+)
+
+// deviceApprovalMsg is the JSON payload of one protocol message in the
+// approvals conversation.
+type deviceApprovalMsg struct {
+	Kind      string `json:"kind"` // "request" or "response"
+	ID        string `json:"id"`
+	Operation string `json:"operation,omitempty"`
+	// Device is the name of the device that posted the message.
+	Device    string `json:"device"`
+	Approve   bool   `json:"approve,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"` // unix seconds
+}
+
+func (msg deviceApprovalMsg) expired(now time.Time) bool {
+	return msg.ExpiresAt != 0 && now.Unix() > msg.ExpiresAt
+}
+
+func deviceApprovalConv(m libkb.MetaContext) (chat1.ConversationLocal, error) {
+	uid := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+	username := m.G().GetEnv().GetUsername().String()
+	topicName := deviceApprovalTopicName
+	conv, _, err := m.G().ChatHelper.NewConversation(m.Ctx(), uid, username,
+		&topicName, chat1.TopicType_DEV, chat1.ConversationMembersType_IMPTEAMNATIVE,
+		keybase1.TLFVisibility_PRIVATE)
+	return conv, err
+}
+
+func sendDeviceApprovalMsg(m libkb.MetaContext, conv chat1.ConversationLocal,
+	msg deviceApprovalMsg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return m.G().ChatHelper.SendTextByID(m.Ctx(), conv.GetConvID(),
+		conv.Info.TlfName, string(body), keybase1.TLFVisibility_PRIVATE)
+}
+
+// latestDeviceApprovalMsg returns the newest protocol message in the
+// approvals conversation, or nil if there is none (or the latest text
+// message isn't one of ours).
+func latestDeviceApprovalMsg(m libkb.MetaContext, conv chat1.ConversationLocal) (
+	*deviceApprovalMsg, error) {
+	summary, err := conv.GetMaxMessage(chat1.MessageType_TEXT)
+	if err != nil {
+		// No text messages in the conversation yet.
+		return nil, nil
+	}
+	uid := gregor1.UID(m.G().ActiveDevice.UID().ToBytes())
+	unboxed, err := m.G().ChatHelper.GetMessage(m.Ctx(), uid, conv.GetConvID(),
+		summary.GetMessageID(), true /* resolveSupersedes */, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !unboxed.IsValid() || !unboxed.Valid().MessageBody.IsType(chat1.MessageType_TEXT) {
+		return nil, nil
+	}
+	var msg deviceApprovalMsg
+	if err := json.Unmarshal([]byte(unboxed.Valid().MessageBody.Text().Body), &msg); err != nil {
+		// Not a protocol message; somebody chatted in the conv.
+		return nil, nil
+	}
+	return &msg, nil
+}
+
+// RequestDeviceApproval posts an approval request for operation and
+// returns the request ID to wait on.
+func RequestDeviceApproval(m libkb.MetaContext, operation string) (id string, err error) {
+	defer m.Trace(fmt.Sprintf("RequestDeviceApproval(%s)", operation), &err)()
+	id, err = libkb.RandHexString("", 8)
+	if err != nil {
+		return "", err
+	}
+	conv, err := deviceApprovalConv(m)
+	if err != nil {
+		return "", err
+	}
+	msg := deviceApprovalMsg{
+		Kind:      deviceApprovalKindRequest,
+		ID:        id,
+		Operation: operation,
+		Device:    m.G().ActiveDevice.Name(),
+		ExpiresAt: m.G().Clock().Now().Add(deviceApprovalLifetime).Unix(),
+	}
+	if err := sendDeviceApprovalMsg(m, conv, msg); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// AwaitDeviceApproval polls the approvals conversation until another
+// device responds to request id, the timeout elapses, or the context is
+// canceled. A denial is returned as approved=false with a nil error.
+func AwaitDeviceApproval(m libkb.MetaContext, id string, timeout time.Duration) (
+	approved bool, err error) {
+	defer m.Trace(fmt.Sprintf("AwaitDeviceApproval(%s)", id), &err)()
+	deadline := m.G().Clock().Now().Add(timeout)
+	for {
+		// Re-find the conversation each round to refresh its max
+		// messages.
+		conv, err := deviceApprovalConv(m)
+		if err != nil {
+			return false, err
+		}
+		msg, err := latestDeviceApprovalMsg(m, conv)
+		if err != nil {
+			return false, err
+		}
+		if msg != nil && msg.Kind == deviceApprovalKindResponse && msg.ID == id {
+			return msg.Approve, nil
+		}
+		if m.G().Clock().Now().After(deadline) {
+			return false, fmt.Errorf("timed out waiting for approval of request %s", id)
+		}
+		select {
+		case <-m.Ctx().Done():
+			return false, m.Ctx().Err()
+		case <-time.After(deviceApprovalPollInterval):
+		}
+	}
+}
+
+// PendingDeviceApproval returns the latest unexpired, unanswered
+// request in the approvals conversation, or nil.
+func PendingDeviceApproval(m libkb.MetaContext) (*deviceApprovalMsg, error) {
+	conv, err := deviceApprovalConv(m)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := latestDeviceApprovalMsg(m, conv)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.Kind != deviceApprovalKindRequest {
+		return nil, nil
+	}
+	if msg.expired(m.G().Clock().Now()) {
+		return nil, nil
+	}
+	return msg, nil
+}
+
+// RespondDeviceApproval posts this device's verdict on request id.
+func RespondDeviceApproval(m libkb.MetaContext, id string, approve bool) (err error) {
+	defer m.Trace(fmt.Sprintf("RespondDeviceApproval(%s,%v)", id, approve), &err)()
+	conv, err := deviceApprovalConv(m)
+	if err != nil {
+		return err
+	}
+	return sendDeviceApprovalMsg(m, conv, deviceApprovalMsg{
+		Kind:    deviceApprovalKindResponse,
+		ID:      id,
+		Device:  m.G().ActiveDevice.Name(),
+		Approve: approve,
+	})
+}
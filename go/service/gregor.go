@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -640,6 +641,48 @@ func (g *gregorHandler) replayInBandMessages(ctx context.Context, cli gregor1.In
 	return msgs, nil
 }
 
+// ReplayCategory replays the in-band items currently held under the
+// given category prefix through all local handlers, as if they had just
+// arrived. It is driven by `keybase ctl gregor replay` for digging out
+// of stuck-notification situations without a logout/login cycle.
+func (g *gregorHandler) ReplayCategory(ctx context.Context, catPrefix gregor1.Category) (res int, err error) {
+	defer g.G().CTrace(ctx, "gregorHandler#ReplayCategory", &err)()
+	cli := g.getRPCCli()
+	if !g.IsConnected() || cli == nil {
+		return 0, errors.New("gregor not connected")
+	}
+	gcli, err := g.getGregorCli()
+	if err != nil {
+		return 0, err
+	}
+	state, err := gcli.StateMachineState(ctx, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	msgs, err := gcli.InBandMessagesFromState(state)
+	if err != nil {
+		return 0, err
+	}
+	incoming := gregor1.IncomingClient{Cli: cli}
+	for _, msg := range msgs {
+		sum := msg.ToStateUpdateMessage()
+		if sum == nil || sum.Creation() == nil {
+			continue
+		}
+		if !strings.HasPrefix(sum.Creation().Category().String(), catPrefix.String()) {
+			continue
+		}
+		g.Debug(ctx, "ReplayCategory: replaying: %s", msg.Metadata().MsgID())
+		// Keep going on individual failures, matching replayInBandMessages.
+		if err := g.handleInBandMessage(ctx, incoming, msg); err != nil {
+			g.Debug(ctx, "ReplayCategory: failure in message replay: %s", err)
+			continue
+		}
+		res++
+	}
+	return res, nil
+}
+
 func (g *gregorHandler) IsShutdown() bool {
 	g.connMutex.Lock()
 	defer g.connMutex.Unlock()
@@ -60,7 +60,8 @@ func (h *IdentifyHandler) Identify2(netCtx context.Context, arg keybase1.Identif
 	eng := engine.NewResolveThenIdentify2(h.G(), &arg)
 	err = engine.RunEngine2(m, eng)
 	if err != nil {
-		return res, err
+		return res, libkb.NewStructuredError(libkb.ErrorSubsystemIdentify, libkb.ErrorIsRetryable(err),
+			"Verify the assertion is spelled correctly and the user has not reset", err)
 	}
 	resp, err := eng.Result(m)
 	if err != nil {
@@ -6,6 +6,7 @@
 package service
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -37,6 +38,8 @@ func (r *kbfsFavoritesHandler) Create(ctx context.Context, cli gregor1.IncomingI
 	switch category {
 	case "kbfs.favorites":
 		return true, r.favoritesChanged(ctx, cli, item)
+	case "kbfs.share_invite":
+		return true, r.shareInvite(ctx, cli, item)
 	default:
 		if strings.HasPrefix(category, "kbfs.") {
 			return false, fmt.Errorf("unknown KBFS category: %q", category)
@@ -73,3 +76,23 @@ func (r *kbfsFavoritesHandler) favoritesChanged(ctx context.Context,
 	r.Contextified.G().NotifyRouter.HandleFavoritesChanged(kbUID)
 	return nil
 }
+
+func (r *kbfsFavoritesHandler) shareInvite(ctx context.Context,
+	cli gregor1.IncomingInterface,
+	item gregor.Item) error {
+	r.G().Log.CDebugf(ctx, "kbfsFavoritesHandler: kbfs.share_invite received")
+
+	var invite libkb.TlfInvite
+	if err := json.Unmarshal(item.Body().Bytes(), &invite); err != nil {
+		return err
+	}
+
+	// Locally dismiss so each client only plays the invite once:
+	if err := r.G().GregorState.LocalDismissItem(ctx, item.Metadata().MsgID()); err != nil {
+		r.G().Log.CDebugf(ctx,
+			"failed to locally dismiss shareInvite notification: %s", err)
+	}
+
+	r.G().NotifyRouter.HandleTlfShareInvite(ctx, invite)
+	return nil
+}
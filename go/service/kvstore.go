@@ -39,7 +39,21 @@ func NewKVStoreHandler(xp rpc.Transporter, g *libkb.GlobalContext) *KVStoreHandl
 }
 
 func (h *KVStoreHandler) resolveTeam(mctx libkb.MetaContext, userInputTeamName string) (teamID keybase1.TeamID, err error) {
-	if strings.Contains(userInputTeamName, ",") {
+	isImplicit := strings.Contains(userInputTeamName, ",")
+	username := mctx.ActiveDevice().Username(mctx)
+	if userInputTeamName == "" {
+		// An empty team name means the caller's own per-user store,
+		// which lives in their implicit self-team.
+		if username.IsNil() {
+			return teamID, libkb.NewLoginRequiredError("per-user kvstore")
+		}
+		userInputTeamName = username.String()
+		isImplicit = true
+	} else if libkb.NewNormalizedUsername(userInputTeamName).Eq(username) {
+		// The caller's own username also names the per-user store.
+		isImplicit = true
+	}
+	if isImplicit {
 		// it's an implicit team that might not exist yet
 		team, _, _, err := teams.LookupOrCreateImplicitTeam(mctx.Ctx(), mctx.G(), userInputTeamName, false /*public*/)
 		if err != nil {
@@ -249,6 +263,7 @@ func (h *KVStoreHandler) putKVEntryLocked(ctx context.Context, arg keybase1.PutK
 		mctx.Debug("%+v: %s", entryID, err)
 		return res, err
 	}
+	kvstore.NotifyChange(entryID, apiRes.Revision)
 	return keybase1.KVPutResult{
 		TeamName:  arg.TeamName,
 		Namespace: arg.Namespace,
@@ -335,6 +350,7 @@ func (h *KVStoreHandler) delKVEntryLocked(ctx context.Context, arg keybase1.DelK
 		mctx.Debug("%+v: %s", entryID, err)
 		return res, err
 	}
+	kvstore.NotifyChange(entryID, revision)
 	return keybase1.KVDeleteEntryResult{
 		TeamName:  arg.TeamName,
 		Namespace: arg.Namespace,
@@ -122,7 +122,9 @@ func (h *LoginHandler) Login(ctx context.Context, arg keybase1.LoginArg) error {
 	eng := engine.NewLoginWithUserSwitch(h.G(), arg.DeviceType, arg.Username, arg.ClientType, arg.DoUserSwitch)
 	eng.PaperKey = arg.PaperKey
 	eng.DeviceName = arg.DeviceName
-	return engine.RunEngine2(m, eng)
+	err := engine.RunEngine2(m, eng)
+	return libkb.NewStructuredError(libkb.ErrorSubsystemLogin, libkb.ErrorIsRetryable(err),
+		"Check your username and connectivity, then retry `keybase login`", err)
 }
 
 func (h *LoginHandler) LoginProvisionedDevice(ctx context.Context, arg keybase1.LoginProvisionedDeviceArg) error {
@@ -14,12 +14,15 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"runtime/trace"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/keybase/cli"
+	"github.com/keybase/client/go/accessibility"
+	"github.com/keybase/client/go/activity"
 	"github.com/keybase/client/go/avatars"
 	"github.com/keybase/client/go/badges"
 	"github.com/keybase/client/go/chat"
@@ -34,6 +37,7 @@ import (
 	"github.com/keybase/client/go/chat/unfurl"
 	"github.com/keybase/client/go/chat/wallet"
 	"github.com/keybase/client/go/contacts"
+	"github.com/keybase/client/go/deeplinks"
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/ephemeral"
 	"github.com/keybase/client/go/externals"
@@ -82,7 +86,9 @@ type Service struct {
 	trackerLoader    *TrackerLoader
 	httpSrv          *manager.Srv
 	avatarSrv        *avatars.Srv
+	activityTimeline *activity.Timeline
 	referrerListener InstallReferrerListener // Android only
+	startupTrace     *libkb.StartupTrace
 
 	loginAttemptMu  sync.Mutex
 	loginAttempt    libkb.LoginAttempt
@@ -115,6 +121,7 @@ func NewService(g *libkb.GlobalContext, isDaemon bool) *Service {
 		walletState:      stellar.NewWalletState(g, remote.NewRemoteNet(g)),
 		offlineRPCCache:  offline.NewRPCCache(g),
 		httpSrv:          manager.NewSrv(g),
+		startupTrace:     libkb.NewStartupTrace(),
 	}
 }
 
@@ -126,6 +133,7 @@ func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID
 	g := d.G()
 	cg := globals.NewContext(g, d.ChatG())
 	contactsProv := NewCachedContactsProvider(g)
+	userSearchHandler := NewUserSearchHandler(xp, g, contactsProv)
 
 	protocols := []rpc.Protocol{
 		keybase1.AccountProtocol(NewAccountHandler(xp, g)),
@@ -185,7 +193,7 @@ func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID
 		keybase1.InviteFriendsProtocol(NewInviteFriendsHandler(xp, g)),
 		keybase1.Identify3Protocol(newIdentify3Handler(xp, g)),
 		keybase1.AuditProtocol(NewAuditHandler(xp, g)),
-		keybase1.UserSearchProtocol(NewUserSearchHandler(xp, g, contactsProv)),
+		keybase1.UserSearchProtocol(userSearchHandler),
 		keybase1.BotProtocol(NewBotHandler(xp, g)),
 		keybase1.FeaturedBotProtocol(NewFeaturedBotHandler(xp, g)),
 		keybase1.WotProtocol(NewWebOfTrustHandler(xp, g)),
@@ -197,7 +205,7 @@ func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID
 		libkb.RPCCancelerReasonLogout))
 	userHandler := NewUserHandler(xp, g, d.ChatG(), d)
 	protocols = append(protocols, keybase1.UserProtocol(userHandler))
-	protocols = append(protocols, keybase1.DebuggingProtocol(NewDebuggingHandler(xp, g, userHandler, walletHandler)))
+	protocols = append(protocols, keybase1.DebuggingProtocol(NewDebuggingHandler(xp, g, userHandler, walletHandler, userSearchHandler, d.badger)))
 	for _, proto := range protocols {
 		if err = srv.Register(proto); err != nil {
 			return err
@@ -304,30 +312,40 @@ func (d *Service) Run() (err error) {
 		return
 	}
 
+	dbStage := d.startupTrace.Stage("local db open")
 	if err = d.G().LocalDb.ForceOpen(); err != nil {
 		return err
 	}
 	if err = d.G().LocalChatDb.ForceOpen(); err != nil {
 		return err
 	}
+	dbStage()
 
 	var l net.Listener
 	if l, err = d.ConfigRPCServer(); err != nil {
 		return err
 	}
 
+	subServicesStage := d.startupTrace.Stage("critical subservices")
 	if err = d.SetupCriticalSubServices(); err != nil {
 		return err
 	}
+	subServicesStage()
 
 	if err = d.runOneshot(mctx); err != nil {
 		return err
 	}
 
+	chatSetupStage := d.startupTrace.Stage("chat modules setup")
 	d.SetupChatModules(nil)
+	chatSetupStage()
 
 	d.RunBackgroundOperations(uir)
 
+	if terr := d.startupTrace.Finish(d.G()); terr != nil {
+		mctx.Debug("unable to write startup trace: %s", terr)
+	}
+
 	// At this point initialization is complete, and we're about to start the
 	// listen loop. This is the natural point to report "startup successful" to
 	// the supervisor (currently just systemd on Linux). This isn't necessary
@@ -356,6 +374,9 @@ func (d *Service) SetupCriticalSubServices() error {
 	d.avatarSrv = avatars.ServiceInit(d.G(), d.httpSrv, d.G().GetAvatarLoader())
 	contacts.ServiceInit(d.G())
 	maps.ServiceInit(allG, d.httpSrv)
+	d.activityTimeline = activity.ServiceInit(d.G(), d.httpSrv)
+	accessibility.ServiceInit(d.G(), d.httpSrv)
+	deeplinks.ServiceInit(d.G(), d.httpSrv)
 	return nil
 }
 
@@ -365,14 +386,21 @@ func (d *Service) RunBackgroundOperations(uir *UIRouter) {
 	// backgrounded.
 	d.G().Log.Debug("RunBackgroundOperations: starting")
 	ctx := context.Background()
+	loginStage := d.startupTrace.Stage("login attempt")
 	d.tryLogin(ctx, libkb.LoginAttemptOnline)
+	loginStage()
 	d.chatOutboxPurgeCheck()
 	d.hourlyChecks()
 	d.slowChecks() // 6 hours
+	gregorStage := d.startupTrace.Stage("gregor startup")
 	d.startupGregor()
+	gregorStage()
+	chatStage := d.startupTrace.Stage("chat modules start")
 	d.startChatModules()
+	chatStage()
 	d.addGlobalHooks()
 	d.configurePath()
+	d.applyConfigLogLevels()
 	d.configureRekey(uir)
 	d.runBackgroundPerUserKeyUpgrade()
 	d.runBackgroundPerUserKeyUpkeep()
@@ -381,13 +409,17 @@ func (d *Service) RunBackgroundOperations(uir *UIRouter) {
 	d.runBackgroundBoxAuditScheduler()
 	d.runBackgroundContactSync()
 	d.runBackgroundInviteFriendsPoll()
+	d.runBackgroundSessionRefresh()
 	d.runTLFUpgrade()
 	d.runTrackerLoader(ctx)
 	d.runRuntimeStats(ctx)
 	d.runTeamUpgrader(ctx)
 	d.runHomePoller(ctx)
 	d.runMerkleAudit(ctx)
+	d.runMerklePrefetch(ctx)
+	d.runConfigWatcher(ctx)
 	d.startInstallReferrerListener(d.MetaContext(ctx))
+	d.G().NotifyRouter.Digester().Start(d.MetaContext(ctx))
 }
 
 func (d *Service) purgeOldChatAttachmentData() {
@@ -540,6 +572,8 @@ func (d *Service) SetupChatModules(ri func() chat1.RemoteInterface) {
 	g.UIThreadLoader = chat.NewUIThreadLoader(g, ri)
 	g.ParticipantsSource = chat.NewCachingParticipantSource(g, ri)
 	g.EmojiSource = chat.NewDevConvEmojiSource(g, ri)
+	g.MessageRequests = chat.NewMessageRequestManager(g)
+	g.MentionGroupSource = chat.NewDevConvMentionGroupSource(g, ri)
 
 	// Set up Offlinables on Syncer
 	chatSyncer.RegisterOfflinable(g.InboxSource)
@@ -599,6 +633,26 @@ func (d *Service) runMerkleAudit(ctx context.Context) {
 	d.G().PushShutdownHook(eng.Shutdown)
 }
 
+func (d *Service) runMerklePrefetch(ctx context.Context) {
+	eng := engine.NewMerklePrefetch(d.G(), &engine.MerklePrefetchArgs{})
+	m := libkb.NewMetaContextBackground(d.G())
+	if err := engine.RunEngine2(m, eng); err != nil {
+		m.Warning("merkle root prefetch error: %v", err)
+	}
+
+	d.G().PushShutdownHook(eng.Shutdown)
+}
+
+func (d *Service) runConfigWatcher(ctx context.Context) {
+	watcher := newConfigWatcher(d.G())
+	watcher.Start(ctx)
+	d.G().PushShutdownHook(func(mctx libkb.MetaContext) error {
+		mctx.Debug("stopping config watcher")
+		watcher.Stop()
+		return nil
+	})
+}
+
 func (d *Service) startupGregor() {
 	g := d.G()
 	if g.Env.GetGregorDisabled() {
@@ -753,6 +807,13 @@ func (d *Service) hourlyChecks() {
 				m.Debug("CheckTracking error: %s", err)
 			}
 
+			m.Debug("| refreshing frequently used UPAKs")
+			if n, err := m.G().GetUPAKLoader().RefreshFrequentlyUsed(m.Ctx(), 50); err != nil {
+				m.Debug("RefreshFrequentlyUsed error: %s", err)
+			} else {
+				m.Debug("| refreshed %d frequently used UPAKs", n)
+			}
+
 			m.Debug("- hourly check loop")
 		}
 	}()
@@ -938,6 +999,37 @@ func (d *Service) runBackgroundInviteFriendsPoll() {
 	})
 }
 
+func (d *Service) runBackgroundSessionRefresh() {
+	eng := engine.NewSessionRefreshBackground(d.G(), &engine.SessionRefreshBackgroundArgs{
+		OnRenewFailure: func(m libkb.MetaContext, err error) {
+			m.Debug("session refresh failed: %v; kicking gregor connection", err)
+			if d.gregor != nil {
+				if _, rerr := d.gregor.Reconnect(m.Ctx()); rerr != nil {
+					m.Debug("gregor reconnect after session refresh failure: %v", rerr)
+				}
+			}
+			// If we need a full re-login, tell the UI now rather
+			// than waiting for API calls to start erroring.
+			if _, ok := err.(libkb.LoginRequiredError); ok {
+				m.G().NotifyRouter.HandleLogout(m.Ctx())
+			}
+		},
+	})
+	go func() {
+		m := libkb.NewMetaContextBackground(d.G())
+		err := engine.RunEngine2(m, eng)
+		if err != nil {
+			m.Warning("background SessionRefresh error: %v", err)
+		}
+	}()
+
+	d.G().PushShutdownHook(func(mctx libkb.MetaContext) error {
+		d.G().Log.Debug("stopping background SessionRefresh")
+		eng.Shutdown()
+		return nil
+	})
+}
+
 func (d *Service) OnLogin(mctx libkb.MetaContext) error {
 	d.rekeyMaster.Login()
 	if err := d.gregordConnect(); err != nil {
@@ -1330,6 +1422,34 @@ func (d *Service) configurePath() {
 	}
 }
 
+// applyConfigLogLevels reapplies any per-module log level overrides
+// persisted in the config file, so levels set with `keybase log
+// set-level` survive a service restart.
+func (d *Service) applyConfigLogLevels() {
+	i, err := d.G().Env.GetConfig().GetInterfaceAtPath(strings.TrimSuffix(logLevelsConfigPrefix, "."))
+	if err != nil || i == nil {
+		return
+	}
+	var apply func(prefix string, v interface{})
+	apply = func(prefix string, v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if err := applyModuleLogLevel(prefix, v); err != nil {
+				d.G().Log.Debug("applyConfigLogLevels: skipping %s: %s", prefix, err)
+			}
+		case map[string]interface{}:
+			for key, sub := range v {
+				name := key
+				if prefix != "" {
+					name = prefix + "." + key
+				}
+				apply(name, sub)
+			}
+		}
+	}
+	apply("", i)
+}
+
 // tryLogin runs LoginOffline which will load the local session file and unlock the
 // local device keys without making any network requests.
 //
@@ -0,0 +1,265 @@
+// Copyright 2026 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keybase/client/go/engine"
+	"github.com/keybase/client/go/libkb"
+	gregor1 "github.com/keybase/client/go/protocol/gregor1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// personPickerWeights are the client-side ranking weights for the
+// unified person search. A candidate found through several sources
+// accumulates the weight of each, so a followed teammate outranks a
+// bare service assertion with the same match quality.
+type personPickerWeights struct {
+	TeamMember       float64 `json:"teamMember"`
+	Followed         float64 `json:"followed"`
+	RecentChat       float64 `json:"recentChat"`
+	Contact          float64 `json:"contact"`
+	Keybase          float64 `json:"keybase"`
+	ServiceAssertion float64 `json:"serviceAssertion"`
+}
+
+func defaultPersonPickerWeights() personPickerWeights {
+	return personPickerWeights{
+		TeamMember:       3,
+		Followed:         2.5,
+		RecentChat:       2,
+		Contact:          1.5,
+		Keybase:          1,
+		ServiceAssertion: 0.75,
+	}
+}
+
+// personPickerResult is one ranked "add people" candidate.
+type personPickerResult struct {
+	Assertion string   `json:"assertion"`
+	FullName  string   `json:"fullName,omitempty"`
+	Sources   []string `json:"sources"`
+	Score     float64  `json:"score"`
+}
+
+// editDistanceAtMostOne reports whether b can be made from a with at
+// most one insertion, deletion, or substitution.
+func editDistanceAtMostOne(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	switch len(b) - len(a) {
+	case 0:
+		diffs := 0
+		for i := range a {
+			if a[i] != b[i] {
+				diffs++
+				if diffs > 1 {
+					return false
+				}
+			}
+		}
+		return true
+	case 1:
+		for i := 0; i < len(a); i++ {
+			if a[i] != b[i] {
+				return a[i:] == b[i+1:]
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// fuzzyMatchScore scores how well candidate matches query, in [0, 1].
+// Exact matches beat prefixes beat substrings, and a single typo still
+// matches, just weaker, so "add people" boxes tolerate small mistakes.
+func fuzzyMatchScore(query, candidate string) float64 {
+	query = normalizeText(query)
+	candidate = normalizeText(candidate)
+	if query == "" || candidate == "" {
+		return 0
+	}
+	switch {
+	case candidate == query:
+		return 1
+	case strings.HasPrefix(candidate, query):
+		return 0.9
+	case strings.Contains(candidate, query):
+		return 0.75
+	case editDistanceAtMostOne(query, candidate):
+		return 0.6
+	}
+	return 0
+}
+
+// personPickerAccumulator merges candidates found through multiple
+// sources, keyed by lowercased assertion.
+type personPickerAccumulator struct {
+	results map[string]*personPickerResult
+}
+
+func newPersonPickerAccumulator() *personPickerAccumulator {
+	return &personPickerAccumulator{results: make(map[string]*personPickerResult)}
+}
+
+func (a *personPickerAccumulator) add(assertion, fullName, source string, score float64) {
+	if score <= 0 {
+		return
+	}
+	key := strings.ToLower(assertion)
+	res, ok := a.results[key]
+	if !ok {
+		res = &personPickerResult{Assertion: assertion}
+		a.results[key] = res
+	}
+	if res.FullName == "" {
+		res.FullName = fullName
+	}
+	for _, seen := range res.Sources {
+		if seen == source {
+			return
+		}
+	}
+	res.Sources = append(res.Sources, source)
+	res.Score += score
+}
+
+func (a *personPickerAccumulator) ranked(max int) (res []personPickerResult) {
+	for _, r := range a.results {
+		res = append(res, *r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Assertion < res[j].Assertion
+	})
+	if len(res) > max {
+		res = res[:max]
+	}
+	return res
+}
+
+// UnifiedPersonSearch combines team members, followed users, recent
+// chat partners, synced contacts, and service assertions into a single
+// ranked list, so every "add people" box behaves the same way. Each
+// source is best effort: a failure is logged and the rest still
+// contribute.
+func (h *UserSearchHandler) UnifiedPersonSearch(mctx libkb.MetaContext, query string,
+	maxResults int, weights personPickerWeights) ([]personPickerResult, error) {
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+	acc := newPersonPickerAccumulator()
+
+	// Followed users, from the local tracking cache.
+	trackingEng := engine.NewListTrackingEngine(mctx.G(), &engine.ListTrackingEngineArg{
+		CachedOnly: true,
+	})
+	if err := engine.RunEngine2(mctx, trackingEng); err != nil {
+		mctx.Debug("UnifiedPersonSearch: tracking list failed: %s", err)
+	} else {
+		for _, user := range trackingEng.TableResult().Users {
+			score := fuzzyMatchScore(query, user.Username)
+			if s := fuzzyMatchScore(query, user.FullName); s > score {
+				score = s
+			}
+			acc.add(user.Username, user.FullName, "followed", weights.Followed*score)
+		}
+	}
+
+	// Recent chat partners and teammates, from the inbox cache.
+	if chatHelper := mctx.G().ChatHelper; chatHelper != nil {
+		uid := gregor1.UID(mctx.G().ActiveDevice.UID().ToBytes())
+		direct, teammates, err := chatHelper.RecentChatPartners(mctx.Ctx(), uid)
+		if err != nil {
+			mctx.Debug("UnifiedPersonSearch: recent chat partners failed: %s", err)
+		}
+		for _, username := range direct {
+			acc.add(username, "", "recentChat",
+				weights.RecentChat*fuzzyMatchScore(query, username))
+		}
+		for _, username := range teammates {
+			acc.add(username, "", "teamMember",
+				weights.TeamMember*fuzzyMatchScore(query, username))
+		}
+	}
+
+	// Synced contacts.
+	contactResults, err := contactSearch(mctx, keybase1.UserSearchArg{
+		Query:           query,
+		MaxResults:      maxResults,
+		IncludeContacts: true,
+	})
+	if err != nil {
+		mctx.Debug("UnifiedPersonSearch: contact search failed: %s", err)
+	}
+	for _, res := range contactResults {
+		if res.Contact == nil {
+			continue
+		}
+		name := res.Contact.Username
+		if name == "" {
+			name = res.Contact.Assertion
+		}
+		score := res.RawScore
+		if score > 1 {
+			score = 1
+		}
+		acc.add(name, res.Contact.DisplayName, "contact", weights.Contact*score)
+	}
+
+	// Keybase users from the server.
+	serverResults, err := h.searchProvider.MakeSearchRequest(mctx, keybase1.UserSearchArg{
+		Query:      query,
+		Service:    "keybase",
+		MaxResults: maxResults,
+	})
+	if err != nil {
+		mctx.Debug("UnifiedPersonSearch: server search failed: %s", err)
+	}
+	for _, res := range serverResults {
+		if res.Keybase == nil {
+			continue
+		}
+		var fullName string
+		if res.Keybase.FullName != nil {
+			fullName = *res.Keybase.FullName
+		}
+		score := fuzzyMatchScore(query, res.Keybase.Username)
+		if s := fuzzyMatchScore(query, fullName); s > score {
+			score = s
+		}
+		acc.add(res.Keybase.Username, fullName, "keybase", weights.Keybase*score)
+	}
+
+	// A query like "alice@twitter" also searches that service's
+	// namespace for assertion candidates.
+	if parts := strings.SplitN(query, "@", 2); len(parts) == 2 &&
+		parts[0] != "" && parts[1] != "" {
+		assertionResults, err := h.searchProvider.MakeSearchRequest(mctx, keybase1.UserSearchArg{
+			Query:      parts[0],
+			Service:    parts[1],
+			MaxResults: maxResults,
+		})
+		if err != nil {
+			mctx.Debug("UnifiedPersonSearch: %q search failed: %s", parts[1], err)
+		}
+		for _, res := range assertionResults {
+			if res.Service == nil {
+				continue
+			}
+			assertion := fmt.Sprintf("%s@%s", res.Service.Username, res.Service.ServiceName)
+			acc.add(assertion, res.Service.FullName, "serviceAssertion",
+				weights.ServiceAssertion*fuzzyMatchScore(parts[0], res.Service.Username))
+		}
+	}
+
+	return acc.ranked(maxResults), nil
+}
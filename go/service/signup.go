@@ -4,6 +4,7 @@
 package service
 
 import (
+	bot "github.com/keybase/client/go/bot"
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
@@ -87,6 +88,13 @@ func (h *SignupHandler) CheckUsernameAvailable(ctx context.Context, arg keybase1
 }
 
 func (h *SignupHandler) Signup(ctx context.Context, arg keybase1.SignupArg) (res keybase1.SignupRes, err error) {
+	// Scoped bot tokens can't sign up bots; reject them at dispatch rather
+	// than partway through the signup engine.
+	if arg.BotToken.Exists() {
+		if err = bot.CheckTokenForSignup(libkb.NewMetaContext(ctx, h.G()), arg.BotToken); err != nil {
+			return res, err
+		}
+	}
 	uis := libkb.UIs{
 		LogUI:     h.getLogUI(arg.SessionID),
 		GPGUI:     h.getGPGUI(arg.SessionID),
@@ -79,7 +79,9 @@ func (s *SimpleFSHandler) client(ctx context.Context) (*keybase1.SimpleFSClient,
 	for xp == nil {
 		select {
 		case <-ctx.Done():
-			return nil, WaitingForKBFSTimeoutError{ctx.Err()}
+			return nil, libkb.NewStructuredError(libkb.ErrorSubsystemSimpleFS, true,
+				"KBFS is not running; start it with `keybase ctl start` or run the Keybase app",
+				WaitingForKBFSTimeoutError{ctx.Err()})
 		default:
 		}
 		time.Sleep(waitForTransporterInterval)
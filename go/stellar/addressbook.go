@@ -0,0 +1,257 @@
+package stellar
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/protocol/stellar1"
+	"github.com/keybase/client/go/stellar/remote"
+)
+
+// AddressBookEntry is one label a user has attached to a Stellar
+// account ID, along with the Keybase identity that the account
+// resolved to when the entry was last written (if any).
+type AddressBookEntry struct {
+	AccountID stellar1.AccountID `json:"account_id"`
+	Label     string             `json:"label"`
+	// KeybaseUser is the verified Keybase username that owned
+	// AccountID when the entry was last written, or empty if the
+	// account didn't resolve to a Keybase user.
+	KeybaseUser string        `json:"keybase_user,omitempty"`
+	Mtime       keybase1.Time `json:"mtime"`
+}
+
+type addressBookDBEntry struct {
+	Version int
+	Entries map[stellar1.AccountID]AddressBookEntry
+}
+
+const addressBookDBVersion = 1
+
+// addressBookLock serializes read-modify-write cycles on the address
+// book, which is stored as a single DB entry per user.
+var addressBookLock sync.Mutex
+
+func addressBookDBKey(uv keybase1.UserVersion) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBStellarAddressBook,
+		Key: uv.String(),
+	}
+}
+
+func addressBookRead(mctx libkb.MetaContext) (res addressBookDBEntry, err error) {
+	uv, err := mctx.G().GetMeUV(mctx.Ctx())
+	if err != nil {
+		return res, err
+	}
+	found, err := mctx.G().LocalDb.GetInto(&res, addressBookDBKey(uv))
+	if err != nil {
+		return res, err
+	}
+	if !found || res.Version != addressBookDBVersion {
+		res = addressBookDBEntry{Version: addressBookDBVersion}
+	}
+	if res.Entries == nil {
+		res.Entries = make(map[stellar1.AccountID]AddressBookEntry)
+	}
+	return res, nil
+}
+
+func addressBookWrite(mctx libkb.MetaContext, entry addressBookDBEntry) error {
+	uv, err := mctx.G().GetMeUV(mctx.Ctx())
+	if err != nil {
+		return err
+	}
+	return mctx.G().LocalDb.PutObj(addressBookDBKey(uv), nil, entry)
+}
+
+// addressBookResolveUser does a verified lookup of the Keybase user
+// that owns accountID, returning the empty string if the account
+// doesn't belong to a Keybase user.
+func addressBookResolveUser(
+	mctx libkb.MetaContext, accountID stellar1.AccountID) string {
+	_, username, err := LookupUserByAccountID(mctx, accountID)
+	if err != nil {
+		mctx.Debug("addressBookResolveUser: no keybase user for %v: %v",
+			accountID, err)
+		return ""
+	}
+	return username.String()
+}
+
+// AddressBookList returns every entry in the user's address book,
+// sorted by label, then account ID.
+func AddressBookList(mctx libkb.MetaContext) (res []AddressBookEntry, err error) {
+	defer mctx.Trace("Stellar.AddressBookList", &err)()
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil {
+		return nil, err
+	}
+	res = make([]AddressBookEntry, 0, len(book.Entries))
+	for _, entry := range book.Entries {
+		res = append(res, entry)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Label != res[j].Label {
+			return res[i].Label < res[j].Label
+		}
+		return res[i].AccountID < res[j].AccountID
+	})
+	return res, nil
+}
+
+// AddressBookPut creates or replaces the entry for accountID, redoing
+// the verified lookup of its Keybase owner.
+func AddressBookPut(mctx libkb.MetaContext, accountID stellar1.AccountID,
+	label string) (res AddressBookEntry, err error) {
+	defer mctx.Trace("Stellar.AddressBookPut", &err)()
+	_, err = libkb.ParseStellarAccountID(accountID.String())
+	if err != nil {
+		return res, err
+	}
+	res = AddressBookEntry{
+		AccountID:   accountID,
+		Label:       label,
+		KeybaseUser: addressBookResolveUser(mctx, accountID),
+		Mtime:       keybase1.ToTime(mctx.G().Clock().Now()),
+	}
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil {
+		return res, err
+	}
+	book.Entries[accountID] = res
+	return res, addressBookWrite(mctx, book)
+}
+
+// AddressBookRemove deletes the entry for accountID, erroring if
+// there isn't one.
+func AddressBookRemove(
+	mctx libkb.MetaContext, accountID stellar1.AccountID) (err error) {
+	defer mctx.Trace("Stellar.AddressBookRemove", &err)()
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := book.Entries[accountID]; !ok {
+		return fmt.Errorf("no address book entry for %v", accountID)
+	}
+	delete(book.Entries, accountID)
+	return addressBookWrite(mctx, book)
+}
+
+// AddressBookLookup returns the entry for accountID, if there is one.
+// Lookup failures (e.g. while logged out) are treated as a miss.
+func AddressBookLookup(mctx libkb.MetaContext,
+	accountID stellar1.AccountID) (res AddressBookEntry, found bool) {
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil {
+		mctx.Debug("AddressBookLookup: %v", err)
+		return res, false
+	}
+	res, found = book.Entries[accountID]
+	return res, found
+}
+
+// AddressBookImportFromHistory adds an unlabeled entry for every
+// counterparty address found in the recent payments of the user's own
+// accounts that isn't already in the address book.
+func AddressBookImportFromHistory(mctx libkb.MetaContext,
+	remoter remote.Remoter) (added int, err error) {
+	defer mctx.Trace("Stellar.AddressBookImportFromHistory", &err)()
+	accounts, err := AllWalletAccounts(mctx, remoter)
+	if err != nil {
+		return 0, err
+	}
+	own := make(map[stellar1.AccountID]bool, len(accounts))
+	for _, account := range accounts {
+		own[account.AccountID] = true
+	}
+	counterparties := make(map[stellar1.AccountID]bool)
+	collect := func(accountIDs ...stellar1.AccountID) {
+		for _, accountID := range accountIDs {
+			if !accountID.IsNil() && !own[accountID] {
+				counterparties[accountID] = true
+			}
+		}
+	}
+	for _, account := range accounts {
+		page, err := remoter.RecentPayments(mctx.Ctx(), remote.RecentPaymentsArg{
+			AccountID:       account.AccountID,
+			IncludeAdvanced: true,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range page.Payments {
+			typ, err := p.Typ()
+			if err != nil {
+				continue
+			}
+			switch typ {
+			case stellar1.PaymentSummaryType_STELLAR:
+				collect(p.Stellar().From, p.Stellar().To)
+			case stellar1.PaymentSummaryType_DIRECT:
+				collect(p.Direct().FromStellar, p.Direct().ToStellar)
+			case stellar1.PaymentSummaryType_RELAY:
+				collect(p.Relay().FromStellar)
+			}
+		}
+	}
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil {
+		return 0, err
+	}
+	for accountID := range counterparties {
+		if _, ok := book.Entries[accountID]; ok {
+			continue
+		}
+		book.Entries[accountID] = AddressBookEntry{
+			AccountID:   accountID,
+			KeybaseUser: addressBookResolveUser(mctx, accountID),
+			Mtime:       keybase1.ToTime(mctx.G().Clock().Now()),
+		}
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, addressBookWrite(mctx, book)
+}
+
+// AnnotatePaymentWithAddressBook fills in the from/to usernames of a
+// localized payment when the payment only has raw Stellar addresses
+// and the address book has a verified Keybase identity for them.
+func AnnotatePaymentWithAddressBook(
+	mctx libkb.MetaContext, p *stellar1.PaymentCLILocal) {
+	addressBookLock.Lock()
+	defer addressBookLock.Unlock()
+	book, err := addressBookRead(mctx)
+	if err != nil || len(book.Entries) == 0 {
+		return
+	}
+	if p.FromUsername == nil {
+		if entry, ok := book.Entries[p.FromStellar]; ok && entry.KeybaseUser != "" {
+			username := entry.KeybaseUser
+			p.FromUsername = &username
+		}
+	}
+	if p.ToUsername == nil && p.ToStellar != nil {
+		if entry, ok := book.Entries[*p.ToStellar]; ok && entry.KeybaseUser != "" {
+			username := entry.KeybaseUser
+			p.ToUsername = &username
+		}
+	}
+}
@@ -304,6 +304,24 @@ func BuildPaymentLocal(mctx libkb.MetaContext, arg stellar1.BuildPaymentLocalArg
 					}
 				}
 
+				if recipient.AccountID != nil {
+					entry, ok := AddressBookLookup(
+						mctx, stellar1.AccountID(recipient.AccountID.String()))
+					if ok && entry.Label != "" {
+						message := fmt.Sprintf(
+							"This recipient is in your address book as %q.", entry.Label)
+						if entry.KeybaseUser != "" {
+							message = fmt.Sprintf(
+								"This recipient is in your address book as %q (Keybase user %v).",
+								entry.Label, entry.KeybaseUser)
+						}
+						res.Banners = append(res.Banners, stellar1.SendBannerLocal{
+							Level:   "info",
+							Message: message,
+						})
+					}
+				}
+
 				if recipient.HasMemo() {
 					res.PublicMemoOverride = *recipient.PublicMemo
 					log("recipient has federation public memo override: %q", res.PublicMemoOverride)
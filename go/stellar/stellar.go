@@ -1480,6 +1480,7 @@ func RecentPaymentsCLILocal(mctx libkb.MetaContext, remoter remote.Remoter, acco
 	for _, p := range page.Payments {
 		lp, err := localizePayment(mctx, p)
 		if err == nil {
+			AnnotatePaymentWithAddressBook(mctx, &lp)
 			res = append(res, stellar1.PaymentOrErrorCLILocal{
 				Payment: &lp,
 			})
@@ -1504,6 +1505,7 @@ func PaymentDetailCLILocal(ctx context.Context, g *libkb.GlobalContext, remoter
 	if err != nil {
 		return res, err
 	}
+	AnnotatePaymentWithAddressBook(mctx, &p)
 
 	p.PublicNote = payment.Memo
 	p.PublicNoteType = payment.MemoType
@@ -42,6 +42,12 @@ func New(g *libkb.GlobalContext, uiSource UISource, walletState *stellar.WalletS
 	}
 }
 
+// Remoter returns the remoter this server uses to talk to the
+// Stellar network.
+func (s *Server) Remoter() remote.Remoter {
+	return s.remoter
+}
+
 func (s *Server) assertLoggedIn(mctx libkb.MetaContext) error {
 	loggedIn := mctx.ActiveDevice().Valid()
 	if !loggedIn {